@@ -0,0 +1,353 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/cmd/order-replay/replay"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// Accounts of the orders in the book
+const (
+	StrategyAccount = "strategy"
+	HistoryAccount  = "history"
+)
+
+// Backtest runs a strategy against historical order flow in-process with the matching of the exchange-service.
+// The clock is the timestamp of the instruction which is fed, there are no limits and no events are published.
+type Backtest struct {
+	strategy    Strategy
+	book        *models.OrderBook
+	appender    trading.Appender
+	amender     trading.Amender
+	trader      trading.Trader
+	canceller   trading.Canceller
+	tracker     *marketdata.BookTracker
+	now         time.Time
+	ids         map[string]uuid.UUID
+	arrivals    map[uuid.UUID]float64
+	positions   map[string]*models.AccountPosition
+	marks       map[string]float64
+	pending     []*models.Trade
+	changed     []string
+	dispatching bool
+	report      *Report
+}
+
+// NewBacktest creates a new backtest of the strategy with a empty book
+func NewBacktest(strategy Strategy) *Backtest {
+
+	publisher := &discardPublisher{}
+
+	b := &Backtest{strategy, models.NewOrderBook(), trading.NewOrderAppender(), trading.NewOrderAmender(publisher), nil,
+		trading.NewOrderCanceller(publisher), marketdata.NewBookTracker(), time.Time{}, make(map[string]uuid.UUID),
+		make(map[uuid.UUID]float64), make(map[string]*models.AccountPosition), make(map[string]float64), nil, nil, false, &Report{}}
+	b.trader = trading.NewOrderTraderWithClock(publisher, b.Now, b)
+
+	return b
+}
+
+// Run feeds the instructions in order and returns the report.
+// The clock does not go back for instructions out of order, instructions which the exchange would reject are counted.
+func (b *Backtest) Run(instructions []replay.Instruction) *Report {
+
+	for _, instruction := range instructions {
+
+		if instruction.Timestamp.After(b.now) {
+			b.now = instruction.Timestamp
+		}
+		if b.report.Start.IsZero() {
+			b.report.Start = b.now
+		}
+		b.report.Instructions++
+
+		err := b.apply(instruction)
+		if err != nil {
+			b.report.Rejects++
+		}
+		b.dispatch()
+	}
+
+	return b.Report()
+}
+
+// Report returns the report of the instructions fed so far, open positions are marked at the last trade price
+func (b *Backtest) Report() *Report {
+
+	report := *b.report
+	report.End = b.now
+	report.Fills = append([]Fill(nil), b.report.Fills...)
+	report.Positions = getPositionReports(b.positions, b.marks)
+
+	for _, position := range report.Positions {
+		report.RealizedPnL += position.RealizedPnL
+		report.UnrealizedPnL += position.UnrealizedPnL
+	}
+
+	return &report
+}
+
+// Now returns the time of the instruction which is fed
+func (b *Backtest) Now() time.Time {
+	return b.now
+}
+
+// Submit matches a order of the strategy and adds the rest to the book
+func (b *Backtest) Submit(symbol string, direction models.TradeDirection, price float64, quantity uint) (uuid.UUID, error) {
+
+	id := uuid.NewV4()
+
+	arrival := price
+	top := b.tracker.TopOfBook(symbol)
+	if top.BidQuantity > 0 && top.AskQuantity > 0 {
+		arrival = (top.BidPrice + top.AskPrice) / 2
+	}
+	b.arrivals[id] = arrival
+
+	_, err := b.execute(StrategyAccount, handlers.OrderDTO{ID: id.String(), Symbol: symbol, Quantity: quantity, Direction: direction.String(), Price: price})
+	if err != nil {
+		delete(b.arrivals, id)
+		return uuid.Nil, err
+	}
+
+	b.report.Orders++
+	b.dispatch()
+	return id, nil
+}
+
+// Amend increases the quantity of a order of the strategy, the exchange does not decrease quantities
+func (b *Backtest) Amend(id uuid.UUID, quantity uint) error {
+
+	order, ok := b.strategyOrder(id)
+	if !ok {
+		return fmt.Errorf("order %s not found", id)
+	}
+
+	if !b.amender.Amend(b.book, models.NewOrder(id, order.Symbol, order.Price, quantity, order.Direction)) {
+		return fmt.Errorf("order %s not amended", id)
+	}
+
+	b.change(order.Symbol)
+	b.dispatch()
+	return nil
+}
+
+// Cancel cancels a order of the strategy
+func (b *Backtest) Cancel(id uuid.UUID) error {
+
+	order, ok := b.strategyOrder(id)
+	if !ok {
+		return fmt.Errorf("order %s not found", id)
+	}
+
+	if !b.canceller.Cancel(b.book, id) {
+		return fmt.Errorf("order %s is %s", id, order.Status)
+	}
+
+	b.change(order.Symbol)
+	b.dispatch()
+	return nil
+}
+
+// Order returns a copy of a order of the strategy
+func (b *Backtest) Order(id uuid.UUID) (models.Order, bool) {
+
+	order, ok := b.strategyOrder(id)
+	if !ok {
+		return models.Order{}, false
+	}
+	return *order, true
+}
+
+// TopOfBook returns the best bid and offer of the symbol
+func (b *Backtest) TopOfBook(symbol string) marketdata.TopOfBook {
+	return b.tracker.TopOfBook(symbol)
+}
+
+// Depth returns the price levels of the symbol
+func (b *Backtest) Depth(symbol string) marketdata.Depth {
+	return b.tracker.Depth(symbol)
+}
+
+// Position returns the position of the strategy in the symbol
+func (b *Backtest) Position(symbol string) models.AccountPosition {
+
+	position, ok := b.positions[symbol]
+	if !ok {
+		return models.AccountPosition{Account: StrategyAccount, Symbol: symbol}
+	}
+	return *position
+}
+
+// Traded queues the trade for the callbacks, the strategy is called back after the matching has finished
+func (b *Backtest) Traded(trade *models.Trade) {
+	b.pending = append(b.pending, trade)
+}
+
+// apply applies a historical instruction to the book, the external ids are mapped to the ids of the orders
+func (b *Backtest) apply(instruction replay.Instruction) error {
+
+	if instruction.Action == replay.NewAction {
+
+		order, err := b.execute(HistoryAccount, handlers.OrderDTO{ID: uuid.NewV4().String(), Symbol: instruction.Symbol,
+			Quantity: instruction.Quantity, Direction: instruction.Direction, Price: instruction.Price})
+		if err != nil {
+			return err
+		}
+		b.ids[instruction.ID] = order.ID
+		return nil
+	}
+
+	id, ok := b.ids[instruction.ID]
+	if !ok {
+		return errors.New(replay.UnknownIDReason)
+	}
+	order := b.book.Orders[id]
+
+	if instruction.Action == replay.CancelAction {
+		if !b.canceller.Cancel(b.book, id) {
+			return fmt.Errorf("order %s is %s", id, order.Status)
+		}
+		b.change(order.Symbol)
+		return nil
+	}
+
+	if !b.amender.Amend(b.book, models.NewOrder(id, order.Symbol, order.Price, instruction.Quantity, order.Direction)) {
+		return fmt.Errorf("order %s not amended", id)
+	}
+	b.change(order.Symbol)
+	return nil
+}
+
+// execute matches a order as the exchange-service does, orders filled on arrival are kept for the queries
+func (b *Backtest) execute(account string, dto handlers.OrderDTO) (*models.Order, error) {
+
+	order, err := handlers.ValidateOrder(dto)
+	if err != nil {
+		return nil, err
+	}
+	order.Account = account
+
+	b.trader.Trade(b.book, order)
+
+	if order.Status.IsTradeable() {
+		err = b.appender.Append(b.book, order)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		b.book.Orders[order.ID] = order
+	}
+
+	b.change(order.Symbol)
+	return order, nil
+}
+
+// dispatch calls the strategy back for the queued trades and then for the changed books until nothing is left.
+// Callbacks of actions of the strategy in a callback are queued to keep the order of the callbacks.
+func (b *Backtest) dispatch() {
+
+	if b.dispatching {
+		return
+	}
+	b.dispatching = true
+	defer func() { b.dispatching = false }()
+
+	for len(b.pending) > 0 || len(b.changed) > 0 {
+
+		if len(b.pending) > 0 {
+			trade := b.pending[0]
+			b.pending = b.pending[1:]
+			b.traded(trade)
+			continue
+		}
+
+		symbol := b.changed[0]
+		b.changed = b.changed[1:]
+		if b.tracker.Track(b.book, symbol) != nil {
+			b.strategy.OnBook(b, BookUpdate{symbol, b.now, b.tracker.TopOfBook(symbol), b.tracker.Depth(symbol)})
+		}
+	}
+}
+
+// traded updates the fills and positions of the orders of the strategy in the trade and calls the strategy back
+func (b *Backtest) traded(trade *models.Trade) {
+
+	b.report.Trades++
+	b.marks[trade.Symbol] = trade.Price
+
+	for _, direction := range []models.TradeDirection{models.Buy, models.Sell} {
+
+		id := trade.BuyOrderID
+		if direction == models.Sell {
+			id = trade.SellOrderID
+		}
+		arrival, ok := b.arrivals[id]
+		if !ok {
+			continue
+		}
+
+		slippage := trade.Price - arrival
+		quantity := int(trade.Quantity)
+		if direction == models.Sell {
+			slippage = -slippage
+			quantity = -quantity
+		}
+
+		fill := Fill{id, trade.ID, trade.Occured, trade.Symbol, direction, trade.Price, trade.Quantity, trade.Aggressor == direction, arrival, slippage}
+		b.report.Fills = append(b.report.Fills, fill)
+
+		position, ok := b.positions[trade.Symbol]
+		if !ok {
+			position = models.NewAccountPosition(StrategyAccount, trade.Symbol, 0, 0, 0, 0.0, trade.Occured)
+			b.positions[trade.Symbol] = position
+		}
+		position.Fill(quantity, trade.Price, trade.Occured)
+
+		b.strategy.OnFill(b, fill)
+	}
+
+	b.strategy.OnTrade(b, *trade)
+}
+
+// change queues the symbol for a book callback
+func (b *Backtest) change(symbol string) {
+
+	for _, changed := range b.changed {
+		if changed == symbol {
+			return
+		}
+	}
+	b.changed = append(b.changed, symbol)
+}
+
+func (b *Backtest) strategyOrder(id uuid.UUID) (*models.Order, bool) {
+
+	if _, ok := b.arrivals[id]; !ok {
+		return nil, false
+	}
+	order, ok := b.book.Orders[id]
+	return order, ok
+}
+
+// discardPublisher drops the events of the matching
+type discardPublisher struct {
+}
+
+func (dp *discardPublisher) Open() error {
+	return nil
+}
+
+func (dp *discardPublisher) Close() {
+}
+
+func (dp *discardPublisher) Publish(envelope *events.OrderEventEnvelope) error {
+	return nil
+}
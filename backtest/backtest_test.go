@@ -0,0 +1,163 @@
+package backtest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/order-replay/replay"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+var start = time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+
+type recorder struct {
+	events []string
+	fills  []Fill
+	onBook func(exchange Exchange, book BookUpdate)
+}
+
+func (r *recorder) OnBook(exchange Exchange, book BookUpdate) {
+	r.events = append(r.events, fmt.Sprintf("book %s %g/%g", book.Symbol, book.TopOfBook.BidPrice, book.TopOfBook.AskPrice))
+	if r.onBook != nil {
+		r.onBook(exchange, book)
+	}
+}
+
+func (r *recorder) OnTrade(exchange Exchange, trade models.Trade) {
+	r.events = append(r.events, fmt.Sprintf("trade %s %d@%g", trade.Symbol, trade.Quantity, trade.Price))
+}
+
+func (r *recorder) OnFill(exchange Exchange, fill Fill) {
+	r.fills = append(r.fills, fill)
+	r.events = append(r.events, fmt.Sprintf("fill %s %d@%g", fill.Direction, fill.Quantity, fill.Price))
+}
+
+func instruction(offset time.Duration, action string, direction string, price float64, quantity uint, id string) replay.Instruction {
+	return replay.Instruction{Timestamp: start.Add(offset), Action: action, Symbol: "TT", Direction: direction, Price: price, Quantity: quantity, ID: id}
+}
+
+func TestBacktest(t *testing.T) {
+
+	require := require.New(t)
+
+	var buyID uuid.UUID
+	strategy := &recorder{}
+	strategy.onBook = func(exchange Exchange, book BookUpdate) {
+		if buyID == uuid.Nil && book.TopOfBook.BidQuantity > 0 && book.TopOfBook.AskQuantity > 0 {
+			id, err := exchange.Submit("TT", models.Buy, 10, 50)
+			require.Nil(err)
+			buyID = id
+		}
+	}
+
+	backtest := NewBacktest(strategy)
+
+	report := backtest.Run([]replay.Instruction{
+		instruction(0, replay.NewAction, models.SellText, 10.1, 100, "h1"),
+		instruction(0, replay.NewAction, models.BuyText, 9.9, 100, "h2"),
+		instruction(time.Second, replay.NewAction, models.SellText, 10, 30, "h3"),
+		instruction(2*time.Second, replay.CancelAction, "", 0, 0, "h1"),
+		instruction(2*time.Second, replay.NewAction, models.SellText, 10.2, 100, "h4"),
+	})
+
+	require.Equal([]string{
+		"book TT 0/10.1",
+		"book TT 9.9/10.1",
+		"book TT 10/10.1",
+		"fill Buy 30@10",
+		"trade TT 30@10",
+		"book TT 10/10.1",
+		"book TT 10/0",
+		"book TT 10/10.2",
+	}, strategy.events)
+
+	require.Len(strategy.fills, 1)
+	require.Equal(Fill{buyID, strategy.fills[0].TradeID, start.Add(time.Second), "TT", models.Buy, 10, 30, false, 10, 0}, strategy.fills[0])
+
+	require.Equal(5, report.Instructions)
+	require.Equal(1, report.Trades)
+	require.Equal(1, report.Orders)
+	require.Equal([]PositionReport{{"TT", 30, 10, 10, 0, 0}}, report.Positions)
+
+	order, ok := backtest.Order(buyID)
+	require.True(ok)
+	require.Equal(uint(30), order.Traded)
+	require.Equal(models.PartiallyFilled, order.Status)
+
+	require.EqualError(backtest.Amend(buyID, 40), fmt.Sprintf("order %s not amended", buyID))
+	require.Nil(backtest.Amend(buyID, 60))
+	require.Equal(uint(30), backtest.TopOfBook("TT").BidQuantity)
+	require.Nil(backtest.Cancel(buyID))
+	require.EqualError(backtest.Cancel(buyID), fmt.Sprintf("order %s is Cancelled", buyID))
+
+	// the arrival price is the mid of 9.90 and 10.20
+	sellID, err := backtest.Submit("TT", models.Sell, 9.9, 40)
+	require.Nil(err)
+
+	report = backtest.Run([]replay.Instruction{
+		instruction(3*time.Second, replay.NewAction, models.BuyText, 10.2, 10, "h5"),
+		instruction(4*time.Second, replay.CancelAction, "", 0, 0, "h9"),
+	})
+
+	require.Len(strategy.fills, 2)
+	fill := strategy.fills[1]
+	require.Equal(sellID, fill.OrderID)
+	require.Equal(start.Add(2*time.Second), fill.Time)
+	require.Equal(models.Sell, fill.Direction)
+	require.Equal(uint(40), fill.Quantity)
+	require.True(fill.Aggressor)
+	require.InDelta(10.05, fill.Arrival, 1e-9)
+	require.InDelta(0.15, fill.Slippage, 1e-9)
+
+	require.Equal(start, report.Start)
+	require.Equal(start.Add(4*time.Second), report.End)
+	require.Equal(7, report.Instructions)
+	require.Equal(1, report.Rejects)
+	require.Equal(3, report.Trades)
+	require.Equal(2, report.Orders)
+	require.Len(report.Fills, 2)
+	require.Equal(uint(70), report.Volume())
+	require.InDelta(6, report.Slippage(), 1e-9)
+
+	require.Len(report.Positions, 1)
+	position := report.Positions[0]
+	require.Equal(-10, position.Quantity)
+	require.Equal(9.9, position.Price)
+	require.Equal(10.2, position.Mark)
+	require.InDelta(-3, position.RealizedPnL, 1e-9)
+	require.InDelta(-3, position.UnrealizedPnL, 1e-9)
+	require.InDelta(-6, report.PnL(), 1e-9)
+
+	require.Equal(-10, backtest.Position("TT").Quantity)
+	require.Equal(0, backtest.Position("XX").Quantity)
+}
+
+func TestBacktestRejects(t *testing.T) {
+
+	require := require.New(t)
+
+	backtest := NewBacktest(&recorder{})
+
+	_, err := backtest.Submit("TT", models.Buy, 0, 10)
+	require.NotNil(err)
+
+	report := backtest.Run([]replay.Instruction{
+		instruction(0, replay.NewAction, models.BuyText, 10, 0, "h1"),
+		instruction(0, replay.AmendAction, models.BuyText, 10, 20, "h1"),
+		instruction(0, replay.NewAction, models.BuyText, 10, 10, "h2"),
+		instruction(0, replay.AmendAction, models.BuyText, 10, 5, "h2"),
+		instruction(0, replay.AmendAction, models.BuyText, 10, 20, "h2"),
+	})
+
+	require.Equal(5, report.Instructions)
+	require.Equal(3, report.Rejects)
+	require.Equal(0, report.Orders)
+	require.Equal(uint(20), backtest.TopOfBook("TT").BidQuantity)
+
+	_, ok := backtest.Order(uuid.NewV4())
+	require.False(ok)
+	require.EqualError(backtest.Cancel(uuid.Nil), fmt.Sprintf("order %s not found", uuid.Nil))
+}
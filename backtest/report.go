@@ -0,0 +1,89 @@
+package backtest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// PositionReport is the position of the strategy in a symbol, the price is the average cost and the mark the last trade price
+type PositionReport struct {
+	Symbol        string
+	Quantity      int
+	Price         float64
+	Mark          float64
+	RealizedPnL   float64
+	UnrealizedPnL float64
+}
+
+// Report summarizes a backtest.
+// Rejects are the historical instructions which the exchange rejected, trades are all trades of the book.
+// Slippage is the cost of the fills against their arrival prices.
+type Report struct {
+	Start         time.Time
+	End           time.Time
+	Instructions  int
+	Rejects       int
+	Trades        int
+	Orders        int
+	Fills         []Fill
+	Positions     []PositionReport
+	RealizedPnL   float64
+	UnrealizedPnL float64
+}
+
+// PnL returns the realized and unrealized PnL
+func (r *Report) PnL() float64 {
+	return r.RealizedPnL + r.UnrealizedPnL
+}
+
+// Volume returns the filled quantity
+func (r *Report) Volume() uint {
+
+	volume := uint(0)
+	for _, fill := range r.Fills {
+		volume += fill.Quantity
+	}
+	return volume
+}
+
+// Slippage returns the slippage cost of the fills
+func (r *Report) Slippage() float64 {
+
+	slippage := 0.0
+	for _, fill := range r.Fills {
+		slippage += fill.Slippage * float64(fill.Quantity)
+	}
+	return slippage
+}
+
+// Write writes the report with the positions by symbol
+func (r *Report) Write(w io.Writer) {
+
+	fmt.Fprintf(w, "from %s to %s\n", r.Start.Format(time.RFC3339Nano), r.End.Format(time.RFC3339Nano))
+	fmt.Fprintf(w, "instructions %d rejected %d trades %d\n", r.Instructions, r.Rejects, r.Trades)
+	fmt.Fprintf(w, "orders %d fills %d volume %d slippage %.4f\n", r.Orders, len(r.Fills), r.Volume(), r.Slippage())
+	fmt.Fprintf(w, "pnl %.4f realized %.4f unrealized %.4f\n", r.PnL(), r.RealizedPnL, r.UnrealizedPnL)
+
+	for _, position := range r.Positions {
+		fmt.Fprintf(w, "  %-8s %8d @ %.4f mark %.4f realized %.4f unrealized %.4f\n", position.Symbol, position.Quantity, position.Price,
+			position.Mark, position.RealizedPnL, position.UnrealizedPnL)
+	}
+}
+
+func getPositionReports(positions map[string]*models.AccountPosition, marks map[string]float64) []PositionReport {
+
+	reports := make([]PositionReport, 0, len(positions))
+
+	for symbol, position := range positions {
+		mark := marks[symbol]
+		reports = append(reports, PositionReport{symbol, position.Quantity, position.Price, mark, position.RealizedPnL,
+			float64(position.Quantity) * (mark - position.Price)})
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Symbol < reports[j].Symbol })
+
+	return reports
+}
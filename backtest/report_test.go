@@ -0,0 +1,37 @@
+package backtest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportWrite(t *testing.T) {
+
+	require := require.New(t)
+
+	report := &Report{
+		Start:         start,
+		End:           start.Add(time.Minute),
+		Instructions:  12,
+		Rejects:       1,
+		Trades:        4,
+		Orders:        2,
+		Fills:         []Fill{{Quantity: 10, Slippage: 0.5}, {Quantity: 20, Slippage: -0.25}},
+		Positions:     []PositionReport{{"TT", -10, 9.9, 10.2, -3, -3}},
+		RealizedPnL:   -3,
+		UnrealizedPnL: -3,
+	}
+
+	var buffer bytes.Buffer
+	report.Write(&buffer)
+
+	require.Equal(`from 2024-01-02T09:30:00Z to 2024-01-02T09:31:00Z
+instructions 12 rejected 1 trades 4
+orders 2 fills 2 volume 30 slippage 0.0000
+pnl -6.0000 realized -3.0000 unrealized -3.0000
+  TT            -10 @ 9.9000 mark 10.2000 realized -3.0000 unrealized -3.0000
+`, buffer.String())
+}
@@ -0,0 +1,54 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// Exchange is the simulated exchange which the strategy trades on, it is implemented by *Backtest
+type Exchange interface {
+	Now() time.Time
+	Submit(symbol string, direction models.TradeDirection, price float64, quantity uint) (uuid.UUID, error)
+	Amend(id uuid.UUID, quantity uint) error
+	Cancel(id uuid.UUID) error
+	Order(id uuid.UUID) (models.Order, bool)
+	TopOfBook(symbol string) marketdata.TopOfBook
+	Depth(symbol string) marketdata.Depth
+	Position(symbol string) models.AccountPosition
+}
+
+// Strategy gets called back by the backtest and trades on the exchange.
+// The callbacks follow the action which caused them, orders submitted in a callback are matched at once
+// and their callbacks follow the callbacks which are pending.
+type Strategy interface {
+	OnBook(exchange Exchange, book BookUpdate)
+	OnTrade(exchange Exchange, trade models.Trade)
+	OnFill(exchange Exchange, fill Fill)
+}
+
+// BookUpdate is the book of a symbol after it has changed
+type BookUpdate struct {
+	Symbol    string
+	Time      time.Time
+	TopOfBook marketdata.TopOfBook
+	Depth     marketdata.Depth
+}
+
+// Fill is a trade of a order of the strategy.
+// The arrival price is the mid when the order was submitted, or the order price when a side was empty.
+// Slippage is the price difference to the arrival price per unit, positive when the fill is worse.
+type Fill struct {
+	OrderID   uuid.UUID
+	TradeID   uuid.UUID
+	Time      time.Time
+	Symbol    string
+	Direction models.TradeDirection
+	Price     float64
+	Quantity  uint
+	Aggressor bool
+	Arrival   float64
+	Slippage  float64
+}
@@ -0,0 +1,148 @@
+package candles
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tradsim/tradsim-go/events"
+)
+
+// Aggregator builds the bars of every interval from the traded events and rebuilds them on busted events
+type Aggregator struct {
+	intervals []Interval
+	store     Store
+	seen      map[string]bool
+	order     []string
+	next      int
+	mu        sync.Mutex
+}
+
+// NewAggregator creates a new aggregator which remembers up to maxTrades trade ids to skip duplicates
+func NewAggregator(intervals []Interval, store Store, maxTrades int) *Aggregator {
+	return &Aggregator{intervals, store, make(map[string]bool), make([]string, maxTrades), 0, sync.Mutex{}}
+}
+
+// Aggregate adds the trade of the event to its bars and saves the trade and the bars of all intervals together.
+// Both orders of a trade publish a event, so events of a already aggregated trade are skipped.
+func (a *Aggregator) Aggregate(event events.OrderTraded) error {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if event.TradeID == "" || a.seen[event.TradeID] {
+		return nil
+	}
+
+	stored, err := a.store.Trade(event.TradeID)
+	if err != nil {
+		return err
+	}
+	if stored != nil {
+		a.remember(event.TradeID)
+		return nil
+	}
+
+	bars := make([]*Bar, 0, len(a.intervals))
+
+	for _, interval := range a.intervals {
+
+		bar, err := a.store.Get(event.Symbol, interval.Name, interval.Start(event.Occured))
+		if err != nil {
+			return err
+		}
+
+		if bar == nil {
+			bar = NewBar(event.Symbol, interval, event.Price, event.Quantity, event.Occured)
+		} else {
+			bar.Add(event.Price, event.Quantity, event.Occured)
+		}
+
+		bars = append(bars, bar)
+	}
+
+	trade := &Trade{event.TradeID, event.Symbol, event.Price, event.Quantity, event.Occured}
+
+	err = a.store.Add(trade, bars...)
+	if err != nil {
+		return err
+	}
+
+	for _, bar := range bars {
+		log.Printf("Bar updated %s", bar)
+	}
+
+	a.remember(event.TradeID)
+
+	return nil
+}
+
+// Bust removes the trade of the event and rebuilds its bars from the remaining trades.
+// Both orders of a trade publish a event, so events of a already busted trade are skipped.
+func (a *Aggregator) Bust(event events.OrderTradeBusted) error {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	trade, err := a.store.Trade(event.TradeID)
+	if err != nil || trade == nil {
+		return err
+	}
+
+	bars := make([]*Bar, 0, len(a.intervals))
+
+	for _, interval := range a.intervals {
+
+		start := interval.Start(trade.Occured)
+
+		trades, err := a.store.Trades(trade.Symbol, start, start.Add(interval.Duration))
+		if err != nil {
+			return err
+		}
+
+		bars = append(bars, newBarWithout(interval, start, trades, trade))
+	}
+
+	err = a.store.Bust(trade, bars...)
+	if err != nil {
+		return err
+	}
+
+	for _, bar := range bars {
+		log.Printf("Bar rebuilt %s", bar)
+	}
+
+	return nil
+}
+
+// newBarWithout builds the bar of the interval from the trades except the busted one,
+// the bar has no trades when the busted trade was its only trade
+func newBarWithout(interval Interval, start time.Time, trades []*Trade, busted *Trade) *Bar {
+
+	bar := &Bar{Symbol: busted.Symbol, Interval: interval.Name, Start: start}
+
+	for _, trade := range trades {
+		if trade.ID == busted.ID {
+			continue
+		}
+		if bar.Trades == 0 {
+			bar = NewBar(trade.Symbol, interval, trade.Price, trade.Quantity, trade.Occured)
+		} else {
+			bar.Add(trade.Price, trade.Quantity, trade.Occured)
+		}
+	}
+
+	return bar
+}
+
+func (a *Aggregator) remember(tradeID string) {
+
+	if len(a.order) == 0 {
+		return
+	}
+
+	delete(a.seen, a.order[a.next])
+	a.order[a.next] = tradeID
+	a.seen[tradeID] = true
+	a.next = (a.next + 1) % len(a.order)
+}
@@ -0,0 +1,187 @@
+package candles
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/events"
+)
+
+func getTradedEvent(tradeID string, price float64, quantity uint, occured time.Time) events.OrderTraded {
+	return *events.NewOrderTraded(uuid.NewV4().String(), "ACC1", occured, tradeID, "TT", price, quantity, 1)
+}
+
+type failingStore struct {
+	*MemoryStore
+	interval string
+	failures int
+}
+
+func (fs *failingStore) Get(symbol string, interval string, start time.Time) (*Bar, error) {
+	if interval == fs.interval && fs.failures > 0 {
+		fs.failures--
+		return nil, errors.New("get failed")
+	}
+	return fs.MemoryStore.Get(symbol, interval, start)
+}
+
+func getBustedEvent(tradeID string, occured time.Time) events.OrderTradeBusted {
+	return *events.NewOrderTradeBusted(uuid.NewV4().String(), "ACC1", occured, tradeID, "TT", 0.0, 0, 2)
+}
+
+func TestAggregatorBuildsBarsPerInterval(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewMemoryStore()
+	aggregator := NewAggregator([]Interval{OneSecond, OneMinute}, store, 10)
+	start := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+
+	require.Nil(aggregator.Aggregate(getTradedEvent("T1", 2.0, 10, start.Add(500*time.Millisecond))))
+	require.Nil(aggregator.Aggregate(getTradedEvent("T2", 2.5, 5, start.Add(1500*time.Millisecond))))
+
+	seconds, _ := store.Bars("TT", "1s", time.Time{}, time.Time{})
+	require.Len(seconds, 2)
+
+	minutes, _ := store.Bars("TT", "1m", time.Time{}, time.Time{})
+	require.Len(minutes, 1)
+	require.Equal(2.0, minutes[0].Open)
+	require.Equal(2.5, minutes[0].Close)
+	require.Equal(uint(15), minutes[0].Volume)
+}
+
+func TestAggregatorSkipsOtherSideOfTrade(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewMemoryStore()
+	aggregator := NewAggregator([]Interval{OneMinute}, store, 10)
+	occured := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+
+	require.Nil(aggregator.Aggregate(getTradedEvent("T1", 2.0, 10, occured)))
+	require.Nil(aggregator.Aggregate(getTradedEvent("T1", 2.0, 10, occured)))
+
+	bars, _ := store.Bars("TT", "1m", time.Time{}, time.Time{})
+	require.Equal(uint(10), bars[0].Volume)
+	require.Equal(uint(1), bars[0].Trades)
+}
+
+func TestAggregatorLateEventUpdatesClosedBar(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewMemoryStore()
+	aggregator := NewAggregator([]Interval{OneMinute}, store, 10)
+	start := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+
+	aggregator.Aggregate(getTradedEvent("T1", 2.0, 10, start.Add(30*time.Second)))
+	aggregator.Aggregate(getTradedEvent("T2", 3.0, 10, start.Add(90*time.Second)))
+	aggregator.Aggregate(getTradedEvent("T3", 1.0, 5, start.Add(10*time.Second)))
+
+	bars, _ := store.Bars("TT", "1m", time.Time{}, time.Time{})
+	require.Len(bars, 2)
+	require.Equal(1.0, bars[0].Open)
+	require.Equal(1.0, bars[0].Low)
+	require.Equal(2.0, bars[0].Close)
+	require.Equal(uint(15), bars[0].Volume)
+	require.Equal(3.0, bars[1].Open)
+}
+
+func TestAggregatorForgetsOldestTradeIDs(t *testing.T) {
+
+	require := require.New(t)
+
+	aggregator := NewAggregator([]Interval{OneMinute}, NewMemoryStore(), 1)
+	occured := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+
+	aggregator.Aggregate(getTradedEvent("T1", 2.0, 10, occured))
+	aggregator.Aggregate(getTradedEvent("T2", 2.0, 10, occured))
+
+	require.False(aggregator.seen["T1"])
+	require.True(aggregator.seen["T2"])
+}
+
+func TestAggregatorSavesNoBarWhenAIntervalFails(t *testing.T) {
+
+	require := require.New(t)
+
+	store := &failingStore{NewMemoryStore(), "1m", 1}
+	aggregator := NewAggregator([]Interval{OneSecond, OneMinute}, store, 10)
+	occured := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+
+	require.NotNil(aggregator.Aggregate(getTradedEvent("T1", 2.0, 10, occured)))
+
+	seconds, _ := store.Bars("TT", "1s", time.Time{}, time.Time{})
+	require.Empty(seconds)
+
+	require.Nil(aggregator.Aggregate(getTradedEvent("T1", 2.0, 10, occured)))
+
+	seconds, _ = store.Bars("TT", "1s", time.Time{}, time.Time{})
+	require.Equal(uint(10), seconds[0].Volume)
+	minutes, _ := store.Bars("TT", "1m", time.Time{}, time.Time{})
+	require.Equal(uint(10), minutes[0].Volume)
+}
+
+func TestAggregatorSkipsStoredTrade(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewMemoryStore()
+	occured := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+
+	require.Nil(NewAggregator([]Interval{OneMinute}, store, 10).Aggregate(getTradedEvent("T1", 2.0, 10, occured)))
+	require.Nil(NewAggregator([]Interval{OneMinute}, store, 10).Aggregate(getTradedEvent("T1", 2.0, 10, occured)))
+
+	bars, _ := store.Bars("TT", "1m", time.Time{}, time.Time{})
+	require.Equal(uint(10), bars[0].Volume)
+}
+
+func TestAggregatorBustRebuildsBars(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewMemoryStore()
+	aggregator := NewAggregator([]Interval{OneSecond, OneMinute}, store, 10)
+	start := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+
+	aggregator.Aggregate(getTradedEvent("T1", 2.0, 10, start.Add(10*time.Second)))
+	aggregator.Aggregate(getTradedEvent("T2", 3.0, 5, start.Add(20*time.Second)))
+	aggregator.Aggregate(getTradedEvent("T3", 2.5, 4, start.Add(30*time.Second)))
+
+	require.Nil(aggregator.Bust(getBustedEvent("T2", start.Add(time.Hour))))
+	require.Nil(aggregator.Bust(getBustedEvent("T2", start.Add(time.Hour))))
+
+	minutes, _ := store.Bars("TT", "1m", time.Time{}, time.Time{})
+	require.Len(minutes, 1)
+	require.Equal(2.0, minutes[0].Open)
+	require.Equal(2.5, minutes[0].High)
+	require.Equal(2.0, minutes[0].Low)
+	require.Equal(2.5, minutes[0].Close)
+	require.Equal(uint(14), minutes[0].Volume)
+	require.Equal(uint(2), minutes[0].Trades)
+
+	seconds, _ := store.Bars("TT", "1s", time.Time{}, time.Time{})
+	require.Len(seconds, 2)
+
+	trade, _ := store.Trade("T2")
+	require.Nil(trade)
+}
+
+func TestAggregatorBustUnknownTrade(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewMemoryStore()
+	aggregator := NewAggregator([]Interval{OneMinute}, store, 10)
+	occured := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+
+	aggregator.Aggregate(getTradedEvent("T1", 2.0, 10, occured))
+
+	require.Nil(aggregator.Bust(getBustedEvent("T2", occured)))
+
+	bars, _ := store.Bars("TT", "1m", time.Time{}, time.Time{})
+	require.Equal(uint(10), bars[0].Volume)
+}
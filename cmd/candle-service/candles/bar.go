@@ -0,0 +1,56 @@
+package candles
+
+import (
+	"fmt"
+	"time"
+)
+
+// Bar defines the open, high, low, close and volume of a symbol in a interval.
+// OpenTime and CloseTime are the times of the first and last trade, so late trades update the bar correctly.
+type Bar struct {
+	Symbol    string
+	Interval  string
+	Start     time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    uint
+	Trades    uint
+	OpenTime  time.Time
+	CloseTime time.Time
+}
+
+// NewBar creates a new bar from its first trade
+func NewBar(symbol string, interval Interval, price float64, quantity uint, occured time.Time) *Bar {
+	return &Bar{symbol, interval.Name, interval.Start(occured), price, price, price, price, quantity, 1, occured, occured}
+}
+
+// Add a trade to the bar, the trade may be older than trades already added
+func (b *Bar) Add(price float64, quantity uint, occured time.Time) {
+
+	if occured.Before(b.OpenTime) {
+		b.Open = price
+		b.OpenTime = occured
+	}
+
+	if !occured.Before(b.CloseTime) {
+		b.Close = price
+		b.CloseTime = occured
+	}
+
+	if price > b.High {
+		b.High = price
+	}
+
+	if price < b.Low {
+		b.Low = price
+	}
+
+	b.Volume += quantity
+	b.Trades++
+}
+
+func (b *Bar) String() string {
+	return fmt.Sprintf("%s %s %s O:%f H:%f L:%f C:%f V:%d", b.Symbol, b.Interval, b.Start, b.Open, b.High, b.Low, b.Close, b.Volume)
+}
@@ -0,0 +1,51 @@
+package candles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarAddInOrder(t *testing.T) {
+
+	require := require.New(t)
+
+	start := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+	bar := NewBar("TT", OneMinute, 2.0, 10, start.Add(time.Second))
+	bar.Add(3.0, 5, start.Add(2*time.Second))
+	bar.Add(1.0, 5, start.Add(3*time.Second))
+	bar.Add(2.5, 5, start.Add(4*time.Second))
+
+	require.Equal(start, bar.Start)
+	require.Equal(2.0, bar.Open)
+	require.Equal(3.0, bar.High)
+	require.Equal(1.0, bar.Low)
+	require.Equal(2.5, bar.Close)
+	require.Equal(uint(25), bar.Volume)
+	require.Equal(uint(4), bar.Trades)
+}
+
+func TestBarAddLate(t *testing.T) {
+
+	require := require.New(t)
+
+	start := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+	bar := NewBar("TT", OneMinute, 2.0, 10, start.Add(30*time.Second))
+	bar.Add(1.5, 5, start.Add(10*time.Second))
+	bar.Add(2.2, 5, start.Add(20*time.Second))
+
+	require.Equal(1.5, bar.Open)
+	require.Equal(2.0, bar.Close)
+	require.Equal(1.5, bar.Low)
+	require.Equal(uint(20), bar.Volume)
+}
+
+func TestBarString(t *testing.T) {
+
+	require := require.New(t)
+
+	bar := NewBar("TT", OneMinute, 2.0, 10, time.Date(2016, 8, 13, 17, 33, 11, 0, time.UTC))
+
+	require.Equal("TT 1m 2016-08-13 17:33:00 +0000 UTC O:2.000000 H:2.000000 L:2.000000 C:2.000000 V:10", bar.String())
+}
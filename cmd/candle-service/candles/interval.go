@@ -0,0 +1,59 @@
+package candles
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Interval defines the period of a bar
+type Interval struct {
+	Name     string
+	Duration time.Duration
+}
+
+// The supported intervals
+var (
+	OneSecond   = Interval{"1s", time.Second}
+	OneMinute   = Interval{"1m", time.Minute}
+	FiveMinutes = Interval{"5m", 5 * time.Minute}
+	OneHour     = Interval{"1h", time.Hour}
+	OneDay      = Interval{"1d", 24 * time.Hour}
+)
+
+var intervals = []Interval{OneSecond, OneMinute, FiveMinutes, OneHour, OneDay}
+
+// ParseInterval returns the interval by name
+func ParseInterval(name string) (Interval, error) {
+	for _, interval := range intervals {
+		if interval.Name == name {
+			return interval, nil
+		}
+	}
+	return Interval{}, fmt.Errorf("Interval %s not supported", name)
+}
+
+// ParseIntervals returns the intervals of a comma separated list of names
+func ParseIntervals(names string) ([]Interval, error) {
+
+	var parsed []Interval
+
+	for _, name := range strings.Split(names, ",") {
+		interval, err := ParseInterval(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, interval)
+	}
+
+	return parsed, nil
+}
+
+// Start returns the start of the bar which contains the time, days start at midnight UTC
+func (i Interval) Start(t time.Time) time.Time {
+	return t.UTC().Truncate(i.Duration)
+}
+
+func (i Interval) String() string {
+	return i.Name
+}
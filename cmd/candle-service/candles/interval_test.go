@@ -0,0 +1,32 @@
+package candles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIntervals(t *testing.T) {
+
+	require := require.New(t)
+
+	parsed, err := ParseIntervals("1s, 1m,5m,1h,1d")
+	require.Nil(err)
+	require.Equal([]Interval{OneSecond, OneMinute, FiveMinutes, OneHour, OneDay}, parsed)
+
+	_, err = ParseIntervals("1s,2m")
+	require.NotNil(err)
+}
+
+func TestIntervalStart(t *testing.T) {
+
+	require := require.New(t)
+
+	l, _ := time.LoadLocation("Europe/Athens")
+	dt := time.Date(2016, 8, 13, 1, 33, 11, 111, l)
+
+	require.Equal(time.Date(2016, 8, 12, 22, 33, 11, 0, time.UTC), OneSecond.Start(dt))
+	require.Equal(time.Date(2016, 8, 12, 22, 30, 0, 0, time.UTC), FiveMinutes.Start(dt))
+	require.Equal(time.Date(2016, 8, 12, 0, 0, 0, 0, time.UTC), OneDay.Start(dt))
+}
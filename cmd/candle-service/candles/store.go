@@ -0,0 +1,158 @@
+package candles
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store interface
+type Store interface {
+	Get(symbol string, interval string, start time.Time) (*Bar, error)
+	Save(bars ...*Bar) error
+	Bars(symbol string, interval string, from time.Time, to time.Time) ([]*Bar, error)
+	Trade(id string) (*Trade, error)
+	Trades(symbol string, from time.Time, to time.Time) ([]*Trade, error)
+	Add(trade *Trade, bars ...*Bar) error
+	Bust(trade *Trade, bars ...*Bar) error
+}
+
+type barKey struct {
+	symbol   string
+	interval string
+	start    int64
+}
+
+// MemoryStore keeps the bars in memory
+type MemoryStore struct {
+	bars   map[barKey]*Bar
+	trades map[string]*Trade
+	mu     sync.RWMutex
+}
+
+// NewMemoryStore creates a new memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{make(map[barKey]*Bar), make(map[string]*Trade), sync.RWMutex{}}
+}
+
+// Get returns a copy of the bar or nil if not found
+func (ms *MemoryStore) Get(symbol string, interval string, start time.Time) (*Bar, error) {
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	bar, ok := ms.bars[barKey{symbol, interval, start.UnixNano()}]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *bar
+	return &copied, nil
+}
+
+// Save a copy of the bars, bars without trades are deleted
+func (ms *MemoryStore) Save(bars ...*Bar) error {
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.save(bars)
+	return nil
+}
+
+func (ms *MemoryStore) save(bars []*Bar) {
+
+	for _, bar := range bars {
+		key := barKey{bar.Symbol, bar.Interval, bar.Start.UnixNano()}
+		if bar.Trades == 0 {
+			delete(ms.bars, key)
+			continue
+		}
+		copied := *bar
+		ms.bars[key] = &copied
+	}
+}
+
+// Bars returns the bars starting in [from, to) ordered by start, zero times do not filter
+func (ms *MemoryStore) Bars(symbol string, interval string, from time.Time, to time.Time) ([]*Bar, error) {
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	bars := make([]*Bar, 0)
+
+	for key, bar := range ms.bars {
+		if key.symbol != symbol || key.interval != interval {
+			continue
+		}
+		if !from.IsZero() && bar.Start.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !bar.Start.Before(to) {
+			continue
+		}
+		copied := *bar
+		bars = append(bars, &copied)
+	}
+
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Start.Before(bars[j].Start) })
+
+	return bars, nil
+}
+
+// Trade returns a copy of the trade or nil if not found
+func (ms *MemoryStore) Trade(id string) (*Trade, error) {
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	trade, ok := ms.trades[id]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *trade
+	return &copied, nil
+}
+
+// Trades returns the trades of the symbol which occured in [from, to)
+func (ms *MemoryStore) Trades(symbol string, from time.Time, to time.Time) ([]*Trade, error) {
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	trades := make([]*Trade, 0)
+
+	for _, trade := range ms.trades {
+		if trade.Symbol != symbol || trade.Occured.Before(from) || !trade.Occured.Before(to) {
+			continue
+		}
+		copied := *trade
+		trades = append(trades, &copied)
+	}
+
+	return trades, nil
+}
+
+// Add a copy of the trade and save copies of its bars
+func (ms *MemoryStore) Add(trade *Trade, bars ...*Bar) error {
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	copied := *trade
+	ms.trades[trade.ID] = &copied
+	ms.save(bars)
+	return nil
+}
+
+// Bust removes the trade and saves copies of its rebuilt bars, bars without trades are deleted
+func (ms *MemoryStore) Bust(trade *Trade, bars ...*Bar) error {
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.trades, trade.ID)
+	ms.save(bars)
+	return nil
+}
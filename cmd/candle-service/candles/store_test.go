@@ -0,0 +1,81 @@
+package candles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreGetSave(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewMemoryStore()
+	occured := time.Date(2016, 8, 13, 17, 33, 11, 0, time.UTC)
+
+	bar, err := store.Get("TT", "1m", OneMinute.Start(occured))
+	require.Nil(err)
+	require.Nil(bar)
+
+	err = store.Save(NewBar("TT", OneMinute, 2.0, 10, occured))
+	require.Nil(err)
+
+	bar, err = store.Get("TT", "1m", OneMinute.Start(occured))
+	require.Nil(err)
+	require.Equal(uint(10), bar.Volume)
+}
+
+func TestMemoryStoreBars(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewMemoryStore()
+	start := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+
+	for _, minutes := range []int{2, 0, 1} {
+		store.Save(NewBar("TT", OneMinute, 2.0, 10, start.Add(time.Duration(minutes)*time.Minute)))
+	}
+	store.Save(NewBar("TT", OneSecond, 2.0, 10, start))
+	store.Save(NewBar("XX", OneMinute, 2.0, 10, start))
+
+	bars, err := store.Bars("TT", "1m", time.Time{}, time.Time{})
+	require.Nil(err)
+	require.Len(bars, 3)
+	require.Equal(start, bars[0].Start)
+	require.Equal(start.Add(2*time.Minute), bars[2].Start)
+
+	bars, err = store.Bars("TT", "1m", start.Add(time.Minute), start.Add(2*time.Minute))
+	require.Nil(err)
+	require.Len(bars, 1)
+	require.Equal(start.Add(time.Minute), bars[0].Start)
+}
+
+func TestMemoryStoreAddBust(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewMemoryStore()
+	occured := time.Date(2016, 8, 13, 17, 33, 11, 0, time.UTC)
+	trade := &Trade{"T1", "TT", 2.0, 10, occured}
+
+	require.Nil(store.Add(trade, NewBar("TT", OneMinute, 2.0, 10, occured)))
+
+	stored, err := store.Trade("T1")
+	require.Nil(err)
+	require.Equal(trade, stored)
+
+	trades, err := store.Trades("TT", OneMinute.Start(occured), OneMinute.Start(occured).Add(time.Minute))
+	require.Nil(err)
+	require.Len(trades, 1)
+
+	trades, _ = store.Trades("TT", occured.Add(time.Second), occured.Add(time.Minute))
+	require.Empty(trades)
+
+	require.Nil(store.Bust(trade, &Bar{Symbol: "TT", Interval: "1m", Start: OneMinute.Start(occured)}))
+
+	stored, _ = store.Trade("T1")
+	require.Nil(stored)
+	bar, _ := store.Get("TT", "1m", OneMinute.Start(occured))
+	require.Nil(bar)
+}
@@ -0,0 +1,14 @@
+package candles
+
+import (
+	"time"
+)
+
+// Trade defines a aggregated trade, the trades are kept to rebuild the bars of a busted trade
+type Trade struct {
+	ID       string
+	Symbol   string
+	Price    float64
+	Quantity uint
+	Occured  time.Time
+}
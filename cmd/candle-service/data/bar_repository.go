@@ -0,0 +1,248 @@
+package data
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/candle-service/candles"
+)
+
+// BarRepository stores the bars in postgres, see schema.sql
+type BarRepository struct {
+	db              *sql.DB
+	getStmt         *sql.Stmt
+	saveStmt        *sql.Stmt
+	deleteStmt      *sql.Stmt
+	barsStmt        *sql.Stmt
+	tradeStmt       *sql.Stmt
+	tradesStmt      *sql.Stmt
+	insertTradeStmt *sql.Stmt
+	deleteTradeStmt *sql.Stmt
+}
+
+const (
+	barColumns   = `symbol, interval, start, open, high, low, close, volume, trades, open_time, close_time`
+	tradeColumns = `id, symbol, price, quantity, occured`
+)
+
+// NewBarRepository creates a new bar repository
+func NewBarRepository(db *sql.DB) (*BarRepository, error) {
+
+	getStmt, err := db.Prepare(`SELECT ` + barColumns + ` FROM bar WHERE symbol = $1 AND interval = $2 AND start = $3`)
+	if err != nil {
+		return nil, err
+	}
+
+	saveStmt, err := db.Prepare(`INSERT INTO bar (` + barColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (symbol, interval, start) DO UPDATE SET open = $4, high = $5, low = $6, close = $7, volume = $8, trades = $9, open_time = $10, close_time = $11`)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteStmt, err := db.Prepare(`DELETE FROM bar WHERE symbol = $1 AND interval = $2 AND start = $3`)
+	if err != nil {
+		return nil, err
+	}
+
+	barsStmt, err := db.Prepare(`SELECT ` + barColumns + ` FROM bar WHERE symbol = $1 AND interval = $2 AND start >= $3 AND start < $4 ORDER BY start`)
+	if err != nil {
+		return nil, err
+	}
+
+	tradeStmt, err := db.Prepare(`SELECT ` + tradeColumns + ` FROM bar_trade WHERE id = $1`)
+	if err != nil {
+		return nil, err
+	}
+
+	tradesStmt, err := db.Prepare(`SELECT ` + tradeColumns + ` FROM bar_trade WHERE symbol = $1 AND occured >= $2 AND occured < $3 ORDER BY occured`)
+	if err != nil {
+		return nil, err
+	}
+
+	insertTradeStmt, err := db.Prepare(`INSERT INTO bar_trade (` + tradeColumns + `) VALUES ($1, $2, $3, $4, $5)`)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteTradeStmt, err := db.Prepare(`DELETE FROM bar_trade WHERE id = $1`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BarRepository{db, getStmt, saveStmt, deleteStmt, barsStmt, tradeStmt, tradesStmt, insertTradeStmt, deleteTradeStmt}, nil
+}
+
+// Get returns the bar or nil if not found
+func (br *BarRepository) Get(symbol string, interval string, start time.Time) (*candles.Bar, error) {
+
+	rows, err := br.getStmt.Query(symbol, interval, start.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bars, err := scanBars(rows)
+	if err != nil || len(bars) == 0 {
+		return nil, err
+	}
+
+	return bars[0], nil
+}
+
+// Save inserts or updates the bars in one transaction, bars without trades are deleted
+func (br *BarRepository) Save(bars ...*candles.Bar) error {
+
+	tx, err := br.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = br.save(tx, bars)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (br *BarRepository) save(tx *sql.Tx, bars []*candles.Bar) error {
+
+	for _, bar := range bars {
+
+		var err error
+		if bar.Trades == 0 {
+			_, err = tx.Stmt(br.deleteStmt).Exec(bar.Symbol, bar.Interval, bar.Start.UTC())
+		} else {
+			_, err = tx.Stmt(br.saveStmt).Exec(bar.Symbol, bar.Interval, bar.Start.UTC(), bar.Open, bar.High, bar.Low, bar.Close,
+				bar.Volume, bar.Trades, bar.OpenTime.UTC(), bar.CloseTime.UTC())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Bars returns the bars starting in [from, to) ordered by start, zero times do not filter
+func (br *BarRepository) Bars(symbol string, interval string, from time.Time, to time.Time) ([]*candles.Bar, error) {
+
+	if to.IsZero() {
+		to = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	rows, err := br.barsStmt.Query(symbol, interval, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanBars(rows)
+}
+
+// Trade returns the trade or nil if not found
+func (br *BarRepository) Trade(id string) (*candles.Trade, error) {
+
+	rows, err := br.tradeStmt.Query(id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trades, err := scanTrades(rows)
+	if err != nil || len(trades) == 0 {
+		return nil, err
+	}
+
+	return trades[0], nil
+}
+
+// Trades returns the trades of the symbol which occured in [from, to)
+func (br *BarRepository) Trades(symbol string, from time.Time, to time.Time) ([]*candles.Trade, error) {
+
+	rows, err := br.tradesStmt.Query(symbol, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTrades(rows)
+}
+
+// Add inserts the trade and saves its bars in one transaction
+func (br *BarRepository) Add(trade *candles.Trade, bars ...*candles.Bar) error {
+
+	tx, err := br.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Stmt(br.insertTradeStmt).Exec(trade.ID, trade.Symbol, trade.Price, trade.Quantity, trade.Occured.UTC())
+	if err != nil {
+		return err
+	}
+
+	err = br.save(tx, bars)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Bust deletes the trade and saves its rebuilt bars in one transaction
+func (br *BarRepository) Bust(trade *candles.Trade, bars ...*candles.Bar) error {
+
+	tx, err := br.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Stmt(br.deleteTradeStmt).Exec(trade.ID)
+	if err != nil {
+		return err
+	}
+
+	err = br.save(tx, bars)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanBars(rows *sql.Rows) ([]*candles.Bar, error) {
+
+	bars := make([]*candles.Bar, 0)
+
+	for rows.Next() {
+		var bar candles.Bar
+		err := rows.Scan(&bar.Symbol, &bar.Interval, &bar.Start, &bar.Open, &bar.High, &bar.Low, &bar.Close,
+			&bar.Volume, &bar.Trades, &bar.OpenTime, &bar.CloseTime)
+		if err != nil {
+			return nil, err
+		}
+		bars = append(bars, &bar)
+	}
+
+	return bars, rows.Err()
+}
+
+func scanTrades(rows *sql.Rows) ([]*candles.Trade, error) {
+
+	trades := make([]*candles.Trade, 0)
+
+	for rows.Next() {
+		var trade candles.Trade
+		err := rows.Scan(&trade.ID, &trade.Symbol, &trade.Price, &trade.Quantity, &trade.Occured)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, &trade)
+	}
+
+	return trades, rows.Err()
+}
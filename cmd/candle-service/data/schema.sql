@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS bar (
+    symbol     varchar(20)      NOT NULL,
+    interval   varchar(5)       NOT NULL,
+    start      timestamp        NOT NULL,
+    open       double precision NOT NULL,
+    high       double precision NOT NULL,
+    low        double precision NOT NULL,
+    close      double precision NOT NULL,
+    volume     bigint           NOT NULL,
+    trades     bigint           NOT NULL,
+    open_time  timestamp        NOT NULL,
+    close_time timestamp        NOT NULL,
+    PRIMARY KEY (symbol, interval, start)
+);
+
+CREATE TABLE IF NOT EXISTS bar_trade (
+    id       varchar(36)      NOT NULL,
+    symbol   varchar(20)      NOT NULL,
+    price    double precision NOT NULL,
+    quantity bigint           NOT NULL,
+    occured  timestamp        NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS bar_trade_symbol_occured ON bar_trade (symbol, occured);
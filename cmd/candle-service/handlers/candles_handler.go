@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/tradsim/tradsim-go/cmd/candle-service/candles"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// Paging of the bars
+const (
+	DefaultBarsLimit = 500
+	MaxBarsLimit     = 5000
+)
+
+// BarResponse returns a OHLCV bar
+type BarResponse struct {
+	Start  time.Time `json:"start"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume uint      `json:"volume"`
+	Trades uint      `json:"trades"`
+}
+
+// BarsResponse returns the bars of a symbol and interval
+type BarsResponse struct {
+	Symbol   string        `json:"symbol"`
+	Interval string        `json:"interval"`
+	Bars     []BarResponse `json:"bars"`
+}
+
+// CandlesHandler handles bar requests
+type CandlesHandler struct {
+	store candles.Store
+}
+
+// NewCandlesHandler creates a new candles handler
+func NewCandlesHandler(store candles.Store) *CandlesHandler {
+	return &CandlesHandler{store}
+}
+
+// GetBarsHandle is the handler for getting the bars of a symbol.
+// The query parameters are interval (default 1m), from and to (RFC 3339) and limit, which keeps the most recent bars.
+func (ch *CandlesHandler) GetBarsHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	symbol := strings.ToUpper(p.ByName("symbol"))
+	values := r.URL.Query()
+
+	name := values.Get("interval")
+	if name == "" {
+		name = candles.OneMinute.Name
+	}
+
+	interval, err := candles.ParseInterval(name)
+	if err != nil {
+		log.Printf("GetBarsHandle: %s", err)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	from, to, limit, err := getRange(values.Get("from"), values.Get("to"), values.Get("limit"))
+	if err != nil {
+		log.Printf("GetBarsHandle: Invalid query! %s", err)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	bars, err := ch.store.Bars(symbol, interval.Name, from, to)
+	if err != nil {
+		log.Printf("GetBarsHandle: Failed to get bars! %s", err)
+		common_http.WriteError(w, r, http.StatusInternalServerError, common_http.InternalErrorCode, "Failed to get bars")
+		return
+	}
+
+	if len(bars) > limit {
+		bars = bars[len(bars)-limit:]
+	}
+
+	response := BarsResponse{symbol, interval.Name, make([]BarResponse, 0, len(bars))}
+	for _, bar := range bars {
+		response.Bars = append(response.Bars, BarResponse{bar.Start, bar.Open, bar.High, bar.Low, bar.Close, bar.Volume, bar.Trades})
+	}
+
+	encoded, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetBarsHandle: Returned %d %s bars of %s", len(bars), interval, symbol)
+}
+
+func getRange(fromValue string, toValue string, limitValue string) (time.Time, time.Time, int, error) {
+
+	var from, to time.Time
+	limit := DefaultBarsLimit
+	var err error
+
+	if fromValue != "" {
+		from, err = time.Parse(time.RFC3339, fromValue)
+		if err != nil {
+			return from, to, limit, err
+		}
+	}
+
+	if toValue != "" {
+		to, err = time.Parse(time.RFC3339, toValue)
+		if err != nil {
+			return from, to, limit, err
+		}
+	}
+
+	if limitValue != "" {
+		limit, err = strconv.Atoi(limitValue)
+		if err != nil {
+			return from, to, limit, err
+		}
+		if limit <= 0 || limit > MaxBarsLimit {
+			return from, to, limit, fmt.Errorf("Limit %d is not between 1 and %d", limit, MaxBarsLimit)
+		}
+	}
+
+	return from, to, limit, nil
+}
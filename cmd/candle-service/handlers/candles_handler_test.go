@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/candle-service/candles"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func getBarsResponse(store candles.Store, uri string) *httptest.ResponseRecorder {
+
+	handler := NewCandlesHandler(store)
+
+	request, _ := http.NewRequest(http.MethodGet, uri, nil)
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/candles/:symbol", common_http.DefaultGETValidationMiddleware(handler.GetBarsHandle))
+	router.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestGetBarsHandle(t *testing.T) {
+
+	require := require.New(t)
+
+	store := candles.NewMemoryStore()
+	start := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		store.Save(candles.NewBar("TT", candles.FiveMinutes, 2.0, uint(i+1), start.Add(time.Duration(i)*5*time.Minute)))
+	}
+
+	response := getBarsResponse(store, "/candles/tt?interval=5m&from=2016-08-13T17:35:00Z&limit=1")
+	require.Equal(http.StatusOK, response.Code)
+
+	var bars BarsResponse
+	err := json.Unmarshal(response.Body.Bytes(), &bars)
+	require.Nil(err)
+	require.Equal("TT", bars.Symbol)
+	require.Equal("5m", bars.Interval)
+	require.Len(bars.Bars, 1)
+	require.Equal(uint(3), bars.Bars[0].Volume)
+	require.Equal(time.Date(2016, 8, 13, 17, 40, 0, 0, time.UTC), bars.Bars[0].Start)
+}
+
+func TestGetBarsHandleBadRequest(t *testing.T) {
+
+	require := require.New(t)
+
+	store := candles.NewMemoryStore()
+
+	require.Equal(http.StatusBadRequest, getBarsResponse(store, "/candles/TT?interval=2m").Code)
+	require.Equal(http.StatusBadRequest, getBarsResponse(store, "/candles/TT?from=today").Code)
+	require.Equal(http.StatusBadRequest, getBarsResponse(store, "/candles/TT?limit=-1").Code)
+}
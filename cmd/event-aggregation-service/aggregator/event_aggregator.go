@@ -52,6 +52,16 @@ func (ea StorageEventAggregator) Aggregate(evs []incmodel.Event) (models.Order,
 			if err != nil {
 				return models.Order{}, err
 			}
+		case string(events.OrderTradeBustedType):
+			err := aggregateTradeBusted(&or, ev)
+			if err != nil {
+				return models.Order{}, err
+			}
+		case string(events.OrderRejectedType):
+			err := aggregateRejected(&or, ev)
+			if err != nil {
+				return models.Order{}, err
+			}
 		default:
 			return models.Order{}, fmt.Errorf("Event type not supported %s", ev.EventType)
 		}
@@ -77,6 +87,7 @@ func aggregateAccepted(o *models.Order, ev incmodel.Event) error {
 	}
 
 	*o = *models.NewOrder(orderID, accepted.Symbol, accepted.Price, accepted.Quantity, dir, commonmodels.Pending, accepted.Occured)
+	o.Account = accepted.Account
 
 	log.Print("Accepted aggregation succeeded")
 	return nil
@@ -107,7 +118,30 @@ func aggregateTraded(o *models.Order, ev incmodel.Event) error {
 	if !ok {
 		return fmt.Errorf("type assertion to %s failed", ev.EventType)
 	}
-	o.Trade(traded.Price, traded.Quantity, traded.Occured)
+	o.Trade(traded.TradeID, traded.Price, traded.Quantity, traded.Occured)
 	log.Print("Traded aggregation succeeded")
 	return nil
 }
+
+func aggregateTradeBusted(o *models.Order, ev incmodel.Event) error {
+	busted, ok := ev.Payload.(events.OrderTradeBusted)
+	if !ok {
+		return fmt.Errorf("type assertion to %s failed", ev.EventType)
+	}
+	o.Bust(busted.TradeID, busted.Quantity, busted.Occured)
+	log.Print("Trade busted aggregation succeeded")
+	return nil
+}
+
+func aggregateRejected(o *models.Order, ev incmodel.Event) error {
+	rejected, ok := ev.Payload.(events.OrderRejected)
+	if !ok {
+		return fmt.Errorf("type assertion to %s failed", ev.EventType)
+	}
+	if o.ID == uuid.Nil {
+		o.ID = ev.SourceID
+	}
+	o.Reject(rejected.Reason.IsFinal(), rejected.Occured)
+	log.Print("Rejected aggregation succeeded")
+	return nil
+}
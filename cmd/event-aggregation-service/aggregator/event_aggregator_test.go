@@ -15,10 +15,10 @@ func TestAggregationSuccess(t *testing.T) {
 
 	require := require.New(t)
 	orderID, err := uuid.FromString("d1de4242-6620-4030-b2a7-4a701631c3ba")
-	accepted := events.NewOrderAccepted(orderID.String(), time.Now().UTC(), "TT", 1.99, 10, models.Buy, 1)
-	traded := events.NewOrderTraded(orderID.String(), time.Now().UTC(), 1.98, 10, 1)
-	amended := events.NewOrderAmended(orderID.String(), 20, time.Now().UTC(), 1)
-	cancelled := events.NewOrderCancelled(orderID.String(), time.Now().UTC(), 1)
+	accepted := events.NewOrderAccepted(orderID.String(), "ACC1", time.Now().UTC(), "TT", 1.99, 10, models.Buy, 1)
+	traded := events.NewOrderTraded(orderID.String(), "ACC1", time.Now().UTC(), uuid.NewV4().String(), "TT", 1.98, 10, 1)
+	amended := events.NewOrderAmended(orderID.String(), "ACC1", 20, time.Now().UTC(), 1)
+	cancelled := events.NewOrderCancelled(orderID.String(), "ACC1", time.Now().UTC(), 1)
 
 	evs := []incmodel.Event{*incmodel.NewEvent(orderID, time.Now().UTC(), *accepted, string(accepted.EventType), 1),
 		*incmodel.NewEvent(orderID, time.Now().UTC(), *traded, string(traded.EventType), 1),
@@ -41,11 +41,34 @@ func TestAggregationSuccess(t *testing.T) {
 	require.Len(o.Trades, 1)
 }
 
+func TestAggregationTradeBustedSuccess(t *testing.T) {
+
+	require := require.New(t)
+	orderID := uuid.NewV4()
+	tradeID := uuid.NewV4().String()
+	accepted := events.NewOrderAccepted(orderID.String(), "ACC1", time.Now().UTC(), "TT", 1.99, 10, models.Buy, 1)
+	traded := events.NewOrderTraded(orderID.String(), "ACC1", time.Now().UTC(), tradeID, "TT", 1.99, 4, 1)
+	busted := events.NewOrderTradeBusted(orderID.String(), "ACC1", time.Now().UTC(), tradeID, "TT", 1.99, 4, 1)
+
+	evs := []incmodel.Event{*incmodel.NewEvent(orderID, time.Now().UTC(), *accepted, string(accepted.EventType), 1),
+		*incmodel.NewEvent(orderID, time.Now().UTC(), *traded, string(traded.EventType), 1),
+		*incmodel.NewEvent(orderID, time.Now().UTC(), *busted, string(busted.EventType), 1)}
+
+	o, err := NewEventAggregator().Aggregate(evs)
+
+	require.Nil(err)
+	require.Equal(models.Pending, o.Status)
+	require.Equal(uint(6), o.Quantity)
+	require.Equal(uint(0), o.Traded)
+	require.Empty(o.Trades)
+	require.Equal(string(events.OrderTradeBustedType), o.Logs[2].Action)
+}
+
 func TestAggregationInvalidEventSuccess(t *testing.T) {
 
 	require := require.New(t)
 	orderID, err := uuid.FromString("d1de4242-6620-4030-b2a7-4a701631c3ba")
-	stored := events.NewOrderEventStored(orderID.String(), time.Now().UTC(), 1)
+	stored := events.NewOrderEventStored(orderID.String(), "ACC1", time.Now().UTC(), 1)
 
 	evs := []incmodel.Event{*incmodel.NewEvent(orderID, time.Now().UTC(), *stored, string(stored.EventType), 1)}
 
@@ -55,3 +78,42 @@ func TestAggregationInvalidEventSuccess(t *testing.T) {
 
 	require.NotNil(err)
 }
+
+func TestAggregationRejectedWithoutAcceptedSuccess(t *testing.T) {
+
+	require := require.New(t)
+	orderID, err := uuid.FromString("d1de4242-6620-4030-b2a7-4a701631c3ba")
+	rejected := events.NewOrderRejected(orderID.String(), "ACC1", time.Now().UTC(), events.InvalidOrderReason, "Not mapped XXX", 1)
+
+	evs := []incmodel.Event{*incmodel.NewEvent(orderID, time.Now().UTC(), *rejected, string(rejected.EventType), 1)}
+
+	ag := NewEventAggregator()
+
+	o, err := ag.Aggregate(evs)
+
+	require.Nil(err)
+	require.Equal(orderID, o.ID)
+	require.Equal(models.Rejected, o.Status)
+	require.Len(o.Logs, 1)
+	require.Equal(string(events.OrderRejectedType), o.Logs[0].Action)
+}
+
+func TestAggregationAmendRejectedSuccess(t *testing.T) {
+
+	require := require.New(t)
+	orderID, err := uuid.FromString("d1de4242-6620-4030-b2a7-4a701631c3ba")
+	accepted := events.NewOrderAccepted(orderID.String(), "ACC1", time.Now().UTC(), "TT", 1.99, 10, models.Buy, 1)
+	rejected := events.NewOrderRejected(orderID.String(), "ACC1", time.Now().UTC(), events.AmendFailedReason, "Amend quantity less or equal than orders", 1)
+
+	evs := []incmodel.Event{*incmodel.NewEvent(orderID, time.Now().UTC(), *accepted, string(accepted.EventType), 1),
+		*incmodel.NewEvent(orderID, time.Now().UTC(), *rejected, string(rejected.EventType), 1)}
+
+	ag := NewEventAggregator()
+
+	o, err := ag.Aggregate(evs)
+
+	require.Nil(err)
+	require.Equal(models.Pending, o.Status)
+	require.Len(o.Logs, 2)
+	require.Equal(string(events.OrderRejectedType), o.Logs[1].Action)
+}
@@ -1,6 +1,7 @@
 package aggregator
 
 import (
+	"sort"
 	"time"
 
 	"github.com/tradsim/tradsim-go/cmd/event-aggregation-service/models"
@@ -9,7 +10,7 @@ import (
 
 // OrderAggregator interface
 type OrderAggregator interface {
-	Aggregate(s string, orders []models.Order) models.Position
+	Aggregate(account string, s string, orders []models.Order) models.Position
 }
 
 // OrderAggregatorImpl aggregates order into a position
@@ -21,19 +22,29 @@ func NewOrderAggregator() *OrderAggregatorImpl {
 	return &OrderAggregatorImpl{}
 }
 
-// Aggregate order into a position
-func (oa OrderAggregatorImpl) Aggregate(s string, orders []models.Order) models.Position {
-	q := 0
+type fill struct {
+	quantity int
+	price    float64
+	occured  time.Time
+}
+
+// Aggregate the orders of a account into a position.
+// The price of the position is the average cost, the realized PnL is cumulative and the daily PnL covers the day of the last update.
+func (oa OrderAggregatorImpl) Aggregate(account string, s string, orders []models.Order) models.Position {
 	var t time.Time
+	var fills []fill
 
 	for _, o := range orders {
-		if o.Symbol != s {
+		if o.Symbol != s || o.Account != account {
 			continue
 		}
-		if o.Direction == commonmodels.Buy {
-			q += int(o.Traded)
-		} else {
-			q -= int(o.Traded)
+
+		for _, tr := range o.Trades {
+			if o.Direction == commonmodels.Buy {
+				fills = append(fills, fill{int(tr.Quantity), tr.Price, tr.Occured})
+			} else {
+				fills = append(fills, fill{-int(tr.Quantity), tr.Price, tr.Occured})
+			}
 		}
 
 		if t.IsZero() {
@@ -45,5 +56,24 @@ func (oa OrderAggregatorImpl) Aggregate(s string, orders []models.Order) models.
 		}
 	}
 
-	return models.Position{0, s, q, t}
+	sort.Stable(byOccured(fills))
+
+	position := commonmodels.NewAccountPosition(account, s, 0, 0.0, 0.0, 0.0, time.Time{})
+
+	for _, f := range fills {
+		position.Fill(f.quantity, f.price, f.occured)
+	}
+
+	if !commonmodels.SameDay(position.Updated, t) {
+		position.DailyPnL = 0.0
+	}
+
+	return models.Position{Account: account, Symbol: s, Quantity: position.Quantity, Price: position.Price,
+		RealizedPnL: position.RealizedPnL, DailyPnL: position.DailyPnL, Updated: t}
 }
+
+type byOccured []fill
+
+func (f byOccured) Len() int           { return len(f) }
+func (f byOccured) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f byOccured) Less(i, j int) bool { return f[i].occured.Before(f[j].occured) }
@@ -18,18 +18,84 @@ func TestOrderAggregator(t *testing.T) {
 	updated := time.Now().UTC()
 
 	order1 := models.NewOrder(orderID, "TT", 1.99, 10, commonmodel.Buy, commonmodel.Pending, updated.Add(-1*time.Hour))
-	order1.Trade(1.95, uint(10), updated.Add(-1*time.Hour))
+	order1.Trade("T1", 1.95, uint(10), updated.Add(-1*time.Hour))
 	order2 := models.NewOrder(orderID, "TT", 1.99, 10, commonmodel.Sell, commonmodel.Pending, updated)
-	order2.Trade(2.0, uint(5), updated)
+	order2.Trade("T2", 2.0, uint(5), updated)
 	order3 := models.NewOrder(orderID, "ETE", 1.99, 5, commonmodel.Sell, commonmodel.Pending, updated)
 
 	orders := []models.Order{*order1, *order2, *order3}
 
 	ag := NewOrderAggregator()
 
-	pos := ag.Aggregate("TT", orders)
+	pos := ag.Aggregate("", "TT", orders)
 
 	require.Equal("TT", pos.Symbol)
 	require.Equal(updated, pos.Updated)
 	require.Equal(5, pos.Quantity)
+	require.Equal(1.95, pos.Price)
+	require.InDelta(0.25, pos.RealizedPnL, 0.000001)
+}
+
+func TestOrderAggregatorFiltersAccount(t *testing.T) {
+
+	require := require.New(t)
+
+	updated := time.Now().UTC()
+
+	order1 := models.NewOrder(uuid.NewV4(), "TT", 2.0, 10, commonmodel.Buy, commonmodel.Pending, updated)
+	order1.Account = "ACC1"
+	order1.Trade("T3", 2.0, uint(10), updated)
+	order2 := models.NewOrder(uuid.NewV4(), "TT", 2.0, 10, commonmodel.Buy, commonmodel.Pending, updated)
+	order2.Account = "ACC2"
+	order2.Trade("T4", 2.0, uint(10), updated)
+
+	pos := NewOrderAggregator().Aggregate("ACC1", "TT", []models.Order{*order1, *order2})
+
+	require.Equal("ACC1", pos.Account)
+	require.Equal(10, pos.Quantity)
+}
+
+func TestOrderAggregatorShortFlipAndDailyPnL(t *testing.T) {
+
+	require := require.New(t)
+
+	updated := time.Now().UTC()
+
+	order1 := models.NewOrder(uuid.NewV4(), "TT", 2.0, 10, commonmodel.Sell, commonmodel.Pending, updated)
+	order1.Trade("T5", 2.0, uint(10), updated.Add(-48*time.Hour))
+	order2 := models.NewOrder(uuid.NewV4(), "TT", 3.0, 15, commonmodel.Buy, commonmodel.Pending, updated)
+	order2.Trade("T6", 3.0, uint(15), updated)
+
+	pos := NewOrderAggregator().Aggregate("", "TT", []models.Order{*order1, *order2})
+
+	require.Equal(5, pos.Quantity)
+	require.Equal(3.0, pos.Price)
+	require.Equal(-10.0, pos.RealizedPnL)
+}
+
+func TestOrderAggregatorDailyPnL(t *testing.T) {
+
+	require := require.New(t)
+
+	updated := time.Now().UTC()
+
+	order1 := models.NewOrder(uuid.NewV4(), "TT", 2.0, 20, commonmodel.Buy, commonmodel.Pending, updated)
+	order1.Trade("T7", 2.0, uint(20), updated.Add(-48*time.Hour))
+	order2 := models.NewOrder(uuid.NewV4(), "TT", 1.0, 10, commonmodel.Sell, commonmodel.Pending, updated)
+	order2.Trade("T8", 1.0, uint(10), updated.Add(-48*time.Hour))
+	order3 := models.NewOrder(uuid.NewV4(), "TT", 2.5, 4, commonmodel.Sell, commonmodel.Pending, updated)
+	order3.Trade("T9", 2.5, uint(4), updated)
+
+	pos := NewOrderAggregator().Aggregate("", "TT", []models.Order{*order1, *order2, *order3})
+
+	require.Equal(6, pos.Quantity)
+	require.InDelta(-8.0, pos.RealizedPnL, 0.000001)
+	require.InDelta(2.0, pos.DailyPnL, 0.000001)
+
+	order4 := models.NewOrder(uuid.NewV4(), "TT", 3.0, 10, commonmodel.Sell, commonmodel.Pending, updated.Add(24*time.Hour))
+
+	pos = NewOrderAggregator().Aggregate("", "TT", []models.Order{*order1, *order2, *order3, *order4})
+
+	require.InDelta(-8.0, pos.RealizedPnL, 0.000001)
+	require.Equal(0.0, pos.DailyPnL)
 }
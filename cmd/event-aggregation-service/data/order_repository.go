@@ -6,64 +6,110 @@ import (
 
 	"github.com/satori/go.uuid"
 	"github.com/tradsim/tradsim-go/cmd/event-aggregation-service/models"
-	commonmodels "github.com/tradsim/tradsim-go/models"
 )
 
 // OrderRepository interface
 type OrderRepository interface {
-	GetOrders() ([]models.Order, error)
+	GetOrders(account string, symbol string) ([]models.Order, error)
+	SaveOrder(order models.Order) error
 	GetPositions() ([]models.Position, error)
+	SavePosition(position models.Position) error
 }
 
-// OrderRepositoryImpl order repository
+// OrderRepositoryImpl order repository, see schema.sql
 type OrderRepositoryImpl struct {
-	db           *sql.DB
-	orderStmt    *sql.Stmt
-	logStmt      *sql.Stmt
-	tradeStmt    *sql.Stmt
-	positionStmt *sql.Stmt
+	db               *sql.DB
+	orderStmt        *sql.Stmt
+	logStmt          *sql.Stmt
+	tradeStmt        *sql.Stmt
+	positionStmt     *sql.Stmt
+	saveOrderStmt    *sql.Stmt
+	deleteLogsStmt   *sql.Stmt
+	deleteTradesStmt *sql.Stmt
+	insertLogStmt    *sql.Stmt
+	insertTradeStmt  *sql.Stmt
+	savePositionStmt *sql.Stmt
 }
 
+const (
+	orderColumns    = `id, account, symbol, price, quantity, traded, traded_price, direction, status, created, updated`
+	positionColumns = `account, symbol, quantity, price, realized_pnl, daily_pnl, updated`
+	accountOrders   = `SELECT id FROM public."order" WHERE account = $1 AND symbol = $2`
+)
+
 // NewOrderRepository creates a new order repository
 func NewOrderRepository(db *sql.DB) (*OrderRepositoryImpl, error) {
 
-	orderStmt, err := db.Prepare(`SELECT "order".* FROM public."order"`)
+	orderStmt, err := db.Prepare(`SELECT ` + orderColumns + ` FROM public."order" WHERE account = $1 AND symbol = $2`)
+	if err != nil {
+		return nil, err
+	}
+
+	logStmt, err := db.Prepare(`SELECT id, order_id, action, occured FROM order_log WHERE order_id IN (` + accountOrders + `) ORDER BY id`)
 	if err != nil {
 		return nil, err
 	}
 
-	logStmt, err := db.Prepare(`SELECT * FROM order_log`)
+	tradeStmt, err := db.Prepare(`SELECT id, order_id, trade_id, price, quantity, occured FROM trade WHERE order_id IN (` + accountOrders + `) ORDER BY id`)
 	if err != nil {
 		return nil, err
 	}
 
-	tradeStmt, err := db.Prepare(`SELECT * FROM trade`)
+	positionStmt, err := db.Prepare(`SELECT id, ` + positionColumns + ` FROM position`)
 	if err != nil {
 		return nil, err
 	}
 
-	positionStmt, err := db.Prepare(`SELECT * FROM position`)
+	saveOrderStmt, err := db.Prepare(`INSERT INTO public."order" (` + orderColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET account = $2, symbol = $3, price = $4, quantity = $5, traded = $6, traded_price = $7, direction = $8, status = $9, created = $10, updated = $11`)
 	if err != nil {
 		return nil, err
 	}
 
-	return &OrderRepositoryImpl{db, orderStmt, logStmt, tradeStmt, positionStmt}, nil
+	deleteLogsStmt, err := db.Prepare(`DELETE FROM order_log WHERE order_id = $1`)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteTradesStmt, err := db.Prepare(`DELETE FROM trade WHERE order_id = $1`)
+	if err != nil {
+		return nil, err
+	}
+
+	insertLogStmt, err := db.Prepare(`INSERT INTO order_log (order_id, action, occured) VALUES ($1, $2, $3)`)
+	if err != nil {
+		return nil, err
+	}
+
+	insertTradeStmt, err := db.Prepare(`INSERT INTO trade (order_id, trade_id, price, quantity, occured) VALUES ($1, $2, $3, $4, $5)`)
+	if err != nil {
+		return nil, err
+	}
+
+	savePositionStmt, err := db.Prepare(`INSERT INTO position (` + positionColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (account, symbol) DO UPDATE SET quantity = $3, price = $4, realized_pnl = $5, daily_pnl = $6, updated = $7`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderRepositoryImpl{db, orderStmt, logStmt, tradeStmt, positionStmt, saveOrderStmt, deleteLogsStmt, deleteTradesStmt,
+		insertLogStmt, insertTradeStmt, savePositionStmt}, nil
 }
 
-// GetOrders returns all orders from storage
-func (or *OrderRepositoryImpl) GetOrders() ([]models.Order, error) {
+// GetOrders returns the orders of a account in a symbol with their logs and trades
+func (or *OrderRepositoryImpl) GetOrders(account string, symbol string) ([]models.Order, error) {
 
-	orders, err := or.getOrders()
+	orders, err := or.getOrders(account, symbol)
 	if err != nil {
 		return nil, err
 	}
 
-	logs, err := or.getOrderLogs()
+	logs, err := or.getOrderLogs(account, symbol)
 	if err != nil {
 		return nil, err
 	}
 
-	trades, err := or.getTrades()
+	trades, err := or.getTrades(account, symbol)
 	if err != nil {
 		return nil, err
 	}
@@ -88,8 +134,49 @@ func (or *OrderRepositoryImpl) GetOrders() ([]models.Order, error) {
 	return orders, nil
 }
 
-func (or *OrderRepositoryImpl) getOrders() ([]models.Order, error) {
-	rows, err := or.orderStmt.Query()
+// SaveOrder inserts or updates the order and replaces its logs and trades
+func (or *OrderRepositoryImpl) SaveOrder(order models.Order) error {
+
+	tx, err := or.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Stmt(or.saveOrderStmt).Exec(order.ID, order.Account, order.Symbol, order.Price, order.Quantity, order.Traded,
+		order.TradedPrice, order.Direction, order.Status, order.Created.UTC(), order.Updated.UTC())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Stmt(or.deleteLogsStmt).Exec(order.ID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Stmt(or.deleteTradesStmt).Exec(order.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, log := range order.Logs {
+		_, err = tx.Stmt(or.insertLogStmt).Exec(order.ID, log.Action, log.Occured.UTC())
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, trade := range order.Trades {
+		_, err = tx.Stmt(or.insertTradeStmt).Exec(order.ID, trade.TradeID, trade.Price, trade.Quantity, trade.Occured.UTC())
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (or *OrderRepositoryImpl) getOrders(account string, symbol string) ([]models.Order, error) {
+	rows, err := or.orderStmt.Query(account, symbol)
 	if err != nil {
 		return nil, err
 	}
@@ -97,21 +184,18 @@ func (or *OrderRepositoryImpl) getOrders() ([]models.Order, error) {
 
 	var orders []models.Order
 
-	var id uuid.UUID
-	var symbol string
-	var price float64
-	var quantity uint
-	var direction commonmodels.TradeDirection
-	var status commonmodels.OrderStatus
-	var created time.Time
-
 	for rows.Next() {
-		err := rows.Scan(&id)
+		var order models.Order
+
+		err := rows.Scan(&order.ID, &order.Account, &order.Symbol, &order.Price, &order.Quantity, &order.Traded, &order.TradedPrice,
+			&order.Direction, &order.Status, &order.Created, &order.Updated)
 		if err != nil {
 			return nil, err
 		}
 
-		orders = append(orders, *models.NewOrder(id, symbol, price, quantity, direction, status, created))
+		order.Trades = make([]models.Trade, 0)
+		order.Logs = make([]models.OrderLog, 0)
+		orders = append(orders, order)
 	}
 	err = rows.Err()
 	if err != nil {
@@ -121,8 +205,8 @@ func (or *OrderRepositoryImpl) getOrders() ([]models.Order, error) {
 	return orders, nil
 }
 
-func (or *OrderRepositoryImpl) getOrderLogs() ([]models.OrderLog, error) {
-	rows, err := or.logStmt.Query()
+func (or *OrderRepositoryImpl) getOrderLogs(account string, symbol string) ([]models.OrderLog, error) {
+	rows, err := or.logStmt.Query(account, symbol)
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +225,7 @@ func (or *OrderRepositoryImpl) getOrderLogs() ([]models.OrderLog, error) {
 			return nil, err
 		}
 
-		logs = append(logs, models.OrderLog{id, orderID, action, occured})
+		logs = append(logs, models.OrderLog{ID: id, OrderID: orderID, Action: action, Occured: occured})
 	}
 	err = rows.Err()
 	if err != nil {
@@ -151,8 +235,8 @@ func (or *OrderRepositoryImpl) getOrderLogs() ([]models.OrderLog, error) {
 	return logs, nil
 }
 
-func (or *OrderRepositoryImpl) getTrades() ([]models.Trade, error) {
-	rows, err := or.tradeStmt.Query()
+func (or *OrderRepositoryImpl) getTrades(account string, symbol string) ([]models.Trade, error) {
+	rows, err := or.tradeStmt.Query(account, symbol)
 	if err != nil {
 		return nil, err
 	}
@@ -163,15 +247,16 @@ func (or *OrderRepositoryImpl) getTrades() ([]models.Trade, error) {
 	var quantity uint
 	var occured time.Time
 	var orderID uuid.UUID
+	var tradeID string
 	var id int64
 
 	for rows.Next() {
-		err := rows.Scan(&id, &orderID, &price, &quantity, &occured)
+		err := rows.Scan(&id, &orderID, &tradeID, &price, &quantity, &occured)
 		if err != nil {
 			return nil, err
 		}
 
-		trades = append(trades, models.Trade{id, orderID, price, quantity, occured})
+		trades = append(trades, models.Trade{ID: id, OrderID: orderID, TradeID: tradeID, Price: price, Quantity: quantity, Occured: occured})
 	}
 	err = rows.Err()
 	if err != nil {
@@ -191,18 +276,16 @@ func (or *OrderRepositoryImpl) GetPositions() ([]models.Position, error) {
 	defer rows.Close()
 
 	var positions []models.Position
-	var id int64
-	var symbol string
-	var quantity int
-	var updated time.Time
 
 	for rows.Next() {
-		err := rows.Scan(&id, &symbol, &quantity, &updated)
+		var position models.Position
+
+		err := rows.Scan(&position.ID, &position.Account, &position.Symbol, &position.Quantity, &position.Price, &position.RealizedPnL, &position.DailyPnL, &position.Updated)
 		if err != nil {
 			return nil, err
 		}
 
-		positions = append(positions, models.Position{id, symbol, quantity, updated})
+		positions = append(positions, position)
 	}
 	err = rows.Err()
 	if err != nil {
@@ -211,3 +294,10 @@ func (or *OrderRepositoryImpl) GetPositions() ([]models.Position, error) {
 
 	return positions, nil
 }
+
+// SavePosition inserts or updates the position of the account in the symbol
+func (or *OrderRepositoryImpl) SavePosition(position models.Position) error {
+
+	_, err := or.savePositionStmt.Exec(position.Account, position.Symbol, position.Quantity, position.Price, position.RealizedPnL, position.DailyPnL, position.Updated.UTC())
+	return err
+}
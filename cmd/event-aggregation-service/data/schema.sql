@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS "order" (
+    id           uuid             NOT NULL,
+    account      varchar(64)      NOT NULL DEFAULT '',
+    symbol       varchar(20)      NOT NULL,
+    price        double precision NOT NULL,
+    quantity     bigint           NOT NULL,
+    traded       bigint           NOT NULL,
+    traded_price double precision NOT NULL,
+    direction    smallint         NOT NULL,
+    status       smallint         NOT NULL,
+    created      timestamp        NOT NULL,
+    updated      timestamp        NOT NULL,
+    PRIMARY KEY (id)
+);
+
+-- order tables created before the accounts lack the account
+ALTER TABLE "order" ADD COLUMN IF NOT EXISTS account varchar(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS order_account_symbol ON "order" (account, symbol);
+
+CREATE TABLE IF NOT EXISTS order_log (
+    id       bigserial   NOT NULL,
+    order_id uuid        NOT NULL REFERENCES "order" (id),
+    action   varchar(32) NOT NULL,
+    occured  timestamp   NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS order_log_order_id ON order_log (order_id);
+
+CREATE TABLE IF NOT EXISTS trade (
+    id       bigserial        NOT NULL,
+    order_id uuid             NOT NULL REFERENCES "order" (id),
+    price    double precision NOT NULL,
+    quantity bigint           NOT NULL,
+    occured  timestamp        NOT NULL,
+    PRIMARY KEY (id)
+);
+
+ALTER TABLE trade ADD COLUMN IF NOT EXISTS trade_id varchar(36) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS trade_order_id ON trade (order_id);
+
+CREATE TABLE IF NOT EXISTS position (
+    id           bigserial        NOT NULL,
+    account      varchar(64)      NOT NULL,
+    symbol       varchar(20)      NOT NULL,
+    quantity     bigint           NOT NULL,
+    price        double precision NOT NULL,
+    realized_pnl double precision NOT NULL,
+    daily_pnl    double precision NOT NULL,
+    updated      timestamp        NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (account, symbol)
+);
@@ -11,6 +11,7 @@ import (
 // Order defines the order state
 type Order struct {
 	ID          uuid.UUID
+	Account     string
 	Symbol      string
 	Price       float64
 	Quantity    uint
@@ -26,21 +27,41 @@ type Order struct {
 
 // NewOrder creates a new order
 func NewOrder(id uuid.UUID, symbol string, price float64, quantity uint, direction models.TradeDirection, status models.OrderStatus, created time.Time) *Order {
-	o := Order{id, symbol, price, quantity, uint(0), 0.0, direction, status, created, created, make([]Trade, 0), make([]OrderLog, 0)}
+	o := Order{id, "", symbol, price, quantity, uint(0), 0.0, direction, status, created, created, make([]Trade, 0), make([]OrderLog, 0)}
 	o.appendLog(string(events.OrderAcceptedType), created)
 	return &o
 }
 
 // Trade appends trade to order and updates order
-func (o *Order) Trade(p float64, q uint, t time.Time) {
+func (o *Order) Trade(tradeID string, p float64, q uint, t time.Time) {
 	o.Traded += q
-	o.Trades = append(o.Trades, Trade{0, o.ID, p, q, t})
+	o.Trades = append(o.Trades, Trade{0, o.ID, tradeID, p, q, t})
 	o.updateTradedPrice()
 	o.Status = models.ResolveStatus(o.Quantity, o.Traded)
 	o.Updated = t
 	o.appendLog(string(events.OrderTradedType), t)
 }
 
+// Bust removes the trade from the order and updates the order.
+// The busted quantity is removed from the quantity too, so the remaining quantity does not change.
+func (o *Order) Bust(tradeID string, q uint, t time.Time) {
+	for i, trade := range o.Trades {
+		if trade.TradeID != tradeID {
+			continue
+		}
+		o.Quantity -= q
+		o.Traded -= q
+		o.Trades = append(o.Trades[:i], o.Trades[i+1:]...)
+		o.updateTradedPrice()
+		if o.Status.IsTradeable() {
+			o.Status = models.ResolveStatus(o.Quantity, o.Traded)
+		}
+		break
+	}
+	o.Updated = t
+	o.appendLog(string(events.OrderTradeBustedType), t)
+}
+
 // Amend amends the order
 func (o *Order) Amend(q uint, t time.Time) {
 	o.Quantity = q
@@ -56,6 +77,15 @@ func (o *Order) Cancel(t time.Time) {
 	o.appendLog(string(events.OrderCancelledType), t)
 }
 
+// Reject logs a rejection and marks the order rejected when the rejection is final
+func (o *Order) Reject(final bool, t time.Time) {
+	if final {
+		o.Status = models.Rejected
+	}
+	o.Updated = t
+	o.appendLog(string(events.OrderRejectedType), t)
+}
+
 func (o *Order) appendLog(a string, t time.Time) {
 	o.Logs = append(o.Logs, OrderLog{0, o.ID, a, t})
 }
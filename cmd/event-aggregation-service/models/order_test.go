@@ -97,8 +97,8 @@ func TestAppendTrade(t *testing.T) {
 	o := NewOrder(orderID, "TT", 1.99, uint(10), models.Buy, models.Pending, created)
 
 	updated := time.Now().UTC()
-	o.Trade(1.95, 5, time.Now().UTC())
-	o.Trade(1.96, 2, updated)
+	o.Trade("T1", 1.95, 5, time.Now().UTC())
+	o.Trade("T2", 1.96, 2, updated)
 
 	require.Equal(orderID, o.ID)
 	require.Equal("TT", o.Symbol)
@@ -116,3 +116,67 @@ func TestAppendTrade(t *testing.T) {
 	require.Equal(string(events.OrderTradedType), o.Logs[1].Action, "%v", o.Logs)
 	require.Equal(string(events.OrderTradedType), o.Logs[2].Action)
 }
+
+func TestBust(t *testing.T) {
+
+	require := require.New(t)
+	orderID, _ := uuid.FromString("d1de4242-6620-4030-b2a7-4a701631c3ba")
+	created := time.Now().UTC()
+
+	o := NewOrder(orderID, "TT", 1.99, uint(20), models.Buy, models.Pending, created)
+	o.Trade("T1", 1.95, 5, created)
+	o.Trade("T2", 1.96, 5, created)
+	o.Trade("T3", 1.95, 5, created)
+
+	updated := time.Now().UTC()
+	o.Bust("T3", 5, updated)
+
+	require.Equal(uint(15), o.Quantity)
+	require.Equal(uint(10), o.Traded)
+	require.InDelta(1.955, o.TradedPrice, 0.000001)
+	require.Equal(models.PartiallyFilled, o.Status)
+	require.Equal(updated, o.Updated)
+	require.Equal([]Trade{{0, orderID, "T1", 1.95, 5, created}, {0, orderID, "T2", 1.96, 5, created}}, o.Trades)
+	require.Len(o.Logs, 5)
+	require.Equal(string(events.OrderTradeBustedType), o.Logs[4].Action)
+
+	o.Bust("T3", 5, updated)
+	require.Equal(uint(15), o.Quantity)
+	require.Equal(uint(10), o.Traded)
+}
+
+func TestBustKeepsStatusOfFilledOrder(t *testing.T) {
+
+	require := require.New(t)
+	orderID, _ := uuid.FromString("d1de4242-6620-4030-b2a7-4a701631c3ba")
+	created := time.Now().UTC()
+
+	o := NewOrder(orderID, "TT", 1.99, uint(10), models.Buy, models.Pending, created)
+	o.Trade("T1", 1.95, 10, created)
+	o.Bust("T1", 10, created)
+
+	require.Equal(uint(0), o.Quantity)
+	require.Equal(uint(0), o.Traded)
+	require.Equal(models.FullyFilled, o.Status)
+	require.Empty(o.Trades)
+}
+
+func TestReject(t *testing.T) {
+
+	require := require.New(t)
+	orderID, _ := uuid.FromString("d1de4242-6620-4030-b2a7-4a701631c3ba")
+	created := time.Now().UTC()
+	updated := time.Now().UTC()
+	o := NewOrder(orderID, "TT", 1.99, uint(10), models.Buy, models.Pending, created)
+	o.Reject(false, updated)
+
+	require.Equal(models.Pending, o.Status)
+	require.Equal(updated, o.Updated)
+	require.Len(o.Logs, 2)
+	require.Equal(string(events.OrderRejectedType), o.Logs[1].Action)
+
+	o.Reject(true, updated)
+
+	require.Equal(models.Rejected, o.Status)
+	require.Len(o.Logs, 3)
+}
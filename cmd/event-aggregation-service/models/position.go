@@ -2,10 +2,14 @@ package models
 
 import "time"
 
-// Position defines the position
+// Position defines the position of a account in a symbol
 type Position struct {
-	ID       int64
-	Symbol   string
-	Quantity int
-	Updated  time.Time
+	ID          int64
+	Account     string
+	Symbol      string
+	Quantity    int
+	Price       float64
+	RealizedPnL float64
+	DailyPnL    float64
+	Updated     time.Time
 }
@@ -10,6 +10,7 @@ import (
 type Trade struct {
 	ID       int64
 	OrderID  uuid.UUID
+	TradeID  string
 	Price    float64
 	Quantity uint
 	Occured  time.Time
@@ -1,10 +1,15 @@
 package processor
 
 import (
+	"log"
+	"sync"
+
 	"github.com/mantzas/incata"
+	incmodel "github.com/mantzas/incata/model"
 	uuid "github.com/satori/go.uuid"
 	"github.com/tradsim/tradsim-go/cmd/event-aggregation-service/aggregator"
 	"github.com/tradsim/tradsim-go/cmd/event-aggregation-service/data"
+	"github.com/tradsim/tradsim-go/cmd/event-aggregation-service/models"
 	"github.com/tradsim/tradsim-go/events"
 )
 
@@ -19,17 +24,22 @@ type EventProcessor struct {
 	evagg aggregator.EventAggregator
 	oragg aggregator.OrderAggregator
 	repo  data.OrderRepository
+	pub   events.EventPublisher
+	mu    sync.Mutex
 }
 
 // NewEventProcessor creates a new event processor
 func NewEventProcessor(evr incata.Retriever, evagg aggregator.EventAggregator,
-	oragg aggregator.OrderAggregator, repo data.OrderRepository) *EventProcessor {
-	return &EventProcessor{evr, evagg, oragg, repo}
+	oragg aggregator.OrderAggregator, repo data.OrderRepository, pub events.EventPublisher) *EventProcessor {
+	return &EventProcessor{evr: evr, evagg: evagg, oragg: oragg, repo: repo, pub: pub}
 }
 
-// Process the stored event
+// Process the stored event, the events are processed one at a time since the position is read, updated and stored
 func (ep *EventProcessor) Process(event events.OrderEventStored) error {
 
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
 	sourceID, err := uuid.FromString(event.OrderID)
 	if err != nil {
 		return err
@@ -40,19 +50,73 @@ func (ep *EventProcessor) Process(event events.OrderEventStored) error {
 		return err
 	}
 
+	if !isAccepted(events) {
+		log.Printf("Order %s was never accepted, the events are not aggregated", sourceID)
+		return nil
+	}
+
 	or, err := ep.evagg.Aggregate(events)
 	if err != nil {
 		return err
 	}
 
-	orders, err := ep.repo.GetOrders()
+	err = ep.repo.SaveOrder(or)
+	if err != nil {
+		return err
+	}
+
+	orders, err := ep.repo.GetOrders(or.Account, or.Symbol)
 	if err != nil {
 		return err
 	}
 
-	_ = ep.oragg.Aggregate(or.Symbol, orders)
+	pos := ep.oragg.Aggregate(or.Account, or.Symbol, mergeOrder(orders, or))
 
-	// TODO: Store order and position to db
+	err = ep.repo.SavePosition(pos)
+	if err != nil {
+		return err
+	}
+
+	return ep.publishPosition(pos)
+}
+
+// isAccepted returns true when the events contain the acceptance of the order, the rejections of orders never accepted have no order to aggregate
+func isAccepted(evs []incmodel.Event) bool {
+
+	for _, ev := range evs {
+		if ev.EventType == string(events.OrderAcceptedType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergeOrder replaces the stored order with the processed one or appends it if it is not stored
+func mergeOrder(orders []models.Order, or models.Order) []models.Order {
+
+	for i := range orders {
+		if orders[i].ID == or.ID {
+			orders[i] = or
+			return orders
+		}
+	}
+
+	return append(orders, or)
+}
+
+func (ep *EventProcessor) publishPosition(pos models.Position) error {
+
+	ev := events.NewPositionUpdated(pos.Account, pos.Symbol, pos.Quantity, pos.Price, pos.RealizedPnL, pos.DailyPnL, pos.Updated, uint(1))
+	env, err := events.NewOrderEventEnvelope(ev, ev.EventType)
+	if err != nil {
+		return err
+	}
+
+	err = ep.pub.Publish(env)
+	if err != nil {
+		log.Printf("Failed to publish position updated event: %s", ev.String())
+	}
 
-	return nil
+	return err
 }
@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	incmodel "github.com/mantzas/incata/model"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/event-aggregation-service/aggregator"
+	"github.com/tradsim/tradsim-go/cmd/event-aggregation-service/models"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/mocks"
+	commonmodels "github.com/tradsim/tradsim-go/models"
+)
+
+type retriever struct {
+	events map[uuid.UUID][]incmodel.Event
+}
+
+func (r *retriever) Retrieve(sourceID uuid.UUID) ([]incmodel.Event, error) {
+	return r.events[sourceID], nil
+}
+
+type repository struct {
+	orders    []models.Order
+	positions []models.Position
+}
+
+func (r *repository) GetOrders(account string, symbol string) ([]models.Order, error) {
+
+	var orders []models.Order
+	for _, order := range r.orders {
+		if order.Account == account && order.Symbol == symbol {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (r *repository) SaveOrder(order models.Order) error {
+	r.orders = mergeOrder(r.orders, order)
+	return nil
+}
+
+func (r *repository) GetPositions() ([]models.Position, error) {
+	return r.positions, nil
+}
+
+func (r *repository) SavePosition(position models.Position) error {
+	r.positions = append(r.positions, position)
+	return nil
+}
+
+func TestProcess(t *testing.T) {
+
+	require := require.New(t)
+
+	occured := time.Now().UTC()
+	buyID := uuid.NewV4()
+	sellID := uuid.NewV4()
+
+	buy := events.NewOrderAccepted(buyID.String(), "ACC1", occured, "TT", 2.0, 10, commonmodels.Buy, 1)
+	buyTraded := events.NewOrderTraded(buyID.String(), "ACC1", occured, uuid.NewV4().String(), "TT", 2.0, 10, 1)
+	sell := events.NewOrderAccepted(sellID.String(), "ACC1", occured, "TT", 2.5, 10, commonmodels.Sell, 1)
+	sellTraded := events.NewOrderTraded(sellID.String(), "ACC1", occured, uuid.NewV4().String(), "TT", 2.5, 4, 1)
+
+	evr := &retriever{map[uuid.UUID][]incmodel.Event{
+		buyID: {*incmodel.NewEvent(buyID, occured, *buy, string(buy.EventType), 1),
+			*incmodel.NewEvent(buyID, occured, *buyTraded, string(buyTraded.EventType), 1)},
+		sellID: {*incmodel.NewEvent(sellID, occured, *sell, string(sell.EventType), 1),
+			*incmodel.NewEvent(sellID, occured, *sellTraded, string(sellTraded.EventType), 1)},
+	}}
+	repo := &repository{}
+	pub := &mocks.MockPublisher{}
+
+	prc := NewEventProcessor(evr, aggregator.NewEventAggregator(), aggregator.NewOrderAggregator(), repo, pub)
+
+	require.Nil(prc.Process(*events.NewOrderEventStored(buyID.String(), "ACC1", occured, 1)))
+	require.Nil(prc.Process(*events.NewOrderEventStored(sellID.String(), "ACC1", occured, 1)))
+
+	require.Len(repo.orders, 2)
+	require.Equal("ACC1", repo.orders[1].Account)
+	require.Equal("TT", repo.orders[1].Symbol)
+
+	require.Len(repo.positions, 2)
+	require.Equal(10, repo.positions[0].Quantity)
+	require.Equal(6, repo.positions[1].Quantity)
+	require.InDelta(2.0, repo.positions[1].RealizedPnL, 0.000001)
+
+	require.Len(pub.Envelopes, 2)
+	ev, err := pub.Envelopes[1].GetOrderEvent()
+	require.Nil(err)
+	position := ev.(events.PositionUpdated)
+	require.Equal("ACC1", position.Account)
+	require.Equal(6, position.Quantity)
+	require.Equal(2.0, position.Price)
+}
+
+func TestProcessSkipsOrdersNotAccepted(t *testing.T) {
+
+	require := require.New(t)
+
+	occured := time.Now().UTC()
+	orderID := uuid.NewV4()
+
+	rejected := events.NewOrderRejected(orderID.String(), "ACC1", occured, events.LimitExceededReason, "Position limit exceeded", 1)
+
+	evr := &retriever{map[uuid.UUID][]incmodel.Event{
+		orderID: {*incmodel.NewEvent(orderID, occured, *rejected, string(rejected.EventType), 1)},
+	}}
+	repo := &repository{}
+	pub := &mocks.MockPublisher{}
+
+	prc := NewEventProcessor(evr, aggregator.NewEventAggregator(), aggregator.NewOrderAggregator(), repo, pub)
+
+	require.Nil(prc.Process(*events.NewOrderEventStored(orderID.String(), "ACC1", occured, 1)))
+
+	require.Empty(repo.orders)
+	require.Empty(repo.positions)
+	require.Empty(pub.Envelopes)
+}
@@ -100,6 +100,19 @@ func processEnvelope(envelope *events.OrderEventEnvelope) (string, error) {
 		event := untypedEvent.(events.OrderTraded)
 		sourceID, occured, version, err = getOrderEventData(event.OrderEvent)
 		log.Printf("Order traded received: %s", event.String())
+	case events.OrderTradeBustedType:
+		event := untypedEvent.(events.OrderTradeBusted)
+		sourceID, occured, version, err = getOrderEventData(event.OrderEvent)
+		log.Printf("Order trade busted received: %s", event.String())
+	case events.OrderRejectedType:
+		event := untypedEvent.(events.OrderRejected)
+		sourceID, occured, version, err = getOrderEventData(event.OrderEvent)
+		log.Printf("Order rejected received: %s", event.String())
+		if err != nil {
+			// rejections without a order are stored as audit records of a source of their own and are not aggregated
+			log.Printf("Order rejected with invalid order id %s stored as audit record", event.OrderID)
+			return "", appendEvent(uuid.NewV4(), event.Occured, envelope, event.Version)
+		}
 	default:
 		return "", errors.New("invalid order event type received")
 	}
@@ -108,16 +121,21 @@ func processEnvelope(envelope *events.OrderEventEnvelope) (string, error) {
 		return "", err
 	}
 
+	return sourceID.String(), appendEvent(sourceID, occured, envelope, version)
+}
+
+func appendEvent(sourceID uuid.UUID, occured time.Time, envelope *events.OrderEventEnvelope, version uint) error {
+
 	dbEvent := incatamodel.NewEvent(sourceID, occured, envelope.Payload, string(envelope.EventType), int(version))
 
 	appender, err := incata.NewAppender()
 
 	if err != nil {
 		log.Printf("Faile to create a appender! %s", err)
-		return "", err
+		return err
 	}
 
-	return sourceID.String(), appender.Append(*dbEvent)
+	return appender.Append(*dbEvent)
 }
 
 func getOrderEventData(orderEvent events.OrderEvent) (uuid.UUID, time.Time, uint, error) {
@@ -0,0 +1,329 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// RetryPolicy defines how often and how long idempotent calls are retried.
+// The backoff doubles after every attempt up to the max backoff, a Retry-After header of the response takes precedence.
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries idempotent calls three times starting with 100ms
+var DefaultRetryPolicy = RetryPolicy{3, 100 * time.Millisecond, 2 * time.Second}
+
+// Delay returns the backoff before the retry of the attempt, starting from zero
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+
+	delay := time.Duration(float64(p.Backoff) * math.Pow(2, float64(attempt)))
+	if delay > p.MaxBackoff || delay <= 0 {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// OrdersQuery defines the filters and the page of a orders request, empty fields are not sent
+type OrdersQuery struct {
+	Symbol        string
+	Status        string
+	Direction     string
+	Account       string
+	ClientOrderID string
+	After         string
+	Limit         int
+}
+
+func (q OrdersQuery) values() url.Values {
+
+	values := url.Values{}
+	add := func(key string, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+
+	add("symbol", q.Symbol)
+	add("status", q.Status)
+	add("direction", q.Direction)
+	add("account", q.Account)
+	add("client_order_id", q.ClientOrderID)
+	add("after", q.After)
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return values
+}
+
+// TradesQuery defines the page of a trades request, zero fields are not sent
+type TradesQuery struct {
+	After uint64
+	From  time.Time
+	To    time.Time
+	Limit int
+}
+
+func (q TradesQuery) values() url.Values {
+
+	values := url.Values{}
+	if q.After > 0 {
+		values.Set("after", strconv.FormatUint(q.After, 10))
+	}
+	if !q.From.IsZero() {
+		values.Set("from", q.From.Format(time.RFC3339))
+	}
+	if !q.To.IsZero() {
+		values.Set("to", q.To.Format(time.RFC3339))
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return values
+}
+
+// Client is a typed client of the exchange-service REST api.
+// Failed calls return a *common_http.Problem decoded from the error response.
+type Client struct {
+	baseURL string
+	account *common_http.Account
+	rest    common_http.RestClient
+	retry   RetryPolicy
+}
+
+// NewClient creates a new client of the exchange at the base url, e.g. http://localhost:8081, which authenticates as the account
+func NewClient(baseURL string, account *common_http.Account, rest common_http.RestClient, retry RetryPolicy) *Client {
+	return &Client{strings.TrimRight(baseURL, "/"), account, rest, retry}
+}
+
+// CreateOrder creates a order, the call is retried only when the order has a client order id
+func (c *Client) CreateOrder(ctx context.Context, order handlers.OrderDTO) (*handlers.OrderIDResponse, error) {
+
+	var response handlers.OrderIDResponse
+	err := c.call(ctx, http.MethodPost, "/orders", nil, order, order.ClientOrderID != "", &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateOrderSync creates a order and returns its state and fills after the matching
+func (c *Client) CreateOrderSync(ctx context.Context, order handlers.OrderDTO) (*handlers.ExecutionReportResponse, error) {
+
+	var response handlers.ExecutionReportResponse
+	err := c.call(ctx, http.MethodPost, "/orders", url.Values{"sync": {"true"}}, order, order.ClientOrderID != "", &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AmendOrder increases the quantity of a order identified by id or client order id, the price can not be amended.
+// The call is not retried, the retry of a applied amend would be rejected because the quantity does not increase.
+func (c *Client) AmendOrder(ctx context.Context, order handlers.OrderDTO) error {
+	return c.call(ctx, http.MethodPut, "/orders", nil, order, false, nil)
+}
+
+// CancelOrder cancels a order
+func (c *Client) CancelOrder(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, "/orders/"+url.PathEscape(id), nil, nil, true, nil)
+}
+
+// CancelClientOrder cancels a order by client order id
+func (c *Client) CancelClientOrder(ctx context.Context, clientOrderID string) error {
+	return c.call(ctx, http.MethodDelete, "/orders", url.Values{"client_order_id": {clientOrderID}}, nil, true, nil)
+}
+
+// GetOrder returns a order
+func (c *Client) GetOrder(ctx context.Context, id string) (*handlers.OrderResponse, error) {
+
+	var response handlers.OrderResponse
+	err := c.call(ctx, http.MethodGet, "/orders/"+url.PathEscape(id), nil, nil, true, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetOrders returns a page of orders
+func (c *Client) GetOrders(ctx context.Context, query OrdersQuery) (*handlers.OrdersResponse, error) {
+
+	var response handlers.OrdersResponse
+	err := c.call(ctx, http.MethodGet, "/orders", query.values(), nil, true, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetSymbols returns the symbols of the order book with their prices
+func (c *Client) GetSymbols(ctx context.Context) ([]handlers.SymbolResponse, error) {
+
+	var response []handlers.SymbolResponse
+	err := c.call(ctx, http.MethodGet, "/orderbook", nil, nil, true, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetBook returns the prices of a symbol of the order book
+func (c *Client) GetBook(ctx context.Context, symbol string) (*handlers.SymbolResponse, error) {
+
+	var response handlers.SymbolResponse
+	err := c.call(ctx, http.MethodGet, "/orderbook/"+url.PathEscape(symbol), nil, nil, true, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetSnapshot returns the aggregated depth of a symbol with the sequence of the book events
+func (c *Client) GetSnapshot(ctx context.Context, symbol string) (*handlers.BookSnapshotResponse, error) {
+
+	var response handlers.BookSnapshotResponse
+	err := c.call(ctx, http.MethodGet, "/orderbook/"+url.PathEscape(symbol)+"/snapshot", nil, nil, true, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetTrades returns a page of the trade tape of a symbol
+func (c *Client) GetTrades(ctx context.Context, symbol string, query TradesQuery) (*handlers.TradesResponse, error) {
+
+	var response handlers.TradesResponse
+	err := c.call(ctx, http.MethodGet, "/trades/"+url.PathEscape(symbol), query.values(), nil, true, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// call sends the request, retrying idempotent calls, and decodes the response into the result when it is not nil
+func (c *Client) call(ctx context.Context, method string, path string, query url.Values, payload interface{}, idempotent bool, result interface{}) error {
+
+	uri := c.baseURL + path
+	if len(query) > 0 {
+		uri += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+
+		status, body, header, err := c.send(ctx, method, uri, payload)
+
+		if err == nil && status < http.StatusBadRequest {
+			if result == nil || len(body) == 0 {
+				return nil
+			}
+			return json.Unmarshal(body, result)
+		}
+
+		if err == nil {
+			err = getProblem(status, body)
+		}
+
+		if !idempotent || attempt >= c.retry.MaxRetries || !isRetryable(ctx, status) {
+			return err
+		}
+
+		delay := c.retry.Delay(attempt)
+		if retryAfter, parseErr := strconv.Atoi(header.Get(common_http.RetryAfterHeader)); parseErr == nil {
+			delay = time.Duration(retryAfter) * time.Second
+		}
+
+		log.Printf("%s %s failed, retrying in %s. %s", method, path, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method string, uri string, payload interface{}) (int, []byte, http.Header, error) {
+
+	request, err := c.rest.NewRequest(ctx, method, uri, payload)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	if c.account != nil {
+		err = common_http.SignRequest(request, c.account)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+	}
+
+	response, err := c.rest.Do(request)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	return response.StatusCode, body, response.Header, nil
+}
+
+// isRetryable returns true for transport errors, status zero, and for throttled or unavailable responses
+func isRetryable(ctx context.Context, status int) bool {
+
+	if ctx.Err() != nil {
+		return false
+	}
+
+	switch status {
+	case 0, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// getProblem decodes the problem of a error response, responses without a problem body get the status text as title
+func getProblem(status int, body []byte) *common_http.Problem {
+
+	var problem common_http.Problem
+	err := json.Unmarshal(body, &problem)
+	if err == nil && problem.Status != 0 {
+		return &problem
+	}
+
+	return common_http.NewProblem(status, "", strings.TrimSpace(string(body)))
+}
+
+// StatusCode returns the http status of a error returned by the client, zero for transport errors
+func StatusCode(err error) int {
+
+	if problem, ok := err.(*common_http.Problem); ok {
+		return problem.Status
+	}
+	return 0
+}
+
+// Code returns the error code of a error returned by the client
+func Code(err error) common_http.ErrorCode {
+
+	if problem, ok := err.(*common_http.Problem); ok {
+		return problem.Code
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+var testRetryPolicy = RetryPolicy{3, time.Millisecond, 10 * time.Millisecond}
+
+// startExchange starts a server with the real order and book handlers, the first failures requests fail with 503
+func startExchange(t *testing.T, failures int32, requests *int32) string {
+
+	book := models.NewOrderBook()
+	publisher := &mocks.MockPublisher{}
+	feed := marketdata.NewFeed(10)
+	bookEvents := marketdata.NewBookEventPublisher(&mocks.MockPublisher{})
+	tape := trading.NewTradeTape(10)
+	trader := trading.NewOrderTrader(publisher, feed, tape, trading.NewDailyStats(trading.LastTradeClosing, time.Minute))
+	orderHandler := handlers.NewOrderHandler(book, trading.NewOrderAppender(), trading.NewOrderAmender(publisher), trader, trading.NewOrderCanceller(publisher),
+		&mocks.MockLimitChecker{}, trading.BookListeners{feed, bookEvents}, publisher)
+	orderQueryHandler := handlers.NewOrderQueryHandler(book)
+	orderBookHandler := handlers.NewOrderBookHandler(book)
+	bookSnapshotHandler := handlers.NewBookSnapshotHandler(bookEvents)
+	tradesHandler := handlers.NewTradesHandler(tape)
+
+	accounts := common_http.NewInMemoryAccountStore(
+		&common_http.Account{Name: "ACC1", APIKey: "KEY1", Secret: "SECRET1"},
+		&common_http.Account{Name: "VIEW", APIKey: "KEY2", Role: common_http.ViewerRole},
+	)
+	authorizer := common_http.NewAuthorizer(accounts, common_http.NewJWTVerifier(nil))
+	spec := handlers.NewOpenAPI()
+
+	router := httprouter.New()
+	router.POST("/orders", common_http.POSTJSONValidationMiddleware(authorizer.Authorize(common_http.TraderRole, common_http.RequestValidationMiddleware(spec, "/orders", orderHandler.OrderCreateHandle))))
+	router.PUT("/orders", common_http.PUTJSONValidationMiddleware(authorizer.Authorize(common_http.TraderRole, common_http.RequestValidationMiddleware(spec, "/orders", orderHandler.OrderAmendHandle))))
+	router.DELETE("/orders/:orderid", common_http.DELETEValidationMiddleware(authorizer.Authorize(common_http.TraderRole, orderHandler.OrderCancelHandle)))
+	router.DELETE("/orders", common_http.DELETEValidationMiddleware(authorizer.Authorize(common_http.TraderRole, orderHandler.OrderCancelByClientOrderIDHandle)))
+	router.GET("/orders", common_http.GETValidationMiddleware(authorizer.Authorize(common_http.TraderRole, orderQueryHandler.GetOrdersHandle)))
+	router.GET("/orders/:orderid", common_http.GETValidationMiddleware(authorizer.Authorize(common_http.TraderRole, orderQueryHandler.GetOrderHandle)))
+	router.GET("/orderbook", common_http.GETValidationMiddleware(authorizer.Authorize(common_http.ViewerRole, orderBookHandler.GetSymbolsHandler)))
+	router.GET("/orderbook/:symbol", common_http.GETValidationMiddleware(authorizer.Authorize(common_http.ViewerRole, orderBookHandler.GetSymbolHandler)))
+	router.GET("/orderbook/:symbol/snapshot", common_http.GETValidationMiddleware(authorizer.Authorize(common_http.ViewerRole, bookSnapshotHandler.GetSnapshotHandle)))
+	router.GET("/trades/:symbol", common_http.GETValidationMiddleware(authorizer.Authorize(common_http.ViewerRole, tradesHandler.GetTradesHandle)))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(requests, 1) <= failures {
+			common_http.WriteError(w, r, http.StatusServiceUnavailable, common_http.InternalErrorCode, "unavailable")
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func newTestClient(baseURL string, account *common_http.Account) *Client {
+	return NewClient(baseURL, account, common_http.NewRestClientImpl(time.Second), testRetryPolicy)
+}
+
+var trader = &common_http.Account{Name: "ACC1", APIKey: "KEY1", Secret: "SECRET1"}
+
+func TestClientOrderLifecycle(t *testing.T) {
+
+	require := require.New(t)
+
+	var requests int32
+	c := newTestClient(startExchange(t, 0, &requests), trader)
+	ctx := context.Background()
+
+	created, err := c.CreateOrder(ctx, handlers.OrderDTO{Symbol: "TT", Quantity: 10, Direction: models.BuyText, Price: 1.99, ClientOrderID: "C1"})
+	require.Nil(err)
+	require.NotEmpty(created.ID)
+	require.Equal("C1", created.ClientOrderID)
+
+	order, err := c.GetOrder(ctx, created.ID)
+	require.Nil(err)
+	require.Equal(models.PendingText, order.Status)
+	require.Equal("ACC1", order.Account)
+
+	err = c.AmendOrder(ctx, handlers.OrderDTO{Symbol: "TT", Quantity: 20, Direction: models.BuyText, Price: 1.99, ClientOrderID: "C1"})
+	require.Nil(err)
+
+	book, err := c.GetBook(ctx, "TT")
+	require.Nil(err)
+	require.Equal("TT", book.Symbol)
+	require.Len(book.Prices, 1)
+	require.Equal(uint(20), book.Prices[0].BuyQuantity)
+
+	symbols, err := c.GetSymbols(ctx)
+	require.Nil(err)
+	require.Len(symbols, 1)
+
+	snapshot, err := c.GetSnapshot(ctx, "TT")
+	require.Nil(err)
+	require.Len(snapshot.Bids, 1)
+
+	orders, err := c.GetOrders(ctx, OrdersQuery{Symbol: "TT", Status: models.PendingText})
+	require.Nil(err)
+	require.Len(orders.Orders, 1)
+
+	err = c.CancelClientOrder(ctx, "C1")
+	require.Nil(err)
+
+	order, err = c.GetOrder(ctx, created.ID)
+	require.Nil(err)
+	require.Equal(models.CancelledText, order.Status)
+}
+
+func TestClientCreateOrderSync(t *testing.T) {
+
+	require := require.New(t)
+
+	var requests int32
+	c := newTestClient(startExchange(t, 0, &requests), trader)
+	ctx := context.Background()
+
+	resting, err := c.CreateOrder(ctx, handlers.OrderDTO{Symbol: "TT", Quantity: 10, Direction: models.SellText, Price: 1.99})
+	require.Nil(err)
+
+	report, err := c.CreateOrderSync(ctx, handlers.OrderDTO{Symbol: "TT", Quantity: 10, Direction: models.BuyText, Price: 1.99})
+	require.Nil(err)
+	require.Equal(models.FullyFilledText, report.Order.Status)
+	require.Len(report.Fills, 1)
+	require.Equal(resting.ID, report.Fills[0].CounterpartyOrderID)
+
+	trades, err := c.GetTrades(ctx, "TT", TradesQuery{})
+	require.Nil(err)
+	require.Len(trades.Trades, 1)
+	require.Equal(uint(10), trades.Trades[0].Quantity)
+	require.Equal(models.BuyText, trades.Trades[0].Aggressor)
+
+	trades, err = c.GetTrades(ctx, "TT", TradesQuery{After: trades.NextSequence, Limit: 10})
+	require.Nil(err)
+	require.Empty(trades.Trades)
+}
+
+func TestClientErrors(t *testing.T) {
+
+	require := require.New(t)
+
+	var requests int32
+	baseURL := startExchange(t, 0, &requests)
+	c := newTestClient(baseURL, trader)
+	ctx := context.Background()
+
+	_, err := c.CreateOrder(ctx, handlers.OrderDTO{Symbol: "TT", Quantity: 0, Direction: "Up", Price: 1.99})
+	require.NotNil(err)
+	require.Equal(http.StatusBadRequest, StatusCode(err))
+	require.Equal(common_http.ValidationFailedCode, Code(err))
+	require.Len(err.(*common_http.Problem).Errors, 2)
+
+	_, err = c.GetOrder(ctx, "0f8fad5b-d9cb-469f-a165-70867728950e")
+	require.Equal(http.StatusNotFound, StatusCode(err))
+
+	err = c.CancelClientOrder(ctx, "UNKNOWN")
+	require.Equal(common_http.ErrorCode(events.UnknownOrderReason), Code(err))
+
+	_, err = newTestClient(baseURL, &common_http.Account{APIKey: "KEY2"}).CreateOrder(ctx, handlers.OrderDTO{Symbol: "TT", Quantity: 1, Direction: models.BuyText, Price: 1})
+	require.Equal(http.StatusForbidden, StatusCode(err))
+
+	_, err = newTestClient(baseURL, &common_http.Account{APIKey: "KEY1"}).GetSymbols(ctx)
+	require.Equal(common_http.UnauthorizedCode, Code(err))
+}
+
+func TestClientRetries(t *testing.T) {
+
+	require := require.New(t)
+
+	var requests int32
+	c := newTestClient(startExchange(t, 2, &requests), trader)
+	ctx := context.Background()
+
+	_, err := c.CreateOrder(ctx, handlers.OrderDTO{Symbol: "TT", Quantity: 10, Direction: models.BuyText, Price: 1.99})
+	require.Equal(http.StatusServiceUnavailable, StatusCode(err))
+	require.Equal(int32(1), atomic.LoadInt32(&requests))
+
+	created, err := c.CreateOrder(ctx, handlers.OrderDTO{Symbol: "TT", Quantity: 10, Direction: models.BuyText, Price: 1.99, ClientOrderID: "C1"})
+	require.Nil(err)
+	require.Equal(int32(3), atomic.LoadInt32(&requests))
+
+	atomic.StoreInt32(&requests, 0)
+	symbols, err := c.GetSymbols(ctx)
+	require.Nil(err)
+	require.Equal("TT", symbols[0].Symbol)
+	require.Equal(int32(3), atomic.LoadInt32(&requests))
+
+	order, err := c.GetOrder(ctx, created.ID)
+	require.Nil(err)
+	require.Equal("C1", order.ClientOrderID)
+
+	atomic.StoreInt32(&requests, 1)
+	err = c.AmendOrder(ctx, handlers.OrderDTO{Symbol: "TT", Quantity: 20, Direction: models.BuyText, Price: 1.99, ClientOrderID: "C1"})
+	require.Equal(http.StatusServiceUnavailable, StatusCode(err))
+	require.Equal(int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestClientContext(t *testing.T) {
+
+	require := require.New(t)
+
+	var requests int32
+	c := NewClient(startExchange(t, 100, &requests), trader, common_http.NewRestClientImpl(time.Second), RetryPolicy{10, 50 * time.Millisecond, time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetSymbols(ctx)
+	require.Equal(context.DeadlineExceeded, err)
+	require.Equal(int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+
+	require := require.New(t)
+
+	policy := RetryPolicy{5, 100 * time.Millisecond, time.Second}
+
+	require.Equal(100*time.Millisecond, policy.Delay(0))
+	require.Equal(400*time.Millisecond, policy.Delay(2))
+	require.Equal(time.Second, policy.Delay(4))
+	require.Equal(time.Second, policy.Delay(100))
+}
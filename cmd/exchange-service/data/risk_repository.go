@@ -0,0 +1,129 @@
+package data
+
+import (
+	"database/sql"
+
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// RiskRepository interface
+type RiskRepository interface {
+	GetLimits() ([]models.AccountLimits, error)
+	SaveLimits(limits models.AccountLimits) error
+	RemoveLimits(account string) error
+	GetPositions() ([]models.AccountPosition, error)
+}
+
+// RiskRepositoryImpl risk repository, see schema.sql.
+// The positions are the ones the event aggregation service stores in the same database.
+type RiskRepositoryImpl struct {
+	db               *sql.DB
+	limitsStmt       *sql.Stmt
+	saveLimitsStmt   *sql.Stmt
+	removeLimitsStmt *sql.Stmt
+	positionStmt     *sql.Stmt
+}
+
+const (
+	limitsColumns   = `account, max_long, max_short, max_gross_exposure, daily_loss_limit`
+	positionColumns = `account, symbol, quantity, price, realized_pnl, daily_pnl, updated`
+)
+
+// NewRiskRepository creates a new risk repository
+func NewRiskRepository(db *sql.DB) (*RiskRepositoryImpl, error) {
+
+	limitsStmt, err := db.Prepare(`SELECT ` + limitsColumns + ` FROM account_limits`)
+	if err != nil {
+		return nil, err
+	}
+
+	saveLimitsStmt, err := db.Prepare(`INSERT INTO account_limits (` + limitsColumns + `) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (account) DO UPDATE SET max_long = $2, max_short = $3, max_gross_exposure = $4, daily_loss_limit = $5`)
+	if err != nil {
+		return nil, err
+	}
+
+	removeLimitsStmt, err := db.Prepare(`DELETE FROM account_limits WHERE account = $1`)
+	if err != nil {
+		return nil, err
+	}
+
+	positionStmt, err := db.Prepare(`SELECT ` + positionColumns + ` FROM position`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RiskRepositoryImpl{db, limitsStmt, saveLimitsStmt, removeLimitsStmt, positionStmt}, nil
+}
+
+// GetLimits returns the explicitly set account limits
+func (rr *RiskRepositoryImpl) GetLimits() ([]models.AccountLimits, error) {
+
+	rows, err := rr.limitsStmt.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var limits []models.AccountLimits
+
+	for rows.Next() {
+		var l models.AccountLimits
+
+		err := rows.Scan(&l.Account, &l.MaxLong, &l.MaxShort, &l.MaxGrossExposure, &l.DailyLossLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		limits = append(limits, l)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// SaveLimits inserts or updates the limits of the account
+func (rr *RiskRepositoryImpl) SaveLimits(limits models.AccountLimits) error {
+
+	_, err := rr.saveLimitsStmt.Exec(limits.Account, limits.MaxLong, limits.MaxShort, limits.MaxGrossExposure, limits.DailyLossLimit)
+	return err
+}
+
+// RemoveLimits deletes the limits of the account
+func (rr *RiskRepositoryImpl) RemoveLimits(account string) error {
+
+	_, err := rr.removeLimitsStmt.Exec(account)
+	return err
+}
+
+// GetPositions returns the positions of all accounts
+func (rr *RiskRepositoryImpl) GetPositions() ([]models.AccountPosition, error) {
+
+	rows, err := rr.positionStmt.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []models.AccountPosition
+
+	for rows.Next() {
+		var p models.AccountPosition
+
+		err := rows.Scan(&p.Account, &p.Symbol, &p.Quantity, &p.Price, &p.RealizedPnL, &p.DailyPnL, &p.Updated)
+		if err != nil {
+			return nil, err
+		}
+
+		positions = append(positions, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return positions, nil
+}
@@ -0,0 +1,9 @@
+-- the account limits live next to the positions of the event aggregation service, see its schema.sql
+CREATE TABLE IF NOT EXISTS account_limits (
+    account            varchar(64)      NOT NULL,
+    max_long           bigint           NOT NULL,
+    max_short          bigint           NOT NULL,
+    max_gross_exposure double precision NOT NULL,
+    daily_loss_limit   double precision NOT NULL,
+    PRIMARY KEY (account)
+);
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/satori/go.uuid"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// HaltDTO defines the halt of a symbol
+type HaltDTO struct {
+	Symbol string `json:"symbol"` // symbol to halt
+	Reason string `json:"reason"` // reason of the halt, the rejections of the symbol carry it
+}
+
+// InstrumentDTO defines a instrument of the exchange
+type InstrumentDTO struct {
+	Symbol string `json:"symbol"` // symbol of the instrument
+}
+
+// AdminHandler handles the market operations of the admins, managing instruments, halting symbols and busting trades
+type AdminHandler struct {
+	book      *models.OrderBook
+	tape      *trading.TradeTape
+	listener  trading.BustListener
+	publisher events.EventPublisher
+}
+
+// NewAdminHandler creates a new admin handler, the listener gets notified of the busted trades
+func NewAdminHandler(book *models.OrderBook, tape *trading.TradeTape, listener trading.BustListener, publisher events.EventPublisher) *AdminHandler {
+	return &AdminHandler{book, tape, listener, publisher}
+}
+
+// GetInstrumentsHandle is the handler for getting the instruments, without instruments every symbol trades
+func (ah *AdminHandler) GetInstrumentsHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	ah.book.RLock()
+	response := make([]InstrumentDTO, 0, len(ah.book.Instruments))
+	for symbol := range ah.book.Instruments {
+		response = append(response, InstrumentDTO{symbol})
+	}
+	ah.book.RUnlock()
+
+	sort.Slice(response, func(i, j int) bool { return response[i].Symbol < response[j].Symbol })
+
+	encoded, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetInstrumentsHandle: Returned %d instruments", len(response))
+}
+
+// AddInstrumentHandle is the handler for adding a instrument.
+// Once a instrument is added only the instruments trade.
+func (ah *AdminHandler) AddInstrumentHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	var dto InstrumentDTO
+
+	err := json.NewDecoder(r.Body).Decode(&dto)
+	if err != nil {
+		log.Printf("Failed to bind model! %s", err)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	symbol := strings.ToUpper(dto.Symbol)
+	if symbol == "" {
+		log.Printf("AddInstrumentHandle: Invalid instrument %v", dto)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.ValidationFailedCode, "Symbol is required")
+		return
+	}
+
+	ah.book.Lock()
+	if ah.book.Instruments == nil {
+		ah.book.Instruments = make(map[string]bool)
+	}
+	ah.book.Instruments[symbol] = true
+	ah.book.Unlock()
+
+	w.WriteHeader(http.StatusAccepted)
+	log.Printf("AddInstrumentHandle: Instrument %s added", symbol)
+}
+
+// RemoveInstrumentHandle is the handler for removing a instrument, a instrument with open orders is not removed
+func (ah *AdminHandler) RemoveInstrumentHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	symbol := strings.ToUpper(p.ByName("symbol"))
+
+	ah.book.Lock()
+	defer ah.book.Unlock()
+
+	if !ah.book.Instruments[symbol] {
+		log.Printf("RemoveInstrumentHandle: Instrument %s not found", symbol)
+		common_http.WriteNotFound(w, r, fmt.Sprintf("Instrument %s not found", symbol))
+		return
+	}
+
+	if hasOpenOrders(ah.book.Symbols[symbol]) {
+		log.Printf("RemoveInstrumentHandle: Instrument %s has open orders", symbol)
+		common_http.WriteError(w, r, http.StatusConflict, common_http.ConflictCode, fmt.Sprintf("Instrument %s has open orders", symbol))
+		return
+	}
+
+	delete(ah.book.Instruments, symbol)
+
+	w.WriteHeader(http.StatusAccepted)
+	log.Printf("RemoveInstrumentHandle: Instrument %s removed", symbol)
+}
+
+// GetHaltsHandle is the handler for getting the halted symbols
+func (ah *AdminHandler) GetHaltsHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	ah.book.RLock()
+	response := make([]HaltDTO, 0, len(ah.book.Halted))
+	for symbol, reason := range ah.book.Halted {
+		response = append(response, HaltDTO{symbol, reason})
+	}
+	ah.book.RUnlock()
+
+	sort.Slice(response, func(i, j int) bool { return response[i].Symbol < response[j].Symbol })
+
+	encoded, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetHaltsHandle: Returned %d halted symbols", len(response))
+}
+
+// HaltHandle is the handler for halting a symbol.
+// New orders and amends of a halted symbol are rejected, cancels are accepted.
+func (ah *AdminHandler) HaltHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	var dto HaltDTO
+
+	err := json.NewDecoder(r.Body).Decode(&dto)
+	if err != nil {
+		log.Printf("Failed to bind model! %s", err)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	symbol := strings.ToUpper(dto.Symbol)
+	if symbol == "" {
+		log.Printf("HaltHandle: Invalid halt %v", dto)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.ValidationFailedCode, "Symbol is required")
+		return
+	}
+	if dto.Reason == "" {
+		dto.Reason = "Trading halt"
+	}
+
+	ah.book.Lock()
+	ah.book.Halted[symbol] = dto.Reason
+	ah.book.Unlock()
+
+	w.WriteHeader(http.StatusAccepted)
+	log.Printf("HaltHandle: Symbol %s halted, %s", symbol, dto.Reason)
+}
+
+// ResumeHandle is the handler for resuming the trading of a halted symbol
+func (ah *AdminHandler) ResumeHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	symbol := strings.ToUpper(p.ByName("symbol"))
+
+	ah.book.Lock()
+	_, ok := ah.book.Halted[symbol]
+	delete(ah.book.Halted, symbol)
+	ah.book.Unlock()
+
+	if !ok {
+		log.Printf("ResumeHandle: Symbol %s not halted", symbol)
+		common_http.WriteNotFound(w, r, fmt.Sprintf("Symbol %s is not halted", symbol))
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	log.Printf("ResumeHandle: Symbol %s resumed", symbol)
+}
+
+// BustTradeHandle is the handler for busting a trade of the tape.
+// The trade is removed from the tape, the statistics and the orders, and both orders publish a trade busted event which voids the trade in the positions and candles.
+// The busted quantity is removed from the quantity of the orders, busting does not return it to the book.
+func (ah *AdminHandler) BustTradeHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	tradeID := uuid.FromStringOrNil(p.ByName("tradeid"))
+	if tradeID == uuid.Nil {
+		log.Printf("BustTradeHandle: Invalid trade id %s", p.ByName("tradeid"))
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, "Failed to get trade id")
+		return
+	}
+
+	ah.book.Lock()
+	defer ah.book.Unlock()
+
+	trade, ok := ah.tape.Bust(tradeID)
+	if !ok {
+		log.Printf("BustTradeHandle: Trade %s not found", tradeID)
+		common_http.WriteNotFound(w, r, fmt.Sprintf("Trade %s not found", tradeID))
+		return
+	}
+
+	ah.listener.Busted(trade)
+
+	occured := time.Now().UTC()
+	for _, orderID := range []uuid.UUID{trade.BuyOrderID, trade.SellOrderID} {
+		account := ""
+		if order, ok := ah.book.Orders[orderID]; ok {
+			order.Bust(trade.Quantity)
+			account = order.Account
+		}
+		ah.publish(events.NewOrderTradeBusted(orderID.String(), account, occured, trade.ID.String(), trade.Symbol, trade.Price, trade.Quantity, 1))
+	}
+
+	encoded, _ := json.Marshal(TradeResponse{trade.ID.String(), trade.Sequence, trade.Occured, trade.Price, trade.Quantity, trade.Aggressor.String()})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("BustTradeHandle: Trade %s busted", trade)
+}
+
+// hasOpenOrders returns true if a order of the prices is tradeable, cancelled orders stay in the prices until the next trade
+func hasOpenOrders(prices []*models.OrderPrice) bool {
+
+	for _, price := range prices {
+		for _, quantity := range []models.OrderQuantity{price.Buy, price.Sell} {
+			for _, order := range quantity.Orders {
+				if order.Status.IsTradeable() {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func (ah *AdminHandler) publish(event *events.OrderTradeBusted) {
+
+	envelope, err := events.NewOrderEventEnvelope(event, event.EventType)
+	if err != nil {
+		log.Printf("Failed to create order trade busted event envelope! %s", err)
+		return
+	}
+
+	err = ah.publisher.Publish(envelope)
+	if err != nil {
+		log.Printf("Failed to publish order trade busted event: %s", event.String())
+	}
+}
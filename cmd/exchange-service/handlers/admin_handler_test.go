@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func getAdminResponse(method string, route string, uri string, body string, handle httprouter.Handle) *httptest.ResponseRecorder {
+
+	request, _ := http.NewRequest(method, uri, bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(method, route, handle)
+	router.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestInstrumentHandles(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	admin := NewAdminHandler(book, trading.NewTradeTape(10), &mocks.MockBustListener{}, &mocks.MockPublisher{})
+	orders := newBatchOrderHandler(book)
+
+	response := getAdminResponse(http.MethodPut, "/admin/instruments", "/admin/instruments", `{"symbol":"tt"}`, admin.AddInstrumentHandle)
+	require.Equal(http.StatusAccepted, response.Code)
+
+	execution, err := orders.ExecuteOrder("ACC1", OrderDTO{"", "TT", 10, models.BuyText, 2.0, ""})
+	require.Nil(err)
+
+	_, err = orders.ExecuteOrder("ACC1", OrderDTO{"", "AA", 10, models.BuyText, 2.0, ""})
+	require.Equal(&OrderError{events.UnknownSymbolReason, "Symbol AA is not a instrument", nil}, err)
+
+	results, ok := orders.CheckBatch("ACC1", []BatchInstructionDTO{{CreateAction, OrderDTO{"", "AA", 10, models.BuyText, 2.0, ""}}})
+	require.False(ok)
+	require.Equal(http.StatusBadRequest, results[0].Status)
+
+	response = getAdminResponse(http.MethodGet, "/admin/instruments", "/admin/instruments", "", admin.GetInstrumentsHandle)
+	require.Equal(http.StatusOK, response.Code)
+	var instruments []InstrumentDTO
+	require.Nil(json.Unmarshal(response.Body.Bytes(), &instruments))
+	require.Equal([]InstrumentDTO{{"TT"}}, instruments)
+
+	response = getAdminResponse(http.MethodDelete, "/admin/instruments/:symbol", "/admin/instruments/tt", "", admin.RemoveInstrumentHandle)
+	require.Equal(http.StatusConflict, response.Code)
+
+	require.Nil(orders.CancelOrder("ACC1", execution.Order.ID.String()))
+
+	response = getAdminResponse(http.MethodDelete, "/admin/instruments/:symbol", "/admin/instruments/tt", "", admin.RemoveInstrumentHandle)
+	require.Equal(http.StatusAccepted, response.Code)
+	response = getAdminResponse(http.MethodDelete, "/admin/instruments/:symbol", "/admin/instruments/tt", "", admin.RemoveInstrumentHandle)
+	require.Equal(http.StatusNotFound, response.Code)
+
+	_, err = orders.ExecuteOrder("ACC1", OrderDTO{"", "TT", 10, models.BuyText, 2.0, ""})
+	require.Equal(events.UnknownSymbolReason, err.(*OrderError).Reason)
+
+	response = getAdminResponse(http.MethodPut, "/admin/instruments", "/admin/instruments", `{}`, admin.AddInstrumentHandle)
+	require.Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestHaltHandle(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	admin := NewAdminHandler(book, trading.NewTradeTape(10), &mocks.MockBustListener{}, &mocks.MockPublisher{})
+	orders := newBatchOrderHandler(book)
+
+	execution, err := orders.ExecuteOrder("ACC1", OrderDTO{"", "TT", 10, models.BuyText, 2.0, ""})
+	require.Nil(err)
+
+	response := getAdminResponse(http.MethodPut, "/admin/halts", "/admin/halts", `{"symbol":"tt","reason":"news pending"}`, admin.HaltHandle)
+	require.Equal(http.StatusAccepted, response.Code)
+
+	_, err = orders.ExecuteOrder("ACC1", OrderDTO{"", "TT", 10, models.BuyText, 2.0, ""})
+	require.Equal(&OrderError{events.SymbolHaltedReason, "Symbol TT is halted: news pending", nil}, err)
+
+	err = orders.AmendOrder("ACC1", OrderDTO{execution.Order.ID.String(), "TT", 20, models.BuyText, 2.0, ""})
+	require.Equal(&OrderError{events.AmendFailedReason, "Symbol TT is halted: news pending", nil}, err)
+
+	results, ok := orders.CheckBatch("ACC1", []BatchInstructionDTO{{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 2.0, ""}}})
+	require.False(ok)
+	require.Equal(http.StatusBadRequest, results[0].Status)
+
+	require.Nil(orders.CancelOrder("ACC1", execution.Order.ID.String()))
+
+	response = getAdminResponse(http.MethodGet, "/admin/halts", "/admin/halts", "", admin.GetHaltsHandle)
+	require.Equal(http.StatusOK, response.Code)
+	var halts []HaltDTO
+	require.Nil(json.Unmarshal(response.Body.Bytes(), &halts))
+	require.Equal([]HaltDTO{{"TT", "news pending"}}, halts)
+
+	response = getAdminResponse(http.MethodDelete, "/admin/halts/:symbol", "/admin/halts/tt", "", admin.ResumeHandle)
+	require.Equal(http.StatusAccepted, response.Code)
+	response = getAdminResponse(http.MethodDelete, "/admin/halts/:symbol", "/admin/halts/tt", "", admin.ResumeHandle)
+	require.Equal(http.StatusNotFound, response.Code)
+
+	_, err = orders.ExecuteOrder("ACC1", OrderDTO{"", "TT", 10, models.BuyText, 2.0, ""})
+	require.Nil(err)
+}
+
+func TestHaltHandleBadRequest(t *testing.T) {
+
+	require := require.New(t)
+
+	admin := NewAdminHandler(models.NewOrderBook(), trading.NewTradeTape(10), &mocks.MockBustListener{}, &mocks.MockPublisher{})
+
+	response := getAdminResponse(http.MethodPut, "/admin/halts", "/admin/halts", `{"reason":"news pending"}`, admin.HaltHandle)
+	require.Equal(http.StatusBadRequest, response.Code)
+
+	response = getAdminResponse(http.MethodPut, "/admin/halts", "/admin/halts", `[]`, admin.HaltHandle)
+	require.Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestBustTradeHandle(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	buy := models.NewOrderFull(uuid.NewV4(), "TT", 2.0, 10, 10, models.Buy, models.FullyFilled)
+	buy.Account = "ACC1"
+	sell := models.NewOrderFull(uuid.NewV4(), "TT", 2.0, 20, 10, models.Sell, models.PartiallyFilled)
+	sell.Account = "ACC2"
+	book.Orders[buy.ID] = buy
+	book.Orders[sell.ID] = sell
+
+	tape := trading.NewTradeTape(10)
+	trade := models.NewTrade(uuid.NewV4(), 1, "TT", 2.0, 10, models.Sell, buy.ID, sell.ID, time.Now().UTC())
+	tape.Traded(trade)
+
+	listener := &mocks.MockBustListener{}
+	publisher := &mocks.MockPublisher{}
+	admin := NewAdminHandler(book, tape, listener, publisher)
+
+	response := getAdminResponse(http.MethodDelete, "/admin/trades/:tradeid", "/admin/trades/"+trade.ID.String(), "", admin.BustTradeHandle)
+	require.Equal(http.StatusOK, response.Code)
+
+	var busted TradeResponse
+	require.Nil(json.Unmarshal(response.Body.Bytes(), &busted))
+	require.Equal(trade.ID.String(), busted.TradeID)
+	require.Empty(tape.Trades("TT", trading.TradeQuery{}))
+	require.Equal([]*models.Trade{trade}, listener.Trades)
+	require.Equal(uint(0), buy.Quantity)
+	require.Equal(uint(0), buy.Traded)
+	require.Equal(models.FullyFilled, buy.Status)
+	require.Equal(uint(10), sell.Quantity)
+	require.Equal(uint(0), sell.Traded)
+	require.Equal(models.Pending, sell.Status)
+
+	require.Len(publisher.Envelopes, 2)
+	for i, order := range []*models.Order{buy, sell} {
+		require.Equal(events.OrderTradeBustedType, publisher.Envelopes[i].EventType)
+		event, err := publisher.Envelopes[i].GetOrderEvent()
+		require.Nil(err)
+		bust := event.(events.OrderTradeBusted)
+		require.Equal(order.ID.String(), bust.OrderID)
+		require.Equal(order.Account, bust.Account)
+		require.Equal(trade.ID.String(), bust.TradeID)
+		require.Equal(uint(10), bust.Quantity)
+	}
+
+	response = getAdminResponse(http.MethodDelete, "/admin/trades/:tradeid", "/admin/trades/"+trade.ID.String(), "", admin.BustTradeHandle)
+	require.Equal(http.StatusNotFound, response.Code)
+
+	response = getAdminResponse(http.MethodDelete, "/admin/trades/:tradeid", "/admin/trades/1", "", admin.BustTradeHandle)
+	require.Equal(http.StatusBadRequest, response.Code)
+}
@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	uuid "github.com/satori/go.uuid"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// Batch instruction actions
+const (
+	CreateAction = "create"
+	AmendAction  = "amend"
+	CancelAction = "cancel"
+)
+
+// MaxBatchSize is the maximum number of instructions of a batch
+const MaxBatchSize = 500
+
+// BatchInstructionDTO defines a create, amend or cancel instruction of a batch.
+// Amends and cancels identify the order by the id or else the client order id.
+type BatchInstructionDTO struct {
+	Action string `json:"action"` // create, amend or cancel
+	OrderDTO
+}
+
+// BatchResultResponse returns the result of a batch instruction, the status is the http status of the single request
+type BatchResultResponse struct {
+	Action        string                   `json:"action"`
+	ID            string                   `json:"id,omitempty"`
+	ClientOrderID string                   `json:"client_order_id,omitempty"`
+	Status        int                      `json:"status"`
+	Error         string                   `json:"error,omitempty"`
+	Errors        []common_http.FieldError `json:"errors,omitempty"`
+}
+
+// OrderBatchHandle is the handler for batches of order instructions, the instructions are processed in order.
+// With the query parameter all_or_nothing=true the batch is validated in full before any instruction is processed,
+// and nothing is processed when a instruction is invalid.
+func (oh *OrderHandler) OrderBatchHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	var instructions []BatchInstructionDTO
+
+	err := json.NewDecoder(r.Body).Decode(&instructions)
+	if err != nil {
+		log.Printf("OrderBatchHandle: Failed to bind model! %s", err)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	if len(instructions) == 0 || len(instructions) > MaxBatchSize {
+		text := fmt.Sprintf("Batch of %d instructions is not between 1 and %d", len(instructions), MaxBatchSize)
+		log.Printf("OrderBatchHandle: %s", text)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, text)
+		return
+	}
+
+	account := getAccount(r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Query().Get("all_or_nothing") == "true" {
+		results, ok := oh.ExecuteBatchAllOrNothing(account, instructions)
+		if !ok {
+			log.Printf("OrderBatchHandle: Batch of %s rejected", account)
+			encoded, _ := json.Marshal(results)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(encoded)
+			return
+		}
+		encoded, _ := json.Marshal(results)
+		w.Write(encoded)
+		log.Printf("OrderBatchHandle: Processed %d instructions of %s", len(instructions), account)
+		return
+	}
+
+	encoded, _ := json.Marshal(oh.ExecuteBatch(account, instructions))
+	w.Write(encoded)
+	log.Printf("OrderBatchHandle: Processed %d instructions of %s", len(instructions), account)
+}
+
+// OrderBatchCost returns the rate limit cost of a batch, one token per instruction.
+// The body is restored for the handler, a batch the handler rejects costs one token.
+func OrderBatchCost(r *http.Request) int {
+
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 1
+	}
+
+	var instructions []json.RawMessage
+	err = json.Unmarshal(body, &instructions)
+	if err != nil || len(instructions) == 0 || len(instructions) > MaxBatchSize {
+		return 1
+	}
+
+	return len(instructions)
+}
+
+// ExecuteBatch processes the instructions in order and returns their results, no other request changes the book during the batch
+func (oh *OrderHandler) ExecuteBatch(account string, instructions []BatchInstructionDTO) []BatchResultResponse {
+
+	oh.book.Lock()
+	defer oh.book.Unlock()
+
+	return oh.executeBatch(account, instructions)
+}
+
+// ExecuteBatchAllOrNothing checks the instructions and processes them only if all of them are valid.
+// The book is locked from the check until the batch is processed.
+func (oh *OrderHandler) ExecuteBatchAllOrNothing(account string, instructions []BatchInstructionDTO) ([]BatchResultResponse, bool) {
+
+	oh.book.Lock()
+	defer oh.book.Unlock()
+
+	results, ok := oh.checkBatch(account, instructions)
+	if !ok {
+		return results, false
+	}
+
+	return oh.executeBatch(account, instructions), true
+}
+
+// CheckBatch validates the instructions against the book without processing them.
+// It returns false and the errors of the invalid instructions when the batch can not be processed in full.
+func (oh *OrderHandler) CheckBatch(account string, instructions []BatchInstructionDTO) ([]BatchResultResponse, bool) {
+
+	oh.book.RLock()
+	defer oh.book.RUnlock()
+
+	return oh.checkBatch(account, instructions)
+}
+
+func (oh *OrderHandler) executeBatch(account string, instructions []BatchInstructionDTO) []BatchResultResponse {
+
+	results := make([]BatchResultResponse, 0, len(instructions))
+
+	for _, instruction := range instructions {
+
+		result := BatchResultResponse{Action: instruction.Action, ID: instruction.ID, ClientOrderID: instruction.ClientOrderID}
+		var err error
+
+		switch instruction.Action {
+		case CreateAction:
+			var execution *OrderExecution
+			execution, err = oh.executeClientOrder(account, instruction.OrderDTO)
+			if err == nil {
+				result.ID = execution.Order.ID.String()
+			}
+		case AmendAction:
+			err = oh.amendOrder(account, instruction.OrderDTO)
+		case CancelAction:
+			if instruction.ID == "" && instruction.ClientOrderID != "" {
+				err = oh.cancelClientOrder(account, instruction.ClientOrderID)
+			} else {
+				err = oh.cancelOrder(account, instruction.ID)
+			}
+		default:
+			err = &OrderError{events.InvalidOrderReason, fmt.Sprintf("Unknown action %s", instruction.Action), nil}
+		}
+
+		if err != nil {
+			result.Status, result.Error, result.Errors = getErrorStatus(err), err.Error(), getOrderFieldErrors(err)
+		} else {
+			result.Status = http.StatusAccepted
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func (oh *OrderHandler) checkBatch(account string, instructions []BatchInstructionDTO) ([]BatchResultResponse, bool) {
+
+	results := make([]BatchResultResponse, 0, len(instructions))
+	view := newBatchBook(oh.book, account)
+	ok := true
+
+	for _, instruction := range instructions {
+
+		result := BatchResultResponse{Action: instruction.Action, ID: instruction.ID, ClientOrderID: instruction.ClientOrderID, Status: http.StatusFailedDependency}
+
+		err := oh.checkInstruction(account, instruction, view)
+		if err != nil {
+			result.Status, result.Error, result.Errors = getErrorStatus(err), err.Error(), getOrderFieldErrors(err)
+			ok = false
+		}
+		results = append(results, result)
+	}
+
+	if ok {
+		return nil, true
+	}
+
+	for i := range results {
+		if results[i].Status == http.StatusFailedDependency {
+			results[i].Error = "Batch not processed"
+		}
+	}
+	return results, false
+}
+
+// batchBook is the book as the checked instructions of a batch leave it.
+// It holds copies of the orders which the instructions change, the orders of the book are not modified.
+type batchBook struct {
+	book         *models.OrderBook
+	clientOrders map[string]uuid.UUID
+}
+
+func newBatchBook(book *models.OrderBook, account string) *batchBook {
+
+	view := &batchBook{models.NewOrderBook(), make(map[string]uuid.UUID)}
+	for id, order := range book.Orders {
+		view.book.Orders[id] = order
+	}
+	for _, order := range book.Accounts[account] {
+		view.book.Open(order)
+	}
+	view.book.Instruments = book.Instruments
+	view.book.Halted = book.Halted
+	return view
+}
+
+// update replaces the order of the view with a copy changed by the instruction
+func (bb *batchBook) update(order *models.Order, change func(order *models.Order)) {
+	updated := *order
+	change(&updated)
+	bb.book.Orders[order.ID] = &updated
+	if updated.Status.IsTradeable() {
+		bb.book.Open(&updated)
+	} else {
+		bb.book.Close(&updated)
+	}
+}
+
+// checkInstruction validates a instruction against the view, which it updates with the instruction
+func (oh *OrderHandler) checkInstruction(account string, instruction BatchInstructionDTO, view *batchBook) error {
+
+	dto := instruction.OrderDTO
+
+	switch instruction.Action {
+	case CreateAction:
+		return oh.checkCreate(account, dto, view)
+	case AmendAction, CancelAction:
+	default:
+		return &OrderError{events.InvalidOrderReason, fmt.Sprintf("Unknown action %s", instruction.Action), nil}
+	}
+
+	if dto.ID == "" && dto.ClientOrderID != "" {
+		if id, ok := view.clientOrders[dto.ClientOrderID]; ok {
+			dto.ID = id.String()
+		} else if id, ok := oh.getOrderID(account, dto.ClientOrderID); ok {
+			dto.ID = id
+		} else {
+			return &OrderError{events.UnknownOrderReason, fmt.Sprintf("Client order %s not found", dto.ClientOrderID), nil}
+		}
+	}
+
+	orderID := uuid.FromStringOrNil(strings.ToUpper(dto.ID))
+	if orderID == uuid.Nil {
+		return &OrderError{events.InvalidOrderReason, "Failed to get order id", nil}
+	}
+
+	existing, ok := view.book.Orders[orderID]
+	if !ok {
+		return &OrderError{events.UnknownOrderReason, fmt.Sprintf("Order %s not found", orderID), nil}
+	}
+	if existing.Account != account {
+		return &OrderError{events.NotOwnerReason, "Order belongs to another account", nil}
+	}
+	if !existing.Status.IsTradeable() {
+		return &OrderError{events.OrderNotTradeableReason, fmt.Sprintf("Order %s is %s", orderID, existing.Status), nil}
+	}
+
+	if instruction.Action == CancelAction {
+		view.update(existing, func(order *models.Order) { order.Status = models.Cancelled })
+		return nil
+	}
+
+	order, err := ValidateOrder(dto)
+	if err != nil {
+		return &OrderError{events.AmendFailedReason, err.Error(), getFieldErrors(err)}
+	}
+	order.Account = account
+
+	if order.Symbol != existing.Symbol || order.Direction != existing.Direction || order.Price != existing.Price {
+		return &OrderError{events.AmendFailedReason, fmt.Sprintf("Order %s is not a %s order of %s at %f", orderID, order.Direction, order.Symbol, order.Price), nil}
+	}
+	if order.Quantity <= existing.Quantity {
+		return &OrderError{events.AmendFailedReason, fmt.Sprintf("Quantity %d does not increase the quantity %d of order %s", order.Quantity, existing.Quantity, orderID), nil}
+	}
+
+	err = checkHalted(view.book, order.Symbol)
+	if err != nil {
+		return &OrderError{events.AmendFailedReason, err.Error(), nil}
+	}
+
+	err = oh.checker.Check(view.book, order)
+	if err != nil {
+		return &OrderError{events.AmendFailedReason, err.Error(), nil}
+	}
+
+	view.update(existing, func(amended *models.Order) { amended.Amend(order.Quantity - existing.Quantity) })
+	return nil
+}
+
+// checkCreate validates a create instruction, the limits account for the orders created by the preceding instructions
+func (oh *OrderHandler) checkCreate(account string, dto OrderDTO, view *batchBook) error {
+
+	if dto.ClientOrderID != "" {
+		if _, ok := view.clientOrders[dto.ClientOrderID]; ok {
+			return &OrderError{events.DuplicateOrderReason, fmt.Sprintf("Client order id %s used twice", dto.ClientOrderID), nil}
+		}
+
+		oh.mu.Lock()
+		existing, ok := oh.clientOrders[account][dto.ClientOrderID]
+		oh.mu.Unlock()
+
+		if ok && existing.dto != dto {
+			return &OrderError{events.DuplicateOrderReason, fmt.Sprintf("Client order id %s already used", dto.ClientOrderID), nil}
+		}
+		if ok {
+			// a resubmission repeats the result of the original request
+			if existing.err != nil {
+				return existing.err
+			}
+			view.clientOrders[dto.ClientOrderID] = existing.execution.Order.ID
+			return nil
+		}
+	}
+
+	order, err := validateCreate(dto)
+	if err != nil {
+		return &OrderError{events.InvalidOrderReason, err.Error(), getFieldErrors(err)}
+	}
+	order.Account = account
+	order.ClientOrderID = dto.ClientOrderID
+
+	err = checkInstrument(view.book, order.Symbol)
+	if err != nil {
+		return &OrderError{events.UnknownSymbolReason, err.Error(), nil}
+	}
+
+	err = checkHalted(view.book, order.Symbol)
+	if err != nil {
+		return &OrderError{events.SymbolHaltedReason, err.Error(), nil}
+	}
+
+	err = oh.checker.Check(view.book, order)
+	if err != nil {
+		return &OrderError{events.LimitExceededReason, err.Error(), nil}
+	}
+
+	view.book.Orders[order.ID] = order
+	view.book.Open(order)
+	if dto.ClientOrderID != "" {
+		view.clientOrders[dto.ClientOrderID] = order.ID
+	}
+	return nil
+}
+
+func getOrderFieldErrors(err error) []common_http.FieldError {
+	orderErr, ok := err.(*OrderError)
+	if !ok {
+		return nil
+	}
+	return orderErr.Fields
+}
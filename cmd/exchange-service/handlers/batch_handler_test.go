@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func getBatchResponse(handler *OrderHandler, uri string, instructions []BatchInstructionDTO) (*httptest.ResponseRecorder, []BatchResultResponse) {
+
+	encoded, _ := json.Marshal(instructions)
+
+	request, _ := http.NewRequest(http.MethodPost, uri, bytes.NewBuffer(encoded))
+	request.Header.Set("Content-Type", "application/json")
+	request = request.WithContext(common_http.WithAccount(request.Context(), "ACC1"))
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPost, "/orders/batch", common_http.DefaultPOSTJSONValidationMiddleware(handler.OrderBatchHandle))
+	router.ServeHTTP(response, request)
+
+	var results []BatchResultResponse
+	json.Unmarshal(response.Body.Bytes(), &results)
+
+	return response, results
+}
+
+func newBatchOrderHandler(book *models.OrderBook) *OrderHandler {
+	return NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{Amended: true}, &mocks.MockTrader{}, trading.NewOrderCanceller(&mocks.MockPublisher{}), &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+}
+
+func TestOrderBatchHandle(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	handler := newBatchOrderHandler(book)
+
+	instructions := []BatchInstructionDTO{
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.98, "Q1"}},
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.97, "Q2"}},
+		{AmendAction, OrderDTO{"", "TT", 20, models.BuyText, 1.98, "Q1"}},
+		{CancelAction, OrderDTO{ClientOrderID: "Q2"}},
+		{CancelAction, OrderDTO{ID: uuid.NewV4().String()}},
+		{"replace", OrderDTO{}},
+	}
+
+	response, results := getBatchResponse(handler, "/orders/batch", instructions)
+	require.Equal(http.StatusOK, response.Code)
+	require.Len(results, 6)
+
+	require.Equal(http.StatusAccepted, results[0].Status)
+	require.Equal("Q1", results[0].ClientOrderID)
+	require.NotEmpty(results[0].ID)
+	require.Equal(http.StatusAccepted, results[1].Status)
+	require.Equal(http.StatusAccepted, results[2].Status)
+	require.Equal(http.StatusAccepted, results[3].Status)
+	require.Equal(http.StatusNotFound, results[4].Status)
+	require.NotEmpty(results[4].Error)
+	require.Equal(http.StatusBadRequest, results[5].Status)
+
+	require.Len(book.Orders, 2)
+	require.Equal(models.Cancelled, book.Orders[uuid.FromStringOrNil(results[1].ID)].Status)
+	require.Equal(models.Pending, book.Orders[uuid.FromStringOrNil(results[0].ID)].Status)
+}
+
+func TestOrderBatchHandleAllOrNothing(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	other := models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Sell)
+	other.Account = "ACC2"
+	book.Orders[other.ID] = other
+
+	handler := newBatchOrderHandler(book)
+
+	instructions := []BatchInstructionDTO{
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.98, "Q1"}},
+		{AmendAction, OrderDTO{"", "TT", 20, models.BuyText, 1.98, "Q1"}},
+		{CancelAction, OrderDTO{ID: other.ID.String()}},
+		{CreateAction, OrderDTO{"", "TT", 10, "Up", 1.97, ""}},
+	}
+
+	response, results := getBatchResponse(handler, "/orders/batch?all_or_nothing=true", instructions)
+	require.Equal(http.StatusBadRequest, response.Code)
+	require.Len(results, 4)
+	require.Equal(http.StatusFailedDependency, results[0].Status)
+	require.Equal(http.StatusFailedDependency, results[1].Status)
+	require.Equal(http.StatusForbidden, results[2].Status)
+	require.Equal(http.StatusBadRequest, results[3].Status)
+	require.Len(book.Orders, 1)
+
+	response, results = getBatchResponse(handler, "/orders/batch?all_or_nothing=true", instructions[:2])
+	require.Equal(http.StatusOK, response.Code)
+	require.Equal(http.StatusAccepted, results[0].Status)
+	require.Equal(http.StatusAccepted, results[1].Status)
+	require.Len(book.Orders, 2)
+}
+
+func TestOrderBatchHandleAllOrNothingDuplicates(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	handler := newBatchOrderHandler(book)
+
+	id := uuid.NewV4().String()
+	instructions := []BatchInstructionDTO{
+		{CreateAction, OrderDTO{id, "TT", 10, models.BuyText, 1.98, ""}},
+		{CreateAction, OrderDTO{id, "TT", 10, models.BuyText, 1.97, ""}},
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.96, "Q1"}},
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.95, "Q1"}},
+	}
+
+	response, results := getBatchResponse(handler, "/orders/batch?all_or_nothing=true", instructions)
+	require.Equal(http.StatusBadRequest, response.Code)
+	require.Equal(http.StatusBadRequest, results[0].Status)
+	require.Equal(http.StatusBadRequest, results[1].Status)
+	require.Equal(http.StatusFailedDependency, results[2].Status)
+	require.Equal(http.StatusConflict, results[3].Status)
+	require.Empty(book.Orders)
+}
+
+func TestOrderBatchHandleBadRequest(t *testing.T) {
+
+	require := require.New(t)
+
+	handler := newBatchOrderHandler(models.NewOrderBook())
+
+	response, _ := getBatchResponse(handler, "/orders/batch", []BatchInstructionDTO{})
+	require.Equal(http.StatusBadRequest, response.Code)
+
+	response, _ = getBatchResponse(handler, "/orders/batch", make([]BatchInstructionDTO, MaxBatchSize+1))
+	require.Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestOrderBatchCost(t *testing.T) {
+
+	require := require.New(t)
+
+	cost := func(body string) (int, string) {
+		request, _ := http.NewRequest(http.MethodPost, "/orders/batch", bytes.NewBufferString(body))
+		n := OrderBatchCost(request)
+		restored, _ := ioutil.ReadAll(request.Body)
+		return n, string(restored)
+	}
+
+	body := `[{"action":"create"},{"action":"cancel"},{"action":"cancel"}]`
+	n, restored := cost(body)
+	require.Equal(3, n)
+	require.Equal(body, restored)
+
+	n, _ = cost(`[]`)
+	require.Equal(1, n)
+	n, _ = cost(`{"action":"create"}`)
+	require.Equal(1, n)
+}
+
+func TestOrderBatchHandleAllOrNothingLimits(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	checker := trading.NewAccountLimitChecker(trading.NewRiskCache(*models.NewAccountLimits("", 15, 0, 0.0, 0.0)))
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{Amended: true}, &mocks.MockTrader{}, trading.NewOrderCanceller(&mocks.MockPublisher{}),
+		checker, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+
+	instructions := []BatchInstructionDTO{
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.98, "Q1"}},
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.97, "Q2"}},
+	}
+
+	response, results := getBatchResponse(handler, "/orders/batch?all_or_nothing=true", instructions)
+	require.Equal(http.StatusBadRequest, response.Code)
+	require.Equal(http.StatusFailedDependency, results[0].Status)
+	require.Equal(http.StatusBadRequest, results[1].Status)
+	require.Empty(book.Orders)
+
+	instructions = []BatchInstructionDTO{
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.98, "Q1"}},
+		{CancelAction, OrderDTO{ClientOrderID: "Q1"}},
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.97, "Q2"}},
+	}
+
+	response, _ = getBatchResponse(handler, "/orders/batch?all_or_nothing=true", instructions)
+	require.Equal(http.StatusOK, response.Code)
+	require.Len(book.Orders, 2)
+}
+
+func TestOrderBatchHandleAllOrNothingAmends(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	handler := newBatchOrderHandler(book)
+
+	instructions := []BatchInstructionDTO{
+		{CreateAction, OrderDTO{"", "TT", 10, models.BuyText, 1.98, "Q1"}},
+		{AmendAction, OrderDTO{"", "TT", 20, models.BuyText, 1.97, "Q1"}},
+		{AmendAction, OrderDTO{"", "TT", 0, models.BuyText, 1.98, "Q1"}},
+		{AmendAction, OrderDTO{"", "TT", 5, models.BuyText, 1.98, "Q1"}},
+		{CancelAction, OrderDTO{ClientOrderID: "Q1"}},
+		{AmendAction, OrderDTO{"", "TT", 30, models.BuyText, 1.98, "Q1"}},
+	}
+
+	response, results := getBatchResponse(handler, "/orders/batch?all_or_nothing=true", instructions)
+	require.Equal(http.StatusBadRequest, response.Code)
+	require.Equal(http.StatusFailedDependency, results[0].Status)
+	require.Equal(http.StatusBadRequest, results[1].Status)
+	require.Equal(http.StatusBadRequest, results[2].Status)
+	require.NotEmpty(results[2].Errors)
+	require.Equal(http.StatusBadRequest, results[3].Status)
+	require.Equal(http.StatusFailedDependency, results[4].Status)
+	require.Equal(http.StatusBadRequest, results[5].Status)
+	require.Empty(book.Orders)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// BookSnapshotResponse returns the depth of a symbol as of the sequence of the book events
+type BookSnapshotResponse struct {
+	Symbol    string               `json:"symbol"`
+	Sequence  uint64               `json:"sequence"`
+	Bids      []marketdata.Level   `json:"bids"`
+	Asks      []marketdata.Level   `json:"asks"`
+	TopOfBook marketdata.TopOfBook `json:"top_of_book"`
+}
+
+// BookSnapshotHandler handles snapshot requests of consumers of the book events
+type BookSnapshotHandler struct {
+	publisher *marketdata.BookEventPublisher
+}
+
+// NewBookSnapshotHandler creates a new book snapshot handler
+func NewBookSnapshotHandler(publisher *marketdata.BookEventPublisher) *BookSnapshotHandler {
+	return &BookSnapshotHandler{publisher}
+}
+
+// GetSnapshotHandle is the handler for getting the snapshot of a symbol
+func (bsh *BookSnapshotHandler) GetSnapshotHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	symbol := strings.ToUpper(p.ByName("symbol"))
+
+	sequence, depth, top := bsh.publisher.Snapshot(symbol)
+
+	if sequence == 0 {
+		log.Printf("GetSnapshotHandle: Symbol %s not found", symbol)
+		common_http.WriteNotFound(w, r, fmt.Sprintf("Symbol %s not found", symbol))
+		return
+	}
+
+	encoded, _ := json.Marshal(BookSnapshotResponse{symbol, sequence, depth.Bids, depth.Asks, top})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetSnapshotHandle: Symbol %s returned at sequence %d", symbol, sequence)
+}
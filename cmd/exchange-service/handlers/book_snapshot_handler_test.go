@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func TestGetSnapshotHandle(t *testing.T) {
+
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	trading.NewOrderAppender().Append(book, models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Buy))
+
+	publisher := marketdata.NewBookEventPublisher(&mocks.MockPublisher{})
+	publisher.BookChanged(book, "TT")
+
+	handler := NewBookSnapshotHandler(publisher)
+
+	request, _ := http.NewRequest(http.MethodGet, "/snapshots/tt", nil)
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/snapshots/:symbol", common_http.DefaultGETValidationMiddleware(handler.GetSnapshotHandle))
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusOK, response.Code)
+
+	var snapshot BookSnapshotResponse
+	err := json.Unmarshal(response.Body.Bytes(), &snapshot)
+	require.Nil(err)
+	require.Equal("TT", snapshot.Symbol)
+	require.Equal(uint64(2), snapshot.Sequence)
+	require.Equal([]marketdata.Level{{Side: marketdata.BidSide, Price: 1.99, Quantity: 10, Orders: 1}}, snapshot.Bids)
+	require.Equal(1.99, snapshot.TopOfBook.BidPrice)
+}
+
+func TestGetSnapshotHandleNotFound(t *testing.T) {
+
+	require := require.New(t)
+
+	handler := NewBookSnapshotHandler(marketdata.NewBookEventPublisher(&mocks.MockPublisher{}))
+
+	request, _ := http.NewRequest(http.MethodGet, "/snapshots/TT", nil)
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/snapshots/:symbol", common_http.DefaultGETValidationMiddleware(handler.GetSnapshotHandle))
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusNotFound, response.Code)
+}
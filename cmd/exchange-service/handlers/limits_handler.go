@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/data"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// AccountLimitsDTO model
+type AccountLimitsDTO struct {
+	Account          string  `json:"account"`            // account
+	MaxLong          uint    `json:"max_long"`           // maximum long position per symbol, zero for no limit
+	MaxShort         uint    `json:"max_short"`          // maximum short position per symbol, zero for no limit
+	MaxGrossExposure float64 `json:"max_gross_exposure"` // maximum gross exposure, zero for no limit
+	DailyLossLimit   float64 `json:"daily_loss_limit"`   // daily realized loss limit, zero for no limit
+}
+
+// AccountPositionResponse returns the position of a account in a symbol
+type AccountPositionResponse struct {
+	Symbol      string    `json:"symbol"`
+	Quantity    int       `json:"quantity"`
+	Price       float64   `json:"price"`
+	RealizedPnL float64   `json:"realized_pnl"`
+	DailyPnL    float64   `json:"daily_pnl"`
+	Updated     time.Time `json:"updated"`
+}
+
+// LimitsHandler handles the account limits administration, the limits are stored before they are applied
+type LimitsHandler struct {
+	cache     *trading.RiskCache
+	repo      data.RiskRepository
+	publisher events.EventPublisher
+}
+
+// NewLimitsHandler creates a new limits handler
+func NewLimitsHandler(cache *trading.RiskCache, repo data.RiskRepository, publisher events.EventPublisher) *LimitsHandler {
+	return &LimitsHandler{cache, repo, publisher}
+}
+
+// GetLimitsHandle is the handler for getting all explicitly set account limits
+func (lh *LimitsHandler) GetLimitsHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	limits := lh.cache.AllLimits()
+
+	response := make([]AccountLimitsDTO, 0, len(limits))
+
+	for _, l := range limits {
+		response = append(response, getAccountLimitsDTO(l))
+	}
+
+	encoded, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetLimitsHandle: Returned %d account limits", len(response))
+}
+
+// GetAccountLimitsHandle is the handler for getting the effective limits of a account
+func (lh *LimitsHandler) GetAccountLimitsHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	account := p.ByName("account")
+
+	encoded, _ := json.Marshal(getAccountLimitsDTO(lh.cache.Limits(account)))
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetAccountLimitsHandle: Account %s limits returned", account)
+}
+
+// GetAccountPositionsHandle is the handler for getting the cached positions of a account
+func (lh *LimitsHandler) GetAccountPositionsHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	account := p.ByName("account")
+
+	positions := lh.cache.Positions(account)
+
+	response := make([]AccountPositionResponse, 0, len(positions))
+
+	for _, pos := range positions {
+		response = append(response, AccountPositionResponse{pos.Symbol, pos.Quantity, pos.Price, pos.RealizedPnL, pos.DailyPnL, pos.Updated})
+	}
+
+	encoded, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetAccountPositionsHandle: Returned %d positions of account %s", len(response), account)
+}
+
+// SetLimitsHandle is the handler for setting the limits of a account
+func (lh *LimitsHandler) SetLimitsHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	var dto AccountLimitsDTO
+
+	err := json.NewDecoder(r.Body).Decode(&dto)
+	if err != nil {
+		log.Printf("Failed to bind model! %s", err)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	if dto.Account == "" || dto.MaxGrossExposure < 0 || dto.DailyLossLimit < 0 {
+		log.Printf("SetLimitsHandle: Invalid limits %v", dto)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.ValidationFailedCode, "Account is required and limits must not be negative")
+		return
+	}
+
+	limits := models.NewAccountLimits(dto.Account, dto.MaxLong, dto.MaxShort, dto.MaxGrossExposure, dto.DailyLossLimit)
+
+	err = lh.repo.SaveLimits(*limits)
+	if err != nil {
+		log.Printf("SetLimitsHandle: Failed to save limits %s! %s", limits, err)
+		common_http.WriteError(w, r, http.StatusInternalServerError, common_http.InternalErrorCode, "Failed to save limits")
+		return
+	}
+
+	lh.cache.SetLimits(limits)
+	lh.publish(events.NewAccountLimitsUpdated(limits, time.Now().UTC(), 1))
+
+	w.WriteHeader(http.StatusAccepted)
+	log.Printf("SetLimitsHandle: Limits %s set", limits)
+}
+
+// RemoveLimitsHandle is the handler for removing the limits of a account
+func (lh *LimitsHandler) RemoveLimitsHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	account := p.ByName("account")
+
+	err := lh.repo.RemoveLimits(account)
+	if err != nil {
+		log.Printf("RemoveLimitsHandle: Failed to remove account %s limits! %s", account, err)
+		common_http.WriteError(w, r, http.StatusInternalServerError, common_http.InternalErrorCode, "Failed to remove limits")
+		return
+	}
+
+	if !lh.cache.RemoveLimits(account) {
+		log.Printf("RemoveLimitsHandle: Account %s limits not found", account)
+		common_http.WriteNotFound(w, r, fmt.Sprintf("Limits of account %s not found", account))
+		return
+	}
+
+	lh.publish(events.NewAccountLimitsRemoved(account, time.Now().UTC(), 1))
+
+	w.WriteHeader(http.StatusAccepted)
+	log.Printf("RemoveLimitsHandle: Account %s limits removed", account)
+}
+
+func (lh *LimitsHandler) publish(event *events.AccountLimitsUpdated) {
+
+	envelope, err := events.NewOrderEventEnvelope(event, event.EventType)
+	if err != nil {
+		log.Printf("Failed to create account limits updated event envelope! %s", err)
+		return
+	}
+
+	err = lh.publisher.Publish(envelope)
+	if err != nil {
+		log.Printf("Failed to publish account limits updated event: %s", event.String())
+	}
+}
+
+func getAccountLimitsDTO(limits models.AccountLimits) AccountLimitsDTO {
+	return AccountLimitsDTO{limits.Account, limits.MaxLong, limits.MaxShort, limits.MaxGrossExposure, limits.DailyLossLimit}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func TestSetLimitsHandle(t *testing.T) {
+
+	require := require.New(t)
+	cache := trading.NewRiskCache(*models.NewAccountLimits("", 10, 10, 100.0, 10.0))
+	repo := &mocks.MockRiskRepository{}
+	publisher := &mocks.MockPublisher{}
+
+	handler := NewLimitsHandler(cache, repo, publisher)
+
+	encoded, _ := json.Marshal(AccountLimitsDTO{"ACC1", 100, 50, 1000.0, 20.0})
+
+	request, _ := http.NewRequest(http.MethodPut, "/admin/limits", bytes.NewBuffer(encoded))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPut, "/admin/limits", common_http.DefaultPUTJSONValidationMiddleware(handler.SetLimitsHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusAccepted, response.Code)
+	require.Equal(*models.NewAccountLimits("ACC1", 100, 50, 1000.0, 20.0), cache.Limits("ACC1"))
+	require.Equal(cache.Limits("ACC1"), repo.Limits["ACC1"])
+	require.Len(publisher.Envelopes, 1)
+	require.Equal(events.AccountLimitsUpdatedType, publisher.Envelopes[0].EventType)
+}
+
+func TestSetLimitsHandleSaveFailed(t *testing.T) {
+
+	require := require.New(t)
+	cache := trading.NewRiskCache(*models.NewAccountLimits("", 10, 10, 100.0, 10.0))
+	publisher := &mocks.MockPublisher{}
+
+	handler := NewLimitsHandler(cache, &mocks.MockRiskRepository{Err: errors.New("connection refused")}, publisher)
+
+	encoded, _ := json.Marshal(AccountLimitsDTO{"ACC1", 100, 50, 1000.0, 20.0})
+
+	request, _ := http.NewRequest(http.MethodPut, "/admin/limits", bytes.NewBuffer(encoded))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPut, "/admin/limits", common_http.DefaultPUTJSONValidationMiddleware(handler.SetLimitsHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusInternalServerError, response.Code)
+	require.Empty(cache.AllLimits())
+	require.Empty(publisher.Envelopes)
+}
+
+func TestSetLimitsHandleBadRequest(t *testing.T) {
+
+	require := require.New(t)
+	cache := trading.NewRiskCache(*models.NewAccountLimits("", 10, 10, 100.0, 10.0))
+
+	handler := NewLimitsHandler(cache, &mocks.MockRiskRepository{}, &mocks.MockPublisher{})
+
+	encoded, _ := json.Marshal(AccountLimitsDTO{"", 100, 50, 1000.0, 20.0})
+
+	request, _ := http.NewRequest(http.MethodPut, "/admin/limits", bytes.NewBuffer(encoded))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPut, "/admin/limits", common_http.DefaultPUTJSONValidationMiddleware(handler.SetLimitsHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestGetAccountLimitsHandleDefaults(t *testing.T) {
+
+	require := require.New(t)
+	cache := trading.NewRiskCache(*models.NewAccountLimits("", 10, 10, 100.0, 10.0))
+
+	handler := NewLimitsHandler(cache, &mocks.MockRiskRepository{}, &mocks.MockPublisher{})
+
+	request, _ := http.NewRequest(http.MethodGet, "/admin/limits/ACC1", nil)
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/admin/limits/:account", common_http.DefaultGETValidationMiddleware(handler.GetAccountLimitsHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusOK, response.Code)
+
+	var dto AccountLimitsDTO
+	err := json.Unmarshal(response.Body.Bytes(), &dto)
+	require.Nil(err)
+	require.Equal(AccountLimitsDTO{"ACC1", 10, 10, 100.0, 10.0}, dto)
+}
+
+func TestRemoveLimitsHandle(t *testing.T) {
+
+	require := require.New(t)
+	cache := trading.NewRiskCache(*models.NewAccountLimits("", 10, 10, 100.0, 10.0))
+	cache.SetLimits(models.NewAccountLimits("ACC1", 100, 50, 1000.0, 20.0))
+	repo := &mocks.MockRiskRepository{Limits: map[string]models.AccountLimits{"ACC1": cache.Limits("ACC1")}}
+	publisher := &mocks.MockPublisher{}
+
+	handler := NewLimitsHandler(cache, repo, publisher)
+
+	router := httprouter.New()
+	router.Handle(http.MethodDelete, "/admin/limits/:account", common_http.DefaultDELETEValidationMiddleware(handler.RemoveLimitsHandle))
+
+	request, _ := http.NewRequest(http.MethodDelete, "/admin/limits/ACC1", nil)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusAccepted, response.Code)
+	require.Len(cache.AllLimits(), 0)
+	require.Empty(repo.Limits)
+	require.Len(publisher.Envelopes, 1)
+
+	request, _ = http.NewRequest(http.MethodDelete, "/admin/limits/ACC1", nil)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusNotFound, response.Code)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/julienschmidt/httprouter"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+)
+
+// Subscription actions
+const (
+	SubscribeAction   = "subscribe"
+	UnsubscribeAction = "unsubscribe"
+)
+
+// SubscriptionRequest defines a (un)subscription of a market data channel
+type SubscriptionRequest struct {
+	Action  string `json:"action"`  // subscribe or unsubscribe
+	Channel string `json:"channel"` // l1, l2, l3 or trades
+	Symbol  string `json:"symbol"`  // symbol
+}
+
+// MarketDataHandler streams market data over websockets
+type MarketDataHandler struct {
+	feed       *marketdata.Feed
+	upgrader   websocket.Upgrader
+	bufferSize int
+}
+
+// NewMarketDataHandler creates a new market data handler, connections which fall behind bufferSize messages are closed
+func NewMarketDataHandler(feed *marketdata.Feed, bufferSize int) *MarketDataHandler {
+	return &MarketDataHandler{feed, websocket.Upgrader{}, bufferSize}
+}
+
+// MarketDataHandle upgrades the connection and handles the subscriptions
+func (mdh *MarketDataHandler) MarketDataHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	conn, err := mdh.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("MarketDataHandle: Failed to upgrade connection! %s", err)
+		return
+	}
+
+	subscriber := marketdata.NewSubscriber(mdh.bufferSize)
+	go mdh.write(conn, subscriber)
+
+	defer mdh.feed.Remove(subscriber)
+
+	for {
+		var request SubscriptionRequest
+
+		err := conn.ReadJSON(&request)
+		if err != nil {
+			log.Printf("MarketDataHandle: Connection closed. %s", err)
+			return
+		}
+
+		channel := marketdata.Channel(request.Channel)
+
+		switch request.Action {
+		case SubscribeAction:
+			err = mdh.feed.Subscribe(subscriber, channel, request.Symbol)
+			if err != nil {
+				log.Printf("MarketDataHandle: Failed to subscribe! %s", err)
+				subscriber.Send(&marketdata.Message{Channel: channel, Type: marketdata.ErrorMessage, Symbol: request.Symbol, Data: err.Error()})
+			}
+		case UnsubscribeAction:
+			mdh.feed.Unsubscribe(subscriber, channel, request.Symbol)
+		default:
+			log.Printf("MarketDataHandle: Action %s not supported", request.Action)
+			subscriber.Send(&marketdata.Message{Channel: channel, Type: marketdata.ErrorMessage, Symbol: request.Symbol, Data: "Action not supported"})
+		}
+	}
+}
+
+func (mdh *MarketDataHandler) write(conn *websocket.Conn, subscriber *marketdata.Subscriber) {
+
+	defer conn.Close()
+
+	for message := range subscriber.Messages() {
+		err := conn.WriteJSON(message)
+		if err != nil {
+			log.Printf("MarketDataHandle: Failed to write message! %s", err)
+			mdh.feed.Remove(subscriber)
+			return
+		}
+	}
+}
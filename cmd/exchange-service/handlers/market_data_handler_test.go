@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/julienschmidt/httprouter"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func getMarketDataConnection(feed *marketdata.Feed) (*websocket.Conn, func(), error) {
+
+	handler := NewMarketDataHandler(feed, 10)
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/marketdata", common_http.DefaultGETValidationMiddleware(handler.MarketDataHandle))
+
+	server := httptest.NewServer(router)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/marketdata", nil)
+	if err != nil {
+		server.Close()
+		return nil, nil, err
+	}
+
+	return conn, func() { conn.Close(); server.Close() }, nil
+}
+
+func TestMarketDataHandleSnapshotThenUpdates(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	appender := trading.NewOrderAppender()
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Buy))
+
+	feed := marketdata.NewFeed(10)
+	feed.BookChanged(book, "TT")
+
+	conn, closer, err := getMarketDataConnection(feed)
+	require.Nil(err)
+	defer closer()
+
+	err = conn.WriteJSON(SubscriptionRequest{SubscribeAction, "l1", "TT"})
+	require.Nil(err)
+
+	var snapshot map[string]interface{}
+	err = conn.ReadJSON(&snapshot)
+	require.Nil(err)
+	require.Equal("snapshot", snapshot["type"])
+	require.Equal("l1", snapshot["channel"])
+	require.Equal(1.0, snapshot["sequence"])
+	require.Equal(1.99, snapshot["data"].(map[string]interface{})["bid_price"])
+
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 2.99, 5, models.Sell))
+	feed.BookChanged(book, "TT")
+
+	var update map[string]interface{}
+	err = conn.ReadJSON(&update)
+	require.Nil(err)
+	require.Equal("update", update["type"])
+	require.Equal(2.0, update["sequence"])
+	require.Equal(2.99, update["data"].(map[string]interface{})["ask_price"])
+}
+
+func TestMarketDataHandleUnknownChannel(t *testing.T) {
+
+	require := require.New(t)
+
+	conn, closer, err := getMarketDataConnection(marketdata.NewFeed(10))
+	require.Nil(err)
+	defer closer()
+
+	err = conn.WriteJSON(SubscriptionRequest{SubscribeAction, "l4", "TT"})
+	require.Nil(err)
+
+	var message map[string]interface{}
+	err = conn.ReadJSON(&message)
+	require.Nil(err)
+	require.Equal("error", message["type"])
+}
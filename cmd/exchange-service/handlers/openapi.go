@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// APIVersion is the version of the exchange api in the OpenAPI document
+const APIVersion = "1.0.0"
+
+// NewOpenAPI creates the OpenAPI document of the order and order book routes of the exchange
+func NewOpenAPI() *common_http.OpenAPI {
+
+	doc := common_http.NewOpenAPI("tradsim exchange", APIVersion)
+
+	for name, schema := range getSchemas() {
+		doc.Components.Schemas[name] = schema
+	}
+
+	doc.Components.SecuritySchemes = map[string]*common_http.SecurityScheme{
+		"apiKey":     {Type: "apiKey", Name: common_http.APIKeyHeader, In: "header"},
+		"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+	}
+	doc.Security = []map[string][]string{{"apiKey": {}}, {"bearerAuth": {}}}
+
+	orderID := &common_http.Parameter{Name: "orderid", In: "path", Required: true, Schema: &common_http.Schema{Type: "string", Format: "uuid"}}
+	orderBody := &common_http.RequestBody{Required: true, Content: common_http.JSONContent(common_http.RefSchema("OrderDTO"))}
+
+	doc.Paths["/orders"] = &common_http.PathItem{
+		Get: &common_http.Operation{
+			OperationID: "listOrders",
+			Summary:     "List the orders of the caller, admins list the orders of every account",
+			Parameters: []*common_http.Parameter{
+				{Name: "symbol", In: "query", Schema: &common_http.Schema{Type: "string"}},
+				{Name: "status", In: "query", Schema: &common_http.Schema{Type: "string", Enum: getStatusTexts()}},
+				{Name: "direction", In: "query", Schema: &common_http.Schema{Type: "string", Enum: []string{models.BuyText, models.SellText}}},
+				{Name: "account", In: "query", Schema: &common_http.Schema{Type: "string"}},
+				{Name: "client_order_id", In: "query", Schema: &common_http.Schema{Type: "string"}},
+				{Name: "after", In: "query", Description: "order id of the previous page", Schema: &common_http.Schema{Type: "string", Format: "uuid"}},
+				{Name: "limit", In: "query", Schema: &common_http.Schema{Type: "integer", Minimum: float(1), Maximum: float(MaxOrdersLimit)}},
+			},
+			Responses: getResponses(http.StatusOK, "Page of orders", "OrdersResponse", http.StatusBadRequest, http.StatusForbidden),
+		},
+		Post: &common_http.Operation{
+			OperationID: "createOrder",
+			Summary:     "Create a order, with sync=true the order is executed before the response",
+			Parameters: []*common_http.Parameter{
+				{Name: "sync", In: "query", Schema: &common_http.Schema{Type: "boolean"}},
+			},
+			RequestBody: orderBody,
+			Responses: mergeResponses(
+				getResponses(http.StatusAccepted, "Order accepted", "OrderIDResponse", http.StatusBadRequest, http.StatusConflict),
+				getResponses(http.StatusOK, "Execution report of the sync request", "ExecutionReportResponse")),
+		},
+		Put: &common_http.Operation{
+			OperationID: "amendOrder",
+			Summary:     "Amend a order by id or client order id",
+			RequestBody: orderBody,
+			Responses:   getResponses(http.StatusAccepted, "Amend accepted", "", http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound),
+		},
+		Delete: &common_http.Operation{
+			OperationID: "cancelOrderByClientOrderID",
+			Summary:     "Cancel a order by client order id",
+			Parameters: []*common_http.Parameter{
+				{Name: "client_order_id", In: "query", Required: true, Schema: &common_http.Schema{Type: "string"}},
+			},
+			Responses: getResponses(http.StatusAccepted, "Cancel accepted", "", http.StatusBadRequest, http.StatusNotFound),
+		},
+	}
+
+	doc.Paths["/orders/{orderid}"] = &common_http.PathItem{
+		Get: &common_http.Operation{
+			OperationID: "getOrder",
+			Summary:     "Get a order",
+			Parameters:  []*common_http.Parameter{orderID},
+			Responses:   getResponses(http.StatusOK, "Order", "OrderResponse", http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound),
+		},
+		Delete: &common_http.Operation{
+			OperationID: "cancelOrder",
+			Summary:     "Cancel a order",
+			Parameters:  []*common_http.Parameter{orderID},
+			Responses:   getResponses(http.StatusAccepted, "Cancel accepted", "", http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound),
+		},
+	}
+
+	doc.Paths["/orderbook"] = &common_http.PathItem{
+		Get: &common_http.Operation{
+			OperationID: "listSymbols",
+			Summary:     "List the symbols of the order book with their price levels",
+			Responses: map[string]*common_http.Response{
+				strconv.Itoa(http.StatusOK):       {Description: "Symbols", Content: common_http.JSONContent(common_http.ArraySchema(common_http.RefSchema("SymbolResponse")))},
+				strconv.Itoa(http.StatusNotFound): common_http.ProblemResponse(http.StatusText(http.StatusNotFound)),
+			},
+		},
+	}
+
+	doc.Paths["/orderbook/{symbol}"] = &common_http.PathItem{
+		Get: &common_http.Operation{
+			OperationID: "getSymbol",
+			Summary:     "Get the price levels of a symbol",
+			Parameters: []*common_http.Parameter{
+				{Name: "symbol", In: "path", Required: true, Schema: &common_http.Schema{Type: "string"}},
+			},
+			Responses: getResponses(http.StatusOK, "Symbol", "SymbolResponse", http.StatusNotFound),
+		},
+	}
+
+	return doc
+}
+
+func getSchemas() map[string]*common_http.Schema {
+
+	str := func(description string) *common_http.Schema {
+		return &common_http.Schema{Type: "string", Description: description}
+	}
+	integer := &common_http.Schema{Type: "integer", Minimum: float(0)}
+	number := &common_http.Schema{Type: "number"}
+	direction := &common_http.Schema{Type: "string", Enum: []string{models.BuyText, models.SellText}}
+	id := &common_http.Schema{Type: "string", Format: "uuid"}
+
+	return map[string]*common_http.Schema{
+		"OrderDTO": common_http.ObjectSchema([]string{"symbol", "quantity", "direction", "price"}, map[string]*common_http.Schema{
+			"id":              {Type: "string", Format: "uuid", Description: "id of the order to amend, assigned by the exchange on create"},
+			"symbol":          {Type: "string", MinLength: length(1)},
+			"quantity":        {Type: "integer", Minimum: float(0), ExclusiveMinimum: true},
+			"direction":       direction,
+			"price":           {Type: "number", Minimum: float(0), ExclusiveMinimum: true},
+			"client_order_id": str("id of the client, unique per account in a session"),
+		}),
+		"OrderIDResponse": common_http.ObjectSchema([]string{"id"}, map[string]*common_http.Schema{
+			"id":              id,
+			"client_order_id": str(""),
+		}),
+		"OrderResponse": common_http.ObjectSchema([]string{"id", "account", "symbol", "direction", "price", "quantity", "traded", "remaining", "status"}, map[string]*common_http.Schema{
+			"id":              id,
+			"client_order_id": str(""),
+			"account":         str(""),
+			"symbol":          str(""),
+			"direction":       direction,
+			"price":           number,
+			"quantity":        integer,
+			"traded":          integer,
+			"remaining":       integer,
+			"status":          {Type: "string", Enum: getStatusTexts()},
+		}),
+		"OrdersResponse": common_http.ObjectSchema([]string{"orders", "next_after"}, map[string]*common_http.Schema{
+			"orders":     common_http.ArraySchema(common_http.RefSchema("OrderResponse")),
+			"next_after": str("order id to use as after for the next page"),
+		}),
+		"FillResponse": common_http.ObjectSchema([]string{"trade_id", "counterparty_order_id", "price", "quantity", "time"}, map[string]*common_http.Schema{
+			"trade_id":              id,
+			"counterparty_order_id": id,
+			"price":                 number,
+			"quantity":              integer,
+			"time":                  {Type: "string", Format: "date-time"},
+		}),
+		"ExecutionReportResponse": common_http.ObjectSchema([]string{"order", "fills"}, map[string]*common_http.Schema{
+			"order": common_http.RefSchema("OrderResponse"),
+			"fills": common_http.ArraySchema(common_http.RefSchema("FillResponse")),
+		}),
+		"SymbolPriceResponse": common_http.ObjectSchema([]string{"price", "buy_quantity", "buy_depth", "sell_quantity", "sell_depth"}, map[string]*common_http.Schema{
+			"price":         number,
+			"buy_quantity":  integer,
+			"buy_depth":     integer,
+			"sell_quantity": integer,
+			"sell_depth":    integer,
+		}),
+		"SymbolResponse": common_http.ObjectSchema([]string{"symbol", "prices"}, map[string]*common_http.Schema{
+			"symbol": str(""),
+			"prices": common_http.ArraySchema(common_http.RefSchema("SymbolPriceResponse")),
+		}),
+	}
+}
+
+// getResponses returns the success response of the schema, empty for no content, and problem responses of the error statuses
+func getResponses(status int, description string, schema string, errorStatuses ...int) map[string]*common_http.Response {
+
+	success := &common_http.Response{Description: description}
+	if schema != "" {
+		success.Content = common_http.JSONContent(common_http.RefSchema(schema))
+	}
+
+	responses := map[string]*common_http.Response{strconv.Itoa(status): success}
+
+	errorStatuses = append(errorStatuses, http.StatusUnauthorized, http.StatusTooManyRequests)
+	for _, errorStatus := range errorStatuses {
+		responses[strconv.Itoa(errorStatus)] = common_http.ProblemResponse(http.StatusText(errorStatus))
+	}
+
+	return responses
+}
+
+func mergeResponses(responses ...map[string]*common_http.Response) map[string]*common_http.Response {
+
+	merged := make(map[string]*common_http.Response)
+	for _, r := range responses {
+		for status, response := range r {
+			merged[status] = response
+		}
+	}
+	return merged
+}
+
+func getStatusTexts() []string {
+	return []string{models.PendingText, models.PartiallyFilledText, models.FullyFilledText, models.OverFilledText, models.CancelledText, models.RejectedText}
+}
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func length(value int) *int {
+	return &value
+}
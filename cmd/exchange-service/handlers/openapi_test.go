@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func getJSONValue(v interface{}) interface{} {
+	encoded, _ := json.Marshal(v)
+	var value interface{}
+	json.Unmarshal(encoded, &value)
+	return value
+}
+
+func collectRefs(value interface{}, refs map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if key == "$ref" {
+				refs[strings.TrimPrefix(child.(string), "#/components/schemas/")] = true
+			}
+			collectRefs(child, refs)
+		}
+	case []interface{}:
+		for _, child := range v {
+			collectRefs(child, refs)
+		}
+	}
+}
+
+func TestNewOpenAPIReferences(t *testing.T) {
+
+	require := require.New(t)
+
+	doc := NewOpenAPI()
+
+	for _, path := range []string{"/orders", "/orders/{orderid}", "/orderbook", "/orderbook/{symbol}"} {
+		require.Contains(doc.Paths, path)
+	}
+
+	refs := make(map[string]bool)
+	collectRefs(getJSONValue(doc), refs)
+	require.NotEmpty(refs)
+
+	for ref := range refs {
+		require.Contains(doc.Components.Schemas, ref)
+	}
+}
+
+func TestNewOpenAPIMatchesDTOs(t *testing.T) {
+
+	require := require.New(t)
+
+	doc := NewOpenAPI()
+
+	require.Empty(doc.Validate(common_http.RefSchema("OrderDTO"), "", getJSONValue(OrderDTO{"", "TT", 10, models.BuyText, 1.99, "C1"})))
+	require.Empty(doc.Validate(common_http.RefSchema("OrderDTO"), "", getJSONValue(OrderDTO{uuid.NewV4().String(), "TT", 10, models.SellText, 1.99, ""})))
+	require.Len(doc.Validate(common_http.RefSchema("OrderDTO"), "", getJSONValue(OrderDTO{"1", "", 0, "Up", 0, ""})), 5)
+
+	order := models.NewOrderFull(uuid.NewV4(), "TT", 1.99, 10, 4, models.Sell, models.PartiallyFilled)
+	require.Empty(doc.Validate(common_http.RefSchema("OrdersResponse"), "", getJSONValue(OrdersResponse{[]OrderResponse{getOrderResponse(order)}, order.ID.String()})))
+
+	symbol := getSymbolResponse("TT", []*models.OrderPrice{models.NewOrderPrice(1.99)})
+	require.Empty(doc.Validate(common_http.RefSchema("SymbolResponse"), "", getJSONValue(symbol)))
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
 )
 
 // SymbolPriceResponse returns a price and thq buy sell quantities
@@ -44,14 +46,16 @@ func (obh *OrderBookHandler) GetSymbolsHandler(w http.ResponseWriter, r *http.Re
 
 	var symbols []SymbolResponse
 
+	obh.book.RLock()
 	for key, prices := range obh.book.Symbols {
 
 		symbols = append(symbols, getSymbolResponse(key, prices))
 	}
+	obh.book.RUnlock()
 
 	if len(symbols) == 0 {
 		log.Printf("GetSymbolsHandler: Symbols not found")
-		http.NotFound(w, r)
+		common_http.WriteNotFound(w, r, "Symbols not found")
 		return
 	}
 
@@ -68,16 +72,17 @@ func (obh *OrderBookHandler) GetSymbolHandler(w http.ResponseWriter, r *http.Req
 
 	log.Printf("GetSymbolHandler: Request %s", symbol)
 
+	obh.book.RLock()
 	prices, ok := obh.book.Symbols[symbol]
+	response := getSymbolResponse(symbol, prices)
+	obh.book.RUnlock()
 
 	if !ok {
 		log.Printf("GetSymbolHandler: Symbol %s not found", symbol)
-		http.NotFound(w, r)
+		common_http.WriteNotFound(w, r, fmt.Sprintf("Symbol %s not found", symbol))
 		return
 	}
 
-	response := getSymbolResponse(symbol, prices)
-
 	encoded, _ := json.Marshal(response)
 	w.Header().Set("Content-Type", "application/json")
 	w.Write(encoded)
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
@@ -12,133 +14,571 @@ import (
 	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
 	"github.com/tradsim/tradsim-go/events"
 	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
 )
 
 // OrderDTO model
 type OrderDTO struct {
-	ID        string  `json:"id"`        // unique id (uuid)
-	Symbol    string  `json:"symbol"`    // symbol
-	Quantity  uint    `json:"quantity"`  // quantity
-	Direction string  `json:"direction"` // buy or sell
-	Price     float64 `json:"price"`     // price
+	ID            string  `json:"id,omitempty"`              // unique id (uuid) of the order to amend, assigned by the exchange on create
+	Symbol        string  `json:"symbol"`                    // symbol
+	Quantity      uint    `json:"quantity"`                  // quantity
+	Direction     string  `json:"direction"`                 // buy or sell
+	Price         float64 `json:"price"`                     // price
+	ClientOrderID string  `json:"client_order_id,omitempty"` // id of the client, unique per account in a session
 }
 
-// OrderHandler handles orders
+// OrderIDResponse returns the ids of a created order
+type OrderIDResponse struct {
+	ID            string `json:"id"`
+	ClientOrderID string `json:"client_order_id,omitempty"`
+}
+
+// FillResponse returns a trade of a order
+type FillResponse struct {
+	TradeID             string    `json:"trade_id"`
+	CounterpartyOrderID string    `json:"counterparty_order_id"`
+	Price               float64   `json:"price"`
+	Quantity            uint      `json:"quantity"`
+	Time                time.Time `json:"time"`
+}
+
+// ExecutionReportResponse returns the state of a order after its create request and the fills of the request
+type ExecutionReportResponse struct {
+	Order OrderResponse  `json:"order"`
+	Fills []FillResponse `json:"fills"`
+}
+
+// OrderExecution defines a created order as it was after its matching and the trades produced by the matching
+type OrderExecution struct {
+	Order  *models.Order
+	Trades []*models.Trade
+}
+
+// clientOrder remembers the request and the result of a client order id
+type clientOrder struct {
+	dto       OrderDTO
+	execution *OrderExecution
+	err       error
+}
+
+// OrderHandler handles orders, the requests hold the book lock while they read or update the book
+// and the mutex guards the client orders
 type OrderHandler struct {
-	book      *models.OrderBook
-	appender  trading.Appender
-	amender   trading.Amender
-	trader    trading.Trader
-	canceller trading.Canceller
-	publisher events.EventPublisher
+	book         *models.OrderBook
+	appender     trading.Appender
+	amender      trading.Amender
+	trader       trading.Trader
+	canceller    trading.Canceller
+	checker      trading.LimitChecker
+	listener     trading.BookListener
+	publisher    events.EventPublisher
+	clientOrders map[string]map[string]*clientOrder
+	mu           sync.Mutex
 }
 
 // NewOrderHandler creates a new order handler
-func NewOrderHandler(book *models.OrderBook, appender trading.Appender, amender trading.Amender, trader trading.Trader, canceller trading.Canceller, publisher events.EventPublisher) *OrderHandler {
-	return &OrderHandler{book, appender, amender, trader, canceller, publisher}
+func NewOrderHandler(book *models.OrderBook, appender trading.Appender, amender trading.Amender, trader trading.Trader, canceller trading.Canceller, checker trading.LimitChecker, listener trading.BookListener, publisher events.EventPublisher) *OrderHandler {
+	return &OrderHandler{book, appender, amender, trader, canceller, checker, listener, publisher, make(map[string]map[string]*clientOrder), sync.Mutex{}}
+}
+
+// OrderError defines a rejected order request, invalid orders carry the errors of their fields
+type OrderError struct {
+	Reason events.RejectReason
+	Text   string
+	Fields []common_http.FieldError
+}
+
+func (e *OrderError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Reason, e.Text)
 }
 
-// OrderCreateHandle is the handler for the orders
+// OrderCreateHandle is the handler for the orders, it responds with the ids of the order.
+// With the query parameter sync=true it responds with the execution report of the order instead.
 func (oh *OrderHandler) OrderCreateHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 
-	dto, direction, orderID, err := oh.getPayloadData(r)
+	dto, err := decodeOrder(r)
+	if err != nil {
+		oh.publishRejected("", getAccount(r), events.InvalidOrderReason, err.Error())
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	execution, err := oh.ExecuteOrder(getAccount(r), dto)
+	if err != nil {
+		writeOrderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Query().Get("sync") != "true" {
+		encoded, _ := json.Marshal(OrderIDResponse{execution.Order.ID.String(), execution.Order.ClientOrderID})
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(encoded)
+		return
+	}
+
+	encoded, _ := json.Marshal(getExecutionReportResponse(execution))
+	w.Write(encoded)
+}
+
+// OrderAmendHandle is the handler for amending a order
+func (oh *OrderHandler) OrderAmendHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	dto, err := decodeOrder(r)
+	if err != nil {
+		oh.publishRejected("", getAccount(r), events.AmendFailedReason, err.Error())
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	err = oh.AmendOrder(getAccount(r), dto)
+	if err != nil {
+		writeOrderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// OrderCancelHandle is the handler for cancelling a order
+func (oh *OrderHandler) OrderCancelHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	err := oh.CancelOrder(getAccount(r), p.ByName("orderid"))
+	if err != nil {
+		writeOrderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// OrderCancelByClientOrderIDHandle is the handler for cancelling a order by the client_order_id query parameter
+func (oh *OrderHandler) OrderCancelByClientOrderIDHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	err := oh.CancelClientOrder(getAccount(r), r.URL.Query().Get("client_order_id"))
 	if err != nil {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		writeOrderError(w, r, err)
 		return
 	}
 
-	order := models.NewOrder(orderID, dto.Symbol, dto.Price, dto.Quantity, direction)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ExecuteOrder checks, trades and appends a new order of the account and returns the order with its trades.
+// The exchange assigns the order id, a request carrying a id is rejected.
+// A request repeating the client order id and payload of a previous request returns the original result,
+// a request reusing the client order id with another payload is rejected.
+// The book is locked from the checks until the order is in the book.
+func (oh *OrderHandler) ExecuteOrder(account string, dto OrderDTO) (*OrderExecution, error) {
+
+	oh.book.Lock()
+	defer oh.book.Unlock()
+
+	return oh.executeClientOrder(account, dto)
+}
+
+func (oh *OrderHandler) executeClientOrder(account string, dto OrderDTO) (*OrderExecution, error) {
+
+	oh.mu.Lock()
+	defer oh.mu.Unlock()
+
+	if dto.ClientOrderID == "" {
+		return oh.executeOrder(account, dto)
+	}
+
+	if existing, ok := oh.clientOrders[account][dto.ClientOrderID]; ok {
+		if existing.dto != dto {
+			text := fmt.Sprintf("Client order id %s already used", dto.ClientOrderID)
+			log.Printf("ExecuteOrder: %s by %s", text, account)
+			oh.publishRejected("", account, events.DuplicateOrderReason, text)
+			return nil, &OrderError{events.DuplicateOrderReason, text, nil}
+		}
+		log.Printf("ExecuteOrder: Client order %s of %s resubmitted", dto.ClientOrderID, account)
+		return existing.execution, existing.err
+	}
+
+	execution, err := oh.executeOrder(account, dto)
+
+	// only the outcomes of the order are remembered, a failure of the service can be retried
+	if _, ok := err.(*OrderError); err == nil || ok {
+		if _, ok := oh.clientOrders[account]; !ok {
+			oh.clientOrders[account] = make(map[string]*clientOrder)
+		}
+		oh.clientOrders[account][dto.ClientOrderID] = &clientOrder{dto, execution, err}
+	}
+
+	return execution, err
+}
+
+func (oh *OrderHandler) executeOrder(account string, dto OrderDTO) (*OrderExecution, error) {
+
+	order, err := validateCreate(dto)
+	if err != nil {
+		// the rejection does not carry the id of the request, which may be the id of a order of another account
+		oh.publishRejected("", account, events.InvalidOrderReason, err.Error())
+		return nil, &OrderError{events.InvalidOrderReason, err.Error(), getFieldErrors(err)}
+	}
+	order.Account = account
+	order.ClientOrderID = dto.ClientOrderID
 
-	acceptedEvent := events.NewOrderAccepted(order.ID.String(), time.Now().UTC(), order.Symbol, order.Price, order.Quantity, order.Direction, 1)
+	err = checkInstrument(oh.book, order.Symbol)
+	if err != nil {
+		log.Printf("Order %s rejected! %s", order.ID, err)
+		oh.publishRejected(order.ID.String(), order.Account, events.UnknownSymbolReason, err.Error())
+		return nil, &OrderError{events.UnknownSymbolReason, err.Error(), nil}
+	}
+
+	err = checkHalted(oh.book, order.Symbol)
+	if err != nil {
+		log.Printf("Order %s rejected! %s", order.ID, err)
+		oh.publishRejected(order.ID.String(), order.Account, events.SymbolHaltedReason, err.Error())
+		return nil, &OrderError{events.SymbolHaltedReason, err.Error(), nil}
+	}
+
+	err = oh.checker.Check(oh.book, order)
+	if err != nil {
+		log.Printf("Order %s rejected! %s", order.ID, err)
+		oh.publishRejected(order.ID.String(), order.Account, events.LimitExceededReason, err.Error())
+		return nil, &OrderError{events.LimitExceededReason, err.Error(), nil}
+	}
+
+	acceptedEvent := events.NewOrderAccepted(order.ID.String(), order.Account, time.Now().UTC(), order.Symbol, order.Price, order.Quantity, order.Direction, 1)
 	envelope, err := events.NewOrderEventEnvelope(acceptedEvent, acceptedEvent.EventType)
 	if err != nil {
 		log.Printf("Failed to create order accepted event envelope! %s", err)
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
+		return nil, err
 	}
 	oh.publisher.Publish(envelope)
 
-	oh.trader.Trade(oh.book, order)
+	trades := oh.trader.Trade(oh.book, order)
 
 	if order.Status.IsTradeable() {
 		err = oh.appender.Append(oh.book, order)
 		if err != nil {
 			log.Printf("Failed to append order! %s", err)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-			return
+			oh.publishRejected(order.ID.String(), order.Account, events.AppendFailedReason, err.Error())
+			oh.listener.BookChanged(oh.book, order.Symbol)
+			return nil, &OrderError{events.AppendFailedReason, err.Error(), nil}
 		}
+	} else {
+		// orders filled on arrival never rest in the book, they are kept for the order status queries
+		oh.book.Orders[order.ID] = order
 	}
 
-	w.WriteHeader(http.StatusAccepted)
+	oh.listener.BookChanged(oh.book, order.Symbol)
+
+	// the book keeps updating the order after the lock is released
+	executed := *order
+	return &OrderExecution{&executed, trades}, nil
 }
 
-// OrderAmendHandle is the handler for amending a order
-func (oh *OrderHandler) OrderAmendHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+// AmendOrder amends the quantity of a order of the account, the order is identified by the id or else the client order id
+func (oh *OrderHandler) AmendOrder(account string, dto OrderDTO) error {
+
+	oh.book.Lock()
+	defer oh.book.Unlock()
+
+	return oh.amendOrder(account, dto)
+}
+
+func (oh *OrderHandler) amendOrder(account string, dto OrderDTO) error {
 
-	dto, direction, orderID, err := oh.getPayloadData(r)
+	if dto.ID == "" && dto.ClientOrderID != "" {
+		id, ok := oh.getOrderID(account, dto.ClientOrderID)
+		if !ok {
+			log.Printf("AmendOrder: Client order %s of %s not found", dto.ClientOrderID, account)
+			return &OrderError{events.UnknownOrderReason, fmt.Sprintf("Client order %s not found", dto.ClientOrderID), nil}
+		}
+		dto.ID = id
+	}
+
+	order, err := ValidateOrder(dto)
 	if err != nil {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
+		oh.publishRejected("", account, events.AmendFailedReason, err.Error())
+		return &OrderError{events.AmendFailedReason, err.Error(), getFieldErrors(err)}
+	}
+	order.Account = account
+
+	if !oh.isOwner(order.ID, order.Account) {
+		log.Printf("AmendOrder: Order %s not owned by %s", order.ID, order.Account)
+		oh.publishRejected("", order.Account, events.NotOwnerReason, "Order belongs to another account")
+		return &OrderError{events.NotOwnerReason, "Order belongs to another account", nil}
 	}
 
-	order := models.NewOrder(orderID, dto.Symbol, dto.Price, dto.Quantity, direction)
+	err = checkHalted(oh.book, order.Symbol)
+	if err != nil {
+		log.Printf("Order %s amend rejected! %s", order.ID, err)
+		oh.publishRejected(oh.rejectedID(order.ID, order.Account), order.Account, events.AmendFailedReason, err.Error())
+		return &OrderError{events.AmendFailedReason, err.Error(), nil}
+	}
 
-	amended := oh.amender.Amend(oh.book, order)
+	err = oh.checker.Check(oh.book, order)
+	if err != nil {
+		log.Printf("Order %s amend rejected! %s", order.ID, err)
+		oh.publishRejected(oh.rejectedID(order.ID, order.Account), order.Account, events.AmendFailedReason, err.Error())
+		return &OrderError{events.AmendFailedReason, err.Error(), nil}
+	}
 
-	if amended {
-		w.WriteHeader(http.StatusAccepted)
-	} else {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	if !oh.amender.Amend(oh.book, order) {
+		return &OrderError{events.AmendFailedReason, fmt.Sprintf("Order %s not amended", order.ID), nil}
 	}
+
+	oh.listener.BookChanged(oh.book, order.Symbol)
+	return nil
 }
 
-// OrderCancelHandle is the handler for cancelling a order
-func (oh *OrderHandler) OrderCancelHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+// CancelOrder cancels a order of the account
+func (oh *OrderHandler) CancelOrder(account string, id string) error {
+
+	oh.book.Lock()
+	defer oh.book.Unlock()
+
+	return oh.cancelOrder(account, id)
+}
+
+func (oh *OrderHandler) cancelOrder(account string, id string) error {
 
-	orderID := uuid.FromStringOrNil(strings.ToUpper(p.ByName("orderid")))
+	orderID := uuid.FromStringOrNil(strings.ToUpper(id))
 
 	if orderID == uuid.Nil {
 		log.Print("Failed to get orderID!")
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
+		oh.publishRejected("", account, events.InvalidOrderReason, "Failed to get order id")
+		return &OrderError{events.InvalidOrderReason, "Failed to get order id", nil}
 	}
 
-	cancelled := oh.canceller.Cancel(oh.book, orderID)
+	if !oh.isOwner(orderID, account) {
+		log.Printf("CancelOrder: Order %s not owned by %s", orderID, account)
+		oh.publishRejected("", account, events.NotOwnerReason, "Order belongs to another account")
+		return &OrderError{events.NotOwnerReason, "Order belongs to another account", nil}
+	}
 
-	if cancelled {
-		w.WriteHeader(http.StatusAccepted)
-		log.Printf("OrderCancelHandle: Order %s cancelled", orderID.String())
-	} else {
-		http.NotFound(w, r)
-		log.Printf("OrderCancelHandle: Order %s not found", orderID.String())
+	if !oh.canceller.Cancel(oh.book, orderID) {
+		log.Printf("CancelOrder: Order %s not found", orderID.String())
+		return &OrderError{events.UnknownOrderReason, fmt.Sprintf("Order %s not found", orderID), nil}
 	}
 
+	if order, ok := oh.book.Orders[orderID]; ok {
+		oh.listener.BookChanged(oh.book, order.Symbol)
+	}
+	log.Printf("CancelOrder: Order %s cancelled", orderID.String())
+	return nil
+}
+
+// CancelClientOrder cancels a order of the account by its client order id
+func (oh *OrderHandler) CancelClientOrder(account string, clientOrderID string) error {
+
+	oh.book.Lock()
+	defer oh.book.Unlock()
+
+	return oh.cancelClientOrder(account, clientOrderID)
+}
+
+func (oh *OrderHandler) cancelClientOrder(account string, clientOrderID string) error {
+
+	id, ok := oh.getOrderID(account, clientOrderID)
+	if !ok {
+		log.Printf("CancelClientOrder: Client order %s of %s not found", clientOrderID, account)
+		return &OrderError{events.UnknownOrderReason, fmt.Sprintf("Client order %s not found", clientOrderID), nil}
+	}
+
+	return oh.cancelOrder(account, id)
+}
+
+// SessionClosed forgets the client order ids of the closed session, the ids can be reused in the next session
+func (oh *OrderHandler) SessionClosed(at time.Time) {
+
+	oh.book.Lock()
+	defer oh.book.Unlock()
+
+	oh.mu.Lock()
+	defer oh.mu.Unlock()
+
+	log.Printf("SessionClosed: Forgetting the client orders of %d accounts", len(oh.clientOrders))
+	oh.clientOrders = make(map[string]map[string]*clientOrder)
+}
+
+// getOrderID returns the id of the order created by the client order id of the account
+func (oh *OrderHandler) getOrderID(account string, clientOrderID string) (string, bool) {
+	oh.mu.Lock()
+	defer oh.mu.Unlock()
+
+	existing, ok := oh.clientOrders[account][clientOrderID]
+	if !ok || existing.execution == nil {
+		return "", false
+	}
+	return existing.execution.Order.ID.String(), true
+}
+
+func (oh *OrderHandler) publishRejected(orderID string, account string, reason events.RejectReason, text string) {
+
+	rejectedEvent := events.NewOrderRejected(orderID, account, time.Now().UTC(), reason, text, 1)
+	envelope, err := events.NewOrderEventEnvelope(rejectedEvent, rejectedEvent.EventType)
+	if err != nil {
+		log.Printf("Failed to create order rejected event envelope! %s", err)
+		return
+	}
+
+	err = oh.publisher.Publish(envelope)
+	if err != nil {
+		log.Printf("Failed to publish rejected event: %s", rejectedEvent.String())
+	}
+}
+
+func getAccount(r *http.Request) string {
+	account, _ := common_http.AccountFromContext(r.Context())
+	return account
+}
+
+// isOwner returns false when the order is in the book and belongs to another account
+func (oh *OrderHandler) isOwner(orderID uuid.UUID, account string) bool {
+	order, ok := oh.book.Orders[orderID]
+	return !ok || order.Account == account
+}
+
+// rejectedID returns the id a rejection of the order carries, it is empty unless the order is in the book and belongs to the account
+func (oh *OrderHandler) rejectedID(orderID uuid.UUID, account string) string {
+	if order, ok := oh.book.Orders[orderID]; ok && order.Account == account {
+		return orderID.String()
+	}
+	return ""
 }
 
-func (oh *OrderHandler) getPayloadData(r *http.Request) (OrderDTO, models.TradeDirection, uuid.UUID, error) {
+func decodeOrder(r *http.Request) (OrderDTO, error) {
 
 	var dto OrderDTO
-	var direction models.TradeDirection
-	var orderID uuid.UUID
 
 	err := json.NewDecoder(r.Body).Decode(&dto)
-
 	if err != nil {
 		log.Printf("Failed to bind model! %s", err)
-		return dto, direction, orderID, err
 	}
 
-	direction, err = models.TradeDirectionFromString(dto.Direction)
+	return dto, err
+}
+
+// ValidateOrder validates a order payload and creates the order.
+// It is the validation shared by the order entry apis, the error is a validation error with the errors of the fields.
+func ValidateOrder(dto OrderDTO) (*models.Order, error) {
+
+	validation := &common_http.ValidationError{}
+
+	orderID, err := uuid.FromString(dto.ID)
 	if err != nil {
-		log.Printf("Failed to getting trade direction! %s", err)
-		return dto, direction, orderID, err
+		validation.Add("id", "%q is not a uuid", dto.ID)
+	}
+
+	direction, err := models.TradeDirectionFromString(dto.Direction)
+	if err != nil {
+		validation.Add("direction", "%q is not %s or %s", dto.Direction, models.BuyText, models.SellText)
+	}
+
+	if dto.Symbol == "" {
+		validation.Add("symbol", "is required")
+	}
+
+	if dto.Quantity == 0 {
+		validation.Add("quantity", "must be greater than zero")
 	}
 
-	orderID, err = uuid.FromString(dto.ID)
+	if dto.Price <= 0 {
+		validation.Add("price", "must be greater than zero")
+	}
+
+	if err := validation.Err(); err != nil {
+		log.Printf("Invalid order! %s", err)
+		return nil, err
+	}
+
+	return models.NewOrder(orderID, dto.Symbol, dto.Price, dto.Quantity, direction), nil
+}
+
+// validateCreate validates the payload of a new order and creates the order with a id assigned by the exchange
+func validateCreate(dto OrderDTO) (*models.Order, error) {
+
+	validation := &common_http.ValidationError{}
+	if dto.ID != "" {
+		validation.Add("id", "is assigned by the exchange")
+	}
+
+	dto.ID = uuid.NewV4().String()
+	order, err := ValidateOrder(dto)
 	if err != nil {
-		log.Printf("Failed to getting order id! %s", err)
-		return dto, direction, orderID, err
+		validation.Fields = append(validation.Fields, getFieldErrors(err)...)
+	}
+
+	if err := validation.Err(); err != nil {
+		return nil, err
 	}
+	return order, nil
+}
 
-	return dto, direction, orderID, nil
+// checkInstrument returns a error when the symbol is not a instrument of the book, the caller holds the book lock
+func checkInstrument(book *models.OrderBook, symbol string) error {
+	if !book.IsInstrument(symbol) {
+		return fmt.Errorf("Symbol %s is not a instrument", symbol)
+	}
+	return nil
+}
+
+// checkHalted returns a error when the symbol is halted, the caller holds the book lock
+func checkHalted(book *models.OrderBook, symbol string) error {
+	if reason, ok := book.Halted[symbol]; ok {
+		return fmt.Errorf("Symbol %s is halted: %s", symbol, reason)
+	}
+	return nil
+}
+
+func getFieldErrors(err error) []common_http.FieldError {
+	validation, ok := err.(*common_http.ValidationError)
+	if !ok {
+		return nil
+	}
+	return validation.Fields
+}
+
+// writeOrderError writes the problem of a order error, the error code is the reject reason
+func writeOrderError(w http.ResponseWriter, r *http.Request, err error) {
+
+	orderErr, ok := err.(*OrderError)
+	if !ok {
+		common_http.WriteError(w, r, getErrorStatus(err), common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	common_http.WriteProblem(w, r, common_http.NewProblem(getErrorStatus(err), common_http.ErrorCode(orderErr.Reason), orderErr.Text, orderErr.Fields...))
+}
+
+func getExecutionReportResponse(execution *OrderExecution) ExecutionReportResponse {
+
+	response := ExecutionReportResponse{getOrderResponse(execution.Order), make([]FillResponse, 0, len(execution.Trades))}
+
+	for _, trade := range execution.Trades {
+		counterparty := trade.SellOrderID
+		if trade.SellOrderID == execution.Order.ID {
+			counterparty = trade.BuyOrderID
+		}
+		response.Fills = append(response.Fills, FillResponse{trade.ID.String(), counterparty.String(), trade.Price, trade.Quantity, trade.Occured})
+	}
+
+	return response
+}
+
+// getErrorStatus returns the http status of a order error
+func getErrorStatus(err error) int {
+
+	orderErr, ok := err.(*OrderError)
+	if !ok {
+		return http.StatusBadRequest
+	}
+
+	switch orderErr.Reason {
+	case events.NotOwnerReason:
+		return http.StatusForbidden
+	case events.UnknownOrderReason:
+		return http.StatusNotFound
+	case events.DuplicateOrderReason:
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
 }
@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"fmt"
 
@@ -14,6 +17,7 @@ import (
 	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/events"
 	"github.com/tradsim/tradsim-go/mocks"
 	"github.com/tradsim/tradsim-go/models"
 	common_http "github.com/tradsim/tradsim-go/net/http"
@@ -24,12 +28,13 @@ func TestOrderCreateHandle(t *testing.T) {
 	book := models.NewOrderBook()
 
 	order1 := models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Buy)
-	createOrder := OrderDTO{uuid.NewV4().String(), "TT", 10, models.Sell.String(), 1.99}
+	createOrder := OrderDTO{"", "TT", 10, models.Sell.String(), 1.99, ""}
 
 	ap := trading.NewOrderAppender()
 	ap.Append(book, order1)
 
-	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockPublisher{})
+	listener := &mocks.MockBookListener{}
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockLimitChecker{}, listener, &mocks.MockPublisher{})
 
 	encodedOrder, _ := json.Marshal(createOrder)
 
@@ -44,6 +49,7 @@ func TestOrderCreateHandle(t *testing.T) {
 	router.ServeHTTP(response, request)
 
 	require.Equal(http.StatusAccepted, response.Code)
+	require.Equal([]string{"TT"}, listener.Symbols)
 }
 
 func TestOrderCreateHandleInvalidPayloadBadRequest(t *testing.T) {
@@ -55,7 +61,7 @@ func TestOrderCreateHandleInvalidPayloadBadRequest(t *testing.T) {
 	ap := trading.NewOrderAppender()
 	ap.Append(book, order1)
 
-	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockPublisher{})
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
 
 	encodedOrder, _ := json.Marshal("{\"test\":123}")
 
@@ -81,9 +87,9 @@ func TestOrderCreateHandleInvalidOrderIDBadRequest(t *testing.T) {
 	ap := trading.NewOrderAppender()
 	ap.Append(book, order1)
 
-	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockPublisher{})
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
 
-	createOrder := OrderDTO{"XXX", "TT", 10, models.Sell.String(), 1.99}
+	createOrder := OrderDTO{"XXX", "TT", 10, models.Sell.String(), 1.99, ""}
 	encodedOrder, _ := json.Marshal(createOrder)
 
 	request, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBuffer(encodedOrder))
@@ -99,17 +105,48 @@ func TestOrderCreateHandleInvalidOrderIDBadRequest(t *testing.T) {
 	require.Equal(response.Code, http.StatusBadRequest)
 }
 
+func TestOrderCreateHandleInvalidPayloadPublishesRejected(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	createOrder := OrderDTO{"XXX", "TT", 10, models.Sell.String(), 1.99, ""}
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+
+	encodedOrder, _ := json.Marshal(createOrder)
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBuffer(encodedOrder))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPost, "/orders", common_http.DefaultPOSTJSONValidationMiddleware(handler.OrderCreateHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusBadRequest, response.Code)
+	require.Len(publisher.Envelopes, 1)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	rejected := event.(events.OrderRejected)
+	require.Empty(rejected.OrderID)
+	require.Equal(events.InvalidOrderReason, rejected.Reason)
+}
+
 func TestOrderCreateHandleInvalidTradeDirectionBadRequest(t *testing.T) {
 	require := require.New(t)
 	book := models.NewOrderBook()
 
 	order1 := models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Buy)
-	createOrder := OrderDTO{uuid.NewV4().String(), "TT", 10, "XXX", 1.99}
+	createOrder := OrderDTO{uuid.NewV4().String(), "TT", 10, "XXX", 1.99, ""}
 
 	ap := trading.NewOrderAppender()
 	ap.Append(book, order1)
 
-	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockPublisher{})
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
 
 	encodedOrder, _ := json.Marshal(createOrder)
 
@@ -126,6 +163,39 @@ func TestOrderCreateHandleInvalidTradeDirectionBadRequest(t *testing.T) {
 	require.Equal(response.Code, http.StatusBadRequest)
 }
 
+func TestOrderCreateHandleInvalidOrderProblem(t *testing.T) {
+	require := require.New(t)
+
+	handler := NewOrderHandler(models.NewOrderBook(), &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+
+	encodedOrder, _ := json.Marshal(OrderDTO{"XXX", "TT", 0, "Up", 1.99, ""})
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBuffer(encodedOrder))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPost, "/orders", common_http.DefaultPOSTJSONValidationMiddleware(handler.OrderCreateHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusBadRequest, response.Code)
+	require.Equal(common_http.ProblemContentType, response.Header().Get("Content-Type"))
+
+	var problem common_http.Problem
+	err := json.Unmarshal(response.Body.Bytes(), &problem)
+	require.Nil(err)
+	require.Equal(common_http.ErrorCode(events.InvalidOrderReason), problem.Code)
+	require.Equal(http.StatusBadRequest, problem.Status)
+	require.Equal("/orders", problem.Instance)
+	require.Equal([]common_http.FieldError{
+		{Field: "id", Message: "is assigned by the exchange"},
+		{Field: "direction", Message: `"Up" is not Buy or Sell`},
+		{Field: "quantity", Message: "must be greater than zero"},
+	}, problem.Errors)
+}
+
 func TestOrderCancelHandleAccepted(t *testing.T) {
 
 	require := require.New(t)
@@ -136,7 +206,8 @@ func TestOrderCancelHandleAccepted(t *testing.T) {
 	ap := trading.NewOrderAppender()
 	ap.Append(book, order)
 
-	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockPublisher{})
+	listener := &mocks.MockBookListener{}
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockLimitChecker{}, listener, &mocks.MockPublisher{})
 
 	request, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/orders/%s", order.ID), nil)
 
@@ -148,6 +219,7 @@ func TestOrderCancelHandleAccepted(t *testing.T) {
 	router.ServeHTTP(response, request)
 
 	require.Equal(response.Code, http.StatusAccepted)
+	require.Equal([]string{"TT"}, listener.Symbols)
 }
 
 func TestOrderCancelHandleNotFound(t *testing.T) {
@@ -155,7 +227,7 @@ func TestOrderCancelHandleNotFound(t *testing.T) {
 	require := require.New(t)
 	book := models.NewOrderBook()
 
-	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: false}, &mocks.MockPublisher{})
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: false}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
 
 	request, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/orders/%s", uuid.NewV4()), nil)
 
@@ -174,7 +246,8 @@ func TestOrderCancelHandleBadRequest(t *testing.T) {
 	require := require.New(t)
 	book := models.NewOrderBook()
 
-	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: false}, &mocks.MockPublisher{})
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: false}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
 
 	request, _ := http.NewRequest(http.MethodDelete, "/orders/123", nil)
 
@@ -186,15 +259,20 @@ func TestOrderCancelHandleBadRequest(t *testing.T) {
 	router.ServeHTTP(response, request)
 
 	require.Equal(response.Code, http.StatusBadRequest)
+	require.Len(publisher.Envelopes, 1)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	require.Equal(events.InvalidOrderReason, event.(events.OrderRejected).Reason)
 }
 
 func TestOrderAmendHandleAccepted(t *testing.T) {
 	require := require.New(t)
 	book := models.NewOrderBook()
 
-	amendOrder := OrderDTO{uuid.NewV4().String(), "TT", 10, models.Sell.String(), 1.99}
+	amendOrder := OrderDTO{uuid.NewV4().String(), "TT", 10, models.Sell.String(), 1.99, ""}
 
-	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{Amended: true}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockPublisher{})
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{Amended: true}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
 
 	encodedOrder, _ := json.Marshal(amendOrder)
 
@@ -215,9 +293,9 @@ func TestOrderAmendHandleBadRequest(t *testing.T) {
 	require := require.New(t)
 	book := models.NewOrderBook()
 
-	amendOrder := OrderDTO{uuid.NewV4().String(), "TT", 10, models.Sell.String(), 1.99}
+	amendOrder := OrderDTO{uuid.NewV4().String(), "TT", 10, models.Sell.String(), 1.99, ""}
 
-	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{Amended: false}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockPublisher{})
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{Amended: false}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
 
 	encodedOrder, _ := json.Marshal(amendOrder)
 
@@ -233,3 +311,473 @@ func TestOrderAmendHandleBadRequest(t *testing.T) {
 
 	require.Equal(http.StatusBadRequest, response.Code)
 }
+
+func TestOrderCreateHandleLimitExceededBadRequest(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	createOrder := OrderDTO{"", "TT", 10, models.Sell.String(), 1.99, ""}
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{Err: errors.New("limit")}, &mocks.MockBookListener{}, publisher)
+
+	encodedOrder, _ := json.Marshal(createOrder)
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBuffer(encodedOrder))
+	request.Header.Set("Content-Type", "application/json")
+	request = request.WithContext(common_http.WithAccount(request.Context(), "ACC1"))
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPost, "/orders", common_http.DefaultPOSTJSONValidationMiddleware(handler.OrderCreateHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusBadRequest, response.Code)
+	require.Len(publisher.Envelopes, 1)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	require.Equal(events.LimitExceededReason, event.(events.OrderRejected).Reason)
+}
+
+func TestOrderAmendHandleLimitExceededBadRequest(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	amendOrder := OrderDTO{uuid.NewV4().String(), "TT", 10, models.Sell.String(), 1.99, ""}
+
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{Amended: true}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{Err: errors.New("limit")}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+
+	encodedOrder, _ := json.Marshal(amendOrder)
+
+	request, _ := http.NewRequest(http.MethodPut, "/orders", bytes.NewBuffer(encodedOrder))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPut, "/orders", common_http.DefaultPUTJSONValidationMiddleware(handler.OrderAmendHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestOrderCancelHandleOtherAccountForbidden(t *testing.T) {
+
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	order := models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Buy)
+	order.Account = "ACC1"
+
+	ap := trading.NewOrderAppender()
+	ap.Append(book, order)
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{Cancelled: true}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+
+	request, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/orders/%s", order.ID), nil)
+	request = request.WithContext(common_http.WithAccount(request.Context(), "ACC2"))
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodDelete, "/orders/:orderid", common_http.DefaultDELETEValidationMiddleware(handler.OrderCancelHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusForbidden, response.Code)
+	require.Len(publisher.Envelopes, 1)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	rejected := event.(events.OrderRejected)
+	require.Equal(events.NotOwnerReason, rejected.Reason)
+	require.Equal("ACC2", rejected.Account)
+	require.Empty(rejected.OrderID)
+}
+
+func TestOrderAmendHandleOtherAccountForbidden(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	order := models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Sell)
+	order.Account = "ACC1"
+
+	ap := trading.NewOrderAppender()
+	ap.Append(book, order)
+
+	amendOrder := OrderDTO{order.ID.String(), "TT", 20, models.Sell.String(), 1.99, ""}
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{Amended: true}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+
+	encodedOrder, _ := json.Marshal(amendOrder)
+
+	request, _ := http.NewRequest(http.MethodPut, "/orders", bytes.NewBuffer(encodedOrder))
+	request.Header.Set("Content-Type", "application/json")
+	request = request.WithContext(common_http.WithAccount(request.Context(), "ACC2"))
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPut, "/orders", common_http.DefaultPUTJSONValidationMiddleware(handler.OrderAmendHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusForbidden, response.Code)
+	require.Len(publisher.Envelopes, 1)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	require.Empty(event.(events.OrderRejected).OrderID)
+}
+
+func TestOrderAmendHandleInvalidPayloadKeepsOrder(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	order := models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Sell)
+	order.Account = "ACC1"
+
+	ap := trading.NewOrderAppender()
+	ap.Append(book, order)
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{Amended: true}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+
+	router := httprouter.New()
+	router.Handle(http.MethodPut, "/orders", common_http.DefaultPUTJSONValidationMiddleware(handler.OrderAmendHandle))
+	router.Handle(http.MethodPost, "/orders", common_http.DefaultPOSTJSONValidationMiddleware(handler.OrderCreateHandle))
+
+	// the id of the order of another account decodes before the overflowing quantity fails
+	payload := fmt.Sprintf(`{"id":%q,"symbol":"TT","quantity":99999999999999999999999,"direction":"Sell","price":1.99}`, order.ID)
+	for _, method := range []string{http.MethodPut, http.MethodPost} {
+		request, _ := http.NewRequest(method, "/orders", bytes.NewBufferString(payload))
+		request.Header.Set("Content-Type", "application/json")
+		request = request.WithContext(common_http.WithAccount(request.Context(), "ACC2"))
+
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+
+		require.Equal(http.StatusBadRequest, response.Code)
+	}
+
+	require.Len(publisher.Envelopes, 2)
+	for _, envelope := range publisher.Envelopes {
+		event, err := envelope.GetOrderEvent()
+		require.Nil(err)
+		require.Empty(event.(events.OrderRejected).OrderID)
+	}
+}
+
+func TestOrderCreateHandleAttachesAccount(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	createOrder := OrderDTO{"", "TT", 10, models.Sell.String(), 1.99, ""}
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+	store := common_http.NewInMemoryAccountStore(&common_http.Account{Name: "ACC1", APIKey: "KEY1"})
+
+	encodedOrder, _ := json.Marshal(createOrder)
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBuffer(encodedOrder))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(common_http.APIKeyHeader, "KEY1")
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPost, "/orders", common_http.DefaultPOSTJSONValidationMiddleware(common_http.APIKeyAuthMiddleware(store, handler.OrderCreateHandle)))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusAccepted, response.Code)
+
+	var ids OrderIDResponse
+	json.Unmarshal(response.Body.Bytes(), &ids)
+	require.Equal("ACC1", book.Orders[uuid.FromStringOrNil(ids.ID)].Account)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	require.Equal("ACC1", event.(events.OrderAccepted).Account)
+}
+
+func TestCreateOrderKeepsFilledOrder(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	resting := models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Sell)
+	appender := trading.NewOrderAppender()
+	appender.Append(book, resting)
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, appender, &mocks.MockAmender{}, trading.NewOrderTrader(publisher), &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+
+	createOrder := OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, ""}
+
+	execution, err := handler.ExecuteOrder("ACC1", createOrder)
+	require.Nil(err)
+
+	order, ok := book.Orders[execution.Order.ID]
+	require.True(ok)
+	require.Equal(models.FullyFilled, order.Status)
+	require.Equal("ACC1", order.Account)
+}
+
+func TestOrderCreateHandleSync(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	resting1 := models.NewOrder(uuid.NewV4(), "TT", 1.98, 4, models.Sell)
+	resting2 := models.NewOrder(uuid.NewV4(), "TT", 1.99, 10, models.Sell)
+	appender := trading.NewOrderAppender()
+	appender.Append(book, resting1)
+	appender.Append(book, resting2)
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, appender, &mocks.MockAmender{}, trading.NewOrderTrader(publisher), &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+
+	createOrder := OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, ""}
+	encodedOrder, _ := json.Marshal(createOrder)
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders?sync=true", bytes.NewBuffer(encodedOrder))
+	request.Header.Set("Content-Type", "application/json")
+	request = request.WithContext(common_http.WithAccount(request.Context(), "ACC1"))
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPost, "/orders", common_http.DefaultPOSTJSONValidationMiddleware(handler.OrderCreateHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusOK, response.Code)
+	require.Equal("application/json", response.Header().Get("Content-Type"))
+
+	var report ExecutionReportResponse
+	err := json.Unmarshal(response.Body.Bytes(), &report)
+	require.Nil(err)
+	_, ok := book.Orders[uuid.FromStringOrNil(report.Order.ID)]
+	require.True(ok)
+	require.Equal("ACC1", report.Order.Account)
+	require.Equal(uint(10), report.Order.Traded)
+	require.Equal(uint(0), report.Order.Remaining)
+	require.Equal(models.FullyFilledText, report.Order.Status)
+	require.Len(report.Fills, 2)
+	require.Equal(resting1.ID.String(), report.Fills[0].CounterpartyOrderID)
+	require.Equal(1.98, report.Fills[0].Price)
+	require.Equal(uint(4), report.Fills[0].Quantity)
+	require.NotEmpty(report.Fills[0].TradeID)
+	require.Equal(resting2.ID.String(), report.Fills[1].CounterpartyOrderID)
+	require.Equal(uint(6), report.Fills[1].Quantity)
+}
+
+func TestOrderCreateHandleSyncRejected(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	handler := NewOrderHandler(book, &mocks.MockAppender{}, &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{Err: errors.New("limit")}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+
+	createOrder := OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, ""}
+	encodedOrder, _ := json.Marshal(createOrder)
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders?sync=true", bytes.NewBuffer(encodedOrder))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPost, "/orders", common_http.DefaultPOSTJSONValidationMiddleware(handler.OrderCreateHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestOrderCreateHandleAssignsID(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+
+	createOrder := OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, "C1"}
+	encodedOrder, _ := json.Marshal(createOrder)
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBuffer(encodedOrder))
+	request.Header.Set("Content-Type", "application/json")
+	request = request.WithContext(common_http.WithAccount(request.Context(), "ACC1"))
+
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodPost, "/orders", common_http.DefaultPOSTJSONValidationMiddleware(handler.OrderCreateHandle))
+
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusAccepted, response.Code)
+
+	var ids OrderIDResponse
+	err := json.Unmarshal(response.Body.Bytes(), &ids)
+	require.Nil(err)
+	require.Equal("C1", ids.ClientOrderID)
+
+	order, ok := book.Orders[uuid.FromStringOrNil(ids.ID)]
+	require.True(ok)
+	require.Equal("C1", order.ClientOrderID)
+}
+
+func TestExecuteOrderClientOrderID(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+
+	createOrder := OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, "C1"}
+
+	first, err := handler.ExecuteOrder("ACC1", createOrder)
+	require.Nil(err)
+
+	retried, err := handler.ExecuteOrder("ACC1", createOrder)
+	require.Nil(err)
+	require.Equal(first.Order.ID, retried.Order.ID)
+	require.Len(book.Orders, 1)
+	require.Len(publisher.Envelopes, 1)
+
+	other, err := handler.ExecuteOrder("ACC2", createOrder)
+	require.Nil(err)
+	require.NotEqual(first.Order.ID, other.Order.ID)
+
+	conflicting := createOrder
+	conflicting.Quantity = 20
+	_, err = handler.ExecuteOrder("ACC1", conflicting)
+	require.Equal(events.DuplicateOrderReason, err.(*OrderError).Reason)
+	require.Equal(http.StatusConflict, getErrorStatus(err))
+	require.Len(book.Orders, 2)
+}
+
+func TestExecuteOrderClientOrderIDRepeatsRejection(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	checker := &mocks.MockLimitChecker{Err: errors.New("limit")}
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, checker, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+
+	createOrder := OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, "C1"}
+
+	_, err := handler.ExecuteOrder("ACC1", createOrder)
+	require.Equal(events.LimitExceededReason, err.(*OrderError).Reason)
+
+	checker.Err = nil
+	_, err = handler.ExecuteOrder("ACC1", createOrder)
+	require.Equal(events.LimitExceededReason, err.(*OrderError).Reason)
+	require.Empty(book.Orders)
+}
+
+func TestExecuteOrderConcurrentLimitCheck(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	checker := trading.NewAccountLimitChecker(trading.NewRiskCache(*models.NewAccountLimits("", 100, 0, 0.0, 0.0)))
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, checker, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.ExecuteOrder("ACC1", OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, ""})
+		}()
+	}
+	wg.Wait()
+
+	require.Len(book.Orders, 10)
+}
+
+func TestExecuteOrderRejectsID(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+
+	execution, err := handler.ExecuteOrder("ACC1", OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, ""})
+	require.Nil(err)
+
+	// the id of a order of another account is rejected like any other id
+	for _, id := range []string{execution.Order.ID.String(), uuid.NewV4().String()} {
+		_, err = handler.ExecuteOrder("ACC2", OrderDTO{id, "TT", 5, models.Buy.String(), 1.99, ""})
+		require.Equal(events.InvalidOrderReason, err.(*OrderError).Reason)
+		require.Equal([]common_http.FieldError{{Field: "id", Message: "is assigned by the exchange"}}, err.(*OrderError).Fields)
+	}
+
+	require.Len(book.Orders, 1)
+	require.Equal(uint(10), book.Orders[execution.Order.ID].Quantity)
+
+	event, err := publisher.Envelopes[2].GetOrderEvent()
+	require.Nil(err)
+	require.Empty(event.(events.OrderRejected).OrderID)
+}
+
+func TestSessionClosedForgetsClientOrders(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{}, &mocks.MockTrader{}, &mocks.MockCanceller{}, &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+
+	createOrder := OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, "C1"}
+
+	first, err := handler.ExecuteOrder("ACC1", createOrder)
+	require.Nil(err)
+
+	handler.SessionClosed(time.Now().UTC())
+
+	_, ok := handler.getOrderID("ACC1", "C1")
+	require.False(ok)
+
+	next, err := handler.ExecuteOrder("ACC1", createOrder)
+	require.Nil(err)
+	require.NotEqual(first.Order.ID, next.Order.ID)
+	require.Len(book.Orders, 2)
+}
+
+func TestCancelAndAmendByClientOrderID(t *testing.T) {
+	require := require.New(t)
+	book := models.NewOrderBook()
+
+	amender := &mocks.MockAmender{Amended: true}
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), amender, &mocks.MockTrader{}, trading.NewOrderCanceller(&mocks.MockPublisher{}), &mocks.MockLimitChecker{}, &mocks.MockBookListener{}, &mocks.MockPublisher{})
+
+	execution, err := handler.ExecuteOrder("ACC1", OrderDTO{"", "TT", 10, models.Buy.String(), 1.99, "C1"})
+	require.Nil(err)
+
+	err = handler.AmendOrder("ACC1", OrderDTO{"", "TT", 20, models.Buy.String(), 1.99, "C1"})
+	require.Nil(err)
+
+	err = handler.AmendOrder("ACC1", OrderDTO{"", "TT", 20, models.Buy.String(), 1.99, "C2"})
+	require.Equal(events.UnknownOrderReason, err.(*OrderError).Reason)
+
+	request, _ := http.NewRequest(http.MethodDelete, "/orders?client_order_id=C1", nil)
+	request = request.WithContext(common_http.WithAccount(request.Context(), "ACC2"))
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodDelete, "/orders", common_http.DefaultDELETEValidationMiddleware(handler.OrderCancelByClientOrderIDHandle))
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusNotFound, response.Code)
+
+	request, _ = http.NewRequest(http.MethodDelete, "/orders?client_order_id=C1", nil)
+	request = request.WithContext(common_http.WithAccount(request.Context(), "ACC1"))
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	require.Equal(http.StatusAccepted, response.Code)
+	require.Equal(models.Cancelled, book.Orders[execution.Order.ID].Status)
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	uuid "github.com/satori/go.uuid"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// Paging of the orders
+const (
+	DefaultOrdersLimit = 100
+	MaxOrdersLimit     = 1000
+)
+
+// OrderResponse returns the state of a order
+type OrderResponse struct {
+	ID            string  `json:"id"`
+	ClientOrderID string  `json:"client_order_id,omitempty"`
+	Account       string  `json:"account"`
+	Symbol        string  `json:"symbol"`
+	Direction     string  `json:"direction"`
+	Price         float64 `json:"price"`
+	Quantity      uint    `json:"quantity"`
+	Traded        uint    `json:"traded"`
+	Remaining     uint    `json:"remaining"`
+	Status        string  `json:"status"`
+}
+
+// OrdersResponse returns a page of orders ordered by id, next_after is used as after for the next page
+type OrdersResponse struct {
+	Orders    []OrderResponse `json:"orders"`
+	NextAfter string          `json:"next_after"`
+}
+
+// OrderQuery defines the filters and the page of a orders request, empty filters match every order
+type OrderQuery struct {
+	Account       string
+	ClientOrderID string
+	Symbol        string
+	Status        *models.OrderStatus
+	Direction     *models.TradeDirection
+	After         string
+	Limit         int
+}
+
+// Matches returns true if the order passes the filters
+func (q OrderQuery) Matches(order *models.Order) bool {
+	return (q.Account == "" || order.Account == q.Account) &&
+		(q.ClientOrderID == "" || order.ClientOrderID == q.ClientOrderID) &&
+		(q.Symbol == "" || order.Symbol == q.Symbol) &&
+		(q.Status == nil || order.Status == *q.Status) &&
+		(q.Direction == nil || order.Direction == *q.Direction)
+}
+
+// OrderQueryHandler handles order status requests
+type OrderQueryHandler struct {
+	book *models.OrderBook
+}
+
+// NewOrderQueryHandler creates a new order query handler
+func NewOrderQueryHandler(book *models.OrderBook) *OrderQueryHandler {
+	return &OrderQueryHandler{book}
+}
+
+// GetOrderHandle is the handler for getting a order, only admins can get the orders of other accounts
+func (oqh *OrderQueryHandler) GetOrderHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	orderID := uuid.FromStringOrNil(strings.ToUpper(p.ByName("orderid")))
+	if orderID == uuid.Nil {
+		log.Print("GetOrderHandle: Failed to get order id!")
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, fmt.Sprintf("%q is not a uuid", p.ByName("orderid")))
+		return
+	}
+
+	order, ok := oqh.Order(orderID)
+	if !ok {
+		log.Printf("GetOrderHandle: Order %s not found", orderID)
+		common_http.WriteNotFound(w, r, fmt.Sprintf("Order %s not found", orderID))
+		return
+	}
+
+	if !isAdmin(r) && order.Account != getAccount(r) {
+		log.Printf("GetOrderHandle: Order %s not owned by %s", orderID, getAccount(r))
+		common_http.WriteError(w, r, http.StatusForbidden, common_http.ForbiddenCode, "Order belongs to another account")
+		return
+	}
+
+	encoded, _ := json.Marshal(order)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetOrderHandle: Order %s returned", orderID)
+}
+
+// GetOrdersHandle is the handler for listing orders.
+// The query parameters symbol, status, direction, account and client_order_id filter the orders, after (order id) and limit page through them.
+// Callers other than admins only list their own orders.
+func (oqh *OrderQueryHandler) GetOrdersHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	query, err := getOrderQuery(r.URL.Query())
+	if err != nil {
+		log.Printf("GetOrdersHandle: Invalid query! %s", err)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	if !isAdmin(r) {
+		account := getAccount(r)
+		if query.Account != "" && query.Account != account {
+			log.Printf("GetOrdersHandle: %s is not allowed to list orders of %s", account, query.Account)
+			common_http.WriteError(w, r, http.StatusForbidden, common_http.ForbiddenCode, "Orders of other accounts require the admin role")
+			return
+		}
+		query.Account = account
+	}
+
+	orders := oqh.Orders(query)
+
+	response := OrdersResponse{orders, query.After}
+
+	if len(orders) > 0 {
+		response.NextAfter = orders[len(orders)-1].ID
+	}
+
+	encoded, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetOrdersHandle: Returned %d orders", len(orders))
+}
+
+// Order returns the state of a order
+func (oqh *OrderQueryHandler) Order(orderID uuid.UUID) (OrderResponse, bool) {
+
+	oqh.book.RLock()
+	defer oqh.book.RUnlock()
+
+	order, ok := oqh.book.Orders[orderID]
+	if !ok {
+		return OrderResponse{}, false
+	}
+	return getOrderResponse(order), true
+}
+
+// Orders returns the state of the page of the orders matching the query
+func (oqh *OrderQueryHandler) Orders(query OrderQuery) []OrderResponse {
+
+	oqh.book.RLock()
+	defer oqh.book.RUnlock()
+
+	matching := make([]*models.Order, 0)
+
+	for _, order := range oqh.book.Orders {
+		if order.ID.String() > query.After && query.Matches(order) {
+			matching = append(matching, order)
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool { return matching[i].ID.String() < matching[j].ID.String() })
+
+	if len(matching) > query.Limit {
+		matching = matching[:query.Limit]
+	}
+
+	orders := make([]OrderResponse, 0, len(matching))
+	for _, order := range matching {
+		orders = append(orders, getOrderResponse(order))
+	}
+
+	return orders
+}
+
+func getOrderQuery(values url.Values) (OrderQuery, error) {
+
+	query := OrderQuery{Account: values.Get("account"), ClientOrderID: values.Get("client_order_id"), Symbol: strings.ToUpper(values.Get("symbol")), Limit: DefaultOrdersLimit}
+
+	if value := values.Get("status"); value != "" {
+		status, err := models.OrderStatusFromString(value)
+		if err != nil {
+			return query, err
+		}
+		query.Status = &status
+	}
+
+	if value := values.Get("direction"); value != "" {
+		direction, err := models.TradeDirectionFromString(value)
+		if err != nil {
+			return query, err
+		}
+		query.Direction = &direction
+	}
+
+	if value := values.Get("after"); value != "" {
+		after, err := uuid.FromString(value)
+		if err != nil {
+			return query, err
+		}
+		query.After = after.String()
+	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return query, err
+		}
+		if limit <= 0 || limit > MaxOrdersLimit {
+			return query, fmt.Errorf("Limit %d is not between 1 and %d", limit, MaxOrdersLimit)
+		}
+		query.Limit = limit
+	}
+
+	return query, nil
+}
+
+func getOrderResponse(order *models.Order) OrderResponse {
+	return OrderResponse{order.ID.String(), order.ClientOrderID, order.Account, order.Symbol, order.Direction.String(), order.Price,
+		order.Quantity, order.Traded, order.Remaining(), order.Status.String()}
+}
+
+func isAdmin(r *http.Request) bool {
+	role, _ := common_http.RoleFromContext(r.Context())
+	return role == common_http.AdminRole
+}
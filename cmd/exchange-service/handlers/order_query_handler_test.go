@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func getOrderQueryResponse(book *models.OrderBook, uri string, account string, role common_http.Role) *httptest.ResponseRecorder {
+
+	handler := NewOrderQueryHandler(book)
+
+	request, _ := http.NewRequest(http.MethodGet, uri, nil)
+	request = request.WithContext(common_http.WithRole(common_http.WithAccount(request.Context(), account), role))
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/orders", common_http.DefaultGETValidationMiddleware(handler.GetOrdersHandle))
+	router.Handle(http.MethodGet, "/orders/:orderid", common_http.DefaultGETValidationMiddleware(handler.GetOrderHandle))
+	router.ServeHTTP(response, request)
+
+	return response
+}
+
+func addOrder(book *models.OrderBook, account string, symbol string, direction models.TradeDirection, traded uint) *models.Order {
+	order := models.NewOrderFull(uuid.NewV4(), symbol, 1.99, 10, traded, direction, models.ResolveStatus(10, traded))
+	order.Account = account
+	book.Orders[order.ID] = order
+	return order
+}
+
+func TestGetOrderHandle(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	order := addOrder(book, "ACC1", "TT", models.Sell, 4)
+
+	response := getOrderQueryResponse(book, "/orders/"+order.ID.String(), "ACC1", common_http.TraderRole)
+	require.Equal(http.StatusOK, response.Code)
+	require.Equal("application/json", response.Header().Get("Content-Type"))
+
+	var result OrderResponse
+	err := json.Unmarshal(response.Body.Bytes(), &result)
+	require.Nil(err)
+	require.Equal(OrderResponse{order.ID.String(), "", "ACC1", "TT", models.SellText, 1.99, 10, 4, 6, models.PartiallyFilledText}, result)
+}
+
+func TestGetOrderHandleOwnership(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	order := addOrder(book, "ACC1", "TT", models.Buy, 0)
+
+	response := getOrderQueryResponse(book, "/orders/"+order.ID.String(), "ACC2", common_http.TraderRole)
+	require.Equal(http.StatusForbidden, response.Code)
+
+	response = getOrderQueryResponse(book, "/orders/"+order.ID.String(), "ADMIN", common_http.AdminRole)
+	require.Equal(http.StatusOK, response.Code)
+}
+
+func TestGetOrderHandleNotFound(t *testing.T) {
+
+	require := require.New(t)
+
+	response := getOrderQueryResponse(models.NewOrderBook(), "/orders/"+uuid.NewV4().String(), "ACC1", common_http.TraderRole)
+	require.Equal(http.StatusNotFound, response.Code)
+
+	response = getOrderQueryResponse(models.NewOrderBook(), "/orders/invalid", "ACC1", common_http.TraderRole)
+	require.Equal(http.StatusBadRequest, response.Code)
+}
+
+func TestGetOrdersHandleFilters(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	filled := addOrder(book, "ACC1", "TT", models.Buy, 10)
+	addOrder(book, "ACC1", "TT", models.Sell, 0)
+	addOrder(book, "ACC1", "XX", models.Buy, 0)
+	addOrder(book, "ACC2", "TT", models.Buy, 10)
+
+	var page OrdersResponse
+
+	response := getOrderQueryResponse(book, "/orders?symbol=tt&status=FullyFilled&direction=Buy", "ACC1", common_http.TraderRole)
+	require.Equal(http.StatusOK, response.Code)
+	err := json.Unmarshal(response.Body.Bytes(), &page)
+	require.Nil(err)
+	require.Len(page.Orders, 1)
+	require.Equal(filled.ID.String(), page.Orders[0].ID)
+	require.Equal(uint(0), page.Orders[0].Remaining)
+
+	response = getOrderQueryResponse(book, "/orders", "ACC1", common_http.TraderRole)
+	err = json.Unmarshal(response.Body.Bytes(), &page)
+	require.Nil(err)
+	require.Len(page.Orders, 3)
+
+	response = getOrderQueryResponse(book, "/orders?account=ACC2", "ACC1", common_http.TraderRole)
+	require.Equal(http.StatusForbidden, response.Code)
+
+	response = getOrderQueryResponse(book, "/orders?account=ACC2", "ADMIN", common_http.AdminRole)
+	err = json.Unmarshal(response.Body.Bytes(), &page)
+	require.Nil(err)
+	require.Len(page.Orders, 1)
+	require.Equal("ACC2", page.Orders[0].Account)
+
+	response = getOrderQueryResponse(book, "/orders", "ADMIN", common_http.AdminRole)
+	err = json.Unmarshal(response.Body.Bytes(), &page)
+	require.Nil(err)
+	require.Len(page.Orders, 4)
+}
+
+func TestGetOrdersHandlePaging(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	for i := 0; i < 5; i++ {
+		addOrder(book, "ACC1", "TT", models.Buy, 0)
+	}
+
+	seen := make(map[string]bool)
+	after := ""
+
+	for i := 0; i < 3; i++ {
+		response := getOrderQueryResponse(book, "/orders?limit=2&after="+after, "ACC1", common_http.TraderRole)
+		require.Equal(http.StatusOK, response.Code)
+
+		var page OrdersResponse
+		err := json.Unmarshal(response.Body.Bytes(), &page)
+		require.Nil(err)
+
+		for _, order := range page.Orders {
+			require.True(order.ID > after)
+			seen[order.ID] = true
+		}
+		after = page.NextAfter
+	}
+
+	require.Len(seen, 5)
+
+	response := getOrderQueryResponse(book, "/orders?after="+after, "ACC1", common_http.TraderRole)
+	var page OrdersResponse
+	err := json.Unmarshal(response.Body.Bytes(), &page)
+	require.Nil(err)
+	require.Empty(page.Orders)
+	require.Equal(after, page.NextAfter)
+}
+
+func TestGetOrdersHandleBadRequest(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+
+	for _, uri := range []string{"/orders?status=Open", "/orders?direction=Up", "/orders?limit=0", "/orders?limit=5000", "/orders?after=1"} {
+		response := getOrderQueryResponse(book, uri, "ACC1", common_http.TraderRole)
+		require.Equal(http.StatusBadRequest, response.Code, uri)
+	}
+}
+
+func TestGetOrdersHandleWhileTrading(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	publisher := &mocks.MockPublisher{}
+	handler := NewOrderHandler(book, trading.NewOrderAppender(), &mocks.MockAmender{}, trading.NewOrderTrader(publisher), &mocks.MockCanceller{},
+		&mocks.MockLimitChecker{}, &mocks.MockBookListener{}, publisher)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			direction := models.Buy
+			if i%2 == 1 {
+				direction = models.Sell
+			}
+			handler.ExecuteOrder("ACC1", OrderDTO{"", "TT", 10, direction.String(), 1.99, ""})
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			require.Equal(http.StatusOK, getOrderQueryResponse(book, "/orders?limit=1000", "ACC1", common_http.TraderRole).Code)
+		}
+	}()
+
+	wg.Wait()
+
+	var response OrdersResponse
+	json.Unmarshal(getOrderQueryResponse(book, "/orders?limit=1000", "ACC1", common_http.TraderRole).Body.Bytes(), &response)
+	require.Len(response.Orders, 200)
+}
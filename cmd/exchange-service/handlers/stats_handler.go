@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// ClosePriceResponse returns the official closing price of the previous session
+type ClosePriceResponse struct {
+	Price    float64   `json:"price"`
+	ClosedAt time.Time `json:"closed_at"`
+}
+
+// SymbolStatsResponse returns the statistics of the current session of a symbol
+type SymbolStatsResponse struct {
+	Symbol        string              `json:"symbol"`
+	Open          float64             `json:"open"`
+	High          float64             `json:"high"`
+	Low           float64             `json:"low"`
+	Last          float64             `json:"last"`
+	Volume        uint                `json:"volume"`
+	Turnover      float64             `json:"turnover"`
+	VWAP          float64             `json:"vwap"`
+	Trades        uint                `json:"trades"`
+	Updated       time.Time           `json:"updated"`
+	PreviousClose *ClosePriceResponse `json:"previous_close,omitempty"`
+}
+
+// StatsHandler handles daily statistics requests
+type StatsHandler struct {
+	stats *trading.DailyStats
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(stats *trading.DailyStats) *StatsHandler {
+	return &StatsHandler{stats}
+}
+
+// GetAllStatsHandle is the handler for getting the statistics of all symbols
+func (sh *StatsHandler) GetAllStatsHandle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+
+	all := sh.stats.AllStats()
+
+	response := make([]SymbolStatsResponse, 0, len(all))
+	for _, stats := range all {
+		response = append(response, sh.getSymbolStatsResponse(stats))
+	}
+
+	encoded, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetAllStatsHandle: Returned %d symbols", len(response))
+}
+
+// GetStatsHandle is the handler for getting the statistics of a symbol
+func (sh *StatsHandler) GetStatsHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	symbol := strings.ToUpper(p.ByName("symbol"))
+
+	stats, ok := sh.stats.Stats(symbol)
+	if !ok {
+		_, closed := sh.stats.PreviousClose(symbol)
+		if !closed {
+			log.Printf("GetStatsHandle: Symbol %s not found", symbol)
+			common_http.WriteNotFound(w, r, fmt.Sprintf("Symbol %s not found", symbol))
+			return
+		}
+		stats.Symbol = symbol
+	}
+
+	encoded, _ := json.Marshal(sh.getSymbolStatsResponse(stats))
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetStatsHandle: Symbol %s returned", symbol)
+}
+
+func (sh *StatsHandler) getSymbolStatsResponse(stats trading.SymbolStats) SymbolStatsResponse {
+
+	response := SymbolStatsResponse{stats.Symbol, stats.Open, stats.High, stats.Low, stats.Last, stats.Volume,
+		stats.Turnover, stats.VWAP(), stats.Trades, stats.Updated, nil}
+
+	previous, ok := sh.stats.PreviousClose(stats.Symbol)
+	if ok {
+		response.PreviousClose = &ClosePriceResponse{previous.ClosePrice, previous.ClosedAt}
+	}
+
+	return response
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func getStatsRouter(stats *trading.DailyStats) *httprouter.Router {
+
+	handler := NewStatsHandler(stats)
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/stats", common_http.DefaultGETValidationMiddleware(handler.GetAllStatsHandle))
+	router.Handle(http.MethodGet, "/stats/:symbol", common_http.DefaultGETValidationMiddleware(handler.GetStatsHandle))
+	return router
+}
+
+func TestGetStatsHandle(t *testing.T) {
+
+	require := require.New(t)
+
+	sessionClose := time.Date(2016, 8, 13, 16, 0, 0, 0, time.UTC)
+	stats := trading.NewDailyStats(trading.LastTradeClosing, 5*time.Minute)
+	stats.Traded(models.NewTrade(uuid.NewV4(), 1, "TT", 2.0, 10, models.Buy, uuid.NewV4(), uuid.NewV4(), sessionClose.Add(-time.Hour)))
+	stats.Close(sessionClose)
+	stats.Traded(models.NewTrade(uuid.NewV4(), 2, "TT", 3.0, 10, models.Buy, uuid.NewV4(), uuid.NewV4(), sessionClose.Add(time.Hour)))
+
+	request, _ := http.NewRequest(http.MethodGet, "/stats/tt", nil)
+	response := httptest.NewRecorder()
+	getStatsRouter(stats).ServeHTTP(response, request)
+
+	require.Equal(http.StatusOK, response.Code)
+
+	var tt SymbolStatsResponse
+	err := json.Unmarshal(response.Body.Bytes(), &tt)
+	require.Nil(err)
+	require.Equal("TT", tt.Symbol)
+	require.Equal(3.0, tt.Last)
+	require.Equal(uint(1), tt.Trades)
+	require.Equal(&ClosePriceResponse{2.0, sessionClose}, tt.PreviousClose)
+
+	request, _ = http.NewRequest(http.MethodGet, "/stats", nil)
+	response = httptest.NewRecorder()
+	getStatsRouter(stats).ServeHTTP(response, request)
+
+	var all []SymbolStatsResponse
+	err = json.Unmarshal(response.Body.Bytes(), &all)
+	require.Nil(err)
+	require.Len(all, 1)
+}
+
+func TestGetStatsHandleNotFound(t *testing.T) {
+
+	require := require.New(t)
+
+	request, _ := http.NewRequest(http.MethodGet, "/stats/TT", nil)
+	response := httptest.NewRecorder()
+	getStatsRouter(trading.NewDailyStats(trading.LastTradeClosing, time.Minute)).ServeHTTP(response, request)
+
+	require.Equal(http.StatusNotFound, response.Code)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// Paging of the trade tape
+const (
+	DefaultTradesLimit = 100
+	MaxTradesLimit     = 1000
+)
+
+// TradeResponse returns a execution of the trade tape
+type TradeResponse struct {
+	TradeID   string    `json:"trade_id"`
+	Sequence  uint64    `json:"sequence"`
+	Time      time.Time `json:"time"`
+	Price     float64   `json:"price"`
+	Quantity  uint      `json:"quantity"`
+	Aggressor string    `json:"aggressor"`
+}
+
+// TradesResponse returns a page of the trade tape, next_sequence is used as after for the next page
+type TradesResponse struct {
+	Symbol       string          `json:"symbol"`
+	Trades       []TradeResponse `json:"trades"`
+	NextSequence uint64          `json:"next_sequence"`
+}
+
+// TradesHandler handles trade tape requests
+type TradesHandler struct {
+	tape *trading.TradeTape
+}
+
+// NewTradesHandler creates a new trades handler
+func NewTradesHandler(tape *trading.TradeTape) *TradesHandler {
+	return &TradesHandler{tape}
+}
+
+// GetTradesHandle is the handler for getting the recent trades of a symbol.
+// The query parameters after (sequence), from and to (RFC 3339) and limit page through the tape.
+func (th *TradesHandler) GetTradesHandle(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+
+	symbol := strings.ToUpper(p.ByName("symbol"))
+
+	query, err := getTradeQuery(r.URL.Query())
+	if err != nil {
+		log.Printf("GetTradesHandle: Invalid query! %s", err)
+		common_http.WriteError(w, r, http.StatusBadRequest, common_http.InvalidRequestCode, err.Error())
+		return
+	}
+
+	trades := th.tape.Trades(symbol, query)
+
+	response := TradesResponse{symbol, make([]TradeResponse, 0, len(trades)), query.AfterSequence}
+
+	for _, trade := range trades {
+		response.Trades = append(response.Trades, TradeResponse{trade.ID.String(), trade.Sequence, trade.Occured, trade.Price, trade.Quantity, trade.Aggressor.String()})
+		response.NextSequence = trade.Sequence
+	}
+
+	encoded, _ := json.Marshal(response)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(encoded)
+	log.Printf("GetTradesHandle: Returned %d trades of %s", len(trades), symbol)
+}
+
+func getTradeQuery(values url.Values) (trading.TradeQuery, error) {
+
+	query := trading.TradeQuery{Limit: DefaultTradesLimit}
+	var err error
+
+	if value := values.Get("after"); value != "" {
+		query.AfterSequence, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return query, err
+		}
+	}
+
+	if value := values.Get("from"); value != "" {
+		query.From, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, err
+		}
+	}
+
+	if value := values.Get("to"); value != "" {
+		query.To, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, err
+		}
+	}
+
+	if value := values.Get("limit"); value != "" {
+		query.Limit, err = strconv.Atoi(value)
+		if err != nil {
+			return query, err
+		}
+		if query.Limit <= 0 || query.Limit > MaxTradesLimit {
+			return query, fmt.Errorf("Limit %d is not between 1 and %d", query.Limit, MaxTradesLimit)
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func getTradesResponse(tape *trading.TradeTape, uri string) *httptest.ResponseRecorder {
+
+	handler := NewTradesHandler(tape)
+
+	request, _ := http.NewRequest(http.MethodGet, uri, nil)
+	response := httptest.NewRecorder()
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/trades/:symbol", common_http.DefaultGETValidationMiddleware(handler.GetTradesHandle))
+	router.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestGetTradesHandlePaging(t *testing.T) {
+
+	require := require.New(t)
+
+	start := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+	tape := trading.NewTradeTape(10)
+	for i := 1; i <= 3; i++ {
+		tape.Traded(models.NewTrade(uuid.NewV4(), uint64(i), "TT", 1.99, 10, models.Sell, uuid.NewV4(), uuid.NewV4(), start.Add(time.Duration(i)*time.Second)))
+	}
+
+	response := getTradesResponse(tape, "/trades/tt?after=1&limit=1")
+	require.Equal(http.StatusOK, response.Code)
+
+	var page TradesResponse
+	err := json.Unmarshal(response.Body.Bytes(), &page)
+	require.Nil(err)
+	require.Equal("TT", page.Symbol)
+	require.Len(page.Trades, 1)
+	require.Equal(uint64(2), page.Trades[0].Sequence)
+	require.Equal(models.SellText, page.Trades[0].Aggressor)
+	require.Equal(start.Add(2*time.Second), page.Trades[0].Time)
+	require.Equal(uint64(2), page.NextSequence)
+
+	response = getTradesResponse(tape, "/trades/TT?from=2016-08-13T17:33:03Z")
+	err = json.Unmarshal(response.Body.Bytes(), &page)
+	require.Nil(err)
+	require.Len(page.Trades, 1)
+	require.Equal(uint64(3), page.Trades[0].Sequence)
+}
+
+func TestGetTradesHandleEmpty(t *testing.T) {
+
+	require := require.New(t)
+
+	response := getTradesResponse(trading.NewTradeTape(10), "/trades/TT?after=5")
+	require.Equal(http.StatusOK, response.Code)
+
+	var page TradesResponse
+	err := json.Unmarshal(response.Body.Bytes(), &page)
+	require.Nil(err)
+	require.Empty(page.Trades)
+	require.Equal(uint64(5), page.NextSequence)
+}
+
+func TestGetTradesHandleBadRequest(t *testing.T) {
+
+	require := require.New(t)
+
+	tape := trading.NewTradeTape(10)
+
+	require.Equal(http.StatusBadRequest, getTradesResponse(tape, "/trades/TT?after=x").Code)
+	require.Equal(http.StatusBadRequest, getTradesResponse(tape, "/trades/TT?from=yesterday").Code)
+	require.Equal(http.StatusBadRequest, getTradesResponse(tape, "/trades/TT?limit=0").Code)
+	require.Equal(http.StatusBadRequest, getTradesResponse(tape, "/trades/TT?limit=5000").Code)
+}
@@ -0,0 +1,96 @@
+package marketdata
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// BookEventPublisher publishes the changes of the book as price level and best bid offer events.
+// Every event carries the next sequence of its symbol, so consumers can detect gaps and recover from a snapshot.
+type BookEventPublisher struct {
+	publisher events.EventPublisher
+	tracker   *BookTracker
+	sequences map[string]uint64
+	mu        sync.Mutex
+}
+
+// NewBookEventPublisher creates a new book event publisher
+func NewBookEventPublisher(publisher events.EventPublisher) *BookEventPublisher {
+	return &BookEventPublisher{publisher, NewBookTracker(), make(map[string]uint64), sync.Mutex{}}
+}
+
+// BookChanged tracks the book of the symbol and publishes the changes
+func (bep *BookEventPublisher) BookChanged(book *models.OrderBook, symbol string) {
+
+	bep.mu.Lock()
+	defer bep.mu.Unlock()
+
+	changes := bep.tracker.Track(book, symbol)
+	if changes == nil {
+		return
+	}
+
+	occured := time.Now().UTC()
+
+	for _, change := range changes.Levels {
+		bep.sequences[symbol]++
+		ev := events.NewPriceLevelUpdated(symbol, bep.sequences[symbol], getPriceLevelAction(change.Action), getDirection(change.Side),
+			change.Price, change.Quantity, change.Orders, occured, uint(1))
+		bep.publish(ev, ev.EventType)
+	}
+
+	if changes.TopOfBook != nil {
+		top := changes.TopOfBook
+		bep.sequences[symbol]++
+		ev := events.NewBestBidOfferChanged(symbol, bep.sequences[symbol], top.BidPrice, top.BidQuantity, top.AskPrice, top.AskQuantity, occured, uint(1))
+		bep.publish(ev, ev.EventType)
+	}
+}
+
+// Snapshot returns the depth and the best bid offer of the symbol along with the sequence of the last published event
+func (bep *BookEventPublisher) Snapshot(symbol string) (uint64, Depth, TopOfBook) {
+
+	bep.mu.Lock()
+	defer bep.mu.Unlock()
+
+	symbol = strings.ToUpper(symbol)
+
+	return bep.sequences[symbol], bep.tracker.Depth(symbol), bep.tracker.TopOfBook(symbol)
+}
+
+func (bep *BookEventPublisher) publish(event interface{}, eventType events.OrderEventType) {
+
+	env, err := events.NewOrderEventEnvelope(event, eventType)
+	if err != nil {
+		log.Printf("Failed to create envelope: %s", err.Error())
+		return
+	}
+
+	err = bep.publisher.Publish(env)
+	if err != nil {
+		log.Printf("Failed to publish book event: %s", event)
+	}
+}
+
+func getPriceLevelAction(action Action) events.PriceLevelAction {
+	switch action {
+	case AddedAction:
+		return events.PriceLevelAdded
+	case RemovedAction:
+		return events.PriceLevelRemoved
+	default:
+		return events.PriceLevelChanged
+	}
+}
+
+func getDirection(side Side) models.TradeDirection {
+	if side == BidSide {
+		return models.Buy
+	}
+	return models.Sell
+}
@@ -0,0 +1,78 @@
+package marketdata
+
+import (
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func TestBookEventPublisherPublishesSequencedEvents(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	appender := trading.NewOrderAppender()
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 10.0, 10, models.Buy))
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 11.0, 5, models.Sell))
+
+	publisher := &mocks.MockPublisher{}
+	bep := NewBookEventPublisher(publisher)
+	bep.BookChanged(book, "TT")
+
+	require.Len(publisher.Envelopes, 3)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	level := event.(events.PriceLevelUpdated)
+	require.Equal(uint64(1), level.Sequence)
+	require.Equal(events.PriceLevelAdded, level.Action)
+	require.Equal(models.BuyText, level.Direction)
+	require.Equal(10.0, level.Price)
+	require.Equal(uint(10), level.Quantity)
+
+	event, err = publisher.Envelopes[2].GetOrderEvent()
+	require.Nil(err)
+	bbo := event.(events.BestBidOfferChanged)
+	require.Equal(uint64(3), bbo.Sequence)
+	require.Equal(10.0, bbo.BidPrice)
+	require.Equal(11.0, bbo.AskPrice)
+
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 9.0, 5, models.Buy))
+	bep.BookChanged(book, "TT")
+
+	require.Len(publisher.Envelopes, 4)
+	event, _ = publisher.Envelopes[3].GetOrderEvent()
+	require.Equal(uint64(4), event.(events.PriceLevelUpdated).Sequence)
+
+	sequence, depth, top := bep.Snapshot("tt")
+	require.Equal(uint64(4), sequence)
+	require.Len(depth.Bids, 2)
+	require.Equal(TopOfBook{10.0, 10, 11.0, 5}, top)
+}
+
+func TestBookEventPublisherRemovedLevel(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	order := models.NewOrder(uuid.NewV4(), "TT", 10.0, 10, models.Buy)
+	trading.NewOrderAppender().Append(book, order)
+
+	publisher := &mocks.MockPublisher{}
+	bep := NewBookEventPublisher(publisher)
+	bep.BookChanged(book, "TT")
+
+	trading.NewOrderCanceller(&mocks.MockPublisher{}).Cancel(book, order.ID)
+	bep.BookChanged(book, "TT")
+
+	require.Len(publisher.Envelopes, 4)
+	event, _ := publisher.Envelopes[2].GetOrderEvent()
+	level := event.(events.PriceLevelUpdated)
+	require.Equal(events.PriceLevelRemoved, level.Action)
+	require.Equal(uint(0), level.Quantity)
+}
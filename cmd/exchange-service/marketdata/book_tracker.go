@@ -0,0 +1,317 @@
+package marketdata
+
+import (
+	"sort"
+
+	"github.com/satori/go.uuid"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// Side of the book
+type Side string
+
+// Bid and ask side
+const (
+	BidSide Side = "bid"
+	AskSide Side = "ask"
+)
+
+// Action defines what happened to a price level or order
+type Action string
+
+// The book actions
+const (
+	AddedAction   Action = "added"
+	ChangedAction Action = "changed"
+	RemovedAction Action = "removed"
+)
+
+// Level defines the aggregated quantity of a price on one side
+type Level struct {
+	Side     Side    `json:"side"`
+	Price    float64 `json:"price"`
+	Quantity uint    `json:"quantity"`
+	Orders   uint    `json:"orders"`
+}
+
+// LevelChange defines a change of a price level, removed levels have zero quantity
+type LevelChange struct {
+	Action Action `json:"action"`
+	Level
+}
+
+// OrderEntry defines a resting order
+type OrderEntry struct {
+	OrderID  string  `json:"order_id"`
+	Side     Side    `json:"side"`
+	Price    float64 `json:"price"`
+	Quantity uint    `json:"quantity"`
+}
+
+// OrderChange defines a change of a resting order, removed orders have zero quantity
+type OrderChange struct {
+	Action Action `json:"action"`
+	OrderEntry
+}
+
+// TopOfBook defines the best bid and offer, a empty side has zero price and quantity
+type TopOfBook struct {
+	BidPrice    float64 `json:"bid_price"`
+	BidQuantity uint    `json:"bid_quantity"`
+	AskPrice    float64 `json:"ask_price"`
+	AskQuantity uint    `json:"ask_quantity"`
+}
+
+// Depth defines the price levels, bids descending and asks ascending
+type Depth struct {
+	Bids []Level `json:"bids"`
+	Asks []Level `json:"asks"`
+}
+
+// BookChanges defines the changes of a symbol since the previous tracking.
+// TopOfBook is nil when the best bid and offer did not change.
+type BookChanges struct {
+	Symbol    string
+	Sequence  uint64
+	Levels    []LevelChange
+	Orders    []OrderChange
+	TopOfBook *TopOfBook
+}
+
+type levelKey struct {
+	side  Side
+	price float64
+}
+
+type trackedOrder struct {
+	id       uuid.UUID
+	entry    OrderEntry
+	priority uint64
+}
+
+type symbolState struct {
+	sequence uint64
+	levels   map[levelKey]Level
+	orders   map[uuid.UUID]trackedOrder
+	top      TopOfBook
+}
+
+// BookTracker keeps the last seen state of the book per symbol and computes the changes against it.
+// It is not safe for concurrent use.
+type BookTracker struct {
+	symbols  map[string]*symbolState
+	priority uint64
+}
+
+// NewBookTracker creates a new book tracker
+func NewBookTracker() *BookTracker {
+	return &BookTracker{make(map[string]*symbolState), 0}
+}
+
+// Track compares the book of the symbol with the last seen state and returns the changes, or nil if nothing changed
+func (bt *BookTracker) Track(book *models.OrderBook, symbol string) *BookChanges {
+
+	state := bt.getState(symbol)
+
+	levels := make(map[levelKey]Level)
+	orders := make(map[uuid.UUID]trackedOrder)
+	changes := &BookChanges{symbol, 0, make([]LevelChange, 0), make([]OrderChange, 0), nil}
+
+	for _, price := range book.Symbols[symbol] {
+		bt.trackOrders(state, price.Buy.Orders, levels, orders, changes)
+		bt.trackOrders(state, price.Sell.Orders, levels, orders, changes)
+	}
+
+	for _, tracked := range sortedOrders(state.orders) {
+		if _, ok := orders[tracked.id]; !ok {
+			removed := tracked.entry
+			removed.Quantity = 0
+			changes.Orders = append(changes.Orders, OrderChange{RemovedAction, removed})
+		}
+	}
+
+	for _, level := range sortedLevels(levels) {
+		previous, ok := state.levels[levelKey{level.Side, level.Price}]
+		if !ok {
+			changes.Levels = append(changes.Levels, LevelChange{AddedAction, level})
+		} else if previous != level {
+			changes.Levels = append(changes.Levels, LevelChange{ChangedAction, level})
+		}
+	}
+
+	for _, level := range sortedLevels(state.levels) {
+		if _, ok := levels[levelKey{level.Side, level.Price}]; !ok {
+			changes.Levels = append(changes.Levels, LevelChange{RemovedAction, Level{level.Side, level.Price, 0, 0}})
+		}
+	}
+
+	state.levels = levels
+	state.orders = orders
+
+	top := getTopOfBook(levels)
+	if top != state.top {
+		state.top = top
+		changes.TopOfBook = &top
+	}
+
+	if len(changes.Levels) == 0 && len(changes.Orders) == 0 && changes.TopOfBook == nil {
+		return nil
+	}
+
+	state.sequence++
+	changes.Sequence = state.sequence
+
+	return changes
+}
+
+// Sequence returns the sequence of the last changes of the symbol
+func (bt *BookTracker) Sequence(symbol string) uint64 {
+	return bt.getState(symbol).sequence
+}
+
+// Depth returns the price levels of the symbol
+func (bt *BookTracker) Depth(symbol string) Depth {
+
+	depth := Depth{make([]Level, 0), make([]Level, 0)}
+
+	for _, level := range sortedLevels(bt.getState(symbol).levels) {
+		if level.Side == BidSide {
+			depth.Bids = append(depth.Bids, level)
+		} else {
+			depth.Asks = append(depth.Asks, level)
+		}
+	}
+
+	return depth
+}
+
+// Orders returns the resting orders of the symbol in price and time priority
+func (bt *BookTracker) Orders(symbol string) []OrderEntry {
+
+	entries := make([]OrderEntry, 0)
+
+	for _, tracked := range sortedOrders(bt.getState(symbol).orders) {
+		entries = append(entries, tracked.entry)
+	}
+
+	return entries
+}
+
+// TopOfBook returns the best bid and offer of the symbol
+func (bt *BookTracker) TopOfBook(symbol string) TopOfBook {
+	return bt.getState(symbol).top
+}
+
+func (bt *BookTracker) trackOrders(state *symbolState, bookOrders []*models.Order, levels map[levelKey]Level, orders map[uuid.UUID]trackedOrder, changes *BookChanges) {
+
+	for _, order := range bookOrders {
+
+		if _, ok := orders[order.ID]; ok || !order.Status.IsTradeable() || order.Remaining() == 0 {
+			continue
+		}
+
+		side := getSide(order.Direction)
+		entry := OrderEntry{order.ID.String(), side, order.Price, order.Remaining()}
+
+		previous, ok := state.orders[order.ID]
+		if !ok {
+			bt.priority++
+			orders[order.ID] = trackedOrder{order.ID, entry, bt.priority}
+			changes.Orders = append(changes.Orders, OrderChange{AddedAction, entry})
+		} else {
+			orders[order.ID] = trackedOrder{order.ID, entry, previous.priority}
+			if previous.entry != entry {
+				changes.Orders = append(changes.Orders, OrderChange{ChangedAction, entry})
+			}
+		}
+
+		key := levelKey{side, order.Price}
+		level := levels[key]
+		level.Side = side
+		level.Price = order.Price
+		level.Quantity += entry.Quantity
+		level.Orders++
+		levels[key] = level
+	}
+}
+
+func (bt *BookTracker) getState(symbol string) *symbolState {
+
+	state, ok := bt.symbols[symbol]
+	if !ok {
+		state = &symbolState{0, make(map[levelKey]Level), make(map[uuid.UUID]trackedOrder), TopOfBook{}}
+		bt.symbols[symbol] = state
+	}
+
+	return state
+}
+
+func getSide(direction models.TradeDirection) Side {
+	if direction == models.Buy {
+		return BidSide
+	}
+	return AskSide
+}
+
+func getTopOfBook(levels map[levelKey]Level) TopOfBook {
+
+	var top TopOfBook
+
+	for _, level := range levels {
+		if level.Side == BidSide && (top.BidQuantity == 0 || level.Price > top.BidPrice) {
+			top.BidPrice = level.Price
+			top.BidQuantity = level.Quantity
+		} else if level.Side == AskSide && (top.AskQuantity == 0 || level.Price < top.AskPrice) {
+			top.AskPrice = level.Price
+			top.AskQuantity = level.Quantity
+		}
+	}
+
+	return top
+}
+
+// better returns true if price a comes before price b on the side
+func better(side Side, a float64, b float64) bool {
+	if side == BidSide {
+		return a > b
+	}
+	return a < b
+}
+
+func sortedLevels(levels map[levelKey]Level) []Level {
+
+	sorted := make([]Level, 0, len(levels))
+	for _, level := range levels {
+		sorted = append(sorted, level)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Side != sorted[j].Side {
+			return sorted[i].Side == BidSide
+		}
+		return better(sorted[i].Side, sorted[i].Price, sorted[j].Price)
+	})
+
+	return sorted
+}
+
+func sortedOrders(orders map[uuid.UUID]trackedOrder) []trackedOrder {
+
+	sorted := make([]trackedOrder, 0, len(orders))
+	for _, tracked := range orders {
+		sorted = append(sorted, tracked)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.entry.Side != b.entry.Side {
+			return a.entry.Side == BidSide
+		}
+		if a.entry.Price != b.entry.Price {
+			return better(a.entry.Side, a.entry.Price, b.entry.Price)
+		}
+		return a.priority < b.priority
+	})
+
+	return sorted
+}
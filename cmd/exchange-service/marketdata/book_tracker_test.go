@@ -0,0 +1,127 @@
+package marketdata
+
+import (
+	"testing"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func TestBookTrackerAdded(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	buy := models.NewOrder(uuid.NewV4(), "TT", 10.0, 10, models.Buy)
+	sell := models.NewOrder(uuid.NewV4(), "TT", 11.0, 5, models.Sell)
+
+	appender := trading.NewOrderAppender()
+	appender.Append(book, buy)
+	appender.Append(book, sell)
+
+	tracker := NewBookTracker()
+	changes := tracker.Track(book, "TT")
+
+	require.NotNil(changes)
+	require.Equal(uint64(1), changes.Sequence)
+	require.Equal([]LevelChange{
+		{AddedAction, Level{BidSide, 10.0, 10, 1}},
+		{AddedAction, Level{AskSide, 11.0, 5, 1}},
+	}, changes.Levels)
+	require.Len(changes.Orders, 2)
+	require.Equal(AddedAction, changes.Orders[0].Action)
+	require.Equal(buy.ID.String(), changes.Orders[0].OrderID)
+	require.Equal(&TopOfBook{10.0, 10, 11.0, 5}, changes.TopOfBook)
+
+	require.Nil(tracker.Track(book, "TT"))
+	require.Equal(uint64(1), tracker.Sequence("TT"))
+}
+
+func TestBookTrackerChangedAndRemoved(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	sell1 := models.NewOrder(uuid.NewV4(), "TT", 11.0, 5, models.Sell)
+	sell2 := models.NewOrder(uuid.NewV4(), "TT", 12.0, 5, models.Sell)
+
+	appender := trading.NewOrderAppender()
+	appender.Append(book, sell1)
+	appender.Append(book, sell2)
+
+	tracker := NewBookTracker()
+	tracker.Track(book, "TT")
+
+	buy := models.NewOrder(uuid.NewV4(), "TT", 12.0, 7, models.Buy)
+	trading.NewOrderTrader(&mocks.MockPublisher{}).Trade(book, buy)
+
+	changes := tracker.Track(book, "TT")
+
+	require.NotNil(changes)
+	require.Equal(uint64(2), changes.Sequence)
+	require.Equal([]LevelChange{
+		{ChangedAction, Level{AskSide, 12.0, 3, 1}},
+		{RemovedAction, Level{AskSide, 11.0, 0, 0}},
+	}, changes.Levels)
+	require.Equal([]OrderChange{
+		{ChangedAction, OrderEntry{sell2.ID.String(), AskSide, 12.0, 3}},
+		{RemovedAction, OrderEntry{sell1.ID.String(), AskSide, 11.0, 0}},
+	}, changes.Orders)
+	require.Equal(&TopOfBook{0, 0, 12.0, 3}, changes.TopOfBook)
+}
+
+func TestBookTrackerCancelled(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	buy := models.NewOrder(uuid.NewV4(), "TT", 10.0, 10, models.Buy)
+	trading.NewOrderAppender().Append(book, buy)
+
+	tracker := NewBookTracker()
+	tracker.Track(book, "TT")
+
+	trading.NewOrderCanceller(&mocks.MockPublisher{}).Cancel(book, buy.ID)
+
+	changes := tracker.Track(book, "TT")
+
+	require.NotNil(changes)
+	require.Equal([]LevelChange{{RemovedAction, Level{BidSide, 10.0, 0, 0}}}, changes.Levels)
+	require.Equal(&TopOfBook{}, changes.TopOfBook)
+	require.Equal(Depth{[]Level{}, []Level{}}, tracker.Depth("TT"))
+}
+
+func TestBookTrackerSnapshots(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	buy1 := models.NewOrder(uuid.NewV4(), "TT", 10.0, 10, models.Buy)
+	buy2 := models.NewOrder(uuid.NewV4(), "TT", 10.0, 5, models.Buy)
+	buy3 := models.NewOrder(uuid.NewV4(), "TT", 10.5, 1, models.Buy)
+	sell := models.NewOrder(uuid.NewV4(), "TT", 11.0, 5, models.Sell)
+
+	appender := trading.NewOrderAppender()
+	appender.Append(book, buy1)
+	appender.Append(book, buy2)
+	appender.Append(book, buy3)
+	appender.Append(book, sell)
+
+	tracker := NewBookTracker()
+	tracker.Track(book, "TT")
+
+	require.Equal(Depth{
+		[]Level{{BidSide, 10.5, 1, 1}, {BidSide, 10.0, 15, 2}},
+		[]Level{{AskSide, 11.0, 5, 1}},
+	}, tracker.Depth("TT"))
+	require.Equal([]OrderEntry{
+		{buy3.ID.String(), BidSide, 10.5, 1},
+		{buy1.ID.String(), BidSide, 10.0, 10},
+		{buy2.ID.String(), BidSide, 10.0, 5},
+		{sell.ID.String(), AskSide, 11.0, 5},
+	}, tracker.Orders("TT"))
+	require.Equal(TopOfBook{10.5, 1, 11.0, 5}, tracker.TopOfBook("TT"))
+}
@@ -0,0 +1,205 @@
+package marketdata
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// Channel defines a market data channel
+type Channel string
+
+// The market data channels
+const (
+	TopOfBookChannel Channel = "l1"
+	DepthChannel     Channel = "l2"
+	OrdersChannel    Channel = "l3"
+	TradesChannel    Channel = "trades"
+)
+
+// MessageType defines the type of a message
+type MessageType string
+
+// The message types
+const (
+	SnapshotMessage MessageType = "snapshot"
+	UpdateMessage   MessageType = "update"
+	ErrorMessage    MessageType = "error"
+)
+
+// Message defines a market data message. The sequence is per channel and symbol,
+// a snapshot carries the sequence of the last update it contains.
+type Message struct {
+	Channel  Channel     `json:"channel"`
+	Type     MessageType `json:"type"`
+	Symbol   string      `json:"symbol"`
+	Sequence uint64      `json:"sequence"`
+	Data     interface{} `json:"data"`
+}
+
+// TradeEntry defines a trade
+type TradeEntry struct {
+	TradeID   string    `json:"trade_id"`
+	Sequence  uint64    `json:"sequence"`
+	Price     float64   `json:"price"`
+	Quantity  uint      `json:"quantity"`
+	Aggressor Side      `json:"aggressor"`
+	Occured   time.Time `json:"occured"`
+}
+
+type subscription struct {
+	channel Channel
+	symbol  string
+}
+
+// Feed tracks the book and the trades and sends snapshots and updates to the subscribers
+type Feed struct {
+	tracker       *BookTracker
+	trades        map[string][]TradeEntry
+	maxTrades     int
+	sequences     map[subscription]uint64
+	subscriptions map[subscription]map[*Subscriber]bool
+	mu            sync.Mutex
+}
+
+// NewFeed creates a new feed which keeps up to maxTrades trades per symbol for the trades snapshot
+func NewFeed(maxTrades int) *Feed {
+	return &Feed{NewBookTracker(), make(map[string][]TradeEntry), maxTrades, make(map[subscription]uint64),
+		make(map[subscription]map[*Subscriber]bool), sync.Mutex{}}
+}
+
+// BookChanged tracks the book of the symbol and sends the updates
+func (f *Feed) BookChanged(book *models.OrderBook, symbol string) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	changes := f.tracker.Track(book, symbol)
+	if changes == nil {
+		return
+	}
+
+	if changes.TopOfBook != nil {
+		f.publish(TopOfBookChannel, symbol, *changes.TopOfBook)
+	}
+
+	if len(changes.Levels) > 0 {
+		f.publish(DepthChannel, symbol, changes.Levels)
+	}
+
+	if len(changes.Orders) > 0 {
+		f.publish(OrdersChannel, symbol, changes.Orders)
+	}
+}
+
+// Traded keeps the trade and sends it to the subscribers
+func (f *Feed) Traded(trade *models.Trade) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	symbol := trade.Symbol
+	entry := TradeEntry{trade.ID.String(), trade.Sequence, trade.Price, trade.Quantity, getSide(trade.Aggressor), trade.Occured}
+
+	trades := append(f.trades[symbol], entry)
+	if len(trades) > f.maxTrades {
+		trades = trades[len(trades)-f.maxTrades:]
+	}
+	f.trades[symbol] = trades
+
+	key := subscription{TradesChannel, symbol}
+	f.sequences[key] = trade.Sequence
+	f.send(key, &Message{TradesChannel, UpdateMessage, symbol, trade.Sequence, entry})
+}
+
+// Subscribe sends the snapshot of the channel to the subscriber followed by the updates.
+// Symbols are upper case, like in the order book requests.
+func (f *Feed) Subscribe(subscriber *Subscriber, channel Channel, symbol string) error {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	symbol = strings.ToUpper(symbol)
+	key := subscription{channel, symbol}
+
+	snapshot, err := f.getSnapshot(channel, symbol)
+	if err != nil {
+		return err
+	}
+
+	subscribers, ok := f.subscriptions[key]
+	if !ok {
+		subscribers = make(map[*Subscriber]bool)
+		f.subscriptions[key] = subscribers
+	}
+	subscribers[subscriber] = true
+
+	if !subscriber.Send(&Message{channel, SnapshotMessage, symbol, f.sequences[key], snapshot}) {
+		f.remove(subscriber)
+	}
+
+	return nil
+}
+
+// Unsubscribe stops the updates of the channel to the subscriber
+func (f *Feed) Unsubscribe(subscriber *Subscriber, channel Channel, symbol string) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.subscriptions[subscription{channel, strings.ToUpper(symbol)}], subscriber)
+}
+
+// Remove all subscriptions of the subscriber and close it
+func (f *Feed) Remove(subscriber *Subscriber) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.remove(subscriber)
+}
+
+func (f *Feed) getSnapshot(channel Channel, symbol string) (interface{}, error) {
+
+	switch channel {
+	case TopOfBookChannel:
+		return f.tracker.TopOfBook(symbol), nil
+	case DepthChannel:
+		return f.tracker.Depth(symbol), nil
+	case OrdersChannel:
+		return f.tracker.Orders(symbol), nil
+	case TradesChannel:
+		trades := make([]TradeEntry, len(f.trades[symbol]))
+		copy(trades, f.trades[symbol])
+		return trades, nil
+	default:
+		return nil, fmt.Errorf("Channel %s not supported", channel)
+	}
+}
+
+func (f *Feed) publish(channel Channel, symbol string, data interface{}) {
+
+	key := subscription{channel, symbol}
+	f.sequences[key]++
+	f.send(key, &Message{channel, UpdateMessage, symbol, f.sequences[key], data})
+}
+
+func (f *Feed) send(key subscription, message *Message) {
+
+	for subscriber := range f.subscriptions[key] {
+		if !subscriber.Send(message) {
+			f.remove(subscriber)
+		}
+	}
+}
+
+func (f *Feed) remove(subscriber *Subscriber) {
+
+	for _, subscribers := range f.subscriptions {
+		delete(subscribers, subscriber)
+	}
+	subscriber.Close()
+}
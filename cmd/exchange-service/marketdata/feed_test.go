@@ -0,0 +1,132 @@
+package marketdata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/trading"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func TestFeedSubscribeSnapshotThenUpdates(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	appender := trading.NewOrderAppender()
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 10.0, 10, models.Buy))
+
+	feed := NewFeed(10)
+	feed.BookChanged(book, "TT")
+
+	subscriber := NewSubscriber(10)
+	err := feed.Subscribe(subscriber, DepthChannel, "tt")
+	require.Nil(err)
+
+	snapshot := <-subscriber.Messages()
+	require.Equal(SnapshotMessage, snapshot.Type)
+	require.Equal(uint64(1), snapshot.Sequence)
+	require.Equal(Depth{[]Level{{BidSide, 10.0, 10, 1}}, []Level{}}, snapshot.Data)
+
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 10.0, 5, models.Buy))
+	feed.BookChanged(book, "TT")
+
+	update := <-subscriber.Messages()
+	require.Equal(UpdateMessage, update.Type)
+	require.Equal(DepthChannel, update.Channel)
+	require.Equal(uint64(2), update.Sequence)
+	require.Equal([]LevelChange{{ChangedAction, Level{BidSide, 10.0, 15, 2}}}, update.Data)
+
+	feed.Unsubscribe(subscriber, DepthChannel, "TT")
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 9.0, 5, models.Buy))
+	feed.BookChanged(book, "TT")
+
+	require.Len(subscriber.Messages(), 0)
+}
+
+func TestFeedTopOfBookOnlyOnChange(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	appender := trading.NewOrderAppender()
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 10.0, 10, models.Buy))
+
+	feed := NewFeed(10)
+	subscriber := NewSubscriber(10)
+	feed.Subscribe(subscriber, TopOfBookChannel, "TT")
+	<-subscriber.Messages()
+
+	feed.BookChanged(book, "TT")
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 9.0, 5, models.Buy))
+	feed.BookChanged(book, "TT")
+
+	require.Len(subscriber.Messages(), 1)
+	update := <-subscriber.Messages()
+	require.Equal(uint64(1), update.Sequence)
+	require.Equal(TopOfBook{10.0, 10, 0, 0}, update.Data)
+}
+
+func TestFeedTrades(t *testing.T) {
+
+	require := require.New(t)
+
+	feed := NewFeed(2)
+	occured := time.Now().UTC()
+	trades := []*models.Trade{
+		models.NewTrade(uuid.NewV4(), 1, "TT", 10.0, 1, models.Buy, uuid.NewV4(), uuid.NewV4(), occured),
+		models.NewTrade(uuid.NewV4(), 2, "TT", 10.0, 2, models.Buy, uuid.NewV4(), uuid.NewV4(), occured),
+		models.NewTrade(uuid.NewV4(), 3, "TT", 10.0, 3, models.Sell, uuid.NewV4(), uuid.NewV4(), occured),
+	}
+	for _, trade := range trades {
+		feed.Traded(trade)
+	}
+
+	subscriber := NewSubscriber(10)
+	feed.Subscribe(subscriber, TradesChannel, "TT")
+
+	snapshot := <-subscriber.Messages()
+	require.Equal(uint64(3), snapshot.Sequence)
+	require.Equal([]TradeEntry{
+		{trades[1].ID.String(), 2, 10.0, 2, BidSide, occured},
+		{trades[2].ID.String(), 3, 10.0, 3, AskSide, occured},
+	}, snapshot.Data)
+
+	trade := models.NewTrade(uuid.NewV4(), 4, "TT", 11.0, 4, models.Buy, uuid.NewV4(), uuid.NewV4(), occured)
+	feed.Traded(trade)
+
+	update := <-subscriber.Messages()
+	require.Equal(uint64(4), update.Sequence)
+	require.Equal(TradeEntry{trade.ID.String(), 4, 11.0, 4, BidSide, occured}, update.Data)
+}
+
+func TestFeedUnknownChannel(t *testing.T) {
+
+	require := require.New(t)
+
+	feed := NewFeed(10)
+	err := feed.Subscribe(NewSubscriber(1), Channel("l4"), "TT")
+
+	require.NotNil(err)
+}
+
+func TestFeedSlowSubscriberIsClosed(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	appender := trading.NewOrderAppender()
+
+	feed := NewFeed(10)
+	subscriber := NewSubscriber(1)
+	feed.Subscribe(subscriber, DepthChannel, "TT")
+
+	appender.Append(book, models.NewOrder(uuid.NewV4(), "TT", 10.0, 10, models.Buy))
+	feed.BookChanged(book, "TT")
+
+	<-subscriber.Messages()
+	_, ok := <-subscriber.Messages()
+	require.False(ok)
+}
@@ -0,0 +1,52 @@
+package marketdata
+
+import "sync"
+
+// Subscriber receives the messages of its subscriptions. A subscriber which does not keep up is closed.
+type Subscriber struct {
+	messages chan *Message
+	closed   bool
+	mu       sync.Mutex
+}
+
+// NewSubscriber creates a new subscriber which buffers up to size messages
+func NewSubscriber(size int) *Subscriber {
+	return &Subscriber{make(chan *Message, size), false, sync.Mutex{}}
+}
+
+// Messages returns the channel of the messages, which is closed along with the subscriber
+func (s *Subscriber) Messages() <-chan *Message {
+	return s.messages
+}
+
+// Send queues the message and returns false if the subscriber is closed or its buffer was full
+func (s *Subscriber) Send(message *Message) bool {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	select {
+	case s.messages <- message:
+		return true
+	default:
+		s.closed = true
+		close(s.messages)
+		return false
+	}
+}
+
+// Close the subscriber
+func (s *Subscriber) Close() {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.messages)
+	}
+}
@@ -0,0 +1,150 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	common_http "github.com/tradsim/tradsim-go/net/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// MethodRoles defines the role required by each method of the exchange service
+var MethodRoles = map[string]common_http.Role{
+	Exchange_CreateOrder_FullMethodName:      common_http.TraderRole,
+	Exchange_AmendOrder_FullMethodName:       common_http.TraderRole,
+	Exchange_CancelOrder_FullMethodName:      common_http.TraderRole,
+	Exchange_GetBook_FullMethodName:          common_http.ViewerRole,
+	Exchange_StreamExecutions_FullMethodName: common_http.TraderRole,
+}
+
+// Authenticator authenticates calls by bearer token or api key and authorizes them by the role of the method.
+// Accounts with a secret sign the method name and the request, see SignCall.
+type Authenticator struct {
+	store    common_http.AccountStore
+	verifier *common_http.JWTVerifier
+}
+
+// NewAuthenticator creates a new authenticator
+func NewAuthenticator(store common_http.AccountStore, verifier *common_http.JWTVerifier) *Authenticator {
+	return &Authenticator{store, verifier}
+}
+
+// SignCall returns the signature of a call, the HTTP signature of a POST to the method name
+// with the deterministic protobuf encoding of the request as body
+func SignCall(secret string, method string, timestamp string, req proto.Message) (string, error) {
+
+	body, err := encodeRequest(req)
+	if err != nil {
+		return "", err
+	}
+	return common_http.Sign(secret, "POST", method, timestamp, body), nil
+}
+
+func encodeRequest(req proto.Message) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(req)
+}
+
+// UnaryInterceptor authenticates unary calls
+func (a *Authenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+	message, ok := req.(proto.Message)
+	if !ok {
+		log.Printf("Request of %s is not a protobuf message", info.FullMethod)
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	body, err := encodeRequest(message)
+	if err != nil {
+		log.Printf("Failed to encode request of %s! %s", info.FullMethod, err)
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	ctx, err = a.authenticate(ctx, info.FullMethod, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamInterceptor authenticates streaming calls, the request of a stream is received after the
+// authentication and the executions request has no fields, so stream signatures have a empty body
+func (a *Authenticator) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod, nil)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authenticatedStream{ss, ctx})
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, method string, body []byte) (context.Context, error) {
+
+	required, ok := MethodRoles[method]
+	if !ok {
+		log.Printf("No role defined for %s", method)
+		return nil, status.Error(codes.PermissionDenied, "method not allowed")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var account, apiKey string
+	var role common_http.Role
+
+	if authorization := getMetadata(md, "authorization"); strings.HasPrefix(authorization, "Bearer ") {
+
+		claims, err := a.verifier.Verify(strings.TrimPrefix(authorization, "Bearer "))
+		if err != nil {
+			log.Printf("Failed to verify bearer token! %s", err)
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		account, role = claims.Subject, claims.Role
+
+	} else {
+
+		acc, ok := a.store.GetAccount(getMetadata(md, strings.ToLower(common_http.APIKeyHeader)))
+		if !ok {
+			log.Print("Call with unknown api key")
+			return nil, status.Error(codes.Unauthenticated, "unknown api key")
+		}
+
+		if acc.Secret != "" && !common_http.VerifySignature(acc, "POST", method, getMetadata(md, strings.ToLower(common_http.TimestampHeader)), getMetadata(md, strings.ToLower(common_http.SignatureHeader)), body) {
+			return nil, status.Error(codes.Unauthenticated, "invalid signature")
+		}
+		account, role, apiKey = acc.Name, acc.GetRole(), acc.APIKey
+	}
+
+	if !role.Allows(required) {
+		log.Printf("Role %s was required, but caller has %s", required, role)
+		return nil, status.Error(codes.PermissionDenied, "role not allowed")
+	}
+
+	if apiKey != "" {
+		ctx = common_http.WithAPIKey(ctx, apiKey)
+	}
+
+	return common_http.WithRole(common_http.WithAccount(ctx, account), role), nil
+}
+
+func getMetadata(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,295 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: exchange.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Exchange_CreateOrder_FullMethodName      = "/tradsim.Exchange/CreateOrder"
+	Exchange_AmendOrder_FullMethodName       = "/tradsim.Exchange/AmendOrder"
+	Exchange_CancelOrder_FullMethodName      = "/tradsim.Exchange/CancelOrder"
+	Exchange_GetBook_FullMethodName          = "/tradsim.Exchange/GetBook"
+	Exchange_StreamExecutions_FullMethodName = "/tradsim.Exchange/StreamExecutions"
+)
+
+// ExchangeClient is the client API for Exchange service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExchangeClient interface {
+	// CreateOrder submits a new limit order, repeating a client order id with the same request returns the original reply
+	CreateOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderReply, error)
+	// AmendOrder amends the quantity of a order identified by id or client order id
+	AmendOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderReply, error)
+	// CancelOrder cancels a order identified by id or client order id
+	CancelOrder(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*OrderReply, error)
+	// GetBook returns the depth of a symbol as of the sequence of the book events
+	GetBook(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BookReply, error)
+	// StreamExecutions streams the execution reports of the orders of the caller
+	StreamExecutions(ctx context.Context, in *ExecutionsRequest, opts ...grpc.CallOption) (Exchange_StreamExecutionsClient, error)
+}
+
+type exchangeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExchangeClient(cc grpc.ClientConnInterface) ExchangeClient {
+	return &exchangeClient{cc}
+}
+
+func (c *exchangeClient) CreateOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderReply, error) {
+	out := new(OrderReply)
+	err := c.cc.Invoke(ctx, Exchange_CreateOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeClient) AmendOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderReply, error) {
+	out := new(OrderReply)
+	err := c.cc.Invoke(ctx, Exchange_AmendOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeClient) CancelOrder(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*OrderReply, error) {
+	out := new(OrderReply)
+	err := c.cc.Invoke(ctx, Exchange_CancelOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeClient) GetBook(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BookReply, error) {
+	out := new(BookReply)
+	err := c.cc.Invoke(ctx, Exchange_GetBook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangeClient) StreamExecutions(ctx context.Context, in *ExecutionsRequest, opts ...grpc.CallOption) (Exchange_StreamExecutionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Exchange_ServiceDesc.Streams[0], Exchange_StreamExecutions_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &exchangeStreamExecutionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Exchange_StreamExecutionsClient interface {
+	Recv() (*ExecutionReport, error)
+	grpc.ClientStream
+}
+
+type exchangeStreamExecutionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *exchangeStreamExecutionsClient) Recv() (*ExecutionReport, error) {
+	m := new(ExecutionReport)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ExchangeServer is the server API for Exchange service.
+// All implementations must embed UnimplementedExchangeServer
+// for forward compatibility
+type ExchangeServer interface {
+	// CreateOrder submits a new limit order, repeating a client order id with the same request returns the original reply
+	CreateOrder(context.Context, *OrderRequest) (*OrderReply, error)
+	// AmendOrder amends the quantity of a order identified by id or client order id
+	AmendOrder(context.Context, *OrderRequest) (*OrderReply, error)
+	// CancelOrder cancels a order identified by id or client order id
+	CancelOrder(context.Context, *CancelRequest) (*OrderReply, error)
+	// GetBook returns the depth of a symbol as of the sequence of the book events
+	GetBook(context.Context, *BookRequest) (*BookReply, error)
+	// StreamExecutions streams the execution reports of the orders of the caller
+	StreamExecutions(*ExecutionsRequest, Exchange_StreamExecutionsServer) error
+	mustEmbedUnimplementedExchangeServer()
+}
+
+// UnimplementedExchangeServer must be embedded to have forward compatible implementations.
+type UnimplementedExchangeServer struct {
+}
+
+func (UnimplementedExchangeServer) CreateOrder(context.Context, *OrderRequest) (*OrderReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrder not implemented")
+}
+func (UnimplementedExchangeServer) AmendOrder(context.Context, *OrderRequest) (*OrderReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AmendOrder not implemented")
+}
+func (UnimplementedExchangeServer) CancelOrder(context.Context, *CancelRequest) (*OrderReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedExchangeServer) GetBook(context.Context, *BookRequest) (*BookReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBook not implemented")
+}
+func (UnimplementedExchangeServer) StreamExecutions(*ExecutionsRequest, Exchange_StreamExecutionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamExecutions not implemented")
+}
+func (UnimplementedExchangeServer) mustEmbedUnimplementedExchangeServer() {}
+
+// UnsafeExchangeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExchangeServer will
+// result in compilation errors.
+type UnsafeExchangeServer interface {
+	mustEmbedUnimplementedExchangeServer()
+}
+
+func RegisterExchangeServer(s grpc.ServiceRegistrar, srv ExchangeServer) {
+	s.RegisterService(&Exchange_ServiceDesc, srv)
+}
+
+func _Exchange_CreateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServer).CreateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Exchange_CreateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServer).CreateOrder(ctx, req.(*OrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Exchange_AmendOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServer).AmendOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Exchange_AmendOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServer).AmendOrder(ctx, req.(*OrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Exchange_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Exchange_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServer).CancelOrder(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Exchange_GetBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangeServer).GetBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Exchange_GetBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangeServer).GetBook(ctx, req.(*BookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Exchange_StreamExecutions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecutionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExchangeServer).StreamExecutions(m, &exchangeStreamExecutionsServer{stream})
+}
+
+type Exchange_StreamExecutionsServer interface {
+	Send(*ExecutionReport) error
+	grpc.ServerStream
+}
+
+type exchangeStreamExecutionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *exchangeStreamExecutionsServer) Send(m *ExecutionReport) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Exchange_ServiceDesc is the grpc.ServiceDesc for Exchange service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Exchange_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tradsim.Exchange",
+	HandlerType: (*ExchangeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateOrder",
+			Handler:    _Exchange_CreateOrder_Handler,
+		},
+		{
+			MethodName: "AmendOrder",
+			Handler:    _Exchange_AmendOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _Exchange_CancelOrder_Handler,
+		},
+		{
+			MethodName: "GetBook",
+			Handler:    _Exchange_GetBook_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamExecutions",
+			Handler:       _Exchange_StreamExecutions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "exchange.proto",
+}
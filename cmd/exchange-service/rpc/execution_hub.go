@@ -0,0 +1,183 @@
+package rpc
+
+import (
+	"log"
+	"sync"
+
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ExecutionSubscription receives the execution reports of a account
+type ExecutionSubscription struct {
+	account string
+	reports chan *ExecutionReport
+	closed  bool
+}
+
+// Reports returns the channel of the reports, it is closed when the subscriber falls behind
+func (s *ExecutionSubscription) Reports() <-chan *ExecutionReport {
+	return s.reports
+}
+
+// ExecutionHub is a event publisher which forwards to the wrapped publisher
+// and delivers the order events to the execution subscriptions of the account
+type ExecutionHub struct {
+	publisher     events.EventPublisher
+	bufferSize    int
+	subscriptions map[string]map[*ExecutionSubscription]bool
+	mu            sync.Mutex
+}
+
+// NewExecutionHub creates a new execution hub
+func NewExecutionHub(publisher events.EventPublisher, bufferSize int) *ExecutionHub {
+	return &ExecutionHub{publisher, bufferSize, make(map[string]map[*ExecutionSubscription]bool), sync.Mutex{}}
+}
+
+// Open opens the wrapped publisher
+func (h *ExecutionHub) Open() error {
+	return h.publisher.Open()
+}
+
+// Close closes the wrapped publisher
+func (h *ExecutionHub) Close() {
+	h.publisher.Close()
+}
+
+// Publish publishes the envelope and delivers it to the subscriptions of the account
+func (h *ExecutionHub) Publish(envelope *events.OrderEventEnvelope) error {
+
+	err := h.publisher.Publish(envelope)
+
+	if h.hasSubscriptions() {
+		report, ok := getExecutionReport(envelope)
+		if ok {
+			h.deliver(report)
+		}
+	}
+
+	return err
+}
+
+// Subscribe creates a subscription for the reports of the account
+func (h *ExecutionHub) Subscribe(account string) *ExecutionSubscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &ExecutionSubscription{account, make(chan *ExecutionReport, h.bufferSize), false}
+
+	if _, ok := h.subscriptions[account]; !ok {
+		h.subscriptions[account] = make(map[*ExecutionSubscription]bool)
+	}
+	h.subscriptions[account][sub] = true
+	return sub
+}
+
+// Unsubscribe removes the subscription
+func (h *ExecutionHub) Unsubscribe(sub *ExecutionSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+func (h *ExecutionHub) hasSubscriptions() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscriptions) > 0
+}
+
+func (h *ExecutionHub) deliver(report *ExecutionReport) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscriptions[report.Account] {
+		select {
+		case sub.reports <- report:
+		default:
+			log.Printf("Execution subscriber of %s is too slow, closing", sub.account)
+			h.remove(sub)
+		}
+	}
+}
+
+// remove removes the subscription and closes its channel, the caller holds the lock
+func (h *ExecutionHub) remove(sub *ExecutionSubscription) {
+
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.reports)
+
+	delete(h.subscriptions[sub.account], sub)
+	if len(h.subscriptions[sub.account]) == 0 {
+		delete(h.subscriptions, sub.account)
+	}
+}
+
+func getExecutionReport(envelope *events.OrderEventEnvelope) (*ExecutionReport, bool) {
+
+	switch envelope.EventType {
+	case events.OrderAcceptedType, events.OrderAmendedType, events.OrderCancelledType, events.OrderTradedType, events.OrderRejectedType:
+	default:
+		return nil, false
+	}
+
+	event, err := envelope.GetOrderEvent()
+	if err != nil {
+		log.Printf("Failed to get event. %s", err)
+		return nil, false
+	}
+
+	switch e := event.(type) {
+	case events.OrderAccepted:
+		report := newExecutionReport(ExecutionType_EXECUTION_TYPE_ACCEPTED, &e.OrderEvent)
+		report.Symbol = e.Symbol
+		report.Direction = getDirection(e.Direction)
+		report.Price = e.Price
+		report.Quantity = uint64(e.Quantity)
+		return report, true
+	case events.OrderAmended:
+		report := newExecutionReport(ExecutionType_EXECUTION_TYPE_AMENDED, &e.OrderEvent)
+		report.Quantity = uint64(e.Quantity)
+		return report, true
+	case events.OrderCancelled:
+		return newExecutionReport(ExecutionType_EXECUTION_TYPE_CANCELLED, &e.OrderEvent), true
+	case events.OrderTraded:
+		report := newExecutionReport(ExecutionType_EXECUTION_TYPE_TRADED, &e.OrderEvent)
+		report.TradeId = e.TradeID
+		report.Symbol = e.Symbol
+		report.Price = e.Price
+		report.Quantity = uint64(e.Quantity)
+		return report, true
+	case events.OrderRejected:
+		report := newExecutionReport(ExecutionType_EXECUTION_TYPE_REJECTED, &e.OrderEvent)
+		report.Reason = string(e.Reason)
+		report.Text = e.Text
+		return report, true
+	}
+
+	return nil, false
+}
+
+func newExecutionReport(executionType ExecutionType, event *events.OrderEvent) *ExecutionReport {
+	return &ExecutionReport{
+		Type:    executionType,
+		OrderId: event.OrderID,
+		Account: event.Account,
+		Occured: timestamppb.New(event.Occured),
+	}
+}
+
+func getDirection(direction string) Direction {
+	switch direction {
+	case models.BuyText:
+		return Direction_DIRECTION_BUY
+	case models.SellText:
+		return Direction_DIRECTION_SELL
+	default:
+		return Direction_DIRECTION_UNSPECIFIED
+	}
+}
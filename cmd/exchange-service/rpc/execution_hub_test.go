@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func getEnvelope(event interface{}, eventType events.OrderEventType) *events.OrderEventEnvelope {
+	envelope, _ := events.NewOrderEventEnvelope(event, eventType)
+	return envelope
+}
+
+func TestExecutionHubPublish(t *testing.T) {
+
+	require := require.New(t)
+
+	publisher := &mocks.MockPublisher{}
+	hub := NewExecutionHub(publisher, 10)
+
+	sub := hub.Subscribe("ACC1")
+	other := hub.Subscribe("ACC2")
+
+	accepted := events.NewOrderAccepted("1", "ACC1", time.Now().UTC(), "TT", 2.5, 10, models.Sell, 1)
+	require.Nil(hub.Publish(getEnvelope(accepted, accepted.EventType)))
+
+	require.Len(publisher.Envelopes, 1)
+	require.Len(sub.Reports(), 1)
+	require.Len(other.Reports(), 0)
+
+	report := <-sub.Reports()
+	require.Equal(ExecutionType_EXECUTION_TYPE_ACCEPTED, report.Type)
+	require.Equal("1", report.OrderId)
+	require.Equal("ACC1", report.Account)
+	require.Equal("TT", report.Symbol)
+	require.Equal(Direction_DIRECTION_SELL, report.Direction)
+	require.Equal(2.5, report.Price)
+	require.Equal(uint64(10), report.Quantity)
+	require.Equal(accepted.Occured, report.Occured.AsTime())
+}
+
+func TestExecutionHubReports(t *testing.T) {
+
+	require := require.New(t)
+
+	hub := NewExecutionHub(&mocks.MockPublisher{}, 10)
+	sub := hub.Subscribe("ACC1")
+
+	amended := events.NewOrderAmended("1", "ACC1", 20, time.Now().UTC(), 1)
+	traded := events.NewOrderTraded("1", "ACC1", time.Now().UTC(), "T1", "TT", 2.5, 5, 1)
+	cancelled := events.NewOrderCancelled("1", "ACC1", time.Now().UTC(), 1)
+	rejected := events.NewOrderRejected("1", "ACC1", time.Now().UTC(), events.LimitExceededReason, "too big", 1)
+	position := events.NewPositionUpdated("ACC1", "TT", 5, 2.5, 0, 0, time.Now().UTC(), 1)
+
+	hub.Publish(getEnvelope(amended, amended.EventType))
+	hub.Publish(getEnvelope(traded, traded.EventType))
+	hub.Publish(getEnvelope(cancelled, cancelled.EventType))
+	hub.Publish(getEnvelope(rejected, rejected.EventType))
+	hub.Publish(getEnvelope(position, position.EventType))
+
+	require.Len(sub.Reports(), 4)
+
+	report := <-sub.Reports()
+	require.Equal(ExecutionType_EXECUTION_TYPE_AMENDED, report.Type)
+	require.Equal(uint64(20), report.Quantity)
+
+	report = <-sub.Reports()
+	require.Equal(ExecutionType_EXECUTION_TYPE_TRADED, report.Type)
+	require.Equal("T1", report.TradeId)
+	require.Equal(uint64(5), report.Quantity)
+
+	report = <-sub.Reports()
+	require.Equal(ExecutionType_EXECUTION_TYPE_CANCELLED, report.Type)
+
+	report = <-sub.Reports()
+	require.Equal(ExecutionType_EXECUTION_TYPE_REJECTED, report.Type)
+	require.Equal(string(events.LimitExceededReason), report.Reason)
+	require.Equal("too big", report.Text)
+}
+
+func TestExecutionHubSlowSubscriberClosed(t *testing.T) {
+
+	require := require.New(t)
+
+	hub := NewExecutionHub(&mocks.MockPublisher{}, 1)
+	sub := hub.Subscribe("ACC1")
+
+	cancelled := events.NewOrderCancelled("1", "ACC1", time.Now().UTC(), 1)
+	hub.Publish(getEnvelope(cancelled, cancelled.EventType))
+	hub.Publish(getEnvelope(cancelled, cancelled.EventType))
+
+	_, ok := <-sub.Reports()
+	require.True(ok)
+	_, ok = <-sub.Reports()
+	require.False(ok)
+	require.False(hub.hasSubscriptions())
+
+	hub.Unsubscribe(sub)
+}
+
+func TestExecutionHubUnsubscribe(t *testing.T) {
+
+	require := require.New(t)
+
+	hub := NewExecutionHub(&mocks.MockPublisher{}, 10)
+	sub := hub.Subscribe("ACC1")
+	hub.Unsubscribe(sub)
+
+	require.False(hub.hasSubscriptions())
+	_, ok := <-sub.Reports()
+	require.False(ok)
+}
@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	common_http "github.com/tradsim/tradsim-go/net/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// OrderEntryMethods are the methods counted against the order entry rate limit
+var OrderEntryMethods = map[string]bool{
+	Exchange_CreateOrder_FullMethodName: true,
+	Exchange_AmendOrder_FullMethodName:  true,
+	Exchange_CancelOrder_FullMethodName: true,
+}
+
+// RateLimitInterceptor counts the order entry calls against the budgets of the HTTP order entry.
+// Calls are keyed like requests, chained after the authenticator it can key them by account.
+// Calls over the budget fail with ResourceExhausted and a retry-after trailer.
+func RateLimitInterceptor(limiter *common_http.RateLimiter) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+		if !OrderEntryMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		if trailer, err := allow(ctx, limiter); err != nil {
+			grpc.SetTrailer(ctx, trailer)
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// RemoteRateLimitInterceptor counts every unary call against the budget of its remote address.
+// Chained before the authenticator it limits the authentication attempts of a host.
+func RemoteRateLimitInterceptor(limiter *common_http.RateLimiter) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+		if trailer, err := allow(ctx, limiter); err != nil {
+			grpc.SetTrailer(ctx, trailer)
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// RemoteRateLimitStreamInterceptor counts every streaming call against the budget of its remote address
+// like RemoteRateLimitInterceptor
+func RemoteRateLimitStreamInterceptor(limiter *common_http.RateLimiter) grpc.StreamServerInterceptor {
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		if trailer, err := allow(ss.Context(), limiter); err != nil {
+			ss.SetTrailer(trailer)
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// allow takes a token from the budget of the call, calls over the budget get a error and the retry-after trailer
+func allow(ctx context.Context, limiter *common_http.RateLimiter) (metadata.MD, error) {
+
+	key := limiter.Key(getRequest(ctx))
+
+	ok, wait := limiter.Allow(key)
+	if ok {
+		return nil, nil
+	}
+
+	log.Printf("Call of %s rate limited", key)
+	return metadata.Pairs(strings.ToLower(common_http.RetryAfterHeader), strconv.Itoa(int(math.Ceil(wait.Seconds())))),
+		status.Error(codes.ResourceExhausted, "rate limit exceeded")
+}
+
+// getRequest returns a request with the context and remote address of the call for the rate limit key
+func getRequest(ctx context.Context) *http.Request {
+
+	r := (&http.Request{Header: http.Header{}}).WithContext(ctx)
+
+	if p, ok := peer.FromContext(ctx); ok {
+		r.RemoteAddr = p.Addr.String()
+	}
+
+	return r
+}
@@ -0,0 +1,181 @@
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative exchange.proto
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OrderService executes the order requests of a account
+type OrderService interface {
+	ExecuteOrder(account string, dto handlers.OrderDTO) (*handlers.OrderExecution, error)
+	AmendOrder(account string, dto handlers.OrderDTO) error
+	CancelOrder(account string, id string) error
+	CancelClientOrder(account string, clientOrderID string) error
+}
+
+// BookSource returns the depth of a symbol as of the sequence of the book events
+type BookSource interface {
+	Snapshot(symbol string) (uint64, marketdata.Depth, marketdata.TopOfBook)
+}
+
+// Server implements the exchange gRPC service
+type Server struct {
+	UnimplementedExchangeServer
+	orders     OrderService
+	books      BookSource
+	executions *ExecutionHub
+}
+
+// NewServer creates a new exchange gRPC server
+func NewServer(orders OrderService, books BookSource, executions *ExecutionHub) *Server {
+	return &Server{UnimplementedExchangeServer{}, orders, books, executions}
+}
+
+// CreateOrder submits a new order
+func (s *Server) CreateOrder(ctx context.Context, req *OrderRequest) (*OrderReply, error) {
+
+	execution, err := s.orders.ExecuteOrder(getAccount(ctx), getOrderDTO(req))
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	return &OrderReply{Id: execution.Order.ID.String(), ClientOrderId: execution.Order.ClientOrderID}, nil
+}
+
+// AmendOrder amends the quantity of a order
+func (s *Server) AmendOrder(ctx context.Context, req *OrderRequest) (*OrderReply, error) {
+
+	err := s.orders.AmendOrder(getAccount(ctx), getOrderDTO(req))
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	return &OrderReply{Id: req.Id, ClientOrderId: req.ClientOrderId}, nil
+}
+
+// CancelOrder cancels a order
+func (s *Server) CancelOrder(ctx context.Context, req *CancelRequest) (*OrderReply, error) {
+
+	var err error
+	if req.Id == "" && req.ClientOrderId != "" {
+		err = s.orders.CancelClientOrder(getAccount(ctx), req.ClientOrderId)
+	} else {
+		err = s.orders.CancelOrder(getAccount(ctx), req.Id)
+	}
+	if err != nil {
+		return nil, getStatusError(err)
+	}
+
+	return &OrderReply{Id: req.Id, ClientOrderId: req.ClientOrderId}, nil
+}
+
+// GetBook returns the depth of a symbol
+func (s *Server) GetBook(ctx context.Context, req *BookRequest) (*BookReply, error) {
+
+	symbol := strings.ToUpper(req.Symbol)
+
+	sequence, depth, top := s.books.Snapshot(symbol)
+	if sequence == 0 {
+		return nil, status.Errorf(codes.NotFound, "symbol %s not found", symbol)
+	}
+
+	return &BookReply{
+		Symbol:   symbol,
+		Sequence: sequence,
+		Bids:     getLevels(depth.Bids),
+		Asks:     getLevels(depth.Asks),
+		TopOfBook: &TopOfBook{
+			BidPrice:    top.BidPrice,
+			BidQuantity: uint64(top.BidQuantity),
+			AskPrice:    top.AskPrice,
+			AskQuantity: uint64(top.AskQuantity),
+		},
+	}, nil
+}
+
+// StreamExecutions streams the execution reports of the caller until the call ends
+func (s *Server) StreamExecutions(req *ExecutionsRequest, stream Exchange_StreamExecutionsServer) error {
+
+	account := getAccount(stream.Context())
+
+	sub := s.executions.Subscribe(account)
+	defer s.executions.Unsubscribe(sub)
+
+	log.Printf("StreamExecutions: %s subscribed", account)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			log.Printf("StreamExecutions: %s unsubscribed", account)
+			return nil
+		case report, ok := <-sub.Reports():
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "execution subscriber too slow")
+			}
+			err := stream.Send(report)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func getAccount(ctx context.Context) string {
+	account, _ := common_http.AccountFromContext(ctx)
+	return account
+}
+
+func getOrderDTO(req *OrderRequest) handlers.OrderDTO {
+
+	var direction string
+	switch req.Direction {
+	case Direction_DIRECTION_BUY:
+		direction = models.BuyText
+	case Direction_DIRECTION_SELL:
+		direction = models.SellText
+	}
+
+	return handlers.OrderDTO{ID: req.Id, Symbol: req.Symbol, Quantity: uint(req.Quantity), Direction: direction, Price: req.Price, ClientOrderID: req.ClientOrderId}
+}
+
+func getLevels(levels []marketdata.Level) []*Level {
+
+	result := make([]*Level, 0, len(levels))
+	for _, l := range levels {
+		result = append(result, &Level{Price: l.Price, Quantity: uint64(l.Quantity), Orders: uint64(l.Orders)})
+	}
+	return result
+}
+
+// getStatusError returns the gRPC status of a order error
+func getStatusError(err error) error {
+
+	orderErr, ok := err.(*handlers.OrderError)
+	if !ok {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	switch orderErr.Reason {
+	case events.InvalidOrderReason:
+		return status.Error(codes.InvalidArgument, orderErr.Error())
+	case events.NotOwnerReason:
+		return status.Error(codes.PermissionDenied, orderErr.Error())
+	case events.UnknownOrderReason:
+		return status.Error(codes.NotFound, orderErr.Error())
+	case events.DuplicateOrderReason:
+		return status.Error(codes.AlreadyExists, orderErr.Error())
+	default:
+		return status.Error(codes.FailedPrecondition, orderErr.Error())
+	}
+}
@@ -0,0 +1,301 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type mockOrderService struct {
+	hub     *ExecutionHub
+	err     error
+	account string
+	dto     handlers.OrderDTO
+}
+
+func (m *mockOrderService) ExecuteOrder(account string, dto handlers.OrderDTO) (*handlers.OrderExecution, error) {
+	m.account, m.dto = account, dto
+	if m.err != nil {
+		return nil, m.err
+	}
+	order := models.NewOrder(uuid.NewV4(), dto.Symbol, dto.Price, dto.Quantity, models.Buy)
+	order.ClientOrderID = dto.ClientOrderID
+	accepted := events.NewOrderAccepted(order.ID.String(), account, time.Now().UTC(), dto.Symbol, dto.Price, dto.Quantity, models.Buy, 1)
+	return &handlers.OrderExecution{Order: order}, m.hub.Publish(getEnvelope(accepted, accepted.EventType))
+}
+
+func (m *mockOrderService) AmendOrder(account string, dto handlers.OrderDTO) error {
+	m.account, m.dto = account, dto
+	return m.err
+}
+
+func (m *mockOrderService) CancelOrder(account string, id string) error {
+	m.account, m.dto = account, handlers.OrderDTO{ID: id}
+	return m.err
+}
+
+func (m *mockOrderService) CancelClientOrder(account string, clientOrderID string) error {
+	m.account, m.dto = account, handlers.OrderDTO{ClientOrderID: clientOrderID}
+	return m.err
+}
+
+type mockBookSource struct{}
+
+func (m *mockBookSource) Snapshot(symbol string) (uint64, marketdata.Depth, marketdata.TopOfBook) {
+	if symbol != "TT" {
+		return 0, marketdata.Depth{}, marketdata.TopOfBook{}
+	}
+	depth := marketdata.Depth{
+		Bids: []marketdata.Level{{Side: marketdata.BidSide, Price: 2.0, Quantity: 10, Orders: 2}},
+		Asks: []marketdata.Level{{Side: marketdata.AskSide, Price: 2.5, Quantity: 5, Orders: 1}},
+	}
+	return 7, depth, marketdata.TopOfBook{BidPrice: 2.0, BidQuantity: 10, AskPrice: 2.5, AskQuantity: 5}
+}
+
+func startServer(t *testing.T, orders *mockOrderService) ExchangeClient {
+	return startLimitedServer(t, orders, common_http.NewRateLimiter(1000, 1000, common_http.KeyByRemoteAddr), common_http.NewRateLimiter(1000, 1000, common_http.KeyByAccount))
+}
+
+func startLimitedServer(t *testing.T, orders *mockOrderService, remote *common_http.RateLimiter, limiter *common_http.RateLimiter) ExchangeClient {
+
+	store := common_http.NewInMemoryAccountStore(
+		&common_http.Account{Name: "ACC1", APIKey: "KEY1"},
+		&common_http.Account{Name: "VIEW", APIKey: "KEY2", Role: common_http.ViewerRole},
+		&common_http.Account{Name: "SIGNED", APIKey: "KEY3", Secret: "SECRET"},
+	)
+	auth := NewAuthenticator(store, common_http.NewJWTVerifier(nil))
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(RemoteRateLimitInterceptor(remote), auth.UnaryInterceptor, RateLimitInterceptor(limiter)),
+		grpc.ChainStreamInterceptor(RemoteRateLimitStreamInterceptor(remote), auth.StreamInterceptor))
+	RegisterExchangeServer(server, NewServer(orders, &mockBookSource{}, orders.hub))
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return NewExchangeClient(conn)
+}
+
+func withAPIKey(apiKey string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", apiKey)
+}
+
+func TestServerCreateOrder(t *testing.T) {
+
+	require := require.New(t)
+
+	orders := &mockOrderService{hub: NewExecutionHub(&mocks.MockPublisher{}, 10)}
+	client := startServer(t, orders)
+
+	reply, err := client.CreateOrder(withAPIKey("KEY1"), &OrderRequest{Symbol: "TT", Quantity: 10, Direction: Direction_DIRECTION_SELL, Price: 2.5, ClientOrderId: "C1"})
+	require.Nil(err)
+	require.NotEmpty(reply.Id)
+	require.Equal("C1", reply.ClientOrderId)
+	require.Equal("ACC1", orders.account)
+	require.Equal(handlers.OrderDTO{Symbol: "TT", Quantity: 10, Direction: models.SellText, Price: 2.5, ClientOrderID: "C1"}, orders.dto)
+}
+
+func TestServerCancelOrderByClientOrderID(t *testing.T) {
+
+	require := require.New(t)
+
+	orders := &mockOrderService{hub: NewExecutionHub(&mocks.MockPublisher{}, 10)}
+	client := startServer(t, orders)
+
+	reply, err := client.CancelOrder(withAPIKey("KEY1"), &CancelRequest{ClientOrderId: "C1"})
+	require.Nil(err)
+	require.Equal("C1", reply.ClientOrderId)
+	require.Equal(handlers.OrderDTO{ClientOrderID: "C1"}, orders.dto)
+}
+
+func TestServerStatusErrors(t *testing.T) {
+
+	require := require.New(t)
+
+	orders := &mockOrderService{hub: NewExecutionHub(&mocks.MockPublisher{}, 10)}
+	client := startServer(t, orders)
+
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{&handlers.OrderError{Reason: events.InvalidOrderReason, Text: "invalid"}, codes.InvalidArgument},
+		{&handlers.OrderError{Reason: events.NotOwnerReason, Text: "not owner"}, codes.PermissionDenied},
+		{&handlers.OrderError{Reason: events.UnknownOrderReason, Text: "unknown"}, codes.NotFound},
+		{&handlers.OrderError{Reason: events.DuplicateOrderReason, Text: "duplicate"}, codes.AlreadyExists},
+		{&handlers.OrderError{Reason: events.AmendFailedReason, Text: "failed"}, codes.FailedPrecondition},
+	}
+
+	for _, test := range tests {
+		orders.err = test.err
+		_, err := client.CancelOrder(withAPIKey("KEY1"), &CancelRequest{Id: "1"})
+		require.Equal(test.code, status.Code(err), "%v", test.err)
+	}
+
+	_, err := client.AmendOrder(withAPIKey("KEY1"), &OrderRequest{Id: "1", Quantity: 5})
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServerAuthentication(t *testing.T) {
+
+	require := require.New(t)
+
+	orders := &mockOrderService{hub: NewExecutionHub(&mocks.MockPublisher{}, 10)}
+	client := startServer(t, orders)
+
+	_, err := client.CancelOrder(context.Background(), &CancelRequest{Id: "1"})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = client.CancelOrder(withAPIKey("UNKNOWN"), &CancelRequest{Id: "1"})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = client.CancelOrder(metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid"), &CancelRequest{Id: "1"})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = client.CancelOrder(withAPIKey("KEY2"), &CancelRequest{Id: "1"})
+	require.Equal(codes.PermissionDenied, status.Code(err))
+
+	_, err = client.CancelOrder(withAPIKey("KEY3"), &CancelRequest{Id: "1"})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := SignCall("SECRET", Exchange_CancelOrder_FullMethodName, timestamp, &CancelRequest{Id: "1"})
+	require.Nil(err)
+	ctx := metadata.AppendToOutgoingContext(withAPIKey("KEY3"), "x-timestamp", timestamp, "x-signature", signature)
+
+	_, err = client.CancelOrder(ctx, &CancelRequest{Id: "1"})
+	require.Nil(err)
+	require.Equal("SIGNED", orders.account)
+
+	// the signature of a request does not authenticate another request
+	_, err = client.CancelOrder(ctx, &CancelRequest{Id: "2"})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	// the signature of the method alone does not authenticate a request
+	signature = common_http.Sign("SECRET", "POST", Exchange_CancelOrder_FullMethodName, timestamp, nil)
+	ctx = metadata.AppendToOutgoingContext(withAPIKey("KEY3"), "x-timestamp", timestamp, "x-signature", signature)
+	_, err = client.CancelOrder(ctx, &CancelRequest{Id: "1"})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func TestServerRateLimit(t *testing.T) {
+
+	require := require.New(t)
+
+	orders := &mockOrderService{hub: NewExecutionHub(&mocks.MockPublisher{}, 10)}
+	client := startLimitedServer(t, orders, common_http.NewRateLimiter(1000, 1000, common_http.KeyByRemoteAddr), common_http.NewRateLimiter(0.5, 2, common_http.KeyByAccount))
+
+	_, err := client.CreateOrder(withAPIKey("KEY1"), &OrderRequest{Symbol: "TT", Quantity: 10, Direction: Direction_DIRECTION_BUY, Price: 2.5})
+	require.Nil(err)
+	_, err = client.CancelOrder(withAPIKey("KEY1"), &CancelRequest{Id: "1"})
+	require.Nil(err)
+
+	var trailer metadata.MD
+	_, err = client.AmendOrder(withAPIKey("KEY1"), &OrderRequest{Id: "1", Quantity: 20}, grpc.Trailer(&trailer))
+	require.Equal(codes.ResourceExhausted, status.Code(err))
+	require.Equal([]string{"2"}, trailer.Get("retry-after"))
+
+	// market data is not counted against the order entry budget
+	_, err = client.GetBook(withAPIKey("KEY1"), &BookRequest{Symbol: "TT"})
+	require.Nil(err)
+}
+
+func TestServerRemoteRateLimit(t *testing.T) {
+
+	require := require.New(t)
+
+	orders := &mockOrderService{hub: NewExecutionHub(&mocks.MockPublisher{}, 10)}
+	client := startLimitedServer(t, orders, common_http.NewRateLimiter(0.5, 2, common_http.KeyByRemoteAddr), common_http.NewRateLimiter(1000, 1000, common_http.KeyByAccount))
+
+	// failed authentications are counted too
+	_, err := client.GetBook(withAPIKey("UNKNOWN1"), &BookRequest{Symbol: "TT"})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+	_, err = client.GetBook(withAPIKey("UNKNOWN2"), &BookRequest{Symbol: "TT"})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	var trailer metadata.MD
+	_, err = client.GetBook(withAPIKey("KEY1"), &BookRequest{Symbol: "TT"}, grpc.Trailer(&trailer))
+	require.Equal(codes.ResourceExhausted, status.Code(err))
+	require.Equal([]string{"2"}, trailer.Get("retry-after"))
+
+	stream, err := client.StreamExecutions(withAPIKey("KEY1"), &ExecutionsRequest{})
+	require.Nil(err)
+	_, err = stream.Recv()
+	require.Equal(codes.ResourceExhausted, status.Code(err))
+}
+
+func TestServerGetBook(t *testing.T) {
+
+	require := require.New(t)
+
+	client := startServer(t, &mockOrderService{hub: NewExecutionHub(&mocks.MockPublisher{}, 10)})
+
+	reply, err := client.GetBook(withAPIKey("KEY2"), &BookRequest{Symbol: "tt"})
+	require.Nil(err)
+	require.Equal("TT", reply.Symbol)
+	require.Equal(uint64(7), reply.Sequence)
+	require.Len(reply.Bids, 1)
+	require.Equal(2.0, reply.Bids[0].Price)
+	require.Equal(uint64(2), reply.Bids[0].Orders)
+	require.Len(reply.Asks, 1)
+	require.Equal(uint64(5), reply.Asks[0].Quantity)
+	require.Equal(2.5, reply.TopOfBook.AskPrice)
+
+	_, err = client.GetBook(withAPIKey("KEY2"), &BookRequest{Symbol: "XX"})
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestServerStreamExecutions(t *testing.T) {
+
+	require := require.New(t)
+
+	orders := &mockOrderService{hub: NewExecutionHub(&mocks.MockPublisher{}, 10)}
+	client := startServer(t, orders)
+
+	ctx, cancel := context.WithCancel(withAPIKey("KEY1"))
+	defer cancel()
+
+	stream, err := client.StreamExecutions(ctx, &ExecutionsRequest{})
+	require.Nil(err)
+	require.Eventually(orders.hub.hasSubscriptions, time.Second, 10*time.Millisecond)
+
+	reply, err := client.CreateOrder(withAPIKey("KEY1"), &OrderRequest{Symbol: "TT", Quantity: 10, Direction: Direction_DIRECTION_BUY, Price: 2.5})
+	require.Nil(err)
+
+	report, err := stream.Recv()
+	require.Nil(err)
+	require.Equal(ExecutionType_EXECUTION_TYPE_ACCEPTED, report.Type)
+	require.Equal(reply.Id, report.OrderId)
+	require.Equal("ACC1", report.Account)
+	require.Equal(uint64(10), report.Quantity)
+
+	stream, err = client.StreamExecutions(withAPIKey("KEY2"), &ExecutionsRequest{})
+	require.Nil(err)
+	_, err = stream.Recv()
+	require.Equal(codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,267 @@
+package trading
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// ClosingMethod defines how the official closing price is computed
+type ClosingMethod string
+
+// The closing methods
+const (
+	LastTradeClosing ClosingMethod = "last"
+	VWAPClosing      ClosingMethod = "vwap"
+)
+
+// ClosingMethodFromString returns a closing method from string
+func ClosingMethodFromString(value string) (ClosingMethod, error) {
+	switch ClosingMethod(value) {
+	case LastTradeClosing, VWAPClosing:
+		return ClosingMethod(value), nil
+	default:
+		return "", fmt.Errorf("Closing method %s not supported", value)
+	}
+}
+
+// SymbolStats defines the statistics of a symbol in a session.
+// ClosePrice and ClosedAt are set when the session has been closed.
+type SymbolStats struct {
+	Symbol     string
+	Open       float64
+	High       float64
+	Low        float64
+	Last       float64
+	Volume     uint
+	Turnover   float64
+	Trades     uint
+	Updated    time.Time
+	ClosePrice float64
+	ClosedAt   time.Time
+}
+
+// VWAP returns the volume weighted average price of the session
+func (s *SymbolStats) VWAP() float64 {
+	if s.Volume == 0 {
+		return 0
+	}
+	return s.Turnover / float64(s.Volume)
+}
+
+func (s *SymbolStats) String() string {
+	return fmt.Sprintf("%s O:%f H:%f L:%f LT:%f V:%d T:%f N:%d C:%f", s.Symbol, s.Open, s.High, s.Low, s.Last, s.Volume, s.Turnover, s.Trades, s.ClosePrice)
+}
+
+// DailyStats maintains the statistics of the current session per symbol and computes the official closing price.
+// The trades of the current and of the last closed session are kept, so that busted trades are removed from the statistics.
+type DailyStats struct {
+	method   ClosingMethod
+	window   time.Duration
+	current  map[string]*SymbolStats
+	trades   map[string][]*models.Trade
+	previous map[string]SymbolStats
+	closed   map[string][]*models.Trade
+	mu       sync.RWMutex
+}
+
+// NewDailyStats creates new daily stats, window is the final window of the vwap closing method
+func NewDailyStats(method ClosingMethod, window time.Duration) *DailyStats {
+	return &DailyStats{method, window, make(map[string]*SymbolStats), make(map[string][]*models.Trade), make(map[string]SymbolStats),
+		make(map[string][]*models.Trade), sync.RWMutex{}}
+}
+
+// Traded updates the statistics of the symbol of the trade
+func (ds *DailyStats) Traded(trade *models.Trade) {
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	stats, ok := ds.current[trade.Symbol]
+	if !ok {
+		stats = &SymbolStats{Symbol: trade.Symbol}
+		ds.current[trade.Symbol] = stats
+	}
+
+	stats.add(trade)
+	ds.trades[trade.Symbol] = append(ds.trades[trade.Symbol], trade)
+}
+
+// Bust removes the trade from the statistics of its session.
+// A trade of the last closed session corrects the closed statistics, they are returned with true so that the end of day is published again.
+func (ds *DailyStats) Bust(trade *models.Trade) (SymbolStats, bool) {
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if trades, ok := removeTrade(ds.trades[trade.Symbol], trade.ID); ok {
+		ds.trades[trade.Symbol] = trades
+		if len(trades) == 0 {
+			delete(ds.current, trade.Symbol)
+			delete(ds.trades, trade.Symbol)
+			return SymbolStats{}, false
+		}
+		ds.current[trade.Symbol] = newSymbolStats(trade.Symbol, trades)
+		return SymbolStats{}, false
+	}
+
+	trades, ok := removeTrade(ds.closed[trade.Symbol], trade.ID)
+	if !ok {
+		return SymbolStats{}, false
+	}
+	ds.closed[trade.Symbol] = trades
+
+	previous := ds.previous[trade.Symbol]
+	stats := newSymbolStats(trade.Symbol, trades)
+	stats.ClosePrice = ds.getClosePrice(stats, trades, previous.ClosedAt)
+	stats.ClosedAt = previous.ClosedAt
+	ds.previous[trade.Symbol] = *stats
+
+	return *stats, true
+}
+
+// Stats returns the statistics of the current session of the symbol
+func (ds *DailyStats) Stats(symbol string) (SymbolStats, bool) {
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	stats, ok := ds.current[symbol]
+	if !ok {
+		return SymbolStats{}, false
+	}
+	return *stats, true
+}
+
+// AllStats returns the statistics of the current session of all symbols ordered by symbol
+func (ds *DailyStats) AllStats() []SymbolStats {
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	all := make([]SymbolStats, 0, len(ds.current))
+	for _, stats := range ds.current {
+		all = append(all, *stats)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Symbol < all[j].Symbol })
+
+	return all
+}
+
+// PreviousClose returns the statistics of the last closed session of the symbol
+func (ds *DailyStats) PreviousClose(symbol string) (SymbolStats, bool) {
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	stats, ok := ds.previous[symbol]
+	return stats, ok
+}
+
+// Close ends the session at the provided time, computes the official closing prices and starts a new session.
+// The closed statistics are returned ordered by symbol.
+func (ds *DailyStats) Close(at time.Time) []SymbolStats {
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	closed := make([]SymbolStats, 0, len(ds.current))
+
+	for symbol, stats := range ds.current {
+		stats.ClosePrice = ds.getClosePrice(stats, ds.trades[symbol], at)
+		stats.ClosedAt = at
+		ds.previous[symbol] = *stats
+		closed = append(closed, *stats)
+	}
+
+	ds.current = make(map[string]*SymbolStats)
+	ds.closed = ds.trades
+	ds.trades = make(map[string][]*models.Trade)
+
+	sort.Slice(closed, func(i, j int) bool { return closed[i].Symbol < closed[j].Symbol })
+
+	return closed
+}
+
+func (ds *DailyStats) getClosePrice(stats *SymbolStats, trades []*models.Trade, at time.Time) float64 {
+
+	if ds.method != VWAPClosing {
+		return stats.Last
+	}
+
+	volume := uint(0)
+	turnover := 0.0
+
+	for _, trade := range trades {
+		if trade.Occured.Before(at.Add(-ds.window)) || trade.Occured.After(at) {
+			continue
+		}
+		volume += trade.Quantity
+		turnover += trade.Price * float64(trade.Quantity)
+	}
+
+	if volume == 0 {
+		return stats.Last
+	}
+
+	return turnover / float64(volume)
+}
+
+// add adds the trade to the statistics
+func (s *SymbolStats) add(trade *models.Trade) {
+
+	if s.Trades == 0 {
+		s.Open, s.High, s.Low = trade.Price, trade.Price, trade.Price
+	}
+	if trade.Price > s.High {
+		s.High = trade.Price
+	}
+	if trade.Price < s.Low {
+		s.Low = trade.Price
+	}
+	s.Last = trade.Price
+	s.Volume += trade.Quantity
+	s.Turnover += trade.Price * float64(trade.Quantity)
+	s.Trades++
+	s.Updated = trade.Occured
+}
+
+// newSymbolStats computes the statistics of the trades of a session
+func newSymbolStats(symbol string, trades []*models.Trade) *SymbolStats {
+
+	stats := &SymbolStats{Symbol: symbol}
+	for _, trade := range trades {
+		stats.add(trade)
+	}
+	return stats
+}
+
+// removeTrade removes the trade of the id and returns true if it was found
+func removeTrade(trades []*models.Trade, id uuid.UUID) ([]*models.Trade, bool) {
+
+	for i, trade := range trades {
+		if trade.ID == id {
+			return append(trades[:i:i], trades[i+1:]...), true
+		}
+	}
+
+	return trades, false
+}
+
+// NextSessionClose returns the next session close after now, the offset is the session close from midnight UTC
+func NextSessionClose(now time.Time, offset time.Duration) time.Time {
+
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(offset)
+
+	if !next.After(now) {
+		next = next.Add(24 * time.Hour)
+	}
+
+	return next
+}
@@ -0,0 +1,164 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func getStatsTrade(price float64, quantity uint, occured time.Time) *models.Trade {
+	return models.NewTrade(uuid.NewV4(), 1, "TT", price, quantity, models.Buy, uuid.NewV4(), uuid.NewV4(), occured)
+}
+
+func TestDailyStatsTraded(t *testing.T) {
+
+	require := require.New(t)
+
+	start := time.Date(2016, 8, 13, 10, 0, 0, 0, time.UTC)
+	stats := NewDailyStats(LastTradeClosing, 5*time.Minute)
+	stats.Traded(getStatsTrade(2.0, 10, start))
+	stats.Traded(getStatsTrade(3.0, 10, start.Add(time.Minute)))
+	stats.Traded(getStatsTrade(1.0, 20, start.Add(2*time.Minute)))
+
+	tt, ok := stats.Stats("TT")
+	require.True(ok)
+	require.Equal(2.0, tt.Open)
+	require.Equal(3.0, tt.High)
+	require.Equal(1.0, tt.Low)
+	require.Equal(1.0, tt.Last)
+	require.Equal(uint(40), tt.Volume)
+	require.Equal(70.0, tt.Turnover)
+	require.Equal(1.75, tt.VWAP())
+	require.Equal(uint(3), tt.Trades)
+	require.Len(stats.AllStats(), 1)
+
+	_, ok = stats.Stats("XX")
+	require.False(ok)
+}
+
+func TestDailyStatsBust(t *testing.T) {
+
+	require := require.New(t)
+
+	start := time.Date(2016, 8, 13, 10, 0, 0, 0, time.UTC)
+	stats := NewDailyStats(LastTradeClosing, 5*time.Minute)
+	first := getStatsTrade(2.0, 10, start)
+	high := getStatsTrade(3.0, 10, start.Add(time.Minute))
+	stats.Traded(first)
+	stats.Traded(high)
+	stats.Traded(getStatsTrade(1.0, 20, start.Add(2*time.Minute)))
+
+	_, closed := stats.Bust(high)
+	require.False(closed)
+
+	tt, ok := stats.Stats("TT")
+	require.True(ok)
+	require.Equal(2.0, tt.Open)
+	require.Equal(2.0, tt.High)
+	require.Equal(1.0, tt.Low)
+	require.Equal(1.0, tt.Last)
+	require.Equal(uint(30), tt.Volume)
+	require.Equal(40.0, tt.Turnover)
+	require.Equal(uint(2), tt.Trades)
+
+	// a busted trade is not counted twice
+	_, closed = stats.Bust(high)
+	require.False(closed)
+	tt, _ = stats.Stats("TT")
+	require.Equal(uint(2), tt.Trades)
+
+	closedStats := stats.Close(start.Add(time.Hour))
+	require.Equal(1.0, closedStats[0].ClosePrice)
+
+	previous, closed := stats.Bust(first)
+	require.True(closed)
+	require.Equal(1.0, previous.Open)
+	require.Equal(uint(20), previous.Volume)
+	require.Equal(1.0, previous.ClosePrice)
+	require.Equal(start.Add(time.Hour), previous.ClosedAt)
+
+	stored, ok := stats.PreviousClose("TT")
+	require.True(ok)
+	require.Equal(previous, stored)
+}
+
+func TestSymbolStatsString(t *testing.T) {
+
+	stats := SymbolStats{Symbol: "TT", Open: 2, High: 3, Low: 1, Last: 1.5, Volume: 40, Turnover: 70, Trades: 3}
+
+	require.Equal(t, "TT O:2.000000 H:3.000000 L:1.000000 LT:1.500000 V:40 T:70.000000 N:3 C:0.000000", stats.String())
+}
+
+func TestDailyStatsCloseLastTrade(t *testing.T) {
+
+	require := require.New(t)
+
+	start := time.Date(2016, 8, 13, 10, 0, 0, 0, time.UTC)
+	stats := NewDailyStats(LastTradeClosing, 5*time.Minute)
+	stats.Traded(getStatsTrade(2.0, 10, start))
+	stats.Traded(getStatsTrade(3.0, 10, start.Add(time.Minute)))
+
+	closed := stats.Close(start.Add(time.Hour))
+
+	require.Len(closed, 1)
+	require.Equal(3.0, closed[0].ClosePrice)
+	require.Equal(start.Add(time.Hour), closed[0].ClosedAt)
+	require.Empty(stats.AllStats())
+
+	previous, ok := stats.PreviousClose("TT")
+	require.True(ok)
+	require.Equal(3.0, previous.ClosePrice)
+}
+
+func TestDailyStatsCloseVWAPWindow(t *testing.T) {
+
+	require := require.New(t)
+
+	sessionClose := time.Date(2016, 8, 13, 16, 0, 0, 0, time.UTC)
+	stats := NewDailyStats(VWAPClosing, 5*time.Minute)
+	stats.Traded(getStatsTrade(1.0, 100, sessionClose.Add(-time.Hour)))
+	stats.Traded(getStatsTrade(2.0, 10, sessionClose.Add(-4*time.Minute)))
+	stats.Traded(getStatsTrade(3.0, 30, sessionClose.Add(-time.Minute)))
+
+	closed := stats.Close(sessionClose)
+
+	require.Equal(2.75, closed[0].ClosePrice)
+}
+
+func TestDailyStatsCloseVWAPWithoutTradesInWindow(t *testing.T) {
+
+	require := require.New(t)
+
+	sessionClose := time.Date(2016, 8, 13, 16, 0, 0, 0, time.UTC)
+	stats := NewDailyStats(VWAPClosing, 5*time.Minute)
+	stats.Traded(getStatsTrade(1.5, 100, sessionClose.Add(-time.Hour)))
+
+	closed := stats.Close(sessionClose)
+
+	require.Equal(1.5, closed[0].ClosePrice)
+}
+
+func TestClosingMethodFromString(t *testing.T) {
+
+	require := require.New(t)
+
+	method, err := ClosingMethodFromString("vwap")
+	require.Nil(err)
+	require.Equal(VWAPClosing, method)
+
+	_, err = ClosingMethodFromString("open")
+	require.NotNil(err)
+}
+
+func TestNextSessionClose(t *testing.T) {
+
+	require := require.New(t)
+
+	sessionClose := 21 * time.Hour
+
+	require.Equal(time.Date(2016, 8, 13, 21, 0, 0, 0, time.UTC), NextSessionClose(time.Date(2016, 8, 13, 10, 0, 0, 0, time.UTC), sessionClose))
+	require.Equal(time.Date(2016, 8, 14, 21, 0, 0, 0, time.UTC), NextSessionClose(time.Date(2016, 8, 13, 21, 0, 0, 0, time.UTC), sessionClose))
+}
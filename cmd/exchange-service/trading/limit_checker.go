@@ -0,0 +1,114 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// LimitChecker interface
+type LimitChecker interface {
+	Check(book *models.OrderBook, order *models.Order) error
+}
+
+// AccountLimitChecker checks orders against the account limits before they reach the book
+type AccountLimitChecker struct {
+	cache *RiskCache
+}
+
+// NewAccountLimitChecker creates a new account limit checker
+func NewAccountLimitChecker(cache *RiskCache) *AccountLimitChecker {
+	return &AccountLimitChecker{cache}
+}
+
+// Check returns a error if the order, or the amended order when it increases quantity, would breach the account limits.
+// Open orders of the account are accounted as if they were fully traded, the fills are in the positions of the cache.
+// The caller holds the book lock until the order is in the book, so that concurrent orders are checked against each other.
+func (lc *AccountLimitChecker) Check(book *models.OrderBook, order *models.Order) error {
+
+	quantity := order.Remaining()
+
+	existing, ok := book.Orders[order.ID]
+	if ok && existing.Status.IsTradeable() {
+		if order.Quantity <= existing.Quantity {
+			return nil
+		}
+		quantity = order.Quantity - existing.Traded
+	}
+
+	limits := lc.cache.Limits(order.Account)
+	positions := lc.cache.Positions(order.Account)
+
+	err := lc.checkDailyLoss(limits, positions)
+	if err != nil {
+		return err
+	}
+
+	position := 0
+	exposure := float64(quantity) * order.Price
+
+	for _, p := range positions {
+		if p.Symbol == order.Symbol {
+			position = p.Quantity
+		}
+		exposure += p.Exposure()
+	}
+
+	openBuy, openSell := uint(0), uint(0)
+
+	for _, o := range book.Accounts[order.Account] {
+		if o.ID == order.ID || !o.Status.IsTradeable() {
+			continue
+		}
+		exposure += float64(o.Remaining()) * o.Price
+		if o.Symbol != order.Symbol {
+			continue
+		}
+		if o.Direction == models.Buy {
+			openBuy += o.Remaining()
+		} else {
+			openSell += o.Remaining()
+		}
+	}
+
+	if order.Direction == models.Buy {
+		long := position + int(openBuy) + int(quantity)
+		if limits.MaxLong > 0 && long > int(limits.MaxLong) {
+			return fmt.Errorf("Long position %d of %s exceeds limit %d", long, order.Symbol, limits.MaxLong)
+		}
+	} else {
+		short := -(position - int(openSell) - int(quantity))
+		if limits.MaxShort > 0 && short > int(limits.MaxShort) {
+			return fmt.Errorf("Short position %d of %s exceeds limit %d", short, order.Symbol, limits.MaxShort)
+		}
+	}
+
+	if limits.MaxGrossExposure > 0 && exposure > limits.MaxGrossExposure {
+		return fmt.Errorf("Gross exposure %f exceeds limit %f", exposure, limits.MaxGrossExposure)
+	}
+
+	return nil
+}
+
+func (lc *AccountLimitChecker) checkDailyLoss(limits models.AccountLimits, positions []models.AccountPosition) error {
+
+	if limits.DailyLossLimit <= 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	pnl := 0.0
+
+	for _, p := range positions {
+		if models.SameDay(p.Updated, now) {
+			pnl += p.DailyPnL
+		}
+	}
+
+	if -pnl >= limits.DailyLossLimit {
+		return fmt.Errorf("Daily loss %f reached limit %f", -pnl, limits.DailyLossLimit)
+	}
+
+	return nil
+}
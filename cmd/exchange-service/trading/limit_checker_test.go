@@ -0,0 +1,124 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func getAccountOrder(account string, price float64, quantity uint, direction models.TradeDirection) *models.Order {
+	order := models.NewOrder(uuid.NewV4(), "TT", price, quantity, direction)
+	order.Account = account
+	return order
+}
+
+func TestCheckWithinLimits(t *testing.T) {
+
+	require := require.New(t)
+
+	cache := NewRiskCache(*models.NewAccountLimits("", 100, 100, 1000.0, 50.0))
+	checker := NewAccountLimitChecker(cache)
+
+	require.Nil(checker.Check(models.NewOrderBook(), getAccountOrder("ACC1", 1.0, 100, models.Buy)))
+}
+
+func TestCheckLongLimitCountsPositionAndOpenOrders(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	cache := NewRiskCache(*models.NewAccountLimits("", 100, 100, 0.0, 0.0))
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "TT", 50, 1.0, 0.0, 0.0, time.Now().UTC()))
+	checker := NewAccountLimitChecker(cache)
+
+	ap := NewOrderAppender()
+	ap.Append(book, getAccountOrder("ACC1", 1.0, 30, models.Buy))
+	ap.Append(book, getAccountOrder("ACC2", 1.1, 30, models.Buy))
+
+	require.Nil(checker.Check(book, getAccountOrder("ACC1", 1.0, 20, models.Buy)))
+	require.NotNil(checker.Check(book, getAccountOrder("ACC1", 1.0, 21, models.Buy)))
+}
+
+func TestCheckLongLimitCountsFilledOrders(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	cache := NewRiskCache(*models.NewAccountLimits("", 100, 100, 0.0, 0.0))
+	checker := NewAccountLimitChecker(cache)
+	trader := NewOrderTrader(&mocks.MockPublisher{}, cache)
+
+	NewOrderAppender().Append(book, getAccountOrder("ACC2", 1.0, 500, models.Sell))
+
+	// the filled order leaves the book before its position update arrives
+	order := getAccountOrder("ACC1", 1.0, 100, models.Buy)
+	require.Nil(checker.Check(book, order))
+	trader.Trade(book, order)
+	require.Equal(models.FullyFilled, order.Status)
+
+	require.NotNil(checker.Check(book, getAccountOrder("ACC1", 1.0, 1, models.Buy)))
+	require.Nil(checker.Check(book, getAccountOrder("ACC1", 1.0, 100, models.Sell)))
+}
+
+func TestCheckShortLimit(t *testing.T) {
+
+	require := require.New(t)
+
+	cache := NewRiskCache(*models.NewAccountLimits("", 100, 10, 0.0, 0.0))
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "TT", 5, 1.0, 0.0, 0.0, time.Now().UTC()))
+	checker := NewAccountLimitChecker(cache)
+
+	require.Nil(checker.Check(models.NewOrderBook(), getAccountOrder("ACC1", 1.0, 15, models.Sell)))
+	require.NotNil(checker.Check(models.NewOrderBook(), getAccountOrder("ACC1", 1.0, 16, models.Sell)))
+}
+
+func TestCheckGrossExposure(t *testing.T) {
+
+	require := require.New(t)
+
+	cache := NewRiskCache(*models.NewAccountLimits("", 0, 0, 100.0, 0.0))
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "ETE", -20, 2.0, 0.0, 0.0, time.Now().UTC()))
+	checker := NewAccountLimitChecker(cache)
+
+	require.Nil(checker.Check(models.NewOrderBook(), getAccountOrder("ACC1", 2.0, 30, models.Buy)))
+	require.NotNil(checker.Check(models.NewOrderBook(), getAccountOrder("ACC1", 2.0, 31, models.Buy)))
+}
+
+func TestCheckDailyLoss(t *testing.T) {
+
+	require := require.New(t)
+
+	cache := NewRiskCache(*models.NewAccountLimits("", 0, 0, 0.0, 50.0))
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "ETE", 0, 2.0, -20.0, -20.0, time.Now().UTC().Add(-48*time.Hour)))
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "TT", 0, 2.0, -80.0, -30.0, time.Now().UTC()))
+	checker := NewAccountLimitChecker(cache)
+
+	require.Nil(checker.Check(models.NewOrderBook(), getAccountOrder("ACC1", 2.0, 1, models.Buy)))
+
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "TT", 0, 2.0, -100.0, -50.0, time.Now().UTC()))
+
+	require.NotNil(checker.Check(models.NewOrderBook(), getAccountOrder("ACC1", 2.0, 1, models.Buy)))
+}
+
+func TestCheckAmendOnlyCountsIncrease(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	cache := NewRiskCache(*models.NewAccountLimits("", 100, 0, 0.0, 0.0))
+	checker := NewAccountLimitChecker(cache)
+
+	order := getAccountOrder("ACC1", 1.0, 80, models.Buy)
+	NewOrderAppender().Append(book, order)
+
+	amend := models.NewOrderFull(order.ID, "TT", 1.0, 100, 0, models.Buy, models.Pending)
+	amend.Account = "ACC1"
+	require.Nil(checker.Check(book, amend))
+
+	amend.Quantity = 101
+	require.NotNil(checker.Check(book, amend))
+}
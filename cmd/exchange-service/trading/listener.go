@@ -0,0 +1,37 @@
+package trading
+
+import (
+	"time"
+
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// TradeListener gets notified of every trade
+type TradeListener interface {
+	Traded(trade *models.Trade)
+}
+
+// BustListener gets notified of every busted trade
+type BustListener interface {
+	Busted(trade *models.Trade)
+}
+
+// BookListener gets notified after the book of a symbol has changed
+type BookListener interface {
+	BookChanged(book *models.OrderBook, symbol string)
+}
+
+// BookListeners notifies all its listeners
+type BookListeners []BookListener
+
+// BookChanged notifies the listeners in order
+func (bl BookListeners) BookChanged(book *models.OrderBook, symbol string) {
+	for _, listener := range bl {
+		listener.BookChanged(book, symbol)
+	}
+}
+
+// SessionListener gets notified after a session has closed
+type SessionListener interface {
+	SessionClosed(at time.Time)
+}
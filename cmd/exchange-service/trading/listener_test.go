@@ -0,0 +1,22 @@
+package trading
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func TestBookListenersNotifiesAll(t *testing.T) {
+
+	require := require.New(t)
+
+	first := &mocks.MockBookListener{}
+	second := &mocks.MockBookListener{}
+
+	BookListeners{first, second}.BookChanged(models.NewOrderBook(), "TT")
+
+	require.Equal([]string{"TT"}, first.Symbols)
+	require.Equal([]string{"TT"}, second.Symbols)
+}
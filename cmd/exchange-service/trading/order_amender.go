@@ -2,6 +2,7 @@ package trading
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -37,6 +38,7 @@ func (oa *OrderAmender) Amend(book *models.OrderBook, order *models.Order) bool
 
 	if !ok {
 		log.Printf("Symbol %s not found", order.Symbol)
+		oa.publishRejectedEvent(order.ID, order.Account, fmt.Sprintf("Symbol %s not found", order.Symbol))
 		return false
 	}
 
@@ -44,10 +46,13 @@ func (oa *OrderAmender) Amend(book *models.OrderBook, order *models.Order) bool
 
 	if !found {
 		log.Printf("Price %f not found", order.Price)
+		oa.publishRejectedEvent(order.ID, order.Account, fmt.Sprintf("Price %f not found", order.Price))
 		return false
 	}
 
 	var amended = false
+	var account = order.Account
+	var reason = fmt.Sprintf("Order %s not found", order.ID)
 
 	if order.Direction == models.Buy {
 
@@ -58,11 +63,13 @@ func (oa *OrderAmender) Amend(book *models.OrderBook, order *models.Order) bool
 				d, err := oa.getAmendQuantity(o.Quantity, order.Quantity)
 				if err != nil {
 					log.Printf("%s", err)
+					reason = err.Error()
 					break
 				}
 
 				prices[i].Buy.Quantity += d
 				o.Amend(d)
+				account = o.Account
 				amended = true
 				break
 			}
@@ -77,11 +84,13 @@ func (oa *OrderAmender) Amend(book *models.OrderBook, order *models.Order) bool
 				d, err := oa.getAmendQuantity(o.Quantity, order.Quantity)
 				if err != nil {
 					log.Printf("%s", err)
+					reason = err.Error()
 					break
 				}
 
 				prices[i].Sell.Quantity += d
 				o.Amend(d)
+				account = o.Account
 				amended = true
 				break
 			}
@@ -89,15 +98,17 @@ func (oa *OrderAmender) Amend(book *models.OrderBook, order *models.Order) bool
 	}
 
 	if amended {
-		oa.publishAmendEvent(order.ID, order.Quantity)
+		oa.publishAmendEvent(order.ID, account, order.Quantity)
+	} else {
+		oa.publishRejectedEvent(order.ID, account, reason)
 	}
 
 	return amended
 }
 
-func (oa *OrderAmender) publishAmendEvent(ID uuid.UUID, quantity uint) {
+func (oa *OrderAmender) publishAmendEvent(ID uuid.UUID, account string, quantity uint) {
 
-	ev := events.NewOrderAmended(ID.String(), quantity, time.Now().UTC(), uint(1))
+	ev := events.NewOrderAmended(ID.String(), account, quantity, time.Now().UTC(), uint(1))
 	env, err := events.NewOrderEventEnvelope(ev, ev.EventType)
 
 	if err != nil {
@@ -110,6 +121,21 @@ func (oa *OrderAmender) publishAmendEvent(ID uuid.UUID, quantity uint) {
 	}
 }
 
+func (oa *OrderAmender) publishRejectedEvent(ID uuid.UUID, account string, text string) {
+
+	ev := events.NewOrderRejected(ID.String(), account, time.Now().UTC(), events.AmendFailedReason, text, uint(1))
+	env, err := events.NewOrderEventEnvelope(ev, ev.EventType)
+
+	if err != nil {
+		log.Printf("Failed to create envelope: %s", err.Error())
+	}
+
+	err = oa.publisher.Publish(env)
+	if err != nil {
+		log.Printf("Failed to publish rejected event: %s", ev.String())
+	}
+}
+
 func (oa *OrderAmender) getAmendQuantity(orig uint, amend uint) (uint, error) {
 	if orig >= amend {
 		return 0, errors.New("Amend quantity less or equal than orders")
@@ -5,6 +5,7 @@ import (
 
 	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/events"
 	"github.com/tradsim/tradsim-go/mocks"
 	"github.com/tradsim/tradsim-go/models"
 )
@@ -58,6 +59,33 @@ func TestAmendMissingOrderReturnsFalse(t *testing.T) {
 	require.False(am.Amend(book, order2))
 }
 
+func TestAmendFailurePublishesRejected(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+
+	order1 := models.NewOrder(uuid.NewV4(), "TT", 199.99, 10, models.Sell)
+
+	ap := NewOrderAppender()
+	ap.Append(book, order1)
+
+	publisher := &mocks.MockPublisher{}
+	am := NewOrderAmender(publisher)
+
+	order2 := models.NewOrder(order1.ID, "TT", 199.99, 5, models.Sell)
+
+	require.False(am.Amend(book, order2))
+	require.Len(publisher.Envelopes, 1)
+	require.Equal(events.OrderRejectedType, publisher.Envelopes[0].EventType)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	rejected := event.(events.OrderRejected)
+	require.Equal(events.AmendFailedReason, rejected.Reason)
+	require.Equal(order1.ID.String(), rejected.OrderID)
+}
+
 func TestAmendBuy(t *testing.T) {
 
 	require := require.New(t)
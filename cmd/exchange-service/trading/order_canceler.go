@@ -1,6 +1,7 @@
 package trading
 
 import (
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -34,21 +35,24 @@ func (oc *OrderCanceller) Cancel(book *models.OrderBook, orderID uuid.UUID) bool
 	order, ok := book.Orders[orderID]
 	if !ok {
 		log.Printf("Order with id %s not found", orderID)
+		oc.publishRejectedEvent(orderID, "", events.UnknownOrderReason, fmt.Sprintf("Order %s not found", orderID))
 		return false
 	}
 
 	if !order.Status.IsTradeable() {
+		oc.publishRejectedEvent(orderID, order.Account, events.OrderNotTradeableReason, fmt.Sprintf("Order %s is %s", orderID, order.Status))
 		return false
 	}
 
 	order.Status = models.Cancelled
-	oc.publishCancelledEvent(orderID)
+	book.Close(order)
+	oc.publishCancelledEvent(orderID, order.Account)
 	return true
 }
 
-func (oc *OrderCanceller) publishCancelledEvent(ID uuid.UUID) {
+func (oc *OrderCanceller) publishCancelledEvent(ID uuid.UUID, account string) {
 
-	ev := events.NewOrderCancelled(ID.String(), time.Now().UTC(), uint(1))
+	ev := events.NewOrderCancelled(ID.String(), account, time.Now().UTC(), uint(1))
 	env, err := events.NewOrderEventEnvelope(ev, ev.EventType)
 
 	if err != nil {
@@ -60,3 +64,18 @@ func (oc *OrderCanceller) publishCancelledEvent(ID uuid.UUID) {
 		log.Printf("Failed to publish cancelled event: %s", ev.String())
 	}
 }
+
+func (oc *OrderCanceller) publishRejectedEvent(ID uuid.UUID, account string, reason events.RejectReason, text string) {
+
+	ev := events.NewOrderRejected(ID.String(), account, time.Now().UTC(), reason, text, uint(1))
+	env, err := events.NewOrderEventEnvelope(ev, ev.EventType)
+
+	if err != nil {
+		log.Printf("Failed to create envelope: %s", err.Error())
+	}
+
+	err = oc.publisher.Publish(env)
+	if err != nil {
+		log.Printf("Failed to publish rejected event: %s", ev.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/events"
 	"github.com/tradsim/tradsim-go/mocks"
 	"github.com/tradsim/tradsim-go/models"
 )
@@ -39,3 +40,44 @@ func TestCancelOrder(t *testing.T) {
 	require.Equal(models.Cancelled, prices[0].Sell.Orders[0].Status)
 	require.Len(publisher.Envelopes, 1)
 }
+
+func TestCancelUnknownOrderPublishesRejected(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	publisher := &mocks.MockPublisher{}
+
+	cnc := NewOrderCanceller(publisher)
+	cancelled := cnc.Cancel(book, uuid.NewV4())
+
+	require.False(cancelled)
+	require.Len(publisher.Envelopes, 1)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	require.Equal(events.UnknownOrderReason, event.(events.OrderRejected).Reason)
+}
+
+func TestCancelFilledOrderPublishesRejected(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	order := models.NewOrder(uuid.NewV4(), "TT", 199.99, 10, models.Sell)
+
+	ap := NewOrderAppender()
+	ap.Append(book, order)
+	order.Trade(10)
+	publisher := &mocks.MockPublisher{}
+
+	cnc := NewOrderCanceller(publisher)
+	cancelled := cnc.Cancel(book, order.ID)
+
+	require.False(cancelled)
+	require.Len(publisher.Envelopes, 1)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+	require.Equal(events.OrderNotTradeableReason, event.(events.OrderRejected).Reason)
+}
@@ -12,51 +12,62 @@ import (
 
 // Trader interface
 type Trader interface {
-	Trade(book *models.OrderBook, order *models.Order)
+	Trade(book *models.OrderBook, order *models.Order) []*models.Trade
 }
 
 // OrderTrader implementation
 type OrderTrader struct {
 	publisher events.EventPublisher
+	listeners []TradeListener
+	sequences map[string]uint64
+	now       func() time.Time
 	mu        sync.Mutex
 }
 
-// NewOrderTrader creates a new order trader
-func NewOrderTrader(publisher events.EventPublisher) *OrderTrader {
-	return &OrderTrader{publisher, sync.Mutex{}}
+// NewOrderTrader creates a new order trader, the listeners get notified of every trade
+func NewOrderTrader(publisher events.EventPublisher, listeners ...TradeListener) *OrderTrader {
+	return NewOrderTraderWithClock(publisher, time.Now, listeners...)
 }
 
-// Trade processes a order against the book
-func (ot *OrderTrader) Trade(book *models.OrderBook, order *models.Order) {
+// NewOrderTraderWithClock creates a new order trader whose trades occur at the time of the clock
+func NewOrderTraderWithClock(publisher events.EventPublisher, now func() time.Time, listeners ...TradeListener) *OrderTrader {
+	return &OrderTrader{publisher, listeners, make(map[string]uint64), now, sync.Mutex{}}
+}
+
+// Trade processes a order against the book and returns the trades of the order in execution order
+func (ot *OrderTrader) Trade(book *models.OrderBook, order *models.Order) []*models.Trade {
 
 	ot.mu.Lock()
 	defer ot.mu.Unlock()
 
+	trades := make([]*models.Trade, 0)
+
 	prices, ok := book.Symbols[order.Symbol]
 	if !ok {
 		log.Printf("Symbol %s not in book", order.Symbol)
-		return
+		return trades
 	}
 
 	if order.Direction == models.Buy {
-		ot.tradePricesBuy(prices, order)
+		ot.tradePricesBuy(book, prices, order, &trades)
 	} else {
-		ot.tradePricesSell(prices, order)
+		ot.tradePricesSell(book, prices, order, &trades)
 	}
+	return trades
 }
 
-func (ot *OrderTrader) tradePricesBuy(prices []*models.OrderPrice, order *models.Order) {
+func (ot *OrderTrader) tradePricesBuy(book *models.OrderBook, prices []*models.OrderPrice, order *models.Order, trades *[]*models.Trade) {
 	for _, price := range prices {
 		if price.Price > order.Price {
 			log.Printf("Price %f is greater than order price %f", price.Price, order.Price)
 			return
 		}
 		log.Printf("Trading with price %f. order price %f", price.Price, order.Price)
-		ot.tradePrice(price, order)
+		ot.tradePrice(book, price, order, trades)
 	}
 }
 
-func (ot *OrderTrader) tradePricesSell(prices []*models.OrderPrice, order *models.Order) {
+func (ot *OrderTrader) tradePricesSell(book *models.OrderBook, prices []*models.OrderPrice, order *models.Order, trades *[]*models.Trade) {
 
 	for i := len(prices) - 1; i >= 0; i-- {
 		if prices[i].Price < order.Price {
@@ -64,17 +75,17 @@ func (ot *OrderTrader) tradePricesSell(prices []*models.OrderPrice, order *model
 			return
 		}
 		log.Printf("Trading with price %f. order price %f", prices[i].Price, order.Price)
-		ot.tradePrice(prices[i], order)
+		ot.tradePrice(book, prices[i], order, trades)
 	}
 }
 
-func (ot *OrderTrader) tradePrice(price *models.OrderPrice, order *models.Order) {
+func (ot *OrderTrader) tradePrice(book *models.OrderBook, price *models.OrderPrice, order *models.Order, trades *[]*models.Trade) {
 
 	if order.Direction == models.Buy {
 		if price.Sell.Quantity > 0 {
 			log.Printf("Sell quantity on price %f is %d and order count %d", price.Price, price.Sell.Quantity, len(price.Sell.Orders))
 			for _, existing := range price.Sell.Orders {
-				ot.trade(existing, order)
+				ot.trade(book, existing, order, trades)
 			}
 			price.Sell.Quantity = ot.compactOrdersAndGetQuantity(&price.Sell.Orders)
 			log.Printf("Sell quantity after trade on price %f is %d and order count %d", price.Price, price.Sell.Quantity, len(price.Sell.Orders))
@@ -85,7 +96,7 @@ func (ot *OrderTrader) tradePrice(price *models.OrderPrice, order *models.Order)
 		if price.Buy.Quantity > 0 {
 			log.Printf("Buy quantity on price %f is %d and order count %d", price.Price, price.Buy.Quantity, len(price.Buy.Orders))
 			for _, existing := range price.Buy.Orders {
-				ot.trade(existing, order)
+				ot.trade(book, existing, order, trades)
 			}
 			price.Buy.Quantity = ot.compactOrdersAndGetQuantity(&price.Buy.Orders)
 			log.Printf("Buy quantity after trade on price %f is %d and order count %d", price.Price, price.Buy.Quantity, len(price.Buy.Orders))
@@ -95,7 +106,7 @@ func (ot *OrderTrader) tradePrice(price *models.OrderPrice, order *models.Order)
 	}
 }
 
-func (ot *OrderTrader) trade(existing *models.Order, new *models.Order) {
+func (ot *OrderTrader) trade(book *models.OrderBook, existing *models.Order, new *models.Order, trades *[]*models.Trade) {
 
 	if !existing.Status.IsTradeable() {
 		return
@@ -109,15 +120,44 @@ func (ot *OrderTrader) trade(existing *models.Order, new *models.Order) {
 		traded = existing.Remaining()
 	}
 
+	// nothing left to match, publishing would print a trade which never happened
+	if traded == 0 {
+		return
+	}
+
+	trade := ot.newTrade(existing, new, traded)
+
 	existing.Trade(traded)
-	ot.publishTradedEvent(existing.ID, existing.Price, traded)
+	if !existing.Status.IsTradeable() {
+		book.Close(existing)
+	}
+	ot.publishTradedEvent(existing.ID, existing.Account, trade)
 	new.Trade(traded)
-	ot.publishTradedEvent(new.ID, existing.Price, traded)
+	ot.publishTradedEvent(new.ID, new.Account, trade)
+
+	for _, listener := range ot.listeners {
+		listener.Traded(trade)
+	}
+	*trades = append(*trades, trade)
+}
+
+func (ot *OrderTrader) newTrade(existing *models.Order, new *models.Order, traded uint) *models.Trade {
+
+	ot.sequences[new.Symbol]++
+
+	buy, sell := new, existing
+	if new.Direction == models.Sell {
+		buy, sell = existing, new
+	}
+
+	trade := models.NewTrade(uuid.NewV4(), ot.sequences[new.Symbol], new.Symbol, existing.Price, traded, new.Direction, buy.ID, sell.ID, ot.now().UTC())
+	trade.BuyAccount, trade.SellAccount = buy.Account, sell.Account
+	return trade
 }
 
-func (ot *OrderTrader) publishTradedEvent(ID uuid.UUID, price float64, traded uint) {
+func (ot *OrderTrader) publishTradedEvent(ID uuid.UUID, account string, trade *models.Trade) {
 
-	ev := events.NewOrderTraded(ID.String(), time.Now().UTC(), price, traded, uint(1))
+	ev := events.NewOrderTraded(ID.String(), account, trade.Occured, trade.ID.String(), trade.Symbol, trade.Price, trade.Quantity, uint(1))
 	env, err := events.NewOrderEventEnvelope(ev, ev.EventType)
 
 	if err != nil {
@@ -2,9 +2,11 @@ package trading
 
 import (
 	"testing"
+	"time"
 
 	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/events"
 	"github.com/tradsim/tradsim-go/mocks"
 	"github.com/tradsim/tradsim-go/models"
 )
@@ -49,10 +51,17 @@ func TestTradeBuyLeavingFullyFilled(t *testing.T) {
 
 	publisher := &mocks.MockPublisher{}
 	trader := NewOrderTrader(publisher)
-	trader.Trade(book, orderBuy)
+	trades := trader.Trade(book, orderBuy)
 
 	prices := book.Symbols["TT"]
 
+	require.Len(trades, 2)
+	require.Equal(199.97, trades[0].Price)
+	require.Equal(orderSell3.ID, trades[0].SellOrderID)
+	require.Equal(orderBuy.ID, trades[0].BuyOrderID)
+	require.Equal(orderSell2.ID, trades[1].SellOrderID)
+	require.Equal(uint(10), trades[1].Quantity)
+
 	require.Len(prices, 3)
 	require.Equal(uint(20), orderBuy.Traded)
 	require.Equal(models.FullyFilled, orderBuy.Status)
@@ -101,3 +110,96 @@ func TestTradeSellLeavingFullyFilled(t *testing.T) {
 	require.Len(prices[2].Buy.Orders, 0)
 	require.Len(publisher.Envelopes, 4)
 }
+
+func TestTradeNotifiesListeners(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	orderSell1 := models.NewOrder(uuid.NewV4(), "TT", 199.98, 10, models.Sell)
+	orderSell2 := models.NewOrder(uuid.NewV4(), "TT", 199.99, 10, models.Sell)
+
+	ap := NewOrderAppender()
+	ap.Append(book, orderSell1)
+	ap.Append(book, orderSell2)
+
+	orderBuy := models.NewOrder(uuid.NewV4(), "TT", 199.99, 15, models.Buy)
+
+	listener := &mocks.MockTradeListener{}
+	trader := NewOrderTrader(&mocks.MockPublisher{}, listener)
+	trader.Trade(book, orderBuy)
+
+	require.Len(listener.Trades, 2)
+	require.Equal(uint64(1), listener.Trades[0].Sequence)
+	require.Equal(199.98, listener.Trades[0].Price)
+	require.Equal(uint(10), listener.Trades[0].Quantity)
+	require.Equal(models.Buy, listener.Trades[0].Aggressor)
+	require.Equal(orderBuy.ID, listener.Trades[0].BuyOrderID)
+	require.Equal(orderSell1.ID, listener.Trades[0].SellOrderID)
+	require.Equal(uint64(2), listener.Trades[1].Sequence)
+	require.Equal(199.99, listener.Trades[1].Price)
+	require.Equal(uint(5), listener.Trades[1].Quantity)
+}
+
+func TestTradeClosesFilledOrdersOfTheAccounts(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	orderSell1 := models.NewOrder(uuid.NewV4(), "TT", 199.98, 10, models.Sell)
+	orderSell1.Account = "ACC1"
+	orderSell2 := models.NewOrder(uuid.NewV4(), "TT", 199.99, 10, models.Sell)
+	orderSell2.Account = "ACC1"
+
+	ap := NewOrderAppender()
+	ap.Append(book, orderSell1)
+	ap.Append(book, orderSell2)
+
+	orderBuy := models.NewOrder(uuid.NewV4(), "TT", 199.99, 15, models.Buy)
+	orderBuy.Account = "ACC2"
+
+	listener := &mocks.MockTradeListener{}
+	trader := NewOrderTrader(&mocks.MockPublisher{}, listener)
+	trader.Trade(book, orderBuy)
+
+	require.Equal(map[uuid.UUID]*models.Order{orderSell2.ID: orderSell2}, book.Accounts["ACC1"])
+	require.Equal("ACC2", listener.Trades[0].BuyAccount)
+	require.Equal("ACC1", listener.Trades[0].SellAccount)
+}
+
+func TestTradePublishesSameTradeIDForBothOrders(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	orderSell := models.NewOrder(uuid.NewV4(), "TT", 199.98, 10, models.Sell)
+	NewOrderAppender().Append(book, orderSell)
+
+	publisher := &mocks.MockPublisher{}
+	trader := NewOrderTrader(publisher)
+	trader.Trade(book, models.NewOrder(uuid.NewV4(), "TT", 199.98, 10, models.Buy))
+
+	require.Len(publisher.Envelopes, 2)
+
+	first, _ := publisher.Envelopes[0].GetOrderEvent()
+	second, _ := publisher.Envelopes[1].GetOrderEvent()
+	require.Equal(orderSell.ID.String(), first.(events.OrderTraded).OrderID)
+	require.Equal("TT", first.(events.OrderTraded).Symbol)
+	require.NotEmpty(first.(events.OrderTraded).TradeID)
+	require.Equal(first.(events.OrderTraded).TradeID, second.(events.OrderTraded).TradeID)
+}
+
+func TestTradeWithClock(t *testing.T) {
+
+	require := require.New(t)
+
+	book := models.NewOrderBook()
+	NewOrderAppender().Append(book, models.NewOrder(uuid.NewV4(), "TT", 199.98, 10, models.Sell))
+
+	occured := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	trader := NewOrderTraderWithClock(&mocks.MockPublisher{}, func() time.Time { return occured })
+	trades := trader.Trade(book, models.NewOrder(uuid.NewV4(), "TT", 199.98, 10, models.Buy))
+
+	require.Len(trades, 1)
+	require.Equal(occured, trades[0].Occured)
+}
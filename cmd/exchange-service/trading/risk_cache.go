@@ -0,0 +1,204 @@
+package trading
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/data"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// RiskCache holds the account limits and positions.
+// The trades fill the positions as they happen, the position updates of the event stream reconcile them.
+type RiskCache struct {
+	defaults  models.AccountLimits
+	limits    map[string]*models.AccountLimits
+	positions map[string]map[string]*models.AccountPosition
+	mu        sync.RWMutex
+}
+
+// NewRiskCache creates a new risk cache with the limits applied to accounts without explicit limits
+func NewRiskCache(defaults models.AccountLimits) *RiskCache {
+	return &RiskCache{defaults, make(map[string]*models.AccountLimits), make(map[string]map[string]*models.AccountPosition), sync.RWMutex{}}
+}
+
+// Load fills the cache with the stored limits and positions, the cache is loaded before the event stream updates it
+func (rc *RiskCache) Load(repo data.RiskRepository) error {
+
+	limits, err := repo.GetLimits()
+	if err != nil {
+		return err
+	}
+
+	positions, err := repo.GetPositions()
+	if err != nil {
+		return err
+	}
+
+	for i := range limits {
+		rc.SetLimits(&limits[i])
+	}
+
+	for i := range positions {
+		rc.UpdatePosition(&positions[i])
+	}
+
+	log.Printf("Loaded %d account limits and %d positions", len(limits), len(positions))
+	return nil
+}
+
+// Limits returns the limits of the account or the defaults
+func (rc *RiskCache) Limits(account string) models.AccountLimits {
+
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+
+	limits, ok := rc.limits[account]
+	if !ok {
+		defaults := rc.defaults
+		defaults.Account = account
+		return defaults
+	}
+
+	return *limits
+}
+
+// AllLimits returns the explicitly set limits ordered by account
+func (rc *RiskCache) AllLimits() []models.AccountLimits {
+
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+
+	accounts := make([]string, 0, len(rc.limits))
+
+	for account := range rc.limits {
+		accounts = append(accounts, account)
+	}
+
+	sort.Strings(accounts)
+
+	limits := make([]models.AccountLimits, 0, len(accounts))
+
+	for _, account := range accounts {
+		limits = append(limits, *rc.limits[account])
+	}
+
+	return limits
+}
+
+// SetLimits sets the limits of a account
+func (rc *RiskCache) SetLimits(limits *models.AccountLimits) {
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.limits[limits.Account] = limits
+}
+
+// RemoveLimits removes the limits of a account, which falls back to the defaults
+func (rc *RiskCache) RemoveLimits(account string) bool {
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	_, ok := rc.limits[account]
+	delete(rc.limits, account)
+	return ok
+}
+
+// Positions returns the positions of a account
+func (rc *RiskCache) Positions(account string) []models.AccountPosition {
+
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+
+	positions := make([]models.AccountPosition, 0)
+
+	for _, p := range rc.positions[account] {
+		positions = append(positions, *p)
+	}
+
+	return positions
+}
+
+// UpdatePosition sets the position of a account in a symbol, a position older than the cached one is stale and ignored
+func (rc *RiskCache) UpdatePosition(position *models.AccountPosition) {
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	symbols, ok := rc.positions[position.Account]
+	if !ok {
+		symbols = make(map[string]*models.AccountPosition)
+		rc.positions[position.Account] = symbols
+	}
+
+	existing, ok := symbols[position.Symbol]
+	if ok && existing.Updated.After(position.Updated) {
+		log.Printf("Position %s is older than the cached one", position)
+		return
+	}
+
+	symbols[position.Symbol] = position
+}
+
+// Traded fills the positions of the accounts of the trade
+func (rc *RiskCache) Traded(trade *models.Trade) {
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.fill(trade.BuyAccount, trade.Symbol, int(trade.Quantity), trade.Price, trade.Occured)
+	rc.fill(trade.SellAccount, trade.Symbol, -int(trade.Quantity), trade.Price, trade.Occured)
+}
+
+func (rc *RiskCache) fill(account string, symbol string, quantity int, price float64, occured time.Time) {
+
+	if account == "" {
+		return
+	}
+
+	symbols, ok := rc.positions[account]
+	if !ok {
+		symbols = make(map[string]*models.AccountPosition)
+		rc.positions[account] = symbols
+	}
+
+	position, ok := symbols[symbol]
+	if !ok {
+		position = models.NewAccountPosition(account, symbol, 0, 0.0, 0.0, 0.0, occured)
+		symbols[symbol] = position
+	}
+
+	position.Fill(quantity, price, occured)
+}
+
+// Apply updates the cache from a position or account limits event
+func (rc *RiskCache) Apply(envelope *events.OrderEventEnvelope) {
+
+	untypedEvent, err := envelope.GetOrderEvent()
+	if err != nil {
+		log.Printf("Failed to get event. %s", err)
+		return
+	}
+
+	switch envelope.EventType {
+	case events.PositionUpdatedType:
+		event := untypedEvent.(events.PositionUpdated)
+		rc.UpdatePosition(models.NewAccountPosition(event.Account, event.Symbol, event.Quantity, event.Price, event.RealizedPnL, event.DailyPnL, event.Occured))
+		log.Printf("Position updated received: %s", event.String())
+	case events.AccountLimitsUpdatedType:
+		event := untypedEvent.(events.AccountLimitsUpdated)
+		if event.Removed {
+			rc.RemoveLimits(event.Account)
+		} else {
+			rc.SetLimits(event.Limits())
+		}
+		log.Printf("Account limits updated received: %s", event.String())
+	default:
+		log.Printf("Ignoring event %s", envelope.EventType)
+	}
+}
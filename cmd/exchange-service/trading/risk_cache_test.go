@@ -0,0 +1,120 @@
+package trading
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/mocks"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func TestRiskCacheDefaultLimits(t *testing.T) {
+
+	require := require.New(t)
+
+	cache := NewRiskCache(*models.NewAccountLimits("", 10, 20, 100.0, 5.0))
+
+	require.Equal(*models.NewAccountLimits("ACC1", 10, 20, 100.0, 5.0), cache.Limits("ACC1"))
+	require.Len(cache.AllLimits(), 0)
+}
+
+func TestRiskCacheSetAndRemoveLimits(t *testing.T) {
+
+	require := require.New(t)
+
+	cache := NewRiskCache(*models.NewAccountLimits("", 10, 20, 100.0, 5.0))
+	cache.SetLimits(models.NewAccountLimits("ACC2", 1, 2, 3.0, 4.0))
+	cache.SetLimits(models.NewAccountLimits("ACC1", 1, 2, 3.0, 4.0))
+
+	limits := cache.AllLimits()
+	require.Len(limits, 2)
+	require.Equal("ACC1", limits[0].Account)
+	require.Equal("ACC2", limits[1].Account)
+
+	require.True(cache.RemoveLimits("ACC1"))
+	require.False(cache.RemoveLimits("ACC1"))
+	require.Equal(uint(10), cache.Limits("ACC1").MaxLong)
+}
+
+func TestRiskCacheUpdatePositionIgnoresOlder(t *testing.T) {
+
+	require := require.New(t)
+
+	now := time.Now().UTC()
+	cache := NewRiskCache(models.AccountLimits{})
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "TT", 10, 1.0, 0.0, 0.0, now))
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "TT", 5, 1.0, 0.0, 0.0, now.Add(-time.Minute)))
+
+	positions := cache.Positions("ACC1")
+	require.Len(positions, 1)
+	require.Equal(10, positions[0].Quantity)
+}
+
+func TestRiskCacheLoad(t *testing.T) {
+
+	require := require.New(t)
+
+	now := time.Now().UTC()
+	cache := NewRiskCache(models.AccountLimits{})
+	repo := &mocks.MockRiskRepository{
+		Limits:    map[string]models.AccountLimits{"ACC1": *models.NewAccountLimits("ACC1", 1, 2, 3.0, 4.0)},
+		Positions: []models.AccountPosition{*models.NewAccountPosition("ACC1", "TT", 10, 1.0, 0.0, 0.0, now)},
+	}
+
+	require.Nil(cache.Load(repo))
+	require.Equal(*models.NewAccountLimits("ACC1", 1, 2, 3.0, 4.0), cache.Limits("ACC1"))
+	require.Equal(repo.Positions, cache.Positions("ACC1"))
+
+	repo.Err = errors.New("connection refused")
+	require.NotNil(NewRiskCache(models.AccountLimits{}).Load(repo))
+}
+
+func TestRiskCacheTraded(t *testing.T) {
+
+	require := require.New(t)
+
+	now := time.Now().UTC()
+	cache := NewRiskCache(models.AccountLimits{})
+
+	trade := models.NewTrade(uuid.NewV4(), 1, "TT", 2.0, 10, models.Buy, uuid.NewV4(), uuid.NewV4(), now)
+	trade.BuyAccount, trade.SellAccount = "ACC1", "ACC2"
+	cache.Traded(trade)
+
+	require.Equal([]models.AccountPosition{*models.NewAccountPosition("ACC1", "TT", 10, 2.0, 0.0, 0.0, now)}, cache.Positions("ACC1"))
+	require.Equal([]models.AccountPosition{*models.NewAccountPosition("ACC2", "TT", -10, 2.0, 0.0, 0.0, now)}, cache.Positions("ACC2"))
+
+	// the position of the event stream reconciles the fills once it has caught up with them
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "TT", 0, 0.0, 0.0, 0.0, now.Add(-time.Second)))
+	require.Equal(10, cache.Positions("ACC1")[0].Quantity)
+	cache.UpdatePosition(models.NewAccountPosition("ACC1", "TT", 8, 2.0, 0.0, 0.0, now))
+	require.Equal(8, cache.Positions("ACC1")[0].Quantity)
+}
+
+func TestRiskCacheApply(t *testing.T) {
+
+	require := require.New(t)
+
+	cache := NewRiskCache(models.AccountLimits{})
+
+	position := events.NewPositionUpdated("ACC1", "TT", -10, 2.0, 1.0, 1.0, time.Now().UTC(), 1)
+	envelope, _ := events.NewOrderEventEnvelope(position, position.EventType)
+	cache.Apply(envelope)
+
+	limits := events.NewAccountLimitsUpdated(models.NewAccountLimits("ACC1", 1, 2, 3.0, 4.0), time.Now().UTC(), 1)
+	envelope, _ = events.NewOrderEventEnvelope(limits, limits.EventType)
+	cache.Apply(envelope)
+
+	require.Len(cache.Positions("ACC1"), 1)
+	require.Equal(-10, cache.Positions("ACC1")[0].Quantity)
+	require.Equal(uint(1), cache.Limits("ACC1").MaxLong)
+
+	removed := events.NewAccountLimitsRemoved("ACC1", time.Now().UTC(), 1)
+	envelope, _ = events.NewOrderEventEnvelope(removed, removed.EventType)
+	cache.Apply(envelope)
+
+	require.Len(cache.AllLimits(), 0)
+}
@@ -0,0 +1,68 @@
+package trading
+
+import (
+	"log"
+	"time"
+
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// SessionCloser closes the daily statistics at session end and publishes the end of day events
+type SessionCloser struct {
+	stats     *DailyStats
+	publisher events.EventPublisher
+	offset    time.Duration
+	listener  SessionListener
+}
+
+// NewSessionCloser creates a new session closer, the offset is the session close from midnight UTC
+func NewSessionCloser(stats *DailyStats, publisher events.EventPublisher, offset time.Duration, listener SessionListener) *SessionCloser {
+	return &SessionCloser{stats, publisher, offset, listener}
+}
+
+// Run closes every session at its end, it does not return
+func (sc *SessionCloser) Run() {
+	for {
+		next := NextSessionClose(time.Now(), sc.offset)
+		log.Printf("Next session close at %s", next)
+		time.Sleep(time.Until(next))
+		sc.CloseSession(next)
+	}
+}
+
+// CloseSession closes the session at the provided time, publishes a end of day event per symbol and notifies the listener
+func (sc *SessionCloser) CloseSession(at time.Time) {
+
+	defer sc.listener.SessionClosed(at)
+
+	for _, stats := range sc.stats.Close(at) {
+		sc.publishEndOfDay(stats)
+	}
+}
+
+// Busted removes the busted trade from the statistics, a trade of the closed session publishes the corrected end of day event
+func (sc *SessionCloser) Busted(trade *models.Trade) {
+
+	stats, closed := sc.stats.Bust(trade)
+	if closed {
+		log.Printf("End of day of %s corrected after bust of trade %s", stats.Symbol, trade.ID)
+		sc.publishEndOfDay(stats)
+	}
+}
+
+func (sc *SessionCloser) publishEndOfDay(stats SymbolStats) {
+
+	ev := events.NewEndOfDay(stats.Symbol, stats.Open, stats.High, stats.Low, stats.Last, stats.ClosePrice, string(sc.stats.method),
+		stats.Volume, stats.Turnover, stats.VWAP(), stats.Trades, stats.ClosedAt, uint(1))
+	env, err := events.NewOrderEventEnvelope(ev, ev.EventType)
+	if err != nil {
+		log.Printf("Failed to create envelope: %s", err.Error())
+		return
+	}
+
+	err = sc.publisher.Publish(env)
+	if err != nil {
+		log.Printf("Failed to publish end of day event: %s", ev.String())
+	}
+}
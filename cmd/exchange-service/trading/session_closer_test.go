@@ -0,0 +1,71 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/mocks"
+)
+
+func TestSessionCloserPublishesEndOfDay(t *testing.T) {
+
+	require := require.New(t)
+
+	sessionClose := time.Date(2016, 8, 13, 16, 0, 0, 0, time.UTC)
+	stats := NewDailyStats(LastTradeClosing, 5*time.Minute)
+	stats.Traded(getStatsTrade(2.0, 10, sessionClose.Add(-time.Hour)))
+	stats.Traded(getStatsTrade(4.0, 10, sessionClose.Add(-time.Minute)))
+
+	publisher := &mocks.MockPublisher{}
+	listener := &mocks.MockSessionListener{}
+	NewSessionCloser(stats, publisher, 16*time.Hour, listener).CloseSession(sessionClose)
+
+	require.Equal([]time.Time{sessionClose}, listener.Closed)
+	require.Len(publisher.Envelopes, 1)
+
+	event, err := publisher.Envelopes[0].GetOrderEvent()
+	require.Nil(err)
+
+	eod := event.(events.EndOfDay)
+	require.Equal("TT", eod.Symbol)
+	require.Equal(4.0, eod.Close)
+	require.Equal("last", eod.ClosingMethod)
+	require.Equal(3.0, eod.VWAP)
+	require.Equal(uint(20), eod.Volume)
+	require.Equal(uint(2), eod.Trades)
+}
+
+func TestSessionCloserBustedRepublishesEndOfDay(t *testing.T) {
+
+	require := require.New(t)
+
+	sessionClose := time.Date(2016, 8, 13, 16, 0, 0, 0, time.UTC)
+	stats := NewDailyStats(LastTradeClosing, 5*time.Minute)
+	stats.Traded(getStatsTrade(2.0, 10, sessionClose.Add(-time.Hour)))
+	last := getStatsTrade(4.0, 10, sessionClose.Add(-time.Minute))
+	stats.Traded(last)
+
+	publisher := &mocks.MockPublisher{}
+	closer := NewSessionCloser(stats, publisher, 16*time.Hour, &mocks.MockSessionListener{})
+	closer.CloseSession(sessionClose)
+
+	closer.Busted(last)
+	require.Len(publisher.Envelopes, 2)
+
+	event, err := publisher.Envelopes[1].GetOrderEvent()
+	require.Nil(err)
+
+	eod := event.(events.EndOfDay)
+	require.Equal(2.0, eod.Close)
+	require.Equal(uint(10), eod.Volume)
+	require.Equal(uint(1), eod.Trades)
+	require.Equal(sessionClose, eod.Occured)
+
+	// a trade of the current session changes no end of day
+	trade := getStatsTrade(3.0, 10, sessionClose.Add(time.Hour))
+	stats.Traded(trade)
+	closer.Busted(trade)
+	require.Len(publisher.Envelopes, 2)
+}
@@ -0,0 +1,143 @@
+package trading
+
+import (
+	"sync"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// TradeQuery defines the filters of the trade tape. Zero values do not filter.
+type TradeQuery struct {
+	AfterSequence uint64    // only trades with a greater sequence
+	From          time.Time // only trades at or after from
+	To            time.Time // only trades before to
+	Limit         int       // maximum number of trades
+}
+
+type tradeRing struct {
+	trades []*models.Trade
+	next   int
+	count  int
+}
+
+func (tr *tradeRing) add(trade *models.Trade) {
+	tr.trades[tr.next] = trade
+	tr.next = (tr.next + 1) % len(tr.trades)
+	if tr.count < len(tr.trades) {
+		tr.count++
+	}
+}
+
+func (tr *tradeRing) get(i int) *models.Trade {
+	return tr.trades[tr.index(i)]
+}
+
+// remove moves the newer trades over the i-th trade
+func (tr *tradeRing) remove(i int) {
+	for ; i < tr.count-1; i++ {
+		tr.trades[tr.index(i)] = tr.get(i + 1)
+	}
+	tr.next = (tr.next - 1 + len(tr.trades)) % len(tr.trades)
+	tr.trades[tr.next] = nil
+	tr.count--
+}
+
+func (tr *tradeRing) index(i int) int {
+	return (tr.next - tr.count + i + len(tr.trades)) % len(tr.trades)
+}
+
+// TradeTape keeps the most recent trades per symbol in a ring buffer
+type TradeTape struct {
+	capacity int
+	symbols  map[string]*tradeRing
+	mu       sync.RWMutex
+}
+
+// NewTradeTape creates a new trade tape which keeps up to capacity trades per symbol
+func NewTradeTape(capacity int) *TradeTape {
+	return &TradeTape{capacity, make(map[string]*tradeRing), sync.RWMutex{}}
+}
+
+// Traded adds the trade to the tape, replacing the oldest trade of the symbol when full
+func (tt *TradeTape) Traded(trade *models.Trade) {
+
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	ring, ok := tt.symbols[trade.Symbol]
+	if !ok {
+		ring = &tradeRing{make([]*models.Trade, tt.capacity), 0, 0}
+		tt.symbols[trade.Symbol] = ring
+	}
+
+	ring.add(trade)
+}
+
+// Bust removes the trade from the tape and returns it, trades which are no longer on the tape are not found
+func (tt *TradeTape) Bust(id uuid.UUID) (*models.Trade, bool) {
+
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	for _, ring := range tt.symbols {
+		for i := 0; i < ring.count; i++ {
+			if trade := ring.get(i); trade.ID == id {
+				ring.remove(i)
+				return trade, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Trades returns the trades of the symbol matching the query in sequence order.
+// Without a sequence or a from filter the most recent trades are returned, otherwise the oldest matching ones.
+func (tt *TradeTape) Trades(symbol string, query TradeQuery) []*models.Trade {
+
+	tt.mu.RLock()
+	defer tt.mu.RUnlock()
+
+	trades := make([]*models.Trade, 0)
+
+	ring, ok := tt.symbols[symbol]
+	if !ok {
+		return trades
+	}
+
+	for i := 0; i < ring.count; i++ {
+		trade := ring.get(i)
+		if matches(trade, query) {
+			trades = append(trades, trade)
+		}
+	}
+
+	if query.Limit <= 0 || len(trades) <= query.Limit {
+		return trades
+	}
+
+	if query.AfterSequence == 0 && query.From.IsZero() {
+		return trades[len(trades)-query.Limit:]
+	}
+
+	return trades[:query.Limit]
+}
+
+func matches(trade *models.Trade, query TradeQuery) bool {
+
+	if trade.Sequence <= query.AfterSequence {
+		return false
+	}
+
+	if !query.From.IsZero() && trade.Occured.Before(query.From) {
+		return false
+	}
+
+	if !query.To.IsZero() && !trade.Occured.Before(query.To) {
+		return false
+	}
+
+	return true
+}
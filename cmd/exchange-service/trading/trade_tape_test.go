@@ -0,0 +1,92 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func getTapeTrades(tape *TradeTape, count int, start time.Time) {
+	for i := 1; i <= count; i++ {
+		tape.Traded(models.NewTrade(uuid.NewV4(), uint64(i), "TT", 1.99, uint(i), models.Buy, uuid.NewV4(), uuid.NewV4(), start.Add(time.Duration(i)*time.Second)))
+	}
+}
+
+func getSequences(trades []*models.Trade) []uint64 {
+	sequences := make([]uint64, 0)
+	for _, trade := range trades {
+		sequences = append(sequences, trade.Sequence)
+	}
+	return sequences
+}
+
+func TestTradeTapeKeepsCapacity(t *testing.T) {
+
+	require := require.New(t)
+
+	tape := NewTradeTape(3)
+	getTapeTrades(tape, 5, time.Now().UTC())
+
+	require.Equal([]uint64{3, 4, 5}, getSequences(tape.Trades("TT", TradeQuery{})))
+	require.Empty(tape.Trades("XX", TradeQuery{}))
+}
+
+func TestTradeTapeMostRecent(t *testing.T) {
+
+	require := require.New(t)
+
+	tape := NewTradeTape(10)
+	getTapeTrades(tape, 5, time.Now().UTC())
+
+	require.Equal([]uint64{4, 5}, getSequences(tape.Trades("TT", TradeQuery{Limit: 2})))
+}
+
+func TestTradeTapePageBySequence(t *testing.T) {
+
+	require := require.New(t)
+
+	tape := NewTradeTape(10)
+	getTapeTrades(tape, 5, time.Now().UTC())
+
+	require.Equal([]uint64{2, 3}, getSequences(tape.Trades("TT", TradeQuery{AfterSequence: 1, Limit: 2})))
+	require.Equal([]uint64{4, 5}, getSequences(tape.Trades("TT", TradeQuery{AfterSequence: 3, Limit: 2})))
+	require.Empty(tape.Trades("TT", TradeQuery{AfterSequence: 5, Limit: 2}))
+}
+
+func TestTradeTapePageByTime(t *testing.T) {
+
+	require := require.New(t)
+
+	start := time.Date(2016, 8, 13, 17, 33, 0, 0, time.UTC)
+	tape := NewTradeTape(10)
+	getTapeTrades(tape, 5, start)
+
+	query := TradeQuery{From: start.Add(2 * time.Second), To: start.Add(5 * time.Second)}
+	require.Equal([]uint64{2, 3, 4}, getSequences(tape.Trades("TT", query)))
+
+	query.Limit = 2
+	require.Equal([]uint64{2, 3}, getSequences(tape.Trades("TT", query)))
+}
+
+func TestTradeTapeBust(t *testing.T) {
+
+	require := require.New(t)
+
+	tape := NewTradeTape(3)
+	getTapeTrades(tape, 5, time.Now().UTC())
+
+	trades := tape.Trades("TT", TradeQuery{})
+	busted, ok := tape.Bust(trades[1].ID)
+	require.True(ok)
+	require.Equal(uint64(4), busted.Sequence)
+	require.Equal([]uint64{3, 5}, getSequences(tape.Trades("TT", TradeQuery{})))
+
+	_, ok = tape.Bust(trades[1].ID)
+	require.False(ok)
+
+	getTapeTrades(tape, 2, time.Now().UTC())
+	require.Equal([]uint64{5, 1, 2}, getSequences(tape.Trades("TT", TradeQuery{})))
+}
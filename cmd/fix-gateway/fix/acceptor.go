@@ -0,0 +1,83 @@
+package fix
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"time"
+)
+
+// LogonTimeout is the time a new connection has to send the logon
+const LogonTimeout = 10 * time.Second
+
+// Acceptor accepts connections and hands them to the session of the counterparty
+type Acceptor struct {
+	compID   string
+	sessions map[string]*Session
+}
+
+// NewAcceptor creates a new acceptor for the sessions, keyed by the counterparty comp id
+func NewAcceptor(compID string, sessions ...*Session) *Acceptor {
+	acc := &Acceptor{compID, make(map[string]*Session)}
+	for _, s := range sessions {
+		acc.sessions[s.TargetCompID] = s
+	}
+	return acc
+}
+
+// Serve accepts connections until the listener fails
+func (a *Acceptor) Serve(listener net.Listener) error {
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go a.handle(conn)
+	}
+}
+
+func (a *Acceptor) handle(conn net.Conn) {
+
+	reader := NewReader(conn)
+
+	conn.SetReadDeadline(time.Now().Add(LogonTimeout))
+	logon, err := reader.Read()
+	if err != nil {
+		log.Printf("Failed to read logon from %s. %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	session, err := a.getSession(logon)
+	if err != nil {
+		log.Printf("Logon from %s refused. %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	err = session.Run(conn, reader, logon)
+	if err != nil {
+		log.Printf("%s: session ended. %s", session.ID(), err)
+	}
+}
+
+func (a *Acceptor) getSession(logon *Message) (*Session, error) {
+
+	if logon.Type() != LogonType {
+		return nil, fmt.Errorf("first message %s is not a logon", logon.Type())
+	}
+
+	target, _ := logon.Get(TagTargetCompID)
+	if target != a.compID {
+		return nil, fmt.Errorf("unknown target comp id %s", target)
+	}
+
+	sender, _ := logon.Get(TagSenderCompID)
+	session, ok := a.sessions[sender]
+	if !ok {
+		return nil, fmt.Errorf("unknown sender comp id %s", sender)
+	}
+	return session, nil
+}
@@ -0,0 +1,318 @@
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// SOH is the field delimiter of the tag=value encoding
+const SOH = '\x01'
+
+// BeginString of the supported protocol version
+const BeginString = "FIX.4.4"
+
+// TimestampFormat is the UTCTimestamp format with milliseconds
+const TimestampFormat = "20060102-15:04:05.000"
+
+// Tag defines a field tag
+type Tag int
+
+// Tag constants
+const (
+	TagAvgPx            Tag = 6
+	TagBeginSeqNo       Tag = 7
+	TagBeginString      Tag = 8
+	TagBodyLength       Tag = 9
+	TagCheckSum         Tag = 10
+	TagClOrdID          Tag = 11
+	TagCumQty           Tag = 14
+	TagEndSeqNo         Tag = 16
+	TagExecID           Tag = 17
+	TagLastPx           Tag = 31
+	TagLastQty          Tag = 32
+	TagMsgSeqNum        Tag = 34
+	TagMsgType          Tag = 35
+	TagNewSeqNo         Tag = 36
+	TagOrderID          Tag = 37
+	TagOrderQty         Tag = 38
+	TagOrdStatus        Tag = 39
+	TagOrdType          Tag = 40
+	TagOrigClOrdID      Tag = 41
+	TagPossDupFlag      Tag = 43
+	TagPrice            Tag = 44
+	TagRefSeqNum        Tag = 45
+	TagSenderCompID     Tag = 49
+	TagSendingTime      Tag = 52
+	TagSide             Tag = 54
+	TagSymbol           Tag = 55
+	TagTargetCompID     Tag = 56
+	TagText             Tag = 58
+	TagTransactTime     Tag = 60
+	TagEncryptMethod    Tag = 98
+	TagCxlRejReason     Tag = 102
+	TagOrdRejReason     Tag = 103
+	TagHeartBtInt       Tag = 108
+	TagTestReqID        Tag = 112
+	TagOrigSendingTime  Tag = 122
+	TagGapFillFlag      Tag = 123
+	TagResetSeqNumFlag  Tag = 141
+	TagExecType         Tag = 150
+	TagLeavesQty        Tag = 151
+	TagCxlRejResponseTo Tag = 434
+)
+
+// MsgType defines a message type
+type MsgType string
+
+// MsgType constants
+const (
+	HeartbeatType                 MsgType = "0"
+	TestRequestType               MsgType = "1"
+	ResendRequestType             MsgType = "2"
+	RejectType                    MsgType = "3"
+	SequenceResetType             MsgType = "4"
+	LogoutType                    MsgType = "5"
+	ExecutionReportType           MsgType = "8"
+	OrderCancelRejectType         MsgType = "9"
+	LogonType                     MsgType = "A"
+	NewOrderSingleType            MsgType = "D"
+	OrderCancelRequestType        MsgType = "F"
+	OrderCancelReplaceRequestType MsgType = "G"
+)
+
+// IsAdmin returns true for session level message types
+func (t MsgType) IsAdmin() bool {
+	switch t {
+	case HeartbeatType, TestRequestType, ResendRequestType, RejectType, SequenceResetType, LogoutType, LogonType:
+		return true
+	default:
+		return false
+	}
+}
+
+// Field defines a tag value pair
+type Field struct {
+	Tag   Tag
+	Value string
+}
+
+// Message defines a FIX message as an ordered list of body fields.
+// BeginString, BodyLength and CheckSum are computed when the message is serialized.
+type Message struct {
+	Fields []Field
+}
+
+// NewMessage creates a new message of the given type
+func NewMessage(msgType MsgType) *Message {
+	return &Message{[]Field{{TagMsgType, string(msgType)}}}
+}
+
+// Type returns the message type
+func (m *Message) Type() MsgType {
+	v, _ := m.Get(TagMsgType)
+	return MsgType(v)
+}
+
+// Get returns the value of the first field with the tag
+func (m *Message) Get(tag Tag) (string, bool) {
+	for _, f := range m.Fields {
+		if f.Tag == tag {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// GetInt returns the value of the tag as integer
+func (m *Message) GetInt(tag Tag) (int, error) {
+	v, ok := m.Get(tag)
+	if !ok {
+		return 0, fmt.Errorf("tag %d missing", tag)
+	}
+	return strconv.Atoi(v)
+}
+
+// GetFloat returns the value of the tag as float
+func (m *Message) GetFloat(tag Tag) (float64, error) {
+	v, ok := m.Get(tag)
+	if !ok {
+		return 0, fmt.Errorf("tag %d missing", tag)
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+// GetBool returns true if the tag has the value Y
+func (m *Message) GetBool(tag Tag) bool {
+	v, _ := m.Get(tag)
+	return v == "Y"
+}
+
+// Set replaces the value of the tag or appends it
+func (m *Message) Set(tag Tag, value string) *Message {
+	for i, f := range m.Fields {
+		if f.Tag == tag {
+			m.Fields[i].Value = value
+			return m
+		}
+	}
+	m.Fields = append(m.Fields, Field{tag, value})
+	return m
+}
+
+// SetInt sets a integer value
+func (m *Message) SetInt(tag Tag, value int) *Message {
+	return m.Set(tag, strconv.Itoa(value))
+}
+
+// SetFloat sets a float value
+func (m *Message) SetFloat(tag Tag, value float64) *Message {
+	return m.Set(tag, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// SetBool sets a Y/N value
+func (m *Message) SetBool(tag Tag, value bool) *Message {
+	if value {
+		return m.Set(tag, "Y")
+	}
+	return m.Set(tag, "N")
+}
+
+// SetTime sets a UTCTimestamp value
+func (m *Message) SetTime(tag Tag, value time.Time) *Message {
+	return m.Set(tag, value.UTC().Format(TimestampFormat))
+}
+
+// Bytes serializes the message adding BeginString, BodyLength and CheckSum
+func (m *Message) Bytes() []byte {
+
+	var body bytes.Buffer
+	for _, f := range m.Fields {
+		writeField(&body, f.Tag, f.Value)
+	}
+
+	var msg bytes.Buffer
+	writeField(&msg, TagBeginString, BeginString)
+	writeField(&msg, TagBodyLength, strconv.Itoa(body.Len()))
+	msg.Write(body.Bytes())
+	writeField(&msg, TagCheckSum, fmt.Sprintf("%03d", checksum(msg.Bytes())))
+	return msg.Bytes()
+}
+
+func (m *Message) String() string {
+	return string(bytes.Replace(m.Bytes(), []byte{SOH}, []byte{'|'}, -1))
+}
+
+// Parse parses and validates a complete message
+func Parse(data []byte) (*Message, error) {
+
+	if len(data) == 0 || data[len(data)-1] != SOH {
+		return nil, errors.New("message not terminated")
+	}
+
+	var fields []Field
+	for _, raw := range bytes.Split(data[:len(data)-1], []byte{SOH}) {
+		i := bytes.IndexByte(raw, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid field %q", raw)
+		}
+		tag, err := strconv.Atoi(string(raw[:i]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag %q", raw[:i])
+		}
+		fields = append(fields, Field{Tag(tag), string(raw[i+1:])})
+	}
+
+	if len(fields) < 4 || fields[0].Tag != TagBeginString || fields[1].Tag != TagBodyLength || fields[2].Tag != TagMsgType {
+		return nil, errors.New("invalid standard header")
+	}
+
+	if fields[0].Value != BeginString {
+		return nil, fmt.Errorf("unsupported begin string %s", fields[0].Value)
+	}
+
+	last := fields[len(fields)-1]
+	if last.Tag != TagCheckSum {
+		return nil, errors.New("checksum missing")
+	}
+
+	trailer := bytes.LastIndex(data[:len(data)-1], []byte{SOH}) + 1
+	expected := fmt.Sprintf("%03d", checksum(data[:trailer]))
+	if last.Value != expected {
+		return nil, fmt.Errorf("invalid checksum %s, expected %s", last.Value, expected)
+	}
+
+	bodyStart := len(fields[0].Value) + len(fields[1].Value) + 6
+	bodyLength, err := strconv.Atoi(fields[1].Value)
+	if err != nil || bodyLength != trailer-bodyStart {
+		return nil, fmt.Errorf("invalid body length %s", fields[1].Value)
+	}
+
+	return &Message{fields[2 : len(fields)-1]}, nil
+}
+
+// Reader reads framed messages from a stream
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a new message reader
+func NewReader(r io.Reader) *Reader {
+	return &Reader{bufio.NewReader(r)}
+}
+
+// Read reads the next message from the stream
+func (r *Reader) Read() (*Message, error) {
+
+	begin, err := r.r.ReadBytes(SOH)
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := r.r.ReadBytes(SOH)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(length, []byte("9=")) {
+		return nil, errors.New("body length missing")
+	}
+
+	bodyLength, err := strconv.Atoi(string(length[2 : len(length)-1]))
+	if err != nil || bodyLength < 0 {
+		return nil, fmt.Errorf("invalid body length %q", length)
+	}
+
+	body := make([]byte, bodyLength)
+	if _, err = io.ReadFull(r.r, body); err != nil {
+		return nil, err
+	}
+
+	trailer, err := r.r.ReadBytes(SOH)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, len(begin)+len(length)+len(body)+len(trailer))
+	data = append(append(append(append(data, begin...), length...), body...), trailer...)
+	return Parse(data)
+}
+
+func writeField(b *bytes.Buffer, tag Tag, value string) {
+	b.WriteString(strconv.Itoa(int(tag)))
+	b.WriteByte('=')
+	b.WriteString(value)
+	b.WriteByte(SOH)
+}
+
+func checksum(data []byte) int {
+	sum := 0
+	for _, b := range data {
+		sum += int(b)
+	}
+	return sum % 256
+}
@@ -0,0 +1,110 @@
+package fix
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func raw(s string) []byte {
+	return []byte(strings.Replace(s, "|", string(SOH), -1))
+}
+
+func TestMessageBytes(t *testing.T) {
+
+	require := require.New(t)
+
+	msg := NewMessage(HeartbeatType).Set(TagSenderCompID, "A").Set(TagTargetCompID, "B").SetInt(TagMsgSeqNum, 1)
+
+	require.Equal(raw("8=FIX.4.4|9=20|35=0|49=A|56=B|34=1|10=125|"), msg.Bytes())
+}
+
+func TestParse(t *testing.T) {
+
+	require := require.New(t)
+
+	msg, err := Parse(raw("8=FIX.4.4|9=20|35=0|49=A|56=B|34=1|10=125|"))
+	require.Nil(err)
+	require.Equal(HeartbeatType, msg.Type())
+
+	sender, ok := msg.Get(TagSenderCompID)
+	require.True(ok)
+	require.Equal("A", sender)
+
+	seq, err := msg.GetInt(TagMsgSeqNum)
+	require.Nil(err)
+	require.Equal(1, seq)
+}
+
+func TestParseRoundTrip(t *testing.T) {
+
+	require := require.New(t)
+
+	msg := NewMessage(NewOrderSingleType).Set(TagClOrdID, "1").SetFloat(TagPrice, 10.25).SetBool(TagPossDupFlag, true)
+
+	parsed, err := Parse(msg.Bytes())
+	require.Nil(err)
+	require.Equal(msg.Fields, parsed.Fields)
+
+	price, err := parsed.GetFloat(TagPrice)
+	require.Nil(err)
+	require.Equal(10.25, price)
+	require.True(parsed.GetBool(TagPossDupFlag))
+}
+
+func TestParseInvalid(t *testing.T) {
+
+	require := require.New(t)
+
+	tests := []string{
+		"",
+		"8=FIX.4.4|9=20|35=0|49=A|56=B|34=1|10=125",
+		"8=FIX.4.4|9=20|35=0|49=A|56=B|34=1|10=126|",
+		"8=FIX.4.4|9=21|35=0|49=A|56=B|34=1|10=126|",
+		"8=FIX.4.2|9=20|35=0|49=A|56=B|34=1|10=123|",
+		"9=20|8=FIX.4.4|35=0|49=A|56=B|34=1|10=125|",
+		"8=FIX.4.4|9=20|35=0|49=A|56=B|34=1|",
+		"8=FIX.4.4|9=5|35=0|X|10=000|",
+	}
+
+	for _, test := range tests {
+		_, err := Parse(raw(test))
+		require.NotNil(err, test)
+	}
+}
+
+func TestReader(t *testing.T) {
+
+	require := require.New(t)
+
+	var stream bytes.Buffer
+	stream.Write(NewMessage(HeartbeatType).SetInt(TagMsgSeqNum, 1).Bytes())
+	stream.Write(NewMessage(TestRequestType).SetInt(TagMsgSeqNum, 2).Set(TagTestReqID, "a=b").Bytes())
+
+	reader := NewReader(&stream)
+
+	msg, err := reader.Read()
+	require.Nil(err)
+	require.Equal(HeartbeatType, msg.Type())
+
+	msg, err = reader.Read()
+	require.Nil(err)
+	require.Equal(TestRequestType, msg.Type())
+	id, _ := msg.Get(TagTestReqID)
+	require.Equal("a=b", id)
+
+	_, err = reader.Read()
+	require.NotNil(err)
+}
+
+func TestMsgTypeIsAdmin(t *testing.T) {
+
+	require := require.New(t)
+
+	require.True(LogonType.IsAdmin())
+	require.True(SequenceResetType.IsAdmin())
+	require.False(NewOrderSingleType.IsAdmin())
+	require.False(ExecutionReportType.IsAdmin())
+}
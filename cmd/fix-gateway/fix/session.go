@@ -0,0 +1,493 @@
+package fix
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Application receives the application messages of a session
+type Application interface {
+	OnLogon(session *Session)
+	OnLogout(session *Session)
+	FromApp(session *Session, msg *Message)
+}
+
+// ErrNotLoggedOn is returned when sending on a session without a connection
+var ErrNotLoggedOn = errors.New("session not logged on")
+
+// Session implements the session layer of a counterparty.
+// The session outlives its connections, sequence numbers and sent messages are kept in the store.
+type Session struct {
+	SenderCompID string
+	TargetCompID string
+	store        MessageStore
+	app          Application
+	conn         io.ReadWriteCloser
+	heartBtInt   time.Duration
+	lastSent     time.Time
+	lastReceived time.Time
+	testRequest  bool
+	resendEnd    int
+	loggingOut   bool
+	mu           sync.Mutex
+}
+
+// NewSession creates a new session
+func NewSession(senderCompID string, targetCompID string, store MessageStore, app Application) *Session {
+	return &Session{SenderCompID: senderCompID, TargetCompID: targetCompID, store: store, app: app}
+}
+
+// SessionID returns the identifier of the session between the comp ids
+func SessionID(senderCompID string, targetCompID string) string {
+	return fmt.Sprintf("%s-%s-%s", BeginString, senderCompID, targetCompID)
+}
+
+// ID returns the session identifier
+func (s *Session) ID() string {
+	return SessionID(s.SenderCompID, s.TargetCompID)
+}
+
+// IsLoggedOn returns true while the session has a connection
+func (s *Session) IsLoggedOn() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn != nil
+}
+
+// Send sends a application message.
+// When the session is not logged on the message is only stored and will be delivered by a resend request after the next logon.
+func (s *Session) Send(msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.send(msg)
+	if err == ErrNotLoggedOn {
+		log.Printf("%s: stored %s for resend", s.ID(), msg.Type())
+		return nil
+	}
+	return err
+}
+
+// Run takes over the connection after the acceptor received the logon and blocks until it is closed
+func (s *Session) Run(conn io.ReadWriteCloser, reader *Reader, logon *Message) error {
+
+	err := s.logon(conn, logon)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	s.app.OnLogon(s)
+	defer s.app.OnLogout(s)
+	defer s.disconnect()
+
+	messages := make(chan *Message)
+	failed := make(chan error, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			msg, err := reader.Read()
+			if err != nil {
+				failed <- err
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(s.tick())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-messages:
+			done, err := s.receive(msg)
+			if err != nil || done {
+				return err
+			}
+		case err := <-failed:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case <-ticker.C:
+			err := s.checkHeartbeat()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Logout initiates a logout of the session
+func (s *Session) Logout(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loggingOut = true
+	return s.send(NewMessage(LogoutType).Set(TagText, text))
+}
+
+func (s *Session) logon(conn io.ReadWriteCloser, logon *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return fmt.Errorf("%s: session already logged on", s.ID())
+	}
+
+	heartBtInt, err := logon.GetInt(TagHeartBtInt)
+	if err != nil || heartBtInt <= 0 {
+		return fmt.Errorf("%s: invalid heartbeat interval", s.ID())
+	}
+
+	seq, err := logon.GetInt(TagMsgSeqNum)
+	if err != nil {
+		return fmt.Errorf("%s: logon sequence number missing", s.ID())
+	}
+
+	reset := logon.GetBool(TagResetSeqNumFlag)
+	if reset {
+		err = s.store.Reset()
+		if err != nil {
+			return err
+		}
+	}
+
+	expected := s.store.NextTargetSeqNum()
+	if seq < expected {
+		s.conn = conn
+		s.send(NewMessage(LogoutType).Set(TagText, fmt.Sprintf("MsgSeqNum too low, expecting %d but received %d", expected, seq)))
+		s.conn = nil
+		return fmt.Errorf("%s: logon sequence number %d too low, expecting %d", s.ID(), seq, expected)
+	}
+
+	s.conn = conn
+	s.heartBtInt = time.Duration(heartBtInt) * time.Second
+	s.lastReceived = time.Now()
+	s.testRequest = false
+	s.loggingOut = false
+	s.resendEnd = 0
+
+	reply := NewMessage(LogonType).SetInt(TagEncryptMethod, 0).SetInt(TagHeartBtInt, heartBtInt)
+	if reset {
+		reply.SetBool(TagResetSeqNumFlag, true)
+	}
+
+	err = s.send(reply)
+	if err != nil {
+		s.conn = nil
+		return err
+	}
+
+	log.Printf("%s: logged on", s.ID())
+
+	if seq > expected {
+		s.resendEnd = seq
+		return s.requestResend(expected)
+	}
+	return s.store.SetNextTargetSeqNum(expected + 1)
+}
+
+// receive processes a incoming message and returns true when the session should be closed
+func (s *Session) receive(msg *Message) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastReceived = time.Now()
+	s.testRequest = false
+
+	sender, _ := msg.Get(TagSenderCompID)
+	target, _ := msg.Get(TagTargetCompID)
+	if sender != s.TargetCompID || target != s.SenderCompID {
+		s.send(NewMessage(LogoutType).Set(TagText, "CompID problem"))
+		return true, fmt.Errorf("%s: invalid comp ids %s -> %s", s.ID(), sender, target)
+	}
+
+	seq, err := msg.GetInt(TagMsgSeqNum)
+	if err != nil {
+		s.send(NewMessage(LogoutType).Set(TagText, "MsgSeqNum missing"))
+		return true, fmt.Errorf("%s: sequence number missing", s.ID())
+	}
+
+	if msg.Type() == SequenceResetType && !msg.GetBool(TagGapFillFlag) {
+		return false, s.sequenceReset(msg)
+	}
+
+	expected := s.store.NextTargetSeqNum()
+
+	if seq > expected {
+		if s.resendEnd == 0 {
+			err = s.requestResend(expected)
+			if err != nil {
+				return true, err
+			}
+		}
+		if seq > s.resendEnd {
+			s.resendEnd = seq
+		}
+		switch msg.Type() {
+		case ResendRequestType:
+			return false, s.resend(msg)
+		case LogoutType:
+			s.send(NewMessage(LogoutType))
+			return true, nil
+		}
+		return false, nil
+	}
+
+	if seq < expected {
+		if msg.GetBool(TagPossDupFlag) {
+			return false, nil
+		}
+		s.send(NewMessage(LogoutType).Set(TagText, fmt.Sprintf("MsgSeqNum too low, expecting %d but received %d", expected, seq)))
+		return true, fmt.Errorf("%s: sequence number %d too low, expecting %d", s.ID(), seq, expected)
+	}
+
+	err = s.store.SetNextTargetSeqNum(expected + 1)
+	if err != nil {
+		return true, err
+	}
+
+	if s.resendEnd != 0 && seq >= s.resendEnd {
+		s.resendEnd = 0
+	}
+
+	switch msg.Type() {
+	case HeartbeatType, LogonType:
+		return false, nil
+	case TestRequestType:
+		id, _ := msg.Get(TagTestReqID)
+		return false, s.send(NewMessage(HeartbeatType).Set(TagTestReqID, id))
+	case ResendRequestType:
+		return false, s.resend(msg)
+	case SequenceResetType:
+		return false, s.sequenceReset(msg)
+	case RejectType:
+		text, _ := msg.Get(TagText)
+		log.Printf("%s: message rejected by counterparty. %s", s.ID(), text)
+		return false, nil
+	case LogoutType:
+		if !s.loggingOut {
+			s.send(NewMessage(LogoutType))
+		}
+		log.Printf("%s: logged out", s.ID())
+		return true, nil
+	}
+
+	s.mu.Unlock()
+	s.app.FromApp(s, msg)
+	s.mu.Lock()
+	return false, nil
+}
+
+func (s *Session) sequenceReset(msg *Message) error {
+
+	newSeq, err := msg.GetInt(TagNewSeqNo)
+	if err != nil {
+		return s.reject(msg, "NewSeqNo missing")
+	}
+
+	expected := s.store.NextTargetSeqNum()
+	if newSeq < expected && !msg.GetBool(TagGapFillFlag) {
+		return s.reject(msg, fmt.Sprintf("NewSeqNo %d lower than expected %d", newSeq, expected))
+	}
+
+	if newSeq > expected {
+		return s.store.SetNextTargetSeqNum(newSeq)
+	}
+	return nil
+}
+
+func (s *Session) requestResend(begin int) error {
+	log.Printf("%s: requesting resend from %d", s.ID(), begin)
+	return s.send(NewMessage(ResendRequestType).SetInt(TagBeginSeqNo, begin).SetInt(TagEndSeqNo, 0))
+}
+
+// resend sends the requested application messages again and fills the gaps of the session level messages
+func (s *Session) resend(msg *Message) error {
+
+	begin, err := msg.GetInt(TagBeginSeqNo)
+	if err != nil {
+		return s.reject(msg, "BeginSeqNo missing")
+	}
+
+	end, err := msg.GetInt(TagEndSeqNo)
+	if err != nil {
+		return s.reject(msg, "EndSeqNo missing")
+	}
+
+	last := s.store.NextSenderSeqNum() - 1
+	if end == 0 || end > last {
+		end = last
+	}
+
+	log.Printf("%s: resending %d to %d", s.ID(), begin, end)
+
+	stored, err := s.store.Messages(begin, end)
+	if err != nil {
+		return err
+	}
+
+	var seqs []int
+	for seq := range stored {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+
+	gapStart := begin
+	for _, seq := range seqs {
+
+		original, err := Parse(stored[seq])
+		if err != nil || original.Type().IsAdmin() {
+			continue
+		}
+
+		if gapStart < seq {
+			err = s.gapFill(gapStart, seq)
+			if err != nil {
+				return err
+			}
+		}
+
+		sendingTime, _ := original.Get(TagSendingTime)
+		original.SetBool(TagPossDupFlag, true)
+		original.Set(TagOrigSendingTime, sendingTime)
+		original.SetTime(TagSendingTime, time.Now())
+
+		err = s.write(original.Bytes())
+		if err != nil {
+			return err
+		}
+		gapStart = seq + 1
+	}
+
+	if gapStart <= end {
+		return s.gapFill(gapStart, end+1)
+	}
+	return nil
+}
+
+func (s *Session) gapFill(seq int, newSeq int) error {
+
+	msg := NewMessage(SequenceResetType)
+	msg.Set(TagSenderCompID, s.SenderCompID)
+	msg.Set(TagTargetCompID, s.TargetCompID)
+	msg.SetInt(TagMsgSeqNum, seq)
+	msg.SetBool(TagPossDupFlag, true)
+	msg.SetTime(TagSendingTime, time.Now())
+	msg.SetTime(TagOrigSendingTime, time.Now())
+	msg.SetBool(TagGapFillFlag, true)
+	msg.SetInt(TagNewSeqNo, newSeq)
+	return s.write(msg.Bytes())
+}
+
+func (s *Session) reject(msg *Message, text string) error {
+	seq, _ := msg.Get(TagMsgSeqNum)
+	return s.send(NewMessage(RejectType).Set(TagRefSeqNum, seq).Set(TagText, text))
+}
+
+func (s *Session) checkHeartbeat() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	silence := now.Sub(s.lastReceived)
+
+	if s.testRequest && silence >= 2*s.heartBtInt+s.heartBtInt/5 {
+		return fmt.Errorf("%s: heartbeat timeout", s.ID())
+	}
+
+	if !s.testRequest && silence >= s.heartBtInt+s.heartBtInt/5 {
+		s.testRequest = true
+		return s.send(NewMessage(TestRequestType).Set(TagTestReqID, now.UTC().Format(TimestampFormat)))
+	}
+
+	if now.Sub(s.lastSent) >= s.heartBtInt {
+		return s.send(NewMessage(HeartbeatType))
+	}
+	return nil
+}
+
+func (s *Session) tick() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.heartBtInt < 4*time.Second {
+		return s.heartBtInt / 4
+	}
+	return time.Second
+}
+
+// send adds the standard header, stores and writes the message, the caller holds the lock
+func (s *Session) send(msg *Message) error {
+
+	seq := s.store.NextSenderSeqNum()
+
+	header := []Field{
+		{TagMsgType, string(msg.Type())},
+		{TagSenderCompID, s.SenderCompID},
+		{TagTargetCompID, s.TargetCompID},
+		{TagMsgSeqNum, fmt.Sprintf("%d", seq)},
+		{TagSendingTime, time.Now().UTC().Format(TimestampFormat)},
+	}
+
+	var body []Field
+	for _, f := range msg.Fields {
+		switch f.Tag {
+		case TagMsgType, TagSenderCompID, TagTargetCompID, TagMsgSeqNum, TagSendingTime:
+		default:
+			body = append(body, f)
+		}
+	}
+	msg.Fields = append(header, body...)
+
+	data := msg.Bytes()
+	err := s.store.Save(seq, data)
+	if err != nil {
+		return err
+	}
+
+	err = s.store.SetNextSenderSeqNum(seq + 1)
+	if err != nil {
+		return err
+	}
+
+	return s.write(data)
+}
+
+func (s *Session) write(data []byte) error {
+
+	if s.conn == nil {
+		return ErrNotLoggedOn
+	}
+
+	_, err := s.conn.Write(data)
+	if err != nil {
+		return err
+	}
+
+	s.lastSent = time.Now()
+	return nil
+}
+
+func (s *Session) disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	log.Printf("%s: disconnected", s.ID())
+}
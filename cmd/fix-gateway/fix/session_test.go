@@ -0,0 +1,344 @@
+package fix
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testApp struct {
+	received chan *Message
+}
+
+func (a *testApp) OnLogon(session *Session)  {}
+func (a *testApp) OnLogout(session *Session) {}
+func (a *testApp) FromApp(session *Session, msg *Message) {
+	a.received <- msg
+}
+
+type testClient struct {
+	require *require.Assertions
+	conn    net.Conn
+	reader  *Reader
+	seq     int
+}
+
+func (c *testClient) send(msg *Message) {
+	c.sendSeq(msg, c.seq)
+	c.seq++
+}
+
+func (c *testClient) sendSeq(msg *Message, seq int) {
+	msg.Set(TagSenderCompID, "CLIENT").Set(TagTargetCompID, "GW").SetInt(TagMsgSeqNum, seq).SetTime(TagSendingTime, time.Now())
+	_, err := c.conn.Write(msg.Bytes())
+	c.require.Nil(err)
+}
+
+func (c *testClient) read(msgType MsgType) *Message {
+	c.conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	msg, err := c.reader.Read()
+	c.require.Nil(err)
+	c.require.Equal(msgType, msg.Type())
+	return msg
+}
+
+func (c *testClient) int(msg *Message, tag Tag) int {
+	v, err := msg.GetInt(tag)
+	c.require.Nil(err)
+	return v
+}
+
+func newTestStore(t *testing.T) (*FileStore, func()) {
+	dir, err := ioutil.TempDir("", "fixsession")
+	require.Nil(t, err)
+
+	store, err := NewFileStore(dir, SessionID("GW", "CLIENT"))
+	require.Nil(t, err)
+
+	return store, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// connect runs the acceptor side of a in memory connection and logs on with the heartbeat interval
+func connect(t *testing.T, session *Session, heartBtInt int) (*testClient, chan struct{}) {
+
+	server, client := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		NewAcceptor("GW", session).handle(server)
+		close(done)
+	}()
+
+	c := &testClient{require.New(t), client, NewReader(client), 1}
+	c.send(NewMessage(LogonType).SetInt(TagEncryptMethod, 0).SetInt(TagHeartBtInt, heartBtInt))
+	return c, done
+}
+
+func TestSessionLogonAndLogout(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	app := &testApp{make(chan *Message, 10)}
+	session := NewSession("GW", "CLIENT", store, app)
+
+	c, done := connect(t, session, 30)
+
+	logon := c.read(LogonType)
+	require.Equal(1, c.int(logon, TagMsgSeqNum))
+	require.Equal(30, c.int(logon, TagHeartBtInt))
+	require.True(session.IsLoggedOn())
+
+	c.send(NewMessage(NewOrderSingleType).Set(TagClOrdID, "1"))
+	msg := <-app.received
+	clOrdID, _ := msg.Get(TagClOrdID)
+	require.Equal("1", clOrdID)
+
+	c.send(NewMessage(LogoutType))
+	c.read(LogoutType)
+	<-done
+
+	require.False(session.IsLoggedOn())
+	require.Equal(4, store.NextTargetSeqNum())
+	require.Equal(3, store.NextSenderSeqNum())
+}
+
+func TestSessionTestRequest(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	session := NewSession("GW", "CLIENT", store, &testApp{make(chan *Message, 10)})
+
+	c, _ := connect(t, session, 30)
+	c.read(LogonType)
+
+	c.send(NewMessage(TestRequestType).Set(TagTestReqID, "T1"))
+	heartbeat := c.read(HeartbeatType)
+	id, _ := heartbeat.Get(TagTestReqID)
+	require.Equal("T1", id)
+}
+
+func TestSessionGapDetection(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	app := &testApp{make(chan *Message, 10)}
+	session := NewSession("GW", "CLIENT", store, app)
+
+	c, _ := connect(t, session, 30)
+	c.read(LogonType)
+
+	c.sendSeq(NewMessage(NewOrderSingleType).Set(TagClOrdID, "2"), 3)
+	resend := c.read(ResendRequestType)
+	require.Equal(2, c.int(resend, TagBeginSeqNo))
+	require.Equal(0, c.int(resend, TagEndSeqNo))
+
+	c.sendSeq(NewMessage(NewOrderSingleType).Set(TagClOrdID, "3"), 4)
+
+	c.sendSeq(NewMessage(NewOrderSingleType).Set(TagClOrdID, "1").SetBool(TagPossDupFlag, true), 2)
+	c.sendSeq(NewMessage(NewOrderSingleType).Set(TagClOrdID, "2").SetBool(TagPossDupFlag, true), 3)
+	c.sendSeq(NewMessage(NewOrderSingleType).Set(TagClOrdID, "3").SetBool(TagPossDupFlag, true), 4)
+
+	for _, expected := range []string{"1", "2", "3"} {
+		msg := <-app.received
+		clOrdID, _ := msg.Get(TagClOrdID)
+		require.Equal(expected, clOrdID)
+	}
+
+	require.Equal(5, store.NextTargetSeqNum())
+	require.Len(app.received, 0)
+}
+
+func TestSessionSequenceResetGapFill(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	app := &testApp{make(chan *Message, 10)}
+	session := NewSession("GW", "CLIENT", store, app)
+
+	c, _ := connect(t, session, 30)
+	c.read(LogonType)
+
+	c.send(NewMessage(SequenceResetType).SetBool(TagGapFillFlag, true).SetInt(TagNewSeqNo, 10))
+	c.sendSeq(NewMessage(NewOrderSingleType).Set(TagClOrdID, "1"), 10)
+
+	<-app.received
+	require.Equal(11, store.NextTargetSeqNum())
+}
+
+func TestSessionResend(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	session := NewSession("GW", "CLIENT", store, &testApp{make(chan *Message, 10)})
+
+	c, _ := connect(t, session, 30)
+	c.read(LogonType)
+
+	go session.Send(NewMessage(ExecutionReportType).Set(TagClOrdID, "1"))
+	report := c.read(ExecutionReportType)
+	require.Equal(2, c.int(report, TagMsgSeqNum))
+	go session.Send(NewMessage(HeartbeatType))
+	c.read(HeartbeatType)
+
+	c.send(NewMessage(ResendRequestType).SetInt(TagBeginSeqNo, 1).SetInt(TagEndSeqNo, 0))
+
+	gapFill := c.read(SequenceResetType)
+	require.Equal(1, c.int(gapFill, TagMsgSeqNum))
+	require.Equal(2, c.int(gapFill, TagNewSeqNo))
+	require.True(gapFill.GetBool(TagGapFillFlag))
+
+	resent := c.read(ExecutionReportType)
+	require.Equal(2, c.int(resent, TagMsgSeqNum))
+	require.True(resent.GetBool(TagPossDupFlag))
+	original, _ := report.Get(TagSendingTime)
+	origSendingTime, _ := resent.Get(TagOrigSendingTime)
+	require.Equal(original, origSendingTime)
+
+	gapFill = c.read(SequenceResetType)
+	require.Equal(3, c.int(gapFill, TagMsgSeqNum))
+	require.Equal(4, c.int(gapFill, TagNewSeqNo))
+}
+
+func TestSessionStoresMessagesWhileLoggedOff(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	session := NewSession("GW", "CLIENT", store, &testApp{make(chan *Message, 10)})
+
+	require.Nil(session.Send(NewMessage(ExecutionReportType).Set(TagClOrdID, "1")))
+
+	c, _ := connect(t, session, 30)
+	logon := c.read(LogonType)
+	require.Equal(2, c.int(logon, TagMsgSeqNum))
+
+	c.send(NewMessage(ResendRequestType).SetInt(TagBeginSeqNo, 1).SetInt(TagEndSeqNo, 1))
+
+	resent := c.read(ExecutionReportType)
+	require.Equal(1, c.int(resent, TagMsgSeqNum))
+	require.True(resent.GetBool(TagPossDupFlag))
+}
+
+func TestSessionLogonSequenceTooLow(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	require.Nil(store.SetNextTargetSeqNum(5))
+	session := NewSession("GW", "CLIENT", store, &testApp{make(chan *Message, 10)})
+
+	c, done := connect(t, session, 30)
+	c.read(LogoutType)
+	<-done
+
+	_, err := c.reader.Read()
+	require.Equal(io.EOF, err)
+	require.False(session.IsLoggedOn())
+}
+
+func TestSessionLogonReset(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	require.Nil(store.SetNextTargetSeqNum(5))
+	require.Nil(store.SetNextSenderSeqNum(7))
+	session := NewSession("GW", "CLIENT", store, &testApp{make(chan *Message, 10)})
+
+	server, client := net.Pipe()
+	go NewAcceptor("GW", session).handle(server)
+
+	c := &testClient{require, client, NewReader(client), 1}
+	c.send(NewMessage(LogonType).SetInt(TagEncryptMethod, 0).SetInt(TagHeartBtInt, 30).SetBool(TagResetSeqNumFlag, true))
+
+	logon := c.read(LogonType)
+	require.Equal(1, c.int(logon, TagMsgSeqNum))
+	require.True(logon.GetBool(TagResetSeqNumFlag))
+
+	c.send(NewMessage(TestRequestType).Set(TagTestReqID, "T1"))
+	c.read(HeartbeatType)
+	require.Equal(3, store.NextTargetSeqNum())
+}
+
+func TestSessionHeartbeats(t *testing.T) {
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	session := NewSession("GW", "CLIENT", store, &testApp{make(chan *Message, 10)})
+
+	c, _ := connect(t, session, 1)
+	c.read(LogonType)
+
+	go func() {
+		for seq := 2; seq < 6; seq++ {
+			time.Sleep(400 * time.Millisecond)
+			msg := NewMessage(HeartbeatType).Set(TagSenderCompID, "CLIENT").Set(TagTargetCompID, "GW").SetInt(TagMsgSeqNum, seq)
+			if _, err := c.conn.Write(msg.Bytes()); err != nil {
+				return
+			}
+		}
+	}()
+
+	c.read(HeartbeatType)
+}
+
+func TestSessionHeartbeatTimeout(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	session := NewSession("GW", "CLIENT", store, &testApp{make(chan *Message, 10)})
+
+	c, done := connect(t, session, 1)
+	c.read(LogonType)
+
+	testRequest := false
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		msg, err := c.reader.Read()
+		if err != nil {
+			break
+		}
+		testRequest = testRequest || msg.Type() == TestRequestType
+	}
+
+	<-done
+	require.True(testRequest)
+	require.False(session.IsLoggedOn())
+}
+
+func TestAcceptorUnknownSession(t *testing.T) {
+
+	require := require.New(t)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	session := NewSession("GW", "OTHER", store, &testApp{make(chan *Message, 10)})
+
+	c, done := connect(t, session, 30)
+	<-done
+
+	_, err := c.reader.Read()
+	require.NotNil(err)
+}
@@ -0,0 +1,237 @@
+package fix
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MessageStore persists sequence numbers and sent messages of a session
+type MessageStore interface {
+	NextSenderSeqNum() int
+	NextTargetSeqNum() int
+	SetNextSenderSeqNum(seq int) error
+	SetNextTargetSeqNum(seq int) error
+	Save(seq int, data []byte) error
+	Messages(begin int, end int) (map[int][]byte, error)
+	Reset() error
+	Close() error
+}
+
+// FileStore is a message store on local disk.
+// Sequence numbers are kept in <session>.seqnums and sent messages are appended to <session>.body.
+type FileStore struct {
+	seqPath  string
+	bodyPath string
+	body     *os.File
+	sender   int
+	target   int
+	messages map[int][]byte
+	mu       sync.Mutex
+}
+
+// NewFileStore opens or creates the store of a session in the directory
+func NewFileStore(dir string, sessionID string) (*FileStore, error) {
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{
+		seqPath:  filepath.Join(dir, sessionID+".seqnums"),
+		bodyPath: filepath.Join(dir, sessionID+".body"),
+		sender:   1,
+		target:   1,
+		messages: make(map[int][]byte),
+	}
+
+	err = s.loadSeqNums()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.loadMessages()
+	if err != nil {
+		return nil, err
+	}
+
+	s.body, err = os.OpenFile(s.bodyPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// NextSenderSeqNum returns the sequence number of the next outgoing message
+func (s *FileStore) NextSenderSeqNum() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sender
+}
+
+// NextTargetSeqNum returns the expected sequence number of the next incoming message
+func (s *FileStore) NextTargetSeqNum() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.target
+}
+
+// SetNextSenderSeqNum sets the sequence number of the next outgoing message
+func (s *FileStore) SetNextSenderSeqNum(seq int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sender = seq
+	return s.saveSeqNums()
+}
+
+// SetNextTargetSeqNum sets the expected sequence number of the next incoming message
+func (s *FileStore) SetNextTargetSeqNum(seq int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.target = seq
+	return s.saveSeqNums()
+}
+
+// Save appends a sent message to the store
+func (s *FileStore) Save(seq int, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintf(s.body, "%d,%d\n", seq, len(data))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.body.Write(data)
+	if err != nil {
+		return err
+	}
+
+	s.messages[seq] = data
+	return nil
+}
+
+// Messages returns the stored messages in the sequence range, a end of 0 means infinity
+func (s *FileStore) Messages(begin int, end int) (map[int][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msgs := make(map[int][]byte)
+	for seq, data := range s.messages {
+		if seq >= begin && (end == 0 || seq <= end) {
+			msgs[seq] = data
+		}
+	}
+	return msgs, nil
+}
+
+// Reset clears the messages and resets both sequence numbers to 1
+func (s *FileStore) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.body.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	s.messages = make(map[int][]byte)
+	s.sender = 1
+	s.target = 1
+	return s.saveSeqNums()
+}
+
+// Close closes the store
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.body.Close()
+}
+
+func (s *FileStore) loadSeqNums() error {
+
+	data, err := ioutil.ReadFile(s.seqPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(strings.TrimSpace(string(data)), ":")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid sequence file %s", s.seqPath)
+	}
+
+	s.sender, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return err
+	}
+
+	s.target, err = strconv.Atoi(parts[1])
+	return err
+}
+
+func (s *FileStore) saveSeqNums() error {
+
+	tmp := s.seqPath + ".tmp"
+	err := ioutil.WriteFile(tmp, []byte(fmt.Sprintf("%d:%d", s.sender, s.target)), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.seqPath)
+}
+
+func (s *FileStore) loadMessages() error {
+
+	file, err := os.Open(s.bodyPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	var offset int64
+	for {
+		header, err := r.ReadString('\n')
+		if err == io.EOF && header == "" {
+			return nil
+		}
+		if err == io.EOF {
+			// a torn write at the end of the file, the message was never sent
+			return os.Truncate(s.bodyPath, offset)
+		}
+		if err != nil {
+			return err
+		}
+
+		var seq, length int
+		_, err = fmt.Sscanf(header, "%d,%d\n", &seq, &length)
+		if err != nil {
+			return fmt.Errorf("invalid message header in %s: %s", s.bodyPath, err)
+		}
+
+		data := make([]byte, length)
+		_, err = io.ReadFull(r, data)
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			return os.Truncate(s.bodyPath, offset)
+		}
+		if err != nil {
+			return err
+		}
+
+		s.messages[seq] = data
+		offset += int64(len(header) + length)
+	}
+}
@@ -0,0 +1,106 @@
+package fix
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStorePersistence(t *testing.T) {
+
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "fixstore")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileStore(dir, "S1")
+	require.Nil(err)
+	require.Equal(1, store.NextSenderSeqNum())
+	require.Equal(1, store.NextTargetSeqNum())
+
+	require.Nil(store.Save(1, []byte("one")))
+	require.Nil(store.Save(2, []byte("two\nlines")))
+	require.Nil(store.SetNextSenderSeqNum(3))
+	require.Nil(store.SetNextTargetSeqNum(5))
+	require.Nil(store.Close())
+
+	store, err = NewFileStore(dir, "S1")
+	require.Nil(err)
+	defer store.Close()
+
+	require.Equal(3, store.NextSenderSeqNum())
+	require.Equal(5, store.NextTargetSeqNum())
+
+	msgs, err := store.Messages(1, 0)
+	require.Nil(err)
+	require.Equal(map[int][]byte{1: []byte("one"), 2: []byte("two\nlines")}, msgs)
+
+	msgs, err = store.Messages(2, 2)
+	require.Nil(err)
+	require.Equal(map[int][]byte{2: []byte("two\nlines")}, msgs)
+}
+
+func TestFileStoreReset(t *testing.T) {
+
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "fixstore")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileStore(dir, "S1")
+	require.Nil(err)
+
+	require.Nil(store.Save(1, []byte("one")))
+	require.Nil(store.SetNextSenderSeqNum(2))
+	require.Nil(store.SetNextTargetSeqNum(2))
+	require.Nil(store.Reset())
+	require.Nil(store.Save(1, []byte("new")))
+	require.Nil(store.Close())
+
+	store, err = NewFileStore(dir, "S1")
+	require.Nil(err)
+	defer store.Close()
+
+	require.Equal(1, store.NextSenderSeqNum())
+	require.Equal(1, store.NextTargetSeqNum())
+
+	msgs, err := store.Messages(1, 0)
+	require.Nil(err)
+	require.Equal(map[int][]byte{1: []byte("new")}, msgs)
+}
+
+func TestFileStoreTornWrite(t *testing.T) {
+
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "fixstore")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "S1.body"), []byte("1,3\none2,10\ntw"), 0644)
+	require.Nil(err)
+
+	store, err := NewFileStore(dir, "S1")
+	require.Nil(err)
+	defer store.Close()
+
+	msgs, err := store.Messages(1, 0)
+	require.Nil(err)
+	require.Equal(map[int][]byte{1: []byte("one")}, msgs)
+
+	require.Nil(store.Save(2, []byte("two")))
+	require.Nil(store.Close())
+
+	store, err = NewFileStore(dir, "S1")
+	require.Nil(err)
+	defer store.Close()
+
+	msgs, err = store.Messages(1, 0)
+	require.Nil(err)
+	require.Equal(map[int][]byte{1: []byte("one"), 2: []byte("two")}, msgs)
+}
@@ -0,0 +1,33 @@
+package gateway
+
+import (
+	"encoding/json"
+	"os"
+
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// SessionConfig maps the comp id of a counterparty to a exchange account
+type SessionConfig struct {
+	CompID  string               `json:"comp_id"`
+	Account *common_http.Account `json:"account"`
+}
+
+// LoadSessionConfigs loads the session configuration from a json file
+func LoadSessionConfigs(path string) ([]*SessionConfig, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var configs []*SessionConfig
+
+	err = json.NewDecoder(file).Decode(&configs)
+	if err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
@@ -0,0 +1,445 @@
+package gateway
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/tradsim/tradsim-go/cmd/fix-gateway/fix"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// Side values
+const (
+	SideBuy  = "1"
+	SideSell = "2"
+)
+
+// ExecType values
+const (
+	ExecNew      = "0"
+	ExecCanceled = "4"
+	ExecReplaced = "5"
+	ExecRejected = "8"
+	ExecTrade    = "F"
+)
+
+// OrdStatus values
+const (
+	StatusNew             = "0"
+	StatusPartiallyFilled = "1"
+	StatusFilled          = "2"
+	StatusCanceled        = "4"
+	StatusRejected        = "8"
+)
+
+// OrdType limit, the only order type of the exchange
+const OrdTypeLimit = "2"
+
+// Reject reason values
+const (
+	OrdRejReasonDuplicateOrder = "6"
+	OrdRejReasonOther          = "99"
+	CxlRejReasonUnknownOrder   = "1"
+	CxlRejReasonPending        = "3"
+	CxlRejReasonOther          = "99"
+	CxlRejResponseToCancel     = "1"
+	CxlRejResponseToReplace    = "2"
+)
+
+// Sender sends messages to a session
+type Sender interface {
+	Send(msg *fix.Message) error
+}
+
+// Gateway translates FIX order messages into exchange orders and order events into execution reports
+type Gateway struct {
+	entry    OrderEntry
+	accounts map[string]*common_http.Account
+	sessions map[string]Sender
+	orders   *OrderTracker
+	mu       sync.Mutex
+}
+
+// NewGateway creates a new gateway, the accounts are keyed by the comp id of the counterparty
+func NewGateway(entry OrderEntry, accounts map[string]*common_http.Account, orders *OrderTracker) *Gateway {
+	return &Gateway{entry, accounts, make(map[string]Sender), orders, sync.Mutex{}}
+}
+
+// OnLogon registers the session for execution reports
+func (g *Gateway) OnLogon(session *fix.Session) {
+	g.Register(session.TargetCompID, session)
+}
+
+// OnLogout keeps the session, reports are stored until the next logon
+func (g *Gateway) OnLogout(session *fix.Session) {
+}
+
+// Register sets the sender of the execution reports of a counterparty
+func (g *Gateway) Register(compID string, sender Sender) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.sessions[compID] = sender
+}
+
+// FromApp handles the application messages of a session
+func (g *Gateway) FromApp(session *fix.Session, msg *fix.Message) {
+	g.Handle(session.TargetCompID, msg)
+}
+
+// Handle handles a application message of a counterparty
+func (g *Gateway) Handle(compID string, msg *fix.Message) {
+
+	switch msg.Type() {
+	case fix.NewOrderSingleType:
+		g.newOrder(compID, msg)
+	case fix.OrderCancelRequestType:
+		g.cancelOrder(compID, msg)
+	case fix.OrderCancelReplaceRequestType:
+		g.replaceOrder(compID, msg)
+	default:
+		log.Printf("%s: unsupported message type %s", compID, msg.Type())
+		seq, _ := msg.Get(fix.TagMsgSeqNum)
+		g.send(compID, fix.NewMessage(fix.RejectType).Set(fix.TagRefSeqNum, seq).Set(fix.TagText, "Unsupported message type"))
+	}
+}
+
+// ProcessEnvelope turns order events of tracked orders into execution reports
+func (g *Gateway) ProcessEnvelope(envelope *events.OrderEventEnvelope) {
+
+	switch envelope.EventType {
+	case events.OrderAcceptedType, events.OrderTradedType, events.OrderCancelledType, events.OrderAmendedType:
+	default:
+		return
+	}
+
+	event, err := envelope.GetOrderEvent()
+	if err != nil {
+		log.Printf("Failed to get event. %s", err)
+		return
+	}
+
+	switch e := event.(type) {
+	case events.OrderAccepted:
+		g.accepted(&e)
+	case events.OrderTraded:
+		g.traded(&e)
+	case events.OrderCancelled:
+		g.cancelled(&e)
+	case events.OrderAmended:
+		g.amended(&e)
+	}
+}
+
+func (g *Gateway) newOrder(compID string, msg *fix.Message) {
+
+	clOrdID, _ := msg.Get(fix.TagClOrdID)
+	symbol, _ := msg.Get(fix.TagSymbol)
+	side, _ := msg.Get(fix.TagSide)
+	ordType, _ := msg.Get(fix.TagOrdType)
+	quantity, qtyErr := msg.GetInt(fix.TagOrderQty)
+	price, pxErr := msg.GetFloat(fix.TagPrice)
+
+	order := &TrackedOrder{OrderID: uuid.NewV4().String(), CompID: compID, ClOrdID: clOrdID, Symbol: symbol, Side: side, Price: price}
+	if qtyErr == nil && quantity > 0 {
+		order.Quantity = uint(quantity)
+	}
+
+	var text string
+	switch {
+	case clOrdID == "":
+		text = "ClOrdID missing"
+	case symbol == "":
+		text = "Symbol missing"
+	case side != SideBuy && side != SideSell:
+		text = "Side must be buy or sell"
+	case ordType != OrdTypeLimit:
+		text = "Only limit orders are supported"
+	case qtyErr != nil || quantity <= 0:
+		text = "OrderQty must be a positive integer"
+	case pxErr != nil || price <= 0:
+		text = "Price must be positive"
+	}
+
+	if text != "" {
+		g.sendReport(order, ExecRejected, StatusRejected, time.Now(), func(er *fix.Message) {
+			er.Set(fix.TagOrdRejReason, OrdRejReasonOther).Set(fix.TagText, text)
+		})
+		return
+	}
+
+	if !g.orders.Add(order) {
+		g.sendReport(order, ExecRejected, StatusRejected, time.Now(), func(er *fix.Message) {
+			er.Set(fix.TagOrdRejReason, OrdRejReasonDuplicateOrder).Set(fix.TagText, "Duplicate ClOrdID")
+		})
+		return
+	}
+
+	err := g.entry.Create(g.accounts[compID], &OrderRequest{order.OrderID, symbol, order.Quantity, direction(side), price})
+	if err != nil {
+		log.Printf("%s: order %s failed. %s", compID, clOrdID, err)
+		g.orders.Remove(order.OrderID)
+		g.sendReport(order, ExecRejected, StatusRejected, time.Now(), func(er *fix.Message) {
+			er.Set(fix.TagOrdRejReason, OrdRejReasonOther).Set(fix.TagText, err.Error())
+		})
+	}
+}
+
+func (g *Gateway) cancelOrder(compID string, msg *fix.Message) {
+
+	clOrdID, _ := msg.Get(fix.TagClOrdID)
+	origClOrdID, _ := msg.Get(fix.TagOrigClOrdID)
+
+	order, text, reason := g.pending(compID, clOrdID, origClOrdID)
+	if order == nil {
+		g.sendCancelReject(compID, clOrdID, origClOrdID, nil, CxlRejResponseToCancel, reason, text)
+		return
+	}
+
+	err := g.entry.Cancel(g.accounts[compID], order.OrderID)
+	if err != nil {
+		log.Printf("%s: cancel %s failed. %s", compID, clOrdID, err)
+		g.clearPending(order)
+		g.sendCancelReject(compID, clOrdID, origClOrdID, order, CxlRejResponseToCancel, CxlRejReasonOther, err.Error())
+	}
+}
+
+func (g *Gateway) replaceOrder(compID string, msg *fix.Message) {
+
+	clOrdID, _ := msg.Get(fix.TagClOrdID)
+	origClOrdID, _ := msg.Get(fix.TagOrigClOrdID)
+	symbol, _ := msg.Get(fix.TagSymbol)
+	side, _ := msg.Get(fix.TagSide)
+	quantity, qtyErr := msg.GetInt(fix.TagOrderQty)
+	price, pxErr := msg.GetFloat(fix.TagPrice)
+
+	order, text, reason := g.pending(compID, clOrdID, origClOrdID)
+	if order == nil {
+		g.sendCancelReject(compID, clOrdID, origClOrdID, nil, CxlRejResponseToReplace, reason, text)
+		return
+	}
+
+	switch {
+	case qtyErr != nil || quantity <= 0:
+		text = "OrderQty must be a positive integer"
+	case symbol != order.Symbol || side != order.Side || (pxErr == nil && price != order.Price):
+		text = "Only the quantity of a order can be replaced"
+	}
+
+	if text != "" {
+		g.clearPending(order)
+		g.sendCancelReject(compID, clOrdID, origClOrdID, order, CxlRejResponseToReplace, CxlRejReasonOther, text)
+		return
+	}
+
+	err := g.entry.Amend(g.accounts[compID], &OrderRequest{order.OrderID, order.Symbol, uint(quantity), direction(order.Side), order.Price})
+	if err != nil {
+		log.Printf("%s: replace %s failed. %s", compID, clOrdID, err)
+		g.clearPending(order)
+		g.sendCancelReject(compID, clOrdID, origClOrdID, order, CxlRejResponseToReplace, CxlRejReasonOther, err.Error())
+	}
+}
+
+// pending looks up the original order and marks the request as pending on it
+func (g *Gateway) pending(compID string, clOrdID string, origClOrdID string) (*TrackedOrder, string, string) {
+
+	if clOrdID == "" {
+		return nil, "ClOrdID missing", CxlRejReasonOther
+	}
+
+	order, ok := g.orders.ByClOrdID(compID, origClOrdID)
+	if !ok {
+		return nil, fmt.Sprintf("Unknown order %s", origClOrdID), CxlRejReasonUnknownOrder
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if order.PendingClOrdID != "" {
+		return nil, "Order has a pending cancel or replace", CxlRejReasonPending
+	}
+
+	if !g.orders.Alias(order, clOrdID) {
+		return nil, "Duplicate ClOrdID", CxlRejReasonOther
+	}
+
+	order.PendingClOrdID = clOrdID
+	g.orders.Update(order)
+	return order, "", ""
+}
+
+func (g *Gateway) clearPending(order *TrackedOrder) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	order.PendingClOrdID = ""
+	g.orders.Update(order)
+}
+
+func (g *Gateway) accepted(e *events.OrderAccepted) {
+
+	order, ok := g.orders.ByOrderID(e.OrderID)
+	if !ok {
+		return
+	}
+	g.sendReport(order, ExecNew, StatusNew, e.Occured, nil)
+}
+
+func (g *Gateway) traded(e *events.OrderTraded) {
+
+	order, ok := g.orders.ByOrderID(e.OrderID)
+	if !ok {
+		return
+	}
+
+	g.mu.Lock()
+	order.Fill(e.Price, e.Quantity)
+	status := StatusPartiallyFilled
+	if order.LeavesQty() == 0 {
+		status = StatusFilled
+		g.orders.Remove(order.OrderID)
+	} else {
+		g.orders.Update(order)
+	}
+	g.mu.Unlock()
+
+	g.sendReport(order, ExecTrade, status, e.Occured, func(er *fix.Message) {
+		er.SetFloat(fix.TagLastPx, e.Price).SetInt(fix.TagLastQty, int(e.Quantity))
+	})
+}
+
+func (g *Gateway) cancelled(e *events.OrderCancelled) {
+
+	order, ok := g.orders.ByOrderID(e.OrderID)
+	if !ok {
+		return
+	}
+
+	g.mu.Lock()
+	order.Cancelled = true
+	origClOrdID := ""
+	if order.PendingClOrdID != "" {
+		origClOrdID = order.ClOrdID
+		order.ClOrdID = order.PendingClOrdID
+		order.PendingClOrdID = ""
+	}
+	g.orders.Remove(order.OrderID)
+	g.mu.Unlock()
+
+	g.sendReport(order, ExecCanceled, StatusCanceled, e.Occured, func(er *fix.Message) {
+		if origClOrdID != "" {
+			er.Set(fix.TagOrigClOrdID, origClOrdID)
+		}
+	})
+}
+
+func (g *Gateway) amended(e *events.OrderAmended) {
+
+	order, ok := g.orders.ByOrderID(e.OrderID)
+	if !ok {
+		return
+	}
+
+	g.mu.Lock()
+	order.Quantity = e.Quantity
+	origClOrdID := order.ClOrdID
+	if order.PendingClOrdID != "" {
+		order.ClOrdID = order.PendingClOrdID
+		order.PendingClOrdID = ""
+	}
+	status := StatusNew
+	if order.CumQty > 0 {
+		status = StatusPartiallyFilled
+	}
+	g.orders.Update(order)
+	g.mu.Unlock()
+
+	g.sendReport(order, ExecReplaced, status, e.Occured, func(er *fix.Message) {
+		er.Set(fix.TagOrigClOrdID, origClOrdID)
+	})
+}
+
+// sendReport sends a execution report with the current state of the order
+func (g *Gateway) sendReport(order *TrackedOrder, execType string, status string, occured time.Time, extra func(er *fix.Message)) {
+
+	g.mu.Lock()
+	er := fix.NewMessage(fix.ExecutionReportType)
+	er.Set(fix.TagOrderID, order.OrderID)
+	er.Set(fix.TagClOrdID, order.ClOrdID)
+	er.Set(fix.TagExecID, uuid.NewV4().String())
+	er.Set(fix.TagExecType, execType)
+	er.Set(fix.TagOrdStatus, status)
+	er.Set(fix.TagSymbol, order.Symbol)
+	er.Set(fix.TagSide, order.Side)
+	er.Set(fix.TagOrdType, OrdTypeLimit)
+	er.SetInt(fix.TagOrderQty, int(order.Quantity))
+	er.SetFloat(fix.TagPrice, order.Price)
+	if status == StatusRejected {
+		er.SetInt(fix.TagLeavesQty, 0)
+	} else {
+		er.SetInt(fix.TagLeavesQty, int(order.LeavesQty()))
+	}
+	er.SetInt(fix.TagCumQty, int(order.CumQty))
+	er.SetFloat(fix.TagAvgPx, order.AvgPx())
+	er.SetTime(fix.TagTransactTime, occured)
+	g.mu.Unlock()
+
+	if extra != nil {
+		extra(er)
+	}
+
+	g.send(order.CompID, er)
+}
+
+// sendCancelReject rejects a cancel or replace request, order is nil for unknown orders
+func (g *Gateway) sendCancelReject(compID string, clOrdID string, origClOrdID string, order *TrackedOrder, responseTo string, reason string, text string) {
+
+	orderID := "NONE"
+	status := StatusRejected
+	if order != nil {
+		g.mu.Lock()
+		orderID = order.OrderID
+		status = StatusNew
+		if order.CumQty > 0 {
+			status = StatusPartiallyFilled
+		}
+		g.mu.Unlock()
+	}
+
+	msg := fix.NewMessage(fix.OrderCancelRejectType)
+	msg.Set(fix.TagOrderID, orderID)
+	msg.Set(fix.TagClOrdID, clOrdID)
+	msg.Set(fix.TagOrigClOrdID, origClOrdID)
+	msg.Set(fix.TagOrdStatus, status)
+	msg.Set(fix.TagCxlRejResponseTo, responseTo)
+	msg.Set(fix.TagCxlRejReason, reason)
+	msg.Set(fix.TagText, text)
+	g.send(compID, msg)
+}
+
+func (g *Gateway) send(compID string, msg *fix.Message) {
+
+	g.mu.Lock()
+	sender, ok := g.sessions[compID]
+	g.mu.Unlock()
+
+	if !ok {
+		log.Printf("No session for %s, dropping %s", compID, msg.Type())
+		return
+	}
+
+	err := sender.Send(msg)
+	if err != nil {
+		log.Printf("%s: failed to send %s. %s", compID, msg.Type(), err)
+	}
+}
+
+func direction(side string) string {
+	if side == SideSell {
+		return models.Sell.String()
+	}
+	return models.Buy.String()
+}
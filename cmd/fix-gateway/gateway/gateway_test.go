@@ -0,0 +1,324 @@
+package gateway
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/fix-gateway/fix"
+	"github.com/tradsim/tradsim-go/events"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+type mockOrderEntry struct {
+	created   []*OrderRequest
+	amended   []*OrderRequest
+	cancelled []string
+	err       error
+}
+
+func (m *mockOrderEntry) Create(account *common_http.Account, order *OrderRequest) error {
+	m.created = append(m.created, order)
+	return m.err
+}
+
+func (m *mockOrderEntry) Amend(account *common_http.Account, order *OrderRequest) error {
+	m.amended = append(m.amended, order)
+	return m.err
+}
+
+func (m *mockOrderEntry) Cancel(account *common_http.Account, orderID string) error {
+	m.cancelled = append(m.cancelled, orderID)
+	return m.err
+}
+
+type mockSender struct {
+	messages []*fix.Message
+}
+
+func (m *mockSender) Send(msg *fix.Message) error {
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *mockSender) last() *fix.Message {
+	return m.messages[len(m.messages)-1]
+}
+
+func getGateway() (*Gateway, *mockOrderEntry, *mockSender) {
+	entry := &mockOrderEntry{}
+	sender := &mockSender{}
+	gw := NewGateway(entry, map[string]*common_http.Account{"CLIENT": {Name: "ACC1", APIKey: "KEY1", Role: common_http.TraderRole}}, NewOrderTracker())
+	gw.Register("CLIENT", sender)
+	return gw, entry, sender
+}
+
+func newOrderSingle(clOrdID string) *fix.Message {
+	return fix.NewMessage(fix.NewOrderSingleType).
+		Set(fix.TagClOrdID, clOrdID).
+		Set(fix.TagSymbol, "TT").
+		Set(fix.TagSide, SideBuy).
+		Set(fix.TagOrdType, OrdTypeLimit).
+		SetInt(fix.TagOrderQty, 10).
+		SetFloat(fix.TagPrice, 2.5)
+}
+
+func envelope(event interface{}, eventType events.OrderEventType) *events.OrderEventEnvelope {
+	env, _ := events.NewOrderEventEnvelope(event, eventType)
+	return env
+}
+
+func value(msg *fix.Message, tag fix.Tag) string {
+	v, _ := msg.Get(tag)
+	return v
+}
+
+func TestGatewayNewOrder(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+
+	gw.Handle("CLIENT", newOrderSingle("C1"))
+
+	require.Len(entry.created, 1)
+	order := entry.created[0]
+	require.Equal("TT", order.Symbol)
+	require.Equal(uint(10), order.Quantity)
+	require.Equal(models.BuyText, order.Direction)
+	require.Equal(2.5, order.Price)
+	require.Len(sender.messages, 0)
+
+	accepted := events.NewOrderAccepted(order.ID, "ACC1", time.Now().UTC(), "TT", 2.5, 10, models.Buy, 1)
+	gw.ProcessEnvelope(envelope(accepted, accepted.EventType))
+
+	require.Len(sender.messages, 1)
+	er := sender.last()
+	require.Equal(fix.ExecutionReportType, er.Type())
+	require.Equal(order.ID, value(er, fix.TagOrderID))
+	require.Equal("C1", value(er, fix.TagClOrdID))
+	require.Equal(ExecNew, value(er, fix.TagExecType))
+	require.Equal(StatusNew, value(er, fix.TagOrdStatus))
+	require.Equal("10", value(er, fix.TagLeavesQty))
+	require.Equal("0", value(er, fix.TagCumQty))
+}
+
+func TestGatewayNewOrderInvalid(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+
+	tests := []*fix.Message{
+		newOrderSingle(""),
+		newOrderSingle("C1").Set(fix.TagSide, "5"),
+		newOrderSingle("C1").Set(fix.TagOrdType, "1"),
+		newOrderSingle("C1").SetInt(fix.TagOrderQty, 0),
+		newOrderSingle("C1").Set(fix.TagPrice, "x"),
+	}
+
+	for _, test := range tests {
+		gw.Handle("CLIENT", test)
+		er := sender.last()
+		require.Equal(ExecRejected, value(er, fix.TagExecType))
+		require.Equal(StatusRejected, value(er, fix.TagOrdStatus))
+	}
+
+	require.Len(entry.created, 0)
+	require.Len(sender.messages, len(tests))
+}
+
+func TestGatewayNewOrderDuplicateClOrdID(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+
+	gw.Handle("CLIENT", newOrderSingle("C1"))
+	gw.Handle("CLIENT", newOrderSingle("C1"))
+
+	require.Len(entry.created, 1)
+	require.Len(sender.messages, 1)
+	require.Equal(OrdRejReasonDuplicateOrder, value(sender.last(), fix.TagOrdRejReason))
+}
+
+func TestGatewayNewOrderExchangeFailure(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+	entry.err = errors.New("exchange responded 401 Unauthorized")
+
+	gw.Handle("CLIENT", newOrderSingle("C1"))
+
+	require.Len(sender.messages, 1)
+	require.Equal(ExecRejected, value(sender.last(), fix.TagExecType))
+	require.Equal("exchange responded 401 Unauthorized", value(sender.last(), fix.TagText))
+}
+
+func TestGatewayTrades(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+
+	gw.Handle("CLIENT", newOrderSingle("C1"))
+	id := entry.created[0].ID
+
+	traded := events.NewOrderTraded(id, "ACC1", time.Now().UTC(), "T1", "TT", 2.0, 4, 1)
+	gw.ProcessEnvelope(envelope(traded, traded.EventType))
+
+	er := sender.last()
+	require.Equal(ExecTrade, value(er, fix.TagExecType))
+	require.Equal(StatusPartiallyFilled, value(er, fix.TagOrdStatus))
+	require.Equal("4", value(er, fix.TagLastQty))
+	require.Equal("2", value(er, fix.TagLastPx))
+	require.Equal("4", value(er, fix.TagCumQty))
+	require.Equal("6", value(er, fix.TagLeavesQty))
+
+	traded = events.NewOrderTraded(id, "ACC1", time.Now().UTC(), "T2", "TT", 3.0, 6, 1)
+	gw.ProcessEnvelope(envelope(traded, traded.EventType))
+
+	er = sender.last()
+	require.Equal(StatusFilled, value(er, fix.TagOrdStatus))
+	require.Equal("10", value(er, fix.TagCumQty))
+	require.Equal("0", value(er, fix.TagLeavesQty))
+	require.Equal("2.6", value(er, fix.TagAvgPx))
+
+	_, ok := gw.orders.ByOrderID(id)
+	require.False(ok)
+}
+
+func TestGatewayIgnoresUntrackedOrders(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, _, sender := getGateway()
+
+	traded := events.NewOrderTraded("other", "ACC2", time.Now().UTC(), "T1", "TT", 2.0, 4, 1)
+	gw.ProcessEnvelope(envelope(traded, traded.EventType))
+
+	require.Len(sender.messages, 0)
+}
+
+func TestGatewayCancel(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+
+	gw.Handle("CLIENT", newOrderSingle("C1"))
+	id := entry.created[0].ID
+
+	gw.Handle("CLIENT", fix.NewMessage(fix.OrderCancelRequestType).Set(fix.TagClOrdID, "C2").Set(fix.TagOrigClOrdID, "C1"))
+	require.Equal([]string{id}, entry.cancelled)
+
+	cancelled := events.NewOrderCancelled(id, "ACC1", time.Now().UTC(), 1)
+	gw.ProcessEnvelope(envelope(cancelled, cancelled.EventType))
+
+	er := sender.last()
+	require.Equal(ExecCanceled, value(er, fix.TagExecType))
+	require.Equal(StatusCanceled, value(er, fix.TagOrdStatus))
+	require.Equal("C2", value(er, fix.TagClOrdID))
+	require.Equal("C1", value(er, fix.TagOrigClOrdID))
+	require.Equal("0", value(er, fix.TagLeavesQty))
+}
+
+func TestGatewayCancelUnknownOrder(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+
+	gw.Handle("CLIENT", fix.NewMessage(fix.OrderCancelRequestType).Set(fix.TagClOrdID, "C2").Set(fix.TagOrigClOrdID, "C1"))
+
+	require.Len(entry.cancelled, 0)
+	msg := sender.last()
+	require.Equal(fix.OrderCancelRejectType, msg.Type())
+	require.Equal(CxlRejReasonUnknownOrder, value(msg, fix.TagCxlRejReason))
+	require.Equal(CxlRejResponseToCancel, value(msg, fix.TagCxlRejResponseTo))
+}
+
+func TestGatewayCancelExchangeFailure(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+
+	gw.Handle("CLIENT", newOrderSingle("C1"))
+	entry.err = errors.New("exchange responded 404 Not Found")
+
+	gw.Handle("CLIENT", fix.NewMessage(fix.OrderCancelRequestType).Set(fix.TagClOrdID, "C2").Set(fix.TagOrigClOrdID, "C1"))
+
+	msg := sender.last()
+	require.Equal(fix.OrderCancelRejectType, msg.Type())
+	require.Equal(StatusNew, value(msg, fix.TagOrdStatus))
+
+	order, ok := gw.orders.ByClOrdID("CLIENT", "C1")
+	require.True(ok)
+	require.Equal("", order.PendingClOrdID)
+}
+
+func TestGatewayReplace(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+
+	gw.Handle("CLIENT", newOrderSingle("C1"))
+	id := entry.created[0].ID
+
+	replace := newOrderSingle("C2").SetInt(fix.TagOrderQty, 15).Set(fix.TagOrigClOrdID, "C1")
+	replace.Fields[0].Value = string(fix.OrderCancelReplaceRequestType)
+	gw.Handle("CLIENT", replace)
+
+	require.Len(entry.amended, 1)
+	require.Equal(id, entry.amended[0].ID)
+	require.Equal(uint(15), entry.amended[0].Quantity)
+
+	amended := events.NewOrderAmended(id, "ACC1", 15, time.Now().UTC(), 1)
+	gw.ProcessEnvelope(envelope(amended, amended.EventType))
+
+	er := sender.last()
+	require.Equal(ExecReplaced, value(er, fix.TagExecType))
+	require.Equal("C2", value(er, fix.TagClOrdID))
+	require.Equal("C1", value(er, fix.TagOrigClOrdID))
+	require.Equal("15", value(er, fix.TagOrderQty))
+
+	order, ok := gw.orders.ByClOrdID("CLIENT", "C2")
+	require.True(ok)
+	require.Equal(id, order.OrderID)
+}
+
+func TestGatewayReplacePriceRejected(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, entry, sender := getGateway()
+
+	gw.Handle("CLIENT", newOrderSingle("C1"))
+
+	replace := newOrderSingle("C2").SetFloat(fix.TagPrice, 3.0).Set(fix.TagOrigClOrdID, "C1")
+	replace.Fields[0].Value = string(fix.OrderCancelReplaceRequestType)
+	gw.Handle("CLIENT", replace)
+
+	require.Len(entry.amended, 0)
+	msg := sender.last()
+	require.Equal(fix.OrderCancelRejectType, msg.Type())
+	require.Equal(CxlRejResponseToReplace, value(msg, fix.TagCxlRejResponseTo))
+}
+
+func TestGatewayUnsupportedMessage(t *testing.T) {
+
+	require := require.New(t)
+
+	gw, _, sender := getGateway()
+
+	gw.Handle("CLIENT", fix.NewMessage("AE").SetInt(fix.TagMsgSeqNum, 7))
+
+	msg := sender.last()
+	require.Equal(fix.RejectType, msg.Type())
+	require.Equal("7", value(msg, fix.TagRefSeqNum))
+}
@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// OrderRequest defines the order payload of the exchange
+type OrderRequest struct {
+	ID        string  `json:"id"`
+	Symbol    string  `json:"symbol"`
+	Quantity  uint    `json:"quantity"`
+	Direction string  `json:"direction"`
+	Price     float64 `json:"price"`
+}
+
+// OrderEntry sends order requests to the exchange
+type OrderEntry interface {
+	Create(account *common_http.Account, order *OrderRequest) error
+	Amend(account *common_http.Account, order *OrderRequest) error
+	Cancel(account *common_http.Account, orderID string) error
+}
+
+// RestOrderEntry sends signed order requests to the exchange service api
+type RestOrderEntry struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewRestOrderEntry creates a new rest order entry
+func NewRestOrderEntry(baseURL string, timeout time.Duration) *RestOrderEntry {
+	return &RestOrderEntry{baseURL, &http.Client{Timeout: timeout}}
+}
+
+// Create submits a new order
+func (e *RestOrderEntry) Create(account *common_http.Account, order *OrderRequest) error {
+	return e.send(account, http.MethodPost, "/orders", order)
+}
+
+// Amend amends the quantity of a order
+func (e *RestOrderEntry) Amend(account *common_http.Account, order *OrderRequest) error {
+	return e.send(account, http.MethodPut, "/orders", order)
+}
+
+// Cancel cancels a order
+func (e *RestOrderEntry) Cancel(account *common_http.Account, orderID string) error {
+	return e.send(account, http.MethodDelete, "/orders/"+orderID, nil)
+}
+
+func (e *RestOrderEntry) send(account *common_http.Account, method string, path string, payload interface{}) error {
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequest(method, e.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	err = common_http.SignRequest(req, account)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("exchange responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func getExchangeServer(account *common_http.Account, requests *[]string, payloads *[]OrderRequest) *httptest.Server {
+
+	store := common_http.NewInMemoryAccountStore(account)
+
+	handle := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		*requests = append(*requests, r.Method+" "+r.URL.Path)
+		if r.Method != http.MethodDelete {
+			var payload OrderRequest
+			json.NewDecoder(r.Body).Decode(&payload)
+			*payloads = append(*payloads, payload)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+
+	router := httprouter.New()
+	router.POST("/orders", common_http.APIKeyAuthMiddleware(store, handle))
+	router.PUT("/orders", common_http.APIKeyAuthMiddleware(store, handle))
+	router.DELETE("/orders/:orderid", common_http.APIKeyAuthMiddleware(store, handle))
+	return httptest.NewServer(router)
+}
+
+func TestRestOrderEntry(t *testing.T) {
+
+	require := require.New(t)
+
+	account := &common_http.Account{Name: "ACC1", APIKey: "KEY1", Secret: "SECRET", Role: common_http.TraderRole}
+
+	var requests []string
+	var payloads []OrderRequest
+	server := getExchangeServer(account, &requests, &payloads)
+	defer server.Close()
+
+	entry := NewRestOrderEntry(server.URL, time.Second)
+
+	order := &OrderRequest{"ID1", "TT", 10, "Buy", 2.5}
+	require.Nil(entry.Create(account, order))
+	require.Nil(entry.Amend(account, order))
+	require.Nil(entry.Cancel(account, "ID1"))
+
+	require.Equal([]string{"POST /orders", "PUT /orders", "DELETE /orders/ID1"}, requests)
+	require.Equal([]OrderRequest{*order, *order}, payloads)
+}
+
+func TestRestOrderEntryError(t *testing.T) {
+
+	require := require.New(t)
+
+	account := &common_http.Account{Name: "ACC1", APIKey: "KEY1", Secret: "SECRET", Role: common_http.TraderRole}
+
+	var requests []string
+	var payloads []OrderRequest
+	server := getExchangeServer(account, &requests, &payloads)
+	defer server.Close()
+
+	entry := NewRestOrderEntry(server.URL, time.Second)
+
+	err := entry.Create(&common_http.Account{Name: "ACC1", APIKey: "KEY1", Secret: "WRONG", Role: common_http.TraderRole}, &OrderRequest{"ID1", "TT", 10, "Buy", 2.5})
+	require.NotNil(err)
+	require.Len(requests, 0)
+}
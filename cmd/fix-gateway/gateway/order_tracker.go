@@ -0,0 +1,297 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TrackedOrder holds the FIX view of a exchange order
+type TrackedOrder struct {
+	OrderID        string  `json:"order_id"`
+	CompID         string  `json:"comp_id"`
+	ClOrdID        string  `json:"cl_ord_id"`
+	PendingClOrdID string  `json:"pending_cl_ord_id"`
+	Symbol         string  `json:"symbol"`
+	Side           string  `json:"side"`
+	Price          float64 `json:"price"`
+	Quantity       uint    `json:"quantity"`
+	CumQty         uint    `json:"cum_qty"`
+	notional       float64
+	Cancelled      bool `json:"cancelled"`
+}
+
+// LeavesQty returns the open quantity
+func (o *TrackedOrder) LeavesQty() uint {
+	if o.Cancelled || o.CumQty >= o.Quantity {
+		return 0
+	}
+	return o.Quantity - o.CumQty
+}
+
+// AvgPx returns the average traded price
+func (o *TrackedOrder) AvgPx() float64 {
+	if o.CumQty == 0 {
+		return 0
+	}
+	return o.notional / float64(o.CumQty)
+}
+
+// Fill adds a trade to the order
+func (o *TrackedOrder) Fill(price float64, quantity uint) {
+	o.CumQty += quantity
+	o.notional += price * float64(quantity)
+}
+
+// OrderTracker maps the client order ids of the sessions to exchange orders.
+// A tracker with a journal appends every change of a order to it, so the orders survive a restart.
+type OrderTracker struct {
+	orders   map[string]*TrackedOrder
+	clOrdIDs map[string]string
+	aliases  map[string][]string
+	journal  *os.File
+	mu       sync.Mutex
+}
+
+// trackedRecord is a journal line with the state of a order, the client order ids of removed orders stay used
+type trackedRecord struct {
+	Order    TrackedOrder `json:"order"`
+	Notional float64      `json:"notional"`
+	ClOrdIDs []string     `json:"cl_ord_ids"`
+	Removed  bool         `json:"removed"`
+}
+
+// NewOrderTracker creates a new order tracker which keeps the orders in memory only
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{make(map[string]*TrackedOrder), make(map[string]string), make(map[string][]string), nil, sync.Mutex{}}
+}
+
+// NewFileOrderTracker opens or creates the order journal in the directory of the message stores.
+// The journal is replayed and compacted, so reports and cancel or replace requests of orders sent before a restart still work.
+func NewFileOrderTracker(dir string) (*OrderTracker, error) {
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewOrderTracker()
+	path := filepath.Join(dir, "orders.journal")
+
+	removed, err := t.load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.compact(path, removed)
+	if err != nil {
+		return nil, err
+	}
+
+	t.journal, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Add tracks a order, it returns false when the client order id is already used by the session
+func (t *OrderTracker) Add(order *TrackedOrder) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := clOrdKey(order.CompID, order.ClOrdID)
+	if _, ok := t.clOrdIDs[key]; ok {
+		return false
+	}
+
+	t.clOrdIDs[key] = order.OrderID
+	t.aliases[order.OrderID] = []string{order.ClOrdID}
+	t.orders[order.OrderID] = order
+	t.write(order, false)
+	return true
+}
+
+// Alias maps a additional client order id of a cancel or replace request to the order
+func (t *OrderTracker) Alias(order *TrackedOrder, clOrdID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := clOrdKey(order.CompID, clOrdID)
+	if _, ok := t.clOrdIDs[key]; ok {
+		return false
+	}
+
+	t.clOrdIDs[key] = order.OrderID
+	t.aliases[order.OrderID] = append(t.aliases[order.OrderID], clOrdID)
+	t.write(order, false)
+	return true
+}
+
+// Update journals the changed state of a tracked order, the caller holds the lock guarding the order
+func (t *OrderTracker) Update(order *TrackedOrder) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.orders[order.OrderID]; ok {
+		t.write(order, false)
+	}
+}
+
+// Remove stops tracking a order
+func (t *OrderTracker) Remove(orderID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[orderID]
+	if !ok {
+		return
+	}
+
+	delete(t.orders, orderID)
+	t.write(order, true)
+}
+
+// ByOrderID returns the order with the exchange order id
+func (t *OrderTracker) ByOrderID(orderID string) (*TrackedOrder, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[orderID]
+	return order, ok
+}
+
+// ByClOrdID returns the order of the session with the client order id
+func (t *OrderTracker) ByClOrdID(compID string, clOrdID string) (*TrackedOrder, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	orderID, ok := t.clOrdIDs[clOrdKey(compID, clOrdID)]
+	if !ok {
+		return nil, false
+	}
+
+	order, ok := t.orders[orderID]
+	return order, ok
+}
+
+// Close closes the journal
+func (t *OrderTracker) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.journal == nil {
+		return nil
+	}
+	return t.journal.Close()
+}
+
+// write appends the state of the order to the journal, a failed write is logged since the order is already handled
+func (t *OrderTracker) write(order *TrackedOrder, removed bool) {
+
+	if t.journal == nil {
+		return
+	}
+
+	data, err := json.Marshal(t.record(order, removed))
+	if err == nil {
+		_, err = t.journal.Write(append(data, '\n'))
+	}
+	if err != nil {
+		log.Printf("Failed to journal order %s! %s", order.OrderID, err)
+	}
+}
+
+func (t *OrderTracker) record(order *TrackedOrder, removed bool) trackedRecord {
+	return trackedRecord{*order, order.notional, t.aliases[order.OrderID], removed}
+}
+
+// load replays the journal and returns the removed orders, whose client order ids stay used
+func (t *OrderTracker) load(path string) (map[string]*TrackedOrder, error) {
+
+	removed := make(map[string]*TrackedOrder)
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return removed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// a empty or torn line at the end of the file, the compaction drops it
+			return removed, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var record trackedRecord
+		err = json.Unmarshal(line, &record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid order record in %s: %s", path, err)
+		}
+
+		order := record.Order
+		order.notional = record.Notional
+
+		t.aliases[order.OrderID] = record.ClOrdIDs
+		for _, clOrdID := range record.ClOrdIDs {
+			t.clOrdIDs[clOrdKey(order.CompID, clOrdID)] = order.OrderID
+		}
+
+		if record.Removed {
+			delete(t.orders, order.OrderID)
+			removed[order.OrderID] = &order
+		} else {
+			delete(removed, order.OrderID)
+			t.orders[order.OrderID] = &order
+		}
+	}
+}
+
+// compact rewrites the journal with a line per order
+func (t *OrderTracker) compact(path string, removed map[string]*TrackedOrder) error {
+
+	records := make([]trackedRecord, 0, len(t.orders)+len(removed))
+	for _, order := range t.orders {
+		records = append(records, t.record(order, false))
+	}
+	for _, order := range removed {
+		records = append(records, t.record(order, true))
+	}
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	tmp := path + ".tmp"
+	err := ioutil.WriteFile(tmp, buf.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func clOrdKey(compID string, clOrdID string) string {
+	return compID + "\x00" + clOrdID
+}
@@ -0,0 +1,113 @@
+package gateway
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderTracker(t *testing.T) {
+
+	require := require.New(t)
+
+	tracker := NewOrderTracker()
+	order := &TrackedOrder{OrderID: "O1", CompID: "CLIENT", ClOrdID: "C1", Quantity: 10}
+
+	require.True(tracker.Add(order))
+	require.False(tracker.Add(&TrackedOrder{OrderID: "O2", CompID: "CLIENT", ClOrdID: "C1"}))
+	require.True(tracker.Add(&TrackedOrder{OrderID: "O3", CompID: "OTHER", ClOrdID: "C1"}))
+
+	found, ok := tracker.ByClOrdID("CLIENT", "C1")
+	require.True(ok)
+	require.Equal(order, found)
+
+	require.True(tracker.Alias(order, "C2"))
+	require.False(tracker.Alias(order, "C1"))
+
+	found, ok = tracker.ByClOrdID("CLIENT", "C2")
+	require.True(ok)
+	require.Equal(order, found)
+
+	tracker.Remove("O1")
+
+	_, ok = tracker.ByOrderID("O1")
+	require.False(ok)
+	_, ok = tracker.ByClOrdID("CLIENT", "C1")
+	require.False(ok)
+	require.False(tracker.Add(&TrackedOrder{OrderID: "O4", CompID: "CLIENT", ClOrdID: "C1"}))
+}
+
+func TestFileOrderTrackerPersistence(t *testing.T) {
+
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "fixorders")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	tracker, err := NewFileOrderTracker(dir)
+	require.Nil(err)
+
+	order := &TrackedOrder{OrderID: "O1", CompID: "CLIENT", ClOrdID: "C1", Symbol: "TT", Side: SideBuy, Price: 2.0, Quantity: 10}
+	require.True(tracker.Add(order))
+	require.True(tracker.Alias(order, "C2"))
+	order.PendingClOrdID = "C2"
+	order.Fill(2.0, 4)
+	tracker.Update(order)
+
+	require.True(tracker.Add(&TrackedOrder{OrderID: "O2", CompID: "CLIENT", ClOrdID: "C3", Quantity: 5}))
+	tracker.Remove("O2")
+	require.Nil(tracker.Close())
+
+	tracker, err = NewFileOrderTracker(dir)
+	require.Nil(err)
+
+	found, ok := tracker.ByClOrdID("CLIENT", "C2")
+	require.True(ok)
+	require.Equal(order, found)
+	require.Equal(2.0, found.AvgPx())
+
+	_, ok = tracker.ByOrderID("O2")
+	require.False(ok)
+	require.False(tracker.Add(&TrackedOrder{OrderID: "O3", CompID: "CLIENT", ClOrdID: "C3"}))
+	require.Nil(tracker.Close())
+
+	// the journal is compacted to a line per order and a torn last line is dropped
+	path := filepath.Join(dir, "orders.journal")
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.Nil(err)
+	file.WriteString(`{"order":{"order_id":"O4"`)
+	file.Close()
+
+	tracker, err = NewFileOrderTracker(dir)
+	require.Nil(err)
+	defer tracker.Close()
+
+	_, ok = tracker.ByOrderID("O1")
+	require.True(ok)
+	data, err := ioutil.ReadFile(path)
+	require.Nil(err)
+	require.Len(bytes.Split(bytes.TrimSpace(data), []byte("\n")), 2)
+}
+
+func TestTrackedOrderFill(t *testing.T) {
+
+	require := require.New(t)
+
+	order := &TrackedOrder{Quantity: 10}
+	require.Equal(0.0, order.AvgPx())
+
+	order.Fill(2.0, 4)
+	order.Fill(3.0, 6)
+
+	require.Equal(uint(10), order.CumQty)
+	require.Equal(uint(0), order.LeavesQty())
+	require.Equal(2.6, order.AvgPx())
+
+	order = &TrackedOrder{Quantity: 10, CumQty: 4, Cancelled: true}
+	require.Equal(uint(0), order.LeavesQty())
+}
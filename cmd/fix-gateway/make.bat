@@ -0,0 +1,3 @@
+set GOARCH=amd64
+set GOOS=linux
+go build
\ No newline at end of file
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/client"
+	"github.com/tradsim/tradsim-go/cmd/itch-book/reconstruct"
+	"github.com/tradsim/tradsim-go/itch"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func main() {
+
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.Ldate | log.Lmicroseconds | log.LUTC | log.Lshortfile)
+	log.SetPrefix("ib ")
+
+	path := flag.String("file", "", "ITCH 5.0 file, gzip compressed when it ends with .gz")
+	symbol := flag.String("symbol", "", "stock of the book")
+	at := flag.String("at", "", "time HH:MM:SS[.nnn] of the book, the end of the file when empty")
+	exchangeURL := flag.String("compare", "", "exchange service url whose book of the symbol is compared to the reconstructed book")
+	apiKey := flag.String("api-key", os.Getenv("TRADSIM_API_KEY"), "api key of the account")
+	secret := flag.String("secret", os.Getenv("TRADSIM_SECRET"), "secret of the account, requests are signed when set")
+	flag.Parse()
+
+	if *path == "" || *symbol == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	stock := strings.ToUpper(*symbol)
+
+	until := 24 * time.Hour
+	if *at != "" {
+		parsed, err := itch.ParseTime(*at)
+		if err != nil {
+			log.Fatal(err)
+		}
+		until = parsed
+	}
+
+	file, err := itch.OpenFile(*path)
+	if err != nil {
+		log.Fatalf("Failed to open %s! %s", *path, err)
+	}
+	defer file.Close()
+
+	book, failed, err := reconstruct.Reconstruct(file, until)
+	if err != nil {
+		log.Fatalf("Failed to read %s! %s", *path, err)
+	}
+	if failed > 0 {
+		log.Printf("Skipped %d messages of unknown orders.", failed)
+	}
+
+	response := reconstruct.GetSymbolResponse(book, stock)
+
+	encoded, _ := json.MarshalIndent(response, "", "  ")
+	fmt.Println(string(encoded))
+
+	if *exchangeURL == "" {
+		return
+	}
+
+	account := &common_http.Account{APIKey: *apiKey, Secret: *secret}
+	exchange := client.NewClient(*exchangeURL, account, common_http.NewRestClientImpl(5*time.Second), client.DefaultRetryPolicy)
+
+	actual, err := exchange.GetBook(context.Background(), stock)
+	if err != nil {
+		log.Fatalf("Failed to get book of %s! %s", stock, err)
+	}
+
+	differences := reconstruct.Compare(response, *actual)
+	if len(differences) == 0 {
+		fmt.Println("books match")
+		return
+	}
+	for _, difference := range differences {
+		fmt.Println(difference)
+	}
+	os.Exit(1)
+}
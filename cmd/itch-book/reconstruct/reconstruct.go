@@ -0,0 +1,110 @@
+package reconstruct
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/itch"
+)
+
+// Source returns the messages of a ITCH feed, it is implemented by *itch.Reader
+type Source interface {
+	Next() (itch.Message, error)
+}
+
+// Reconstruct applies the messages until the time and returns the book at the time.
+// Messages of unknown orders are counted and skipped, as the feed can start after the orders were added.
+func Reconstruct(source Source, at time.Duration) (*itch.Book, int, error) {
+
+	book := itch.NewBook()
+	failed := 0
+
+	for {
+		message, err := source.Next()
+		if err == io.EOF {
+			return book, failed, nil
+		}
+		if err != nil {
+			return book, failed, err
+		}
+		if message.GetHeader().Timestamp > at {
+			return book, failed, nil
+		}
+
+		if book.Apply(message) != nil {
+			failed++
+		}
+	}
+}
+
+// GetSymbolResponse returns the levels of the stock in the book as the exchange returns its book, ascending by price
+func GetSymbolResponse(book *itch.Book, stock string) handlers.SymbolResponse {
+
+	response := handlers.SymbolResponse{Symbol: stock, Prices: make([]handlers.SymbolPriceResponse, 0)}
+	prices := make(map[uint32]*handlers.SymbolPriceResponse)
+
+	price := func(p uint32) *handlers.SymbolPriceResponse {
+		if _, ok := prices[p]; !ok {
+			prices[p] = &handlers.SymbolPriceResponse{Price: itch.Price(p)}
+		}
+		return prices[p]
+	}
+
+	bids, asks := book.Levels(stock)
+	for _, level := range bids {
+		p := price(level.Price)
+		p.BuyQuantity = uint(level.Shares)
+		p.BuyDepth = uint(level.Orders)
+	}
+	for _, level := range asks {
+		p := price(level.Price)
+		p.SellQuantity = uint(level.Shares)
+		p.SellDepth = uint(level.Orders)
+	}
+
+	for _, p := range prices {
+		response.Prices = append(response.Prices, *p)
+	}
+	sort.Slice(response.Prices, func(i, j int) bool { return response.Prices[i].Price < response.Prices[j].Price })
+
+	return response
+}
+
+// Compare returns the differences of the quantities and depths of the exchange book to the reconstructed book by price
+func Compare(reconstructed handlers.SymbolResponse, exchange handlers.SymbolResponse) []string {
+
+	prices := make(map[uint32]*[2]handlers.SymbolPriceResponse)
+	for i, response := range []handlers.SymbolResponse{reconstructed, exchange} {
+		for _, price := range response.Prices {
+			key := itch.ToPrice(price.Price)
+			if _, ok := prices[key]; !ok {
+				prices[key] = &[2]handlers.SymbolPriceResponse{}
+			}
+			prices[key][i] = price
+		}
+	}
+
+	keys := make([]uint32, 0, len(prices))
+	for key := range prices {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	var differences []string
+	for _, key := range keys {
+		r, e := prices[key][0], prices[key][1]
+		compare := func(field string, reconstructed uint, exchange uint) {
+			if reconstructed != exchange {
+				differences = append(differences, fmt.Sprintf("%.4f %s %d exchange %d", itch.Price(key), field, reconstructed, exchange))
+			}
+		}
+		compare("buy quantity", r.BuyQuantity, e.BuyQuantity)
+		compare("buy depth", r.BuyDepth, e.BuyDepth)
+		compare("sell quantity", r.SellQuantity, e.SellQuantity)
+		compare("sell depth", r.SellDepth, e.SellDepth)
+	}
+	return differences
+}
@@ -0,0 +1,72 @@
+package reconstruct
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/itch"
+)
+
+func reconstructSample(require *require.Assertions, at time.Duration) (*itch.Book, int) {
+
+	file, err := itch.OpenFile("../../../itch/testdata/sample.itch.gz")
+	require.Nil(err)
+	defer file.Close()
+
+	book, failed, err := Reconstruct(file, at)
+	require.Nil(err)
+	return book, failed
+}
+
+func TestReconstruct(t *testing.T) {
+
+	require := require.New(t)
+
+	book, failed := reconstructSample(require, 9*time.Hour+30*time.Minute+1500*time.Millisecond)
+	require.Equal(0, failed)
+	require.Equal(9*time.Hour+30*time.Minute+time.Second, book.Time())
+
+	require.Equal(handlers.SymbolResponse{Symbol: "AAPL", Prices: []handlers.SymbolPriceResponse{
+		{Price: 149.9, BuyQuantity: 50, BuyDepth: 1},
+		{Price: 150, BuyQuantity: 60, BuyDepth: 1},
+		{Price: 150.1, SellQuantity: 200, SellDepth: 1},
+	}}, GetSymbolResponse(book, "AAPL"))
+
+	book, _ = reconstructSample(require, 24*time.Hour)
+	require.Equal(handlers.SymbolResponse{Symbol: "AAPL", Prices: []handlers.SymbolPriceResponse{
+		{Price: 149.9, BuyQuantity: 40, BuyDepth: 1},
+		{Price: 150, BuyQuantity: 130, BuyDepth: 2},
+		{Price: 150.05, SellQuantity: 150, SellDepth: 1},
+	}}, GetSymbolResponse(book, "AAPL"))
+
+	require.Equal(handlers.SymbolResponse{Symbol: "MSFT", Prices: []handlers.SymbolPriceResponse{}}, GetSymbolResponse(book, "MSFT"))
+}
+
+func TestCompare(t *testing.T) {
+
+	require := require.New(t)
+
+	reconstructed := handlers.SymbolResponse{Symbol: "AAPL", Prices: []handlers.SymbolPriceResponse{
+		{Price: 149.9, BuyQuantity: 40, BuyDepth: 1},
+		{Price: 150, BuyQuantity: 130, BuyDepth: 2},
+		{Price: 150.05, SellQuantity: 150, SellDepth: 1},
+	}}
+
+	require.Empty(Compare(reconstructed, reconstructed))
+
+	exchange := handlers.SymbolResponse{Symbol: "AAPL", Prices: []handlers.SymbolPriceResponse{
+		{Price: 150, BuyQuantity: 100, BuyDepth: 2},
+		{Price: 150.05, SellQuantity: 150, SellDepth: 1},
+		{Price: 150.1, SellQuantity: 20, SellDepth: 1},
+	}}
+
+	require.Equal([]string{
+		"149.9000 buy quantity 40 exchange 0",
+		"149.9000 buy depth 1 exchange 0",
+		"150.0000 buy quantity 130 exchange 100",
+		"150.1000 sell quantity 0 exchange 20",
+		"150.1000 sell depth 0 exchange 1",
+	}, Compare(reconstructed, exchange))
+}
@@ -0,0 +1,192 @@
+package convert
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/order-replay/replay"
+	"github.com/tradsim/tradsim-go/itch"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// Conversions of the executions of resting orders
+const (
+	TradeExecutions  = "trade"
+	ReduceExecutions = "reduce"
+)
+
+// Converter turns ITCH messages into order instructions of the replay.
+// The external ids are the order reference numbers. The exchange only amends up, so partial cancels re-add the rest of
+// the order and replaces cancel and add, which loses the priority as ITCH replaces do. Client order ids are unique in a
+// session, so the n-th re-add of a order gets the id <ref>.<n>, which later instructions of the order use.
+// Executions are either aggressive orders of the opposite side, which trade against the resting order in the exchange,
+// or reductions of the resting order like partial cancels.
+type Converter struct {
+	book       *itch.Book
+	date       time.Time
+	stocks     map[string]bool
+	executions string
+	from       time.Duration
+	seeded     bool
+	readds     map[uint64]int
+}
+
+// NewConverter creates a new converter of the session date, messages before from only seed the book,
+// whose orders are added at from. Empty stocks convert every stock.
+func NewConverter(date time.Time, stocks []string, executions string, from time.Duration) *Converter {
+
+	selected := make(map[string]bool, len(stocks))
+	for _, stock := range stocks {
+		selected[stock] = true
+	}
+
+	return &Converter{itch.NewBook(), date, selected, executions, from, from == 0, make(map[uint64]int)}
+}
+
+// Book returns the book of the applied messages
+func (c *Converter) Book() *itch.Book {
+	return c.book
+}
+
+// Convert applies the message to the book and returns its instructions
+func (c *Converter) Convert(message itch.Message) ([]replay.Instruction, error) {
+
+	header := message.GetHeader()
+
+	var instructions []replay.Instruction
+
+	if !c.seeded && header.Timestamp >= c.from {
+		c.seeded = true
+		instructions = c.seed()
+	}
+
+	// the order before the message
+	var order itch.Order
+	switch m := message.(type) {
+	case *itch.OrderExecuted:
+		order = c.lookup(m.OrderRef)
+	case *itch.OrderCancel:
+		order = c.lookup(m.OrderRef)
+	case *itch.OrderDelete:
+		order = c.lookup(m.OrderRef)
+	case *itch.OrderReplace:
+		order = c.lookup(m.OrderRef)
+	}
+
+	err := c.book.Apply(message)
+	if err != nil {
+		return instructions, err
+	}
+
+	if !c.seeded {
+		return instructions, nil
+	}
+
+	switch m := message.(type) {
+	case *itch.AddOrder:
+		if c.selected(m.Stock) {
+			instructions = append(instructions, c.add(header.Timestamp, itch.Order{Ref: m.OrderRef, Stock: m.Stock, Side: m.Side, Shares: m.Shares, Price: m.Price}))
+		}
+	case *itch.OrderExecuted:
+		if !c.selected(order.Stock) {
+			break
+		}
+		if c.executions == ReduceExecutions {
+			instructions = append(instructions, c.reduce(header.Timestamp, order, m.Shares)...)
+			break
+		}
+		price := order.Price
+		if m.Price > 0 {
+			price = m.Price
+		}
+		side := itch.BuySide
+		if order.Side == itch.BuySide {
+			side = itch.SellSide
+		}
+		instructions = append(instructions, c.instruction(header.Timestamp, replay.NewAction, "M"+strconv.FormatUint(m.MatchNumber, 10),
+			itch.Order{Stock: order.Stock, Side: side, Shares: m.Shares, Price: price}))
+	case *itch.OrderCancel:
+		if c.selected(order.Stock) {
+			instructions = append(instructions, c.reduce(header.Timestamp, order, m.Shares)...)
+		}
+	case *itch.OrderDelete:
+		if c.selected(order.Stock) {
+			instructions = append(instructions, c.cancel(header.Timestamp, order))
+		}
+	case *itch.OrderReplace:
+		if c.selected(order.Stock) {
+			replaced, _ := c.book.Order(m.NewOrderRef)
+			instructions = append(instructions, c.cancel(header.Timestamp, order), c.add(header.Timestamp, *replaced))
+		}
+	}
+
+	return instructions, nil
+}
+
+// seed returns the new instructions of the resting orders of the selected stocks in the order they were added
+func (c *Converter) seed() []replay.Instruction {
+
+	var instructions []replay.Instruction
+	for _, order := range c.book.Orders() {
+		if c.selected(order.Stock) {
+			instructions = append(instructions, c.add(c.from, order))
+		}
+	}
+	return instructions
+}
+
+// reduce cancels the order and re-adds the shares which are left with a new id
+func (c *Converter) reduce(timestamp time.Duration, order itch.Order, shares uint32) []replay.Instruction {
+
+	readds := c.readds[order.Ref]
+	instructions := []replay.Instruction{c.cancel(timestamp, order)}
+	if shares < order.Shares {
+		order.Shares -= shares
+		c.readds[order.Ref] = readds + 1
+		instructions = append(instructions, c.add(timestamp, order))
+	}
+	return instructions
+}
+
+func (c *Converter) add(timestamp time.Duration, order itch.Order) replay.Instruction {
+	return c.instruction(timestamp, replay.NewAction, c.id(order.Ref), order)
+}
+
+// cancel cancels the order, which ends its re-adds
+func (c *Converter) cancel(timestamp time.Duration, order itch.Order) replay.Instruction {
+	instruction := c.instruction(timestamp, replay.CancelAction, c.id(order.Ref), order)
+	delete(c.readds, order.Ref)
+	return instruction
+}
+
+// id returns the external id of the order, the reference number suffixed by the number of re-adds
+func (c *Converter) id(ref uint64) string {
+	id := strconv.FormatUint(ref, 10)
+	if readds := c.readds[ref]; readds > 0 {
+		id += "." + strconv.Itoa(readds)
+	}
+	return id
+}
+
+func (c *Converter) instruction(timestamp time.Duration, action string, id string, order itch.Order) replay.Instruction {
+
+	direction := models.SellText
+	if order.Side == itch.BuySide {
+		direction = models.BuyText
+	}
+
+	return replay.Instruction{Timestamp: c.date.Add(timestamp), Action: action, Symbol: order.Stock, Direction: direction,
+		Price: itch.Price(order.Price), Quantity: uint(order.Shares), ID: id}
+}
+
+// lookup returns a copy of the resting order, which is empty for unknown orders
+func (c *Converter) lookup(ref uint64) itch.Order {
+	if order, ok := c.book.Order(ref); ok {
+		return *order
+	}
+	return itch.Order{}
+}
+
+func (c *Converter) selected(stock string) bool {
+	return stock != "" && (len(c.stocks) == 0 || c.stocks[stock])
+}
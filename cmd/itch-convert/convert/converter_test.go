@@ -0,0 +1,119 @@
+package convert
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/order-replay/replay"
+	"github.com/tradsim/tradsim-go/itch"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+var date = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+func instruction(timestamp time.Duration, action string, symbol string, direction string, price float64, quantity uint, id string) replay.Instruction {
+	return replay.Instruction{Timestamp: date.Add(timestamp), Action: action, Symbol: symbol, Direction: direction, Price: price, Quantity: quantity, ID: id}
+}
+
+func convertSample(require *require.Assertions, converter *Converter) []replay.Instruction {
+
+	file, err := itch.OpenFile("../../../itch/testdata/sample.itch")
+	require.Nil(err)
+	defer file.Close()
+
+	var instructions []replay.Instruction
+	for {
+		message, err := file.Next()
+		if err == io.EOF {
+			return instructions
+		}
+		require.Nil(err)
+
+		converted, err := converter.Convert(message)
+		require.Nil(err)
+		instructions = append(instructions, converted...)
+	}
+}
+
+func TestConvertTrades(t *testing.T) {
+
+	require := require.New(t)
+
+	open := 9*time.Hour + 30*time.Minute
+	instructions := convertSample(require, NewConverter(date, nil, TradeExecutions, 0))
+
+	require.Equal([]replay.Instruction{
+		instruction(open+time.Millisecond, replay.NewAction, "AAPL", models.BuyText, 150, 100, "1"),
+		instruction(open+2*time.Millisecond, replay.NewAction, "AAPL", models.SellText, 150.1, 200, "2"),
+		instruction(open+3*time.Millisecond, replay.NewAction, "AAPL", models.BuyText, 149.9, 50, "3"),
+		instruction(open+4*time.Millisecond, replay.NewAction, "MSFT", models.SellText, 100.5, 300, "4"),
+		instruction(open+time.Second, replay.NewAction, "AAPL", models.SellText, 150, 40, "M1"),
+		instruction(open+2*time.Second, replay.NewAction, "AAPL", models.BuyText, 150.1, 50, "M2"),
+		instruction(open+3*time.Second, replay.CancelAction, "AAPL", models.BuyText, 149.9, 50, "3"),
+		instruction(open+3*time.Second, replay.NewAction, "AAPL", models.BuyText, 149.9, 40, "3.1"),
+		instruction(open+4*time.Second, replay.CancelAction, "AAPL", models.SellText, 150.1, 150, "2"),
+		instruction(open+4*time.Second, replay.NewAction, "AAPL", models.SellText, 150.05, 150, "5"),
+		instruction(open+5*time.Second, replay.NewAction, "AAPL", models.BuyText, 150, 70, "6"),
+		instruction(open+6*time.Second, replay.CancelAction, "MSFT", models.SellText, 100.5, 300, "4"),
+	}, instructions)
+}
+
+func TestConvertReduceFrom(t *testing.T) {
+
+	require := require.New(t)
+
+	open := 9*time.Hour + 30*time.Minute
+	from := open + 2500*time.Millisecond
+	converter := NewConverter(date, []string{"AAPL"}, ReduceExecutions, from)
+	instructions := convertSample(require, converter)
+
+	require.Equal([]replay.Instruction{
+		instruction(from, replay.NewAction, "AAPL", models.BuyText, 150, 60, "1"),
+		instruction(from, replay.NewAction, "AAPL", models.SellText, 150.1, 150, "2"),
+		instruction(from, replay.NewAction, "AAPL", models.BuyText, 149.9, 50, "3"),
+		instruction(open+3*time.Second, replay.CancelAction, "AAPL", models.BuyText, 149.9, 50, "3"),
+		instruction(open+3*time.Second, replay.NewAction, "AAPL", models.BuyText, 149.9, 40, "3.1"),
+		instruction(open+4*time.Second, replay.CancelAction, "AAPL", models.SellText, 150.1, 150, "2"),
+		instruction(open+4*time.Second, replay.NewAction, "AAPL", models.SellText, 150.05, 150, "5"),
+		instruction(open+5*time.Second, replay.NewAction, "AAPL", models.BuyText, 150, 70, "6"),
+	}, instructions)
+
+	require.Len(converter.Book().Orders(), 4)
+}
+
+func TestConvertReduceExecutions(t *testing.T) {
+
+	require := require.New(t)
+
+	converter := NewConverter(date, nil, ReduceExecutions, 0)
+	header := itch.Header{Type: itch.AddOrderType, Timestamp: time.Second}
+
+	instructions, err := converter.Convert(&itch.AddOrder{Header: header, OrderRef: 7, Side: itch.SellSide, Shares: 100, Stock: "TT", Price: 12500})
+	require.Nil(err)
+	require.Equal([]replay.Instruction{instruction(time.Second, replay.NewAction, "TT", models.SellText, 1.25, 100, "7")}, instructions)
+
+	header.Type = itch.OrderExecutedType
+	instructions, err = converter.Convert(&itch.OrderExecuted{Header: header, OrderRef: 7, Shares: 30, MatchNumber: 1, Printable: true})
+	require.Nil(err)
+	require.Equal([]replay.Instruction{
+		instruction(time.Second, replay.CancelAction, "TT", models.SellText, 1.25, 100, "7"),
+		instruction(time.Second, replay.NewAction, "TT", models.SellText, 1.25, 70, "7.1"),
+	}, instructions)
+
+	instructions, err = converter.Convert(&itch.OrderExecuted{Header: header, OrderRef: 7, Shares: 20, MatchNumber: 2, Printable: true})
+	require.Nil(err)
+	require.Equal([]replay.Instruction{
+		instruction(time.Second, replay.CancelAction, "TT", models.SellText, 1.25, 70, "7.1"),
+		instruction(time.Second, replay.NewAction, "TT", models.SellText, 1.25, 50, "7.2"),
+	}, instructions)
+
+	instructions, err = converter.Convert(&itch.OrderExecuted{Header: header, OrderRef: 7, Shares: 50, MatchNumber: 3, Printable: true})
+	require.Nil(err)
+	require.Equal([]replay.Instruction{instruction(time.Second, replay.CancelAction, "TT", models.SellText, 1.25, 50, "7.2")}, instructions)
+	require.Empty(converter.readds)
+
+	_, err = converter.Convert(&itch.OrderExecuted{Header: header, OrderRef: 7, Shares: 1, MatchNumber: 4, Printable: true})
+	require.EqualError(err, "unknown order 7")
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/itch-convert/convert"
+	"github.com/tradsim/tradsim-go/cmd/order-replay/replay"
+	"github.com/tradsim/tradsim-go/itch"
+)
+
+func main() {
+
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.Ldate | log.Lmicroseconds | log.LUTC | log.Lshortfile)
+	log.SetPrefix("ic ")
+
+	path := flag.String("file", "", "ITCH 5.0 file, gzip compressed when it ends with .gz")
+	out := flag.String("out", "", "file of the instructions, defaults to stdout")
+	format := flag.String("format", "", "csv or jsonl, defaults to the format of the out file extension")
+	symbols := flag.String("symbols", "", "comma separated stocks to convert, all stocks when empty")
+	day := flag.String("date", "1970-01-01", "session date of the timestamps, YYYY-MM-DD")
+	from := flag.String("from", "", "time HH:MM:SS[.nnn] from which to convert, the book before it is added at it")
+	to := flag.String("to", "", "time HH:MM:SS[.nnn] until which to convert")
+	executions := flag.String("executions", convert.TradeExecutions, "trade sends aggressive orders for executions, reduce reduces the resting orders")
+	flag.Parse()
+
+	if *path == "" || (*executions != convert.TradeExecutions && *executions != convert.ReduceExecutions) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = replay.GetFormat(*out)
+	}
+
+	date, err := time.Parse("2006-01-02", *day)
+	if err != nil {
+		log.Fatalf("Invalid date %q! %s", *day, err)
+	}
+
+	var start, end time.Duration
+	if *from != "" {
+		start, err = itch.ParseTime(*from)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *to != "" {
+		end, err = itch.ParseTime(*to)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var stocks []string
+	for _, symbol := range strings.Split(*symbols, ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			stocks = append(stocks, symbol)
+		}
+	}
+
+	file, err := itch.OpenFile(*path)
+	if err != nil {
+		log.Fatalf("Failed to open %s! %s", *path, err)
+	}
+	defer file.Close()
+
+	var output io.Writer = os.Stdout
+	if *out != "" {
+		created, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Failed to create %s! %s", *out, err)
+		}
+		defer created.Close()
+		output = created
+	}
+	buffered := bufio.NewWriter(output)
+	writer := replay.NewInstructionWriter(buffered, *format)
+
+	converter := convert.NewConverter(date, stocks, *executions, start)
+	messages, instructions, failed := 0, 0, 0
+
+	for {
+		message, err := file.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("Failed to read message %d! %s", messages+1, err)
+			break
+		}
+		if *to != "" && message.GetHeader().Timestamp > end {
+			break
+		}
+		messages++
+
+		converted, err := converter.Convert(message)
+		if err != nil {
+			failed++
+		}
+		for _, instruction := range converted {
+			err = writer.Write(instruction)
+			if err != nil {
+				log.Fatalf("Failed to write instruction! %s", err)
+			}
+			instructions++
+		}
+	}
+
+	err = writer.Flush()
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		log.Fatalf("Failed to write instructions! %s", err)
+	}
+
+	log.Printf("Converted %d messages to %d instructions, %d messages of unknown orders and %d unsupported messages were skipped.",
+		messages, instructions, failed, file.Skipped())
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/client"
+	"github.com/tradsim/tradsim-go/cmd/market-simulator/simulation"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func main() {
+
+	log.SetOutput(os.Stdout)
+	log.SetFlags(log.Ldate | log.Lmicroseconds | log.LUTC | log.Lshortfile)
+	log.SetPrefix("ms ")
+
+	scenarioPath := flag.String("scenario", "scenario.json", "scenario of the symbols and agents")
+	exchangeURL := flag.String("exchange", "http://localhost:8081", "exchange service url")
+	seed := flag.Int64("seed", 0, "random seed, overrides the seed of the scenario when not zero")
+	duration := flag.Duration("duration", 0, "duration of the simulation, overrides the duration of the scenario when not zero")
+	flag.Parse()
+
+	scenario, err := simulation.LoadScenario(*scenarioPath)
+	if err != nil {
+		log.Fatalf("Failed to load scenario! %s", err)
+	}
+	if *seed != 0 {
+		scenario.Seed = *seed
+	}
+	if *duration != 0 {
+		scenario.Duration = duration.Seconds()
+	}
+
+	rest := common_http.NewRestClientImpl(5 * time.Second)
+	simulator, err := simulation.NewSimulator(scenario, func(account *common_http.Account) simulation.Exchange {
+		return client.NewClient(*exchangeURL, account, rest, client.DefaultRetryPolicy)
+	})
+	if err != nil {
+		log.Fatalf("Failed to create simulator! %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, syscall.SIGTERM)
+	go func() {
+		<-c
+		cancel()
+	}()
+
+	log.Printf("Starting market simulator with seed %d and %d agents.", scenario.Seed, len(simulator.Agents()))
+
+	err = simulator.Run(ctx)
+	if err != nil {
+		log.Fatalf("Simulation failed! %s", err)
+	}
+
+	log.Print("Market simulator stopped.")
+}
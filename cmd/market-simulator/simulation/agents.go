@@ -0,0 +1,223 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// Exchange defines the calls of the exchange-service which the simulator uses, it is implemented by *client.Client
+type Exchange interface {
+	GetBook(ctx context.Context, symbol string) (*handlers.SymbolResponse, error)
+	CreateOrder(ctx context.Context, order handlers.OrderDTO) (*handlers.OrderIDResponse, error)
+	CancelOrder(ctx context.Context, id string) error
+}
+
+// Stats counts the requests of a agent
+type Stats struct {
+	Orders  int
+	Cancels int
+	Errors  int
+}
+
+// Agent trades a market in every step of the simulation
+type Agent interface {
+	Name() string
+	Act(ctx context.Context, market *Market, dt float64)
+	Stats() Stats
+}
+
+// NewAgent creates a agent of the type of the config, the random source makes its decisions reproducible
+func NewAgent(config AgentConfig, name string, exchange Exchange, random *rand.Rand) (Agent, error) {
+
+	t := &trader{name, config, exchange, random, nil, Stats{}}
+
+	switch config.Type {
+	case NoiseTrader:
+		return &noiseTrader{t}, nil
+	case MarketMaker:
+		return &marketMaker{t}, nil
+	case MomentumTrader:
+		return &momentumTrader{t}, nil
+	case MeanReversionTrader:
+		return &meanReversionTrader{t}, nil
+	}
+	return nil, fmt.Errorf("unknown agent type %q", config.Type)
+}
+
+// trader places and cancels the orders of a agent, keeping at most the max open orders
+type trader struct {
+	name     string
+	config   AgentConfig
+	exchange Exchange
+	random   *rand.Rand
+	open     []string
+	stats    Stats
+}
+
+func (t *trader) Name() string {
+	return t.name
+}
+
+func (t *trader) Stats() Stats {
+	return t.stats
+}
+
+// arrivals draws the number of orders of the step
+func (t *trader) arrivals(dt float64) int {
+	return Poisson(t.random, t.config.Rate*dt)
+}
+
+// submit places a order, cancelling the oldest open order above the maximum.
+// Open orders may have been filled in the meantime, the exchange rejects their cancellation.
+func (t *trader) submit(ctx context.Context, market *Market, direction string, price float64) {
+
+	if !t.place(ctx, market, direction, price) {
+		return
+	}
+
+	for len(t.open) > t.config.MaxOpenOrders {
+		t.cancel(ctx, t.open[0])
+		t.open = t.open[1:]
+	}
+}
+
+// place places a order of a size drawn from the distribution and returns true if it was accepted
+func (t *trader) place(ctx context.Context, market *Market, direction string, price float64) bool {
+
+	order := handlers.OrderDTO{Symbol: market.Symbol, Quantity: t.config.Size.Sample(t.random), Direction: direction, Price: price}
+
+	response, err := t.exchange.CreateOrder(ctx, order)
+	if err != nil {
+		log.Printf("%s: Failed to create order %s %d@%g! %s", t.name, direction, order.Quantity, price, err)
+		t.stats.Errors++
+		return false
+	}
+	t.stats.Orders++
+	t.open = append(t.open, response.ID)
+	return true
+}
+
+// cancelAll cancels the open orders
+func (t *trader) cancelAll(ctx context.Context) {
+	for _, id := range t.open {
+		t.cancel(ctx, id)
+	}
+	t.open = nil
+}
+
+func (t *trader) cancel(ctx context.Context, id string) {
+
+	err := t.exchange.CancelOrder(ctx, id)
+	if err != nil {
+		t.stats.Errors++
+		return
+	}
+	t.stats.Cancels++
+}
+
+// side returns buy or sell with equal probability
+func (t *trader) side() string {
+	if t.random.Intn(2) == 0 {
+		return models.BuyText
+	}
+	return models.SellText
+}
+
+// aggressive returns the price which crosses the best opposite price by the aggression ticks
+func (t *trader) aggressive(market *Market, direction string) float64 {
+
+	if direction == models.BuyText {
+		if market.BestAsk > 0 {
+			return market.Price(market.BestAsk, t.config.Aggression)
+		}
+		return market.Price(market.Mid(), t.config.Aggression)
+	}
+
+	if market.BestBid > 0 {
+		return market.Price(market.BestBid, -t.config.Aggression)
+	}
+	return market.Price(market.Mid(), -t.config.Aggression)
+}
+
+// noiseTrader places orders of random side at random ticks around the mid, some of them cross the spread
+type noiseTrader struct {
+	*trader
+}
+
+func (n *noiseTrader) Act(ctx context.Context, market *Market, dt float64) {
+
+	for i := n.arrivals(dt); i > 0; i-- {
+		offset := n.random.Intn(2*n.config.Spread+1) - n.config.Spread
+		n.submit(ctx, market, n.side(), market.Price(market.Mid(), offset))
+	}
+}
+
+// marketMaker quotes levels on both sides around the fair value, it requotes at the rate or else in every step
+type marketMaker struct {
+	*trader
+}
+
+func (m *marketMaker) Act(ctx context.Context, market *Market, dt float64) {
+
+	if len(m.open) > 0 && m.config.Rate > 0 && m.arrivals(dt) == 0 {
+		return
+	}
+
+	m.cancelAll(ctx)
+
+	for level := 0; level < m.config.Levels; level++ {
+		m.place(ctx, market, models.BuyText, market.Price(market.Fair.Value, -m.config.Spread-level))
+		m.place(ctx, market, models.SellText, market.Price(market.Fair.Value, m.config.Spread+level))
+	}
+}
+
+// momentumTrader follows the move of the mid over the lookback when it exceeds the threshold
+type momentumTrader struct {
+	*trader
+}
+
+func (m *momentumTrader) Act(ctx context.Context, market *Market, dt float64) {
+
+	for i := m.arrivals(dt); i > 0; i-- {
+
+		momentum, ok := market.Momentum(m.config.Lookback)
+		if !ok {
+			return
+		}
+
+		switch {
+		case momentum > m.config.Threshold:
+			m.submit(ctx, market, models.BuyText, m.aggressive(market, models.BuyText))
+		case momentum < -m.config.Threshold:
+			m.submit(ctx, market, models.SellText, m.aggressive(market, models.SellText))
+		}
+	}
+}
+
+// meanReversionTrader trades against the deviation of the mid from its moving average when it exceeds the threshold
+type meanReversionTrader struct {
+	*trader
+}
+
+func (m *meanReversionTrader) Act(ctx context.Context, market *Market, dt float64) {
+
+	for i := m.arrivals(dt); i > 0; i-- {
+
+		deviation, ok := market.Deviation(m.config.Lookback)
+		if !ok {
+			return
+		}
+
+		switch {
+		case deviation > m.config.Threshold:
+			m.submit(ctx, market, models.SellText, m.aggressive(market, models.SellText))
+		case deviation < -m.config.Threshold:
+			m.submit(ctx, market, models.BuyText, m.aggressive(market, models.BuyText))
+		}
+	}
+}
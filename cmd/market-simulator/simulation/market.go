@@ -0,0 +1,107 @@
+package simulation
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+)
+
+// Market is the view of a symbol which the agents trade on, it is updated from the order book once per step
+type Market struct {
+	Symbol  string
+	Tick    float64
+	Fair    *GBM
+	BestBid float64 // zero without bids
+	BestAsk float64 // zero without asks
+
+	decimals int
+	mids     []float64
+	history  int
+}
+
+// NewMarket creates a new market of the symbol which keeps the mids of the last history steps
+func NewMarket(config SymbolConfig, history int) *Market {
+	return &Market{config.Symbol, config.Tick, &GBM{config.Price, config.Drift, config.Volatility}, 0, 0,
+		getDecimals(config.Tick), make([]float64, 0, history+1), history}
+}
+
+// Update sets the best prices of the book and records the mid
+func (m *Market) Update(book *handlers.SymbolResponse) {
+
+	m.BestBid, m.BestAsk = 0, 0
+	for _, price := range book.Prices {
+		if price.BuyQuantity > 0 && price.Price > m.BestBid {
+			m.BestBid = price.Price
+		}
+		if price.SellQuantity > 0 && (m.BestAsk == 0 || price.Price < m.BestAsk) {
+			m.BestAsk = price.Price
+		}
+	}
+
+	m.mids = append(m.mids, m.Mid())
+	if len(m.mids) > m.history+1 {
+		m.mids = m.mids[1:]
+	}
+}
+
+// Mid returns the mid of the best prices, the price of the only side or else the fair value
+func (m *Market) Mid() float64 {
+
+	switch {
+	case m.BestBid > 0 && m.BestAsk > 0:
+		return (m.BestBid + m.BestAsk) / 2
+	case m.BestBid > 0:
+		return m.BestBid
+	case m.BestAsk > 0:
+		return m.BestAsk
+	}
+	return m.Fair.Value
+}
+
+// Momentum returns the relative change of the mid over the lookback steps, false until enough steps are recorded
+func (m *Market) Momentum(lookback int) (float64, bool) {
+
+	if lookback > m.history || len(m.mids) <= lookback {
+		return 0, false
+	}
+	last := m.mids[len(m.mids)-1]
+	first := m.mids[len(m.mids)-1-lookback]
+	return last/first - 1, true
+}
+
+// Deviation returns the relative deviation of the mid from its moving average over the lookback steps, false until enough steps are recorded
+func (m *Market) Deviation(lookback int) (float64, bool) {
+
+	if lookback > m.history || len(m.mids) < lookback {
+		return 0, false
+	}
+
+	sum := 0.0
+	for _, mid := range m.mids[len(m.mids)-lookback:] {
+		sum += mid
+	}
+	return m.mids[len(m.mids)-1]/(sum/float64(lookback)) - 1, true
+}
+
+// Price returns the price ticks away from the reference rounded to the tick, it is at least one tick
+func (m *Market) Price(reference float64, ticks int) float64 {
+
+	tick := math.Round(reference/m.Tick) + float64(ticks)
+	if tick < 1 {
+		tick = 1
+	}
+	price, _ := strconv.ParseFloat(strconv.FormatFloat(tick*m.Tick, 'f', m.decimals, 64), 64)
+	return price
+}
+
+// getDecimals returns the decimals of the tick size
+func getDecimals(tick float64) int {
+
+	text := strconv.FormatFloat(tick, 'f', -1, 64)
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		return len(text) - i - 1
+	}
+	return 0
+}
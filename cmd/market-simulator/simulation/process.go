@@ -0,0 +1,126 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Size distributions
+const (
+	FixedSize       = "fixed"
+	UniformSize     = "uniform"
+	ExponentialSize = "exponential"
+	LogNormalSize   = "lognormal"
+)
+
+// SizeDistribution defines the distribution of the order quantities.
+// Fixed uses the mean, uniform draws between min and max, exponential has the mean and lognormal the mean and sigma of the log.
+// Draws are rounded to lots and clamped between min and max when they are set.
+type SizeDistribution struct {
+	Type  string  `json:"type"`
+	Mean  float64 `json:"mean"`
+	Sigma float64 `json:"sigma"`
+	Min   uint    `json:"min"`
+	Max   uint    `json:"max"`
+	Lot   uint    `json:"lot"`
+}
+
+// Validate checks the parameters of the distribution
+func (d *SizeDistribution) Validate() error {
+
+	if d.Lot == 0 {
+		d.Lot = 1
+	}
+	if d.Max > 0 && d.Min > d.Max {
+		return fmt.Errorf("size min %d is greater than max %d", d.Min, d.Max)
+	}
+
+	switch d.Type {
+	case FixedSize, ExponentialSize:
+		if d.Mean < 1 {
+			return fmt.Errorf("%s size needs a mean of at least 1", d.Type)
+		}
+	case UniformSize:
+		if d.Min == 0 || d.Max == 0 {
+			return fmt.Errorf("uniform size needs a min and a max")
+		}
+	case LogNormalSize:
+		if d.Mean < 1 || d.Sigma <= 0 {
+			return fmt.Errorf("lognormal size needs a mean of at least 1 and a positive sigma")
+		}
+	default:
+		return fmt.Errorf("unknown size distribution %q", d.Type)
+	}
+	return nil
+}
+
+// Sample draws a quantity, which is at least a lot
+func (d SizeDistribution) Sample(r *rand.Rand) uint {
+
+	var size float64
+
+	switch d.Type {
+	case UniformSize:
+		size = float64(d.Min) + r.Float64()*float64(d.Max-d.Min+1)
+	case ExponentialSize:
+		size = r.ExpFloat64() * d.Mean
+	case LogNormalSize:
+		// the mean of the lognormal is exp(mu + sigma²/2)
+		mu := math.Log(d.Mean) - d.Sigma*d.Sigma/2
+		size = math.Exp(mu + d.Sigma*r.NormFloat64())
+	default:
+		size = d.Mean
+	}
+
+	lots := uint(math.Floor(size / float64(d.Lot)))
+	if lots == 0 {
+		lots = 1
+	}
+	quantity := lots * d.Lot
+
+	if quantity < d.Min {
+		quantity = d.Min
+	}
+	if d.Max > 0 && quantity > d.Max {
+		quantity = d.Max
+	}
+	return quantity
+}
+
+// GBM is a geometric brownian motion with the drift and volatility per second
+type GBM struct {
+	Value      float64
+	Drift      float64
+	Volatility float64
+}
+
+// Next advances the value by dt seconds
+func (g *GBM) Next(r *rand.Rand, dt float64) float64 {
+	g.Value *= math.Exp((g.Drift-g.Volatility*g.Volatility/2)*dt + g.Volatility*math.Sqrt(dt)*r.NormFloat64())
+	return g.Value
+}
+
+// Poisson draws the number of arrivals of a poisson process with the mean
+func Poisson(r *rand.Rand, mean float64) int {
+
+	if mean <= 0 {
+		return 0
+	}
+
+	// Knuth's multiplication method, large means are approximated by the normal distribution
+	if mean > 30 {
+		n := int(math.Round(mean + math.Sqrt(mean)*r.NormFloat64()))
+		if n < 0 {
+			return 0
+		}
+		return n
+	}
+
+	limit := math.Exp(-mean)
+	n := 0
+	for p := r.Float64(); p > limit; p *= r.Float64() {
+		n++
+	}
+	return n
+}
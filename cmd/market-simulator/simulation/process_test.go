@@ -0,0 +1,104 @@
+package simulation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeDistributionSample(t *testing.T) {
+
+	require := require.New(t)
+	r := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		distribution SizeDistribution
+		min          uint
+		max          uint
+		mean         float64
+	}{
+		{SizeDistribution{Type: FixedSize, Mean: 25, Lot: 10}, 20, 20, 20},
+		{SizeDistribution{Type: UniformSize, Min: 10, Max: 20, Lot: 1}, 10, 20, 15},
+		{SizeDistribution{Type: ExponentialSize, Mean: 50, Lot: 1, Max: 1000}, 1, 1000, 50},
+		{SizeDistribution{Type: LogNormalSize, Mean: 100, Sigma: 0.5, Lot: 1}, 1, math.MaxUint32, 100},
+	}
+
+	for _, test := range tests {
+
+		sum := 0.0
+		for i := 0; i < 10000; i++ {
+			size := test.distribution.Sample(r)
+			require.True(size >= test.min && size <= test.max, "%s size %d", test.distribution.Type, size)
+			require.Zero(size % test.distribution.Lot)
+			sum += float64(size)
+		}
+		require.InDelta(test.mean, sum/10000, test.mean*0.05, test.distribution.Type)
+	}
+}
+
+func TestSizeDistributionValidate(t *testing.T) {
+
+	require := require.New(t)
+
+	distribution := SizeDistribution{Type: FixedSize, Mean: 10}
+	require.Nil(distribution.Validate())
+	require.Equal(uint(1), distribution.Lot)
+
+	require.NotNil((&SizeDistribution{Type: UniformSize, Min: 5}).Validate())
+	require.NotNil((&SizeDistribution{Type: UniformSize, Min: 5, Max: 4}).Validate())
+	require.NotNil((&SizeDistribution{Type: LogNormalSize, Mean: 10}).Validate())
+	require.NotNil((&SizeDistribution{Type: ExponentialSize}).Validate())
+}
+
+func TestGBM(t *testing.T) {
+
+	require := require.New(t)
+
+	first := GBM{100, 0, 0.01}
+	second := GBM{100, 0, 0.01}
+	r1 := rand.New(rand.NewSource(3))
+	r2 := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 100; i++ {
+		require.Equal(first.Next(r1, 0.5), second.Next(r2, 0.5))
+	}
+	require.NotEqual(100.0, first.Value)
+
+	drift := GBM{100, 0.1, 0}
+	require.InDelta(100*math.Exp(0.1), drift.Next(r1, 1), 1e-9)
+
+	var logs []float64
+	for i := 0; i < 10000; i++ {
+		g := GBM{100, 0, 0.2}
+		logs = append(logs, math.Log(g.Next(r1, 1)/100))
+	}
+	mean, variance := 0.0, 0.0
+	for _, l := range logs {
+		mean += l / float64(len(logs))
+	}
+	for _, l := range logs {
+		variance += (l - mean) * (l - mean) / float64(len(logs))
+	}
+	require.InDelta(-0.02, mean, 0.01)
+	require.InDelta(0.2, math.Sqrt(variance), 0.01)
+}
+
+func TestPoisson(t *testing.T) {
+
+	require := require.New(t)
+	r := rand.New(rand.NewSource(5))
+
+	require.Equal(0, Poisson(r, 0))
+
+	for _, mean := range []float64{0.2, 3, 50} {
+		sum := 0
+		for i := 0; i < 10000; i++ {
+			n := Poisson(r, mean)
+			require.True(n >= 0)
+			sum += n
+		}
+		require.InDelta(mean, float64(sum)/10000, mean*0.05)
+	}
+}
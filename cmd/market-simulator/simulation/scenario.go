@@ -0,0 +1,166 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// Agent types of a scenario
+const (
+	NoiseTrader         = "noise"
+	MarketMaker         = "market_maker"
+	MomentumTrader      = "momentum"
+	MeanReversionTrader = "mean_reversion"
+)
+
+// Scenario defines the symbols and the agent populations of a simulation.
+// The seed makes the random decisions of the agents reproducible.
+type Scenario struct {
+	Seed     int64                `json:"seed"`
+	Step     float64              `json:"step_seconds"`
+	Duration float64              `json:"duration_seconds"`
+	Account  *common_http.Account `json:"account"`
+	Symbols  []SymbolConfig       `json:"symbols"`
+	Agents   []AgentConfig        `json:"agents"`
+}
+
+// SymbolConfig defines the price dynamics of a symbol.
+// The fair value follows a geometric brownian motion with the drift and volatility per second of simulation.
+type SymbolConfig struct {
+	Symbol     string  `json:"symbol"`
+	Price      float64 `json:"price"`
+	Tick       float64 `json:"tick"`
+	Drift      float64 `json:"drift"`
+	Volatility float64 `json:"volatility"`
+}
+
+// AgentConfig defines a population of agents of a type trading a symbol with a account.
+// Orders arrive as a poisson process with the rate per second, their sizes are drawn from the size distribution.
+type AgentConfig struct {
+	Type          string               `json:"type"`
+	Name          string               `json:"name"`
+	Count         int                  `json:"count"`
+	Symbol        string               `json:"symbol"`
+	Account       *common_http.Account `json:"account"`
+	Rate          float64              `json:"rate"`
+	Size          SizeDistribution     `json:"size"`
+	MaxOpenOrders int                  `json:"max_open_orders"`
+
+	// Spread is the maximum distance in ticks of noise orders from the mid, default 5, and the half spread of market makers
+	Spread int `json:"spread"`
+	// Levels is the number of quotes per side of market makers
+	Levels int `json:"levels"`
+	// Lookback is the number of steps of the momentum and the moving average
+	Lookback int `json:"lookback"`
+	// Threshold is the relative move or deviation from the moving average which triggers a trade
+	Threshold float64 `json:"threshold"`
+	// Aggression is the number of ticks past the best price of aggressive orders
+	Aggression int `json:"aggression"`
+}
+
+// LoadScenario loads a scenario from a json file and validates it
+func LoadScenario(path string) (*Scenario, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var scenario Scenario
+
+	err = json.NewDecoder(file).Decode(&scenario)
+	if err != nil {
+		return nil, err
+	}
+
+	err = scenario.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &scenario, nil
+}
+
+// Validate checks the scenario and sets the defaults of the agents
+func (s *Scenario) Validate() error {
+
+	if s.Step <= 0 {
+		return fmt.Errorf("step_seconds %g is not positive", s.Step)
+	}
+	if s.Duration < 0 {
+		return fmt.Errorf("duration_seconds %g is negative", s.Duration)
+	}
+	if len(s.Symbols) == 0 {
+		return fmt.Errorf("scenario has no symbols")
+	}
+
+	symbols := make(map[string]bool, len(s.Symbols))
+	for _, symbol := range s.Symbols {
+		if symbol.Symbol == "" || symbol.Price <= 0 || symbol.Tick <= 0 || symbol.Volatility < 0 {
+			return fmt.Errorf("symbol %q needs a positive price and tick and a volatility", symbol.Symbol)
+		}
+		symbols[symbol.Symbol] = true
+	}
+
+	for i := range s.Agents {
+
+		agent := &s.Agents[i]
+
+		if agent.Name == "" {
+			agent.Name = fmt.Sprintf("%s-%d", agent.Type, i)
+		}
+		if !symbols[agent.Symbol] {
+			return fmt.Errorf("agent %s trades the unknown symbol %q", agent.Name, agent.Symbol)
+		}
+		if agent.Account == nil && s.Account == nil {
+			return fmt.Errorf("agent %s has no account", agent.Name)
+		}
+		if agent.Account == nil {
+			agent.Account = s.Account
+		}
+		if agent.Count <= 0 {
+			agent.Count = 1
+		}
+		if agent.Rate < 0 {
+			return fmt.Errorf("agent %s has a negative rate", agent.Name)
+		}
+		if agent.MaxOpenOrders <= 0 {
+			agent.MaxOpenOrders = 10
+		}
+
+		err := agent.Size.Validate()
+		if err != nil {
+			return fmt.Errorf("agent %s: %s", agent.Name, err)
+		}
+
+		switch agent.Type {
+		case NoiseTrader:
+			if agent.Spread <= 0 {
+				agent.Spread = 5
+			}
+		case MarketMaker:
+			if agent.Spread <= 0 || agent.Levels <= 0 {
+				return fmt.Errorf("market maker %s needs a positive spread and levels", agent.Name)
+			}
+		case MomentumTrader, MeanReversionTrader:
+			if agent.Lookback <= 0 || agent.Threshold <= 0 {
+				return fmt.Errorf("agent %s needs a positive lookback and threshold", agent.Name)
+			}
+		default:
+			return fmt.Errorf("agent %s has the unknown type %q", agent.Name, agent.Type)
+		}
+	}
+
+	if s.Account == nil && len(s.Agents) > 0 {
+		s.Account = s.Agents[0].Account
+	}
+	if s.Account == nil {
+		return fmt.Errorf("scenario has no account")
+	}
+
+	return nil
+}
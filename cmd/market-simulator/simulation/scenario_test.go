@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func TestLoadScenario(t *testing.T) {
+
+	require := require.New(t)
+
+	scenario, err := LoadScenario("../scenario.json")
+	require.Nil(err)
+	require.Equal(int64(42), scenario.Seed)
+	require.Len(scenario.Symbols, 1)
+	require.Len(scenario.Agents, 4)
+
+	require.Equal("market-maker-key", scenario.Agents[0].Account.APIKey)
+	require.Equal("demo-key", scenario.Agents[1].Account.APIKey)
+	require.Equal(3, scenario.Agents[1].Count)
+	require.Equal(uint(1), scenario.Agents[1].Size.Lot)
+	require.Equal(10, scenario.Agents[0].MaxOpenOrders)
+
+	_, err = LoadScenario("unknown.json")
+	require.NotNil(err)
+}
+
+func newTestScenario() *Scenario {
+	return &Scenario{
+		Seed:    7,
+		Step:    1,
+		Account: &common_http.Account{APIKey: "KEY1"},
+		Symbols: []SymbolConfig{{Symbol: "TT", Price: 100, Tick: 0.01, Volatility: 0.001}},
+		Agents: []AgentConfig{
+			{Type: MarketMaker, Symbol: "TT", Size: SizeDistribution{Type: FixedSize, Mean: 100}, Spread: 2, Levels: 2},
+			{Type: NoiseTrader, Symbol: "TT", Count: 2, Rate: 2, Size: SizeDistribution{Type: UniformSize, Min: 1, Max: 20}},
+			{Type: MomentumTrader, Symbol: "TT", Rate: 1, Size: SizeDistribution{Type: FixedSize, Mean: 10}, Lookback: 2, Threshold: 0.0001},
+			{Type: MeanReversionTrader, Symbol: "TT", Rate: 1, Size: SizeDistribution{Type: FixedSize, Mean: 10}, Lookback: 3, Threshold: 0.0001},
+		},
+	}
+}
+
+func TestScenarioValidate(t *testing.T) {
+
+	require := require.New(t)
+
+	scenario := newTestScenario()
+	require.Nil(scenario.Validate())
+	require.Equal("noise-1", scenario.Agents[1].Name)
+	require.Equal(5, scenario.Agents[1].Spread)
+	require.Equal(scenario.Account, scenario.Agents[2].Account)
+
+	tests := []struct {
+		change func(s *Scenario)
+		err    string
+	}{
+		{func(s *Scenario) { s.Step = 0 }, "step_seconds 0 is not positive"},
+		{func(s *Scenario) { s.Symbols = nil }, "scenario has no symbols"},
+		{func(s *Scenario) { s.Symbols[0].Tick = 0 }, `symbol "TT" needs a positive price and tick and a volatility`},
+		{func(s *Scenario) { s.Agents[1].Symbol = "XX" }, `agent noise-1 trades the unknown symbol "XX"`},
+		{func(s *Scenario) { s.Agents[1].Type = "whale" }, `agent whale-1 has the unknown type "whale"`},
+		{func(s *Scenario) { s.Agents[0].Levels = 0 }, "market maker market_maker-0 needs a positive spread and levels"},
+		{func(s *Scenario) { s.Agents[2].Lookback = 0 }, "agent momentum-2 needs a positive lookback and threshold"},
+		{func(s *Scenario) { s.Agents[3].Size.Type = "" }, `agent mean_reversion-3: unknown size distribution ""`},
+		{func(s *Scenario) { s.Account = nil }, "agent market_maker-0 has no account"},
+	}
+
+	for _, test := range tests {
+		scenario := newTestScenario()
+		test.change(scenario)
+		require.EqualError(scenario.Validate(), test.err)
+	}
+}
@@ -0,0 +1,158 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/client"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// Simulator runs the agents of a scenario against the exchange.
+// In every step the fair values move, the books are read and the agents act in the order of the scenario.
+type Simulator struct {
+	scenario *Scenario
+	exchange Exchange
+	symbols  []string
+	markets  map[string]*Market
+	agents   []Agent
+	symbolOf map[Agent]string
+	random   *rand.Rand
+	steps    int
+}
+
+// NewSimulator creates a new simulator of the scenario, the exchange function returns the exchange of a account.
+// Every agent gets its own random source seeded from the seed of the scenario.
+func NewSimulator(scenario *Scenario, exchange func(account *common_http.Account) Exchange) (*Simulator, error) {
+
+	history := 1
+	for _, config := range scenario.Agents {
+		if config.Lookback > history {
+			history = config.Lookback
+		}
+	}
+
+	random := rand.New(rand.NewSource(scenario.Seed))
+	s := &Simulator{scenario, exchange(scenario.Account), nil, make(map[string]*Market, len(scenario.Symbols)), nil, make(map[Agent]string), random, 0}
+
+	for _, config := range scenario.Symbols {
+		s.symbols = append(s.symbols, config.Symbol)
+		s.markets[config.Symbol] = NewMarket(config, history)
+	}
+
+	exchanges := make(map[string]Exchange)
+
+	for _, config := range scenario.Agents {
+
+		e, ok := exchanges[config.Account.APIKey]
+		if !ok {
+			e = exchange(config.Account)
+			exchanges[config.Account.APIKey] = e
+		}
+
+		for i := 0; i < config.Count; i++ {
+			agent, err := NewAgent(config, fmt.Sprintf("%s-%d", config.Name, i), e, rand.New(rand.NewSource(random.Int63())))
+			if err != nil {
+				return nil, err
+			}
+			s.agents = append(s.agents, agent)
+			s.symbolOf[agent] = config.Symbol
+		}
+	}
+
+	return s, nil
+}
+
+// Market returns the market of the symbol
+func (s *Simulator) Market(symbol string) *Market {
+	return s.markets[symbol]
+}
+
+// Agents returns the agents in the order they act
+func (s *Simulator) Agents() []Agent {
+	return s.agents
+}
+
+// Step advances the simulation by the step of the scenario
+func (s *Simulator) Step(ctx context.Context) error {
+
+	for _, symbol := range s.symbols {
+
+		market := s.markets[symbol]
+		market.Fair.Next(s.random, s.scenario.Step)
+
+		book, err := s.exchange.GetBook(ctx, symbol)
+		if client.StatusCode(err) == http.StatusNotFound {
+			book, err = &handlers.SymbolResponse{Symbol: symbol}, nil
+		}
+		if err != nil {
+			return err
+		}
+		market.Update(book)
+	}
+
+	for _, agent := range s.agents {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		agent.Act(ctx, s.markets[s.symbolOf[agent]], s.scenario.Step)
+	}
+
+	s.steps++
+	return nil
+}
+
+// Run steps in real time until the duration of the scenario, when it is set, or the end of the context
+func (s *Simulator) Run(ctx context.Context) error {
+
+	step := time.Duration(s.scenario.Step * float64(time.Second))
+	ticker := time.NewTicker(step)
+	defer ticker.Stop()
+
+	var deadline <-chan time.Time
+	if s.scenario.Duration > 0 {
+		deadline = time.After(time.Duration(s.scenario.Duration * float64(time.Second)))
+	}
+
+	report := time.NewTicker(time.Minute)
+	defer report.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.Report()
+			return nil
+		case <-deadline:
+			s.Report()
+			return nil
+		case <-report.C:
+			s.Report()
+		case <-ticker.C:
+			err := s.Step(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Step %d failed! %s", s.steps, err)
+			}
+		}
+	}
+}
+
+// Report logs the fair values and mids of the markets and the requests of the agents
+func (s *Simulator) Report() {
+
+	log.Printf("Simulated %d steps", s.steps)
+
+	for _, symbol := range s.symbols {
+		market := s.markets[symbol]
+		log.Printf("%s fair value %.4f mid %.4f bid %.4f ask %.4f", symbol, market.Fair.Value, market.Mid(), market.BestBid, market.BestAsk)
+	}
+
+	for _, agent := range s.agents {
+		stats := agent.Stats()
+		log.Printf("%s orders %d cancels %d errors %d", agent.Name(), stats.Orders, stats.Cancels, stats.Errors)
+	}
+}
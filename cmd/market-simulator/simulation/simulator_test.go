@@ -0,0 +1,233 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+// mockExchange rests every order without matching and returns their prices as the book
+type mockExchange struct {
+	orders    map[string]handlers.OrderDTO
+	created   []handlers.OrderDTO
+	cancelled []string
+	book      *handlers.SymbolResponse
+}
+
+func newMockExchange() *mockExchange {
+	return &mockExchange{orders: map[string]handlers.OrderDTO{}}
+}
+
+func (m *mockExchange) GetBook(ctx context.Context, symbol string) (*handlers.SymbolResponse, error) {
+
+	if m.book != nil {
+		return m.book, nil
+	}
+
+	prices := map[float64]*handlers.SymbolPriceResponse{}
+	for _, order := range m.orders {
+		price, ok := prices[order.Price]
+		if !ok {
+			price = &handlers.SymbolPriceResponse{Price: order.Price}
+			prices[order.Price] = price
+		}
+		if order.Direction == models.BuyText {
+			price.BuyQuantity += order.Quantity
+		} else {
+			price.SellQuantity += order.Quantity
+		}
+	}
+
+	book := handlers.SymbolResponse{Symbol: symbol}
+	for _, price := range prices {
+		book.Prices = append(book.Prices, *price)
+	}
+	return &book, nil
+}
+
+func (m *mockExchange) CreateOrder(ctx context.Context, order handlers.OrderDTO) (*handlers.OrderIDResponse, error) {
+	id := fmt.Sprintf("O%d", len(m.created)+1)
+	m.created = append(m.created, order)
+	m.orders[id] = order
+	return &handlers.OrderIDResponse{ID: id}, nil
+}
+
+func (m *mockExchange) CancelOrder(ctx context.Context, id string) error {
+	if _, ok := m.orders[id]; !ok {
+		return errors.New("unknown order")
+	}
+	delete(m.orders, id)
+	m.cancelled = append(m.cancelled, id)
+	return nil
+}
+
+func newTestSimulator(t *testing.T, scenario *Scenario) (*Simulator, *mockExchange) {
+
+	require.Nil(t, scenario.Validate())
+
+	exchange := newMockExchange()
+	simulator, err := NewSimulator(scenario, func(account *common_http.Account) Exchange { return exchange })
+	require.Nil(t, err)
+	return simulator, exchange
+}
+
+func TestSimulatorStep(t *testing.T) {
+
+	require := require.New(t)
+
+	simulator, exchange := newTestSimulator(t, newTestScenario())
+	require.Len(simulator.Agents(), 5)
+	require.Equal("market_maker-0-0", simulator.Agents()[0].Name())
+	require.Equal("noise-1-1", simulator.Agents()[2].Name())
+
+	ctx := context.Background()
+	err := simulator.Step(ctx)
+	require.Nil(err)
+
+	market := simulator.Market("TT")
+	require.NotEqual(100.0, market.Fair.Value)
+	require.Zero(market.BestBid)
+
+	fair := market.Fair.Value
+	require.Equal(handlers.OrderDTO{Symbol: "TT", Quantity: 100, Direction: models.BuyText, Price: market.Price(fair, -2)}, exchange.created[0])
+	require.Equal(handlers.OrderDTO{Symbol: "TT", Quantity: 100, Direction: models.SellText, Price: market.Price(fair, 2)}, exchange.created[1])
+	require.Equal(market.Price(fair, -3), exchange.created[2].Price)
+	require.Equal(market.Price(fair, 3), exchange.created[3].Price)
+
+	for i := 0; i < 20; i++ {
+		err = simulator.Step(ctx)
+		require.Nil(err)
+	}
+	require.True(market.BestBid > 0 && market.BestAsk > 0)
+
+	stats := simulator.Agents()[0].Stats()
+	require.Equal(21*4, stats.Orders)
+	require.Equal(20*4, stats.Cancels)
+
+	for _, agent := range simulator.Agents()[1:3] {
+		require.True(agent.Stats().Orders > 0, agent.Name())
+	}
+	require.True(len(exchange.orders) <= 4+4*10, "the agents keep at most their max open orders")
+}
+
+func TestSimulatorReproducible(t *testing.T) {
+
+	require := require.New(t)
+
+	run := func(seed int64) []handlers.OrderDTO {
+		scenario := newTestScenario()
+		scenario.Seed = seed
+		simulator, exchange := newTestSimulator(t, scenario)
+		for i := 0; i < 30; i++ {
+			require.Nil(simulator.Step(context.Background()))
+		}
+		return exchange.created
+	}
+
+	first := run(11)
+	require.Equal(first, run(11))
+	require.NotEqual(first, run(12))
+}
+
+func TestMarket(t *testing.T) {
+
+	require := require.New(t)
+
+	market := NewMarket(SymbolConfig{Symbol: "TT", Price: 10, Tick: 0.05}, 2)
+	require.Equal(10.0, market.Mid())
+	require.Equal(10.15, market.Price(10.01, 3))
+	require.Equal(0.05, market.Price(0.2, -10))
+
+	_, ok := market.Momentum(1)
+	require.False(ok)
+
+	market.Update(&handlers.SymbolResponse{Prices: []handlers.SymbolPriceResponse{{Price: 9.9, BuyQuantity: 1}, {Price: 10.1, SellQuantity: 1}, {Price: 10.2, SellQuantity: 1}}})
+	require.Equal(9.9, market.BestBid)
+	require.Equal(10.1, market.BestAsk)
+	require.InDelta(10.0, market.Mid(), 1e-9)
+
+	market.Update(&handlers.SymbolResponse{Prices: []handlers.SymbolPriceResponse{{Price: 10.5, SellQuantity: 1}}})
+	require.Zero(market.BestBid)
+	require.Equal(10.5, market.Mid())
+
+	momentum, ok := market.Momentum(1)
+	require.True(ok)
+	require.InDelta(0.05, momentum, 1e-9)
+
+	_, ok = market.Momentum(2)
+	require.False(ok)
+
+	market.Update(&handlers.SymbolResponse{Prices: []handlers.SymbolPriceResponse{{Price: 10.5, BuyQuantity: 1}}})
+	deviation, ok := market.Deviation(2)
+	require.True(ok)
+	require.InDelta(0, deviation, 1e-9)
+
+	momentum, ok = market.Momentum(2)
+	require.True(ok)
+	require.InDelta(0.05, momentum, 1e-9)
+}
+
+func TestAgents(t *testing.T) {
+
+	require := require.New(t)
+
+	ctx := context.Background()
+	market := NewMarket(SymbolConfig{Symbol: "TT", Price: 10, Tick: 0.1}, 3)
+	size := SizeDistribution{Type: FixedSize, Mean: 5, Lot: 1}
+
+	newAgent := func(config AgentConfig) (Agent, *mockExchange) {
+		config.Size = size
+		if config.MaxOpenOrders == 0 {
+			config.MaxOpenOrders = 10
+		}
+		exchange := newMockExchange()
+		agent, err := NewAgent(config, config.Type, exchange, rand.New(rand.NewSource(1)))
+		require.Nil(err)
+		return agent, exchange
+	}
+
+	for _, mid := range []float64{10, 10, 10.5} {
+		market.Update(&handlers.SymbolResponse{Prices: []handlers.SymbolPriceResponse{{Price: mid - 0.1, BuyQuantity: 1}, {Price: mid + 0.1, SellQuantity: 1}}})
+	}
+
+	momentum, exchange := newAgent(AgentConfig{Type: MomentumTrader, Rate: 100, Lookback: 2, Threshold: 0.01, Aggression: 1, MaxOpenOrders: 3})
+	momentum.Act(ctx, market, 0.1)
+	require.True(len(exchange.created) > 3)
+	require.Equal(handlers.OrderDTO{Symbol: "TT", Quantity: 5, Direction: models.BuyText, Price: 10.7}, exchange.created[0])
+	require.Len(exchange.orders, 3)
+	require.Equal("O1", exchange.cancelled[0])
+
+	reversion, exchange := newAgent(AgentConfig{Type: MeanReversionTrader, Rate: 100, Lookback: 3, Threshold: 0.01})
+	reversion.Act(ctx, market, 0.1)
+	require.Equal(handlers.OrderDTO{Symbol: "TT", Quantity: 5, Direction: models.SellText, Price: 10.4}, exchange.created[0])
+
+	calm, exchange := newAgent(AgentConfig{Type: MeanReversionTrader, Rate: 100, Lookback: 3, Threshold: 0.1})
+	calm.Act(ctx, market, 0.1)
+	require.Empty(exchange.created)
+
+	noise, exchange := newAgent(AgentConfig{Type: NoiseTrader, Rate: 100, Spread: 2})
+	noise.Act(ctx, market, 0.1)
+	require.True(len(exchange.created) > 3)
+	for _, order := range exchange.created {
+		require.True(order.Price >= 10.3-1e-9 && order.Price <= 10.7+1e-9, "price %g", order.Price)
+	}
+
+	quoter, exchange := newAgent(AgentConfig{Type: MarketMaker, Rate: 0, Spread: 1, Levels: 1})
+	quoter.Act(ctx, market, 0.1)
+	quoter.Act(ctx, market, 0.1)
+	require.Len(exchange.created, 4)
+	require.Equal([]string{"O1", "O2"}, exchange.cancelled)
+	require.Equal(9.9, exchange.created[2].Price)
+	require.Equal(10.1, exchange.created[3].Price)
+	require.Equal(Stats{4, 2, 0}, quoter.Stats())
+
+	_, err := NewAgent(AgentConfig{Type: "whale"}, "whale", newMockExchange(), rand.New(rand.NewSource(1)))
+	require.NotNil(err)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/client"
+	"github.com/tradsim/tradsim-go/cmd/order-replay/replay"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+func main() {
+
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.Ldate | log.Lmicroseconds | log.LUTC | log.Lshortfile)
+	log.SetPrefix("or ")
+
+	path := flag.String("file", "", "file of the instructions, CSV or JSONL")
+	format := flag.String("format", "", "csv or jsonl, defaults to the format of the file extension")
+	speed := flag.Float64("speed", 1, "multiple of the recorded speed, 0 sends as fast as possible")
+	exchangeURL := flag.String("exchange", "http://localhost:8081", "exchange service url")
+	apiKey := flag.String("api-key", os.Getenv("TRADSIM_API_KEY"), "api key of the account")
+	secret := flag.String("secret", os.Getenv("TRADSIM_SECRET"), "secret of the account, requests are signed when set")
+	prefix := flag.String("client-order-id-prefix", "", "prefix of the client order ids of the created orders, client order ids are not sent when empty")
+	rejects := flag.Int("rejects", 20, "rejects listed in the report")
+	flag.Parse()
+
+	if *path == "" || *speed < 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = replay.GetFormat(*path)
+	}
+
+	instructions, err := replay.LoadInstructions(*path, *format)
+	if err != nil {
+		log.Fatalf("Failed to load instructions! %s", err)
+	}
+
+	account := &common_http.Account{APIKey: *apiKey, Secret: *secret}
+	exchange := client.NewClient(*exchangeURL, account, common_http.NewRestClientImpl(5*time.Second), client.DefaultRetryPolicy)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, syscall.SIGTERM)
+	go func() {
+		<-c
+		cancel()
+	}()
+
+	log.Printf("Replaying %d instructions of %s at speed %g.", len(instructions), *path, *speed)
+
+	report, err := replay.NewReplayer(exchange, *speed, *prefix).Replay(ctx, instructions)
+	if err != nil {
+		log.Printf("Replay stopped! %s", err)
+	}
+
+	report.Write(os.Stdout, *rejects)
+}
@@ -0,0 +1,288 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// Actions of the instructions
+const (
+	NewAction    = "new"
+	AmendAction  = "amend"
+	CancelAction = "cancel"
+)
+
+// File formats of the instructions
+const (
+	CSVFormat   = "csv"
+	JSONLFormat = "jsonl"
+)
+
+// Columns of the CSV header, which can be in any order
+var columns = []string{"timestamp", "action", "symbol", "side", "price", "quantity", "id"}
+
+// Instruction is a recorded order instruction, the id is the external id of the order
+type Instruction struct {
+	Line      int
+	Timestamp time.Time
+	Action    string
+	Symbol    string
+	Direction string
+	Price     float64
+	Quantity  uint
+	ID        string
+}
+
+// record is a line of a JSONL file, the timestamp is RFC 3339 or unix milliseconds
+type record struct {
+	Timestamp json.RawMessage `json:"timestamp"`
+	Action    string          `json:"action"`
+	Symbol    string          `json:"symbol"`
+	Side      string          `json:"side"`
+	Price     float64         `json:"price"`
+	Quantity  uint            `json:"quantity"`
+	ID        string          `json:"id"`
+}
+
+// GetFormat returns the format of the file extension, .jsonl and .json are JSONL and everything else CSV
+func GetFormat(path string) string {
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".json":
+		return JSONLFormat
+	}
+	return CSVFormat
+}
+
+// LoadInstructions reads the instructions of a file in the format
+func LoadInstructions(path string, format string) ([]Instruction, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch format {
+	case CSVFormat:
+		return ReadCSV(file)
+	case JSONLFormat:
+		return ReadJSONL(file)
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// ReadCSV reads instructions from CSV with a header of the columns timestamp, action, symbol, side, price, quantity and id
+func ReadCSV(reader io.Reader) ([]Instruction, error) {
+
+	r := csv.NewReader(reader)
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %s", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range columns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("header has no %s column", column)
+		}
+	}
+
+	var instructions []Instruction
+
+	for {
+
+		fields, err := r.Read()
+		if err == io.EOF {
+			return instructions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+
+		value := func(column string) string { return strings.TrimSpace(fields[index[column]]) }
+
+		instruction, err := newInstruction(line, value("timestamp"), value("action"), value("symbol"), value("side"), value("price"), value("quantity"), value("id"))
+		if err != nil {
+			return nil, err
+		}
+
+		err = instruction.validate()
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, instruction)
+	}
+}
+
+// ReadJSONL reads instructions from JSON objects, one per line, with the fields of the CSV columns
+func ReadJSONL(reader io.Reader) ([]Instruction, error) {
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var instructions []Instruction
+
+	for line := 1; scanner.Scan(); line++ {
+
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		var r record
+		err := json.Unmarshal(scanner.Bytes(), &r)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+
+		instruction, err := newInstruction(line, strings.Trim(string(r.Timestamp), `"`), r.Action, r.Symbol, r.Side, "", "", r.ID)
+		if err != nil {
+			return nil, err
+		}
+		instruction.Price = r.Price
+		instruction.Quantity = r.Quantity
+
+		err = instruction.validate()
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, instruction)
+	}
+
+	return instructions, scanner.Err()
+}
+
+// InstructionWriter writes instructions as CSV with a header or as JSONL, which the readers read back
+type InstructionWriter struct {
+	format string
+	csv    *csv.Writer
+	json   *json.Encoder
+	header bool
+}
+
+// NewInstructionWriter creates a new instruction writer of the format
+func NewInstructionWriter(writer io.Writer, format string) *InstructionWriter {
+	return &InstructionWriter{format, csv.NewWriter(writer), json.NewEncoder(writer), false}
+}
+
+// Write writes a instruction, the price and quantity of cancels are left empty
+func (w *InstructionWriter) Write(instruction Instruction) error {
+
+	timestamp := instruction.Timestamp.UTC().Format(time.RFC3339Nano)
+	side := strings.ToLower(instruction.Direction)
+	price, quantity := "", ""
+	if instruction.Action != CancelAction {
+		price = strconv.FormatFloat(instruction.Price, 'f', -1, 64)
+		quantity = strconv.FormatUint(uint64(instruction.Quantity), 10)
+	}
+
+	if w.format == JSONLFormat {
+		encoded, _ := json.Marshal(timestamp)
+		return w.json.Encode(record{encoded, instruction.Action, instruction.Symbol, side, instruction.Price, instruction.Quantity, instruction.ID})
+	}
+
+	if !w.header {
+		w.header = true
+		err := w.csv.Write(columns)
+		if err != nil {
+			return err
+		}
+	}
+	return w.csv.Write([]string{timestamp, instruction.Action, instruction.Symbol, side, price, quantity, instruction.ID})
+}
+
+// Flush writes the buffered CSV
+func (w *InstructionWriter) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// newInstruction parses the fields of a instruction, empty side, price and quantity are left empty
+func newInstruction(line int, timestamp, action, symbol, side, price, quantity, id string) (Instruction, error) {
+
+	instruction := Instruction{Line: line, Symbol: strings.ToUpper(symbol), ID: id}
+	var err error
+
+	instruction.Timestamp, err = parseTimestamp(timestamp)
+	if err != nil {
+		return instruction, fmt.Errorf("line %d: invalid timestamp %q", line, timestamp)
+	}
+
+	switch strings.ToLower(action) {
+	case "new", "create", "add":
+		instruction.Action = NewAction
+	case "amend", "modify", "replace":
+		instruction.Action = AmendAction
+	case "cancel", "delete":
+		instruction.Action = CancelAction
+	default:
+		return instruction, fmt.Errorf("line %d: unknown action %q", line, action)
+	}
+
+	switch strings.ToLower(side) {
+	case "buy", "b":
+		instruction.Direction = models.BuyText
+	case "sell", "s":
+		instruction.Direction = models.SellText
+	case "":
+	default:
+		return instruction, fmt.Errorf("line %d: side %q is not buy or sell", line, side)
+	}
+
+	if price != "" {
+		instruction.Price, err = strconv.ParseFloat(price, 64)
+		if err != nil {
+			return instruction, fmt.Errorf("line %d: invalid price %q", line, price)
+		}
+	}
+
+	if quantity != "" {
+		parsed, err := strconv.ParseUint(quantity, 10, 32)
+		if err != nil {
+			return instruction, fmt.Errorf("line %d: invalid quantity %q", line, quantity)
+		}
+		instruction.Quantity = uint(parsed)
+	}
+
+	return instruction, nil
+}
+
+// validate checks the fields which the action requires
+func (i Instruction) validate() error {
+
+	if i.ID == "" {
+		return fmt.Errorf("line %d: id is required", i.Line)
+	}
+	if i.Action == CancelAction {
+		return nil
+	}
+	if i.Symbol == "" || i.Direction == "" {
+		return fmt.Errorf("line %d: %s requires a symbol and a side", i.Line, i.Action)
+	}
+	return nil
+}
+
+// parseTimestamp parses RFC 3339 or unix milliseconds
+func parseTimestamp(value string) (time.Time, error) {
+
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(0, millis*int64(time.Millisecond)).UTC(), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
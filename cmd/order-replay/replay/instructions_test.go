@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+var expectedInstructions = []Instruction{
+	{2, time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC), NewAction, "TT", models.BuyText, 1.99, 10, "A1"},
+	{3, time.Date(2020, 1, 1, 10, 0, 0, 500000000, time.UTC), NewAction, "TT", models.SellText, 2.01, 5, "A2"},
+	{4, time.Date(2020, 1, 1, 10, 0, 1, 0, time.UTC), AmendAction, "TT", models.BuyText, 1.99, 20, "A1"},
+	{5, time.Date(2020, 1, 1, 10, 0, 1, 250000000, time.UTC), CancelAction, "TT", "", 0, 0, "A2"},
+	{6, time.Date(2020, 1, 1, 10, 0, 2, 0, time.UTC), CancelAction, "TT", "", 0, 0, "A9"},
+}
+
+func TestLoadInstructions(t *testing.T) {
+
+	require := require.New(t)
+
+	instructions, err := LoadInstructions("testdata/session.csv", GetFormat("testdata/session.csv"))
+	require.Nil(err)
+	require.Equal(expectedInstructions, instructions)
+
+	instructions, err = LoadInstructions("testdata/session.jsonl", GetFormat("testdata/session.jsonl"))
+	require.Nil(err)
+	require.Len(instructions, 5)
+	for i := range instructions {
+		expected := expectedInstructions[i]
+		expected.Line = instructions[i].Line
+		if expected.Action == CancelAction {
+			expected.Symbol = ""
+		}
+		require.Equal(expected, instructions[i])
+	}
+	require.Equal(5, instructions[3].Line)
+
+	_, err = LoadInstructions("testdata/session.csv", "xml")
+	require.EqualError(err, `unknown format "xml"`)
+}
+
+func TestReadCSVErrors(t *testing.T) {
+
+	require := require.New(t)
+
+	tests := []struct {
+		csv string
+		err string
+	}{
+		{"timestamp,action,symbol,side,price,quantity\n", "header has no id column"},
+		{"id,timestamp,action,symbol,side,price,quantity\nA1,yesterday,new,TT,buy,1,1\n", `line 2: invalid timestamp "yesterday"`},
+		{"id,timestamp,action,symbol,side,price,quantity\nA1,0,trade,TT,buy,1,1\n", `line 2: unknown action "trade"`},
+		{"id,timestamp,action,symbol,side,price,quantity\nA1,0,new,TT,up,1,1\n", `line 2: side "up" is not buy or sell`},
+		{"id,timestamp,action,symbol,side,price,quantity\nA1,0,new,TT,buy,one,1\n", `line 2: invalid price "one"`},
+		{"id,timestamp,action,symbol,side,price,quantity\nA1,0,new,TT,buy,1,-1\n", `line 2: invalid quantity "-1"`},
+		{"id,timestamp,action,symbol,side,price,quantity\nA1,0,new,TT,buy,1,1\n,0,new,TT,buy,1,1\n", "line 3: id is required"},
+		{"id,timestamp,action,symbol,side,price,quantity\nA1,0,amend,TT,,1,1\n", "line 2: amend requires a symbol and a side"},
+	}
+
+	for _, test := range tests {
+		_, err := ReadCSV(strings.NewReader(test.csv))
+		require.EqualError(err, test.err)
+	}
+
+	instructions, err := ReadCSV(strings.NewReader("ID, Timestamp, Action, Symbol, Side, Price, Quantity\nA1,1000,cancel,,,,\n"))
+	require.Nil(err)
+	require.Equal([]Instruction{{2, time.Unix(1, 0).UTC(), CancelAction, "", "", 0, 0, "A1"}}, instructions)
+}
+
+func TestReadJSONLErrors(t *testing.T) {
+
+	require := require.New(t)
+
+	_, err := ReadJSONL(strings.NewReader(`{"timestamp":0,"action":"new","id":"A1","symbol":"TT","side":"buy"}` + "\n{\n"))
+	require.NotNil(err)
+	require.True(strings.HasPrefix(err.Error(), "line 2: "))
+
+	_, err = ReadJSONL(strings.NewReader(`{"timestamp":0,"action":"new","id":"A1","side":"buy"}`))
+	require.EqualError(err, "line 1: new requires a symbol and a side")
+}
+
+func TestInstructionWriter(t *testing.T) {
+
+	require := require.New(t)
+
+	for _, format := range []string{CSVFormat, JSONLFormat} {
+
+		var buffer strings.Builder
+		writer := NewInstructionWriter(&buffer, format)
+		for _, instruction := range expectedInstructions {
+			require.Nil(writer.Write(instruction))
+		}
+		require.Nil(writer.Flush())
+
+		var instructions []Instruction
+		var err error
+		if format == CSVFormat {
+			require.True(strings.HasPrefix(buffer.String(), "timestamp,action,symbol,side,price,quantity,id\n2020-01-01T10:00:00Z,new,TT,buy,1.99,10,A1\n"))
+			instructions, err = ReadCSV(strings.NewReader(buffer.String()))
+		} else {
+			instructions, err = ReadJSONL(strings.NewReader(buffer.String()))
+		}
+		require.Nil(err)
+		require.Len(instructions, len(expectedInstructions))
+		for i, instruction := range instructions {
+			expected := expectedInstructions[i]
+			expected.Line = instruction.Line
+			require.Equal(expected, instruction, format)
+		}
+	}
+}
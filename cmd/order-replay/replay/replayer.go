@@ -0,0 +1,209 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+)
+
+// Exchange defines the calls of the exchange-service which the replay uses, it is implemented by *client.Client
+type Exchange interface {
+	CreateOrder(ctx context.Context, order handlers.OrderDTO) (*handlers.OrderIDResponse, error)
+	AmendOrder(ctx context.Context, order handlers.OrderDTO) error
+	CancelOrder(ctx context.Context, id string) error
+}
+
+// UnknownIDReason rejects amends and cancels of external ids without a accepted order
+const UnknownIDReason = "unknown id"
+
+// Reject is a instruction which was not sent or was rejected by the exchange
+type Reject struct {
+	Line   int
+	Action string
+	ID     string
+	Reason string
+}
+
+// Report summarizes a replay.
+// Drift is how late the instructions were sent compared to their recorded time scaled by the speed.
+type Report struct {
+	Instructions int
+	Sent         int
+	Accepted     map[string]int
+	Rejects      []Reject
+	OutOfOrder   int
+	Recorded     time.Duration
+	Elapsed      time.Duration
+	MeanDrift    time.Duration
+	MaxDrift     time.Duration
+}
+
+// Replayer sends instructions to the exchange keeping the recorded pace scaled by the speed, a speed of zero sends as fast as possible.
+// The external ids are mapped to the ids of the created orders for the amends and cancels.
+type Replayer struct {
+	exchange Exchange
+	speed    float64
+	prefix   string
+	ids      map[string]string
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+// NewReplayer creates a new replayer, created orders get the prefix and the external id as client order id when the prefix is not empty
+func NewReplayer(exchange Exchange, speed float64, prefix string) *Replayer {
+	return &Replayer{exchange, speed, prefix, make(map[string]string), time.Now, sleep}
+}
+
+// Replay sends the instructions in order and returns the report, which is partial when the context ends
+func (r *Replayer) Replay(ctx context.Context, instructions []Instruction) (*Report, error) {
+
+	report := &Report{Instructions: len(instructions), Accepted: make(map[string]int)}
+	if len(instructions) == 0 {
+		return report, nil
+	}
+
+	start := r.now()
+	first := instructions[0].Timestamp
+	last := first
+	var drift time.Duration
+	processed := 0
+
+	defer func() {
+		report.Elapsed = r.now().Sub(start)
+		if processed > 0 {
+			report.MeanDrift = drift / time.Duration(processed)
+		}
+	}()
+
+	for _, instruction := range instructions {
+
+		if instruction.Timestamp.Before(last) {
+			report.OutOfOrder++
+		} else {
+			last = instruction.Timestamp
+		}
+		report.Recorded = last.Sub(first)
+
+		due := start
+		if r.speed > 0 {
+			due = start.Add(time.Duration(float64(instruction.Timestamp.Sub(first)) / r.speed))
+			if wait := due.Sub(r.now()); wait > 0 {
+				err := r.sleep(ctx, wait)
+				if err != nil {
+					return report, err
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		processed++
+		if r.speed > 0 {
+			late := r.now().Sub(due)
+			if late < 0 {
+				late = 0
+			}
+			drift += late
+			if late > report.MaxDrift {
+				report.MaxDrift = late
+			}
+		}
+
+		err := r.send(ctx, instruction, report)
+		if err != nil {
+			report.Rejects = append(report.Rejects, Reject{instruction.Line, instruction.Action, instruction.ID, err.Error()})
+			continue
+		}
+		report.Accepted[instruction.Action]++
+	}
+
+	return report, nil
+}
+
+// send sends the instruction, amends and cancels of unknown external ids are not sent
+func (r *Replayer) send(ctx context.Context, instruction Instruction, report *Report) error {
+
+	if instruction.Action == NewAction {
+
+		order := handlers.OrderDTO{Symbol: instruction.Symbol, Quantity: instruction.Quantity, Direction: instruction.Direction, Price: instruction.Price}
+		if r.prefix != "" {
+			order.ClientOrderID = r.prefix + instruction.ID
+		}
+
+		report.Sent++
+		response, err := r.exchange.CreateOrder(ctx, order)
+		if err != nil {
+			return err
+		}
+		r.ids[instruction.ID] = response.ID
+		return nil
+	}
+
+	id, ok := r.ids[instruction.ID]
+	if !ok {
+		return errors.New(UnknownIDReason)
+	}
+
+	report.Sent++
+	if instruction.Action == CancelAction {
+		return r.exchange.CancelOrder(ctx, id)
+	}
+	return r.exchange.AmendOrder(ctx, handlers.OrderDTO{ID: id, Symbol: instruction.Symbol, Quantity: instruction.Quantity, Direction: instruction.Direction, Price: instruction.Price})
+}
+
+// Write writes the report with the rejects counted by reason and the first rejects listed
+func (r *Report) Write(w io.Writer, rejects int) {
+
+	fmt.Fprintf(w, "instructions %d sent %d accepted new %d amend %d cancel %d rejected %d\n", r.Instructions, r.Sent,
+		r.Accepted[NewAction], r.Accepted[AmendAction], r.Accepted[CancelAction], len(r.Rejects))
+	fmt.Fprintf(w, "recorded %s replayed %s drift mean %s max %s\n", r.Recorded, r.Elapsed, r.MeanDrift, r.MaxDrift)
+	if r.OutOfOrder > 0 {
+		fmt.Fprintf(w, "out of order %d\n", r.OutOfOrder)
+	}
+
+	reasons := make(map[string]int)
+	for _, reject := range r.Rejects {
+		reasons[reject.Reason]++
+	}
+	sorted := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		sorted = append(sorted, reason)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if reasons[sorted[i]] != reasons[sorted[j]] {
+			return reasons[sorted[i]] > reasons[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+	for _, reason := range sorted {
+		fmt.Fprintf(w, "  %6d  %s\n", reasons[reason], reason)
+	}
+
+	for i, reject := range r.Rejects {
+		if i == rejects {
+			fmt.Fprintf(w, "... %d more rejects\n", len(r.Rejects)-rejects)
+			break
+		}
+		fmt.Fprintf(w, "line %d %s %s: %s\n", reject.Line, reject.Action, reject.ID, reject.Reason)
+	}
+}
+
+// sleep waits for the duration or the end of the context
+func sleep(ctx context.Context, d time.Duration) error {
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
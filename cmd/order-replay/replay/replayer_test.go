@@ -0,0 +1,169 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+)
+
+type call struct {
+	at     time.Duration
+	action string
+	order  handlers.OrderDTO
+}
+
+// mockExchange records the calls at the time of the clock, orders without a quantity are rejected
+type mockExchange struct {
+	clock *fakeClock
+	calls []call
+	// latency is added to the clock by every call
+	latency time.Duration
+}
+
+func (m *mockExchange) record(action string, order handlers.OrderDTO) {
+	m.calls = append(m.calls, call{m.clock.elapsed(), action, order})
+	m.clock.current = m.clock.current.Add(m.latency)
+}
+
+func (m *mockExchange) CreateOrder(ctx context.Context, order handlers.OrderDTO) (*handlers.OrderIDResponse, error) {
+	m.record(NewAction, order)
+	if order.Quantity == 0 {
+		return nil, errors.New("400 ValidationFailed: invalid order")
+	}
+	return &handlers.OrderIDResponse{ID: fmt.Sprintf("X%d", len(m.calls))}, nil
+}
+
+func (m *mockExchange) AmendOrder(ctx context.Context, order handlers.OrderDTO) error {
+	m.record(AmendAction, order)
+	return nil
+}
+
+func (m *mockExchange) CancelOrder(ctx context.Context, id string) error {
+	m.record(CancelAction, handlers.OrderDTO{ID: id})
+	return nil
+}
+
+type fakeClock struct {
+	start   time.Time
+	current time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.current
+}
+
+func (c *fakeClock) sleep(ctx context.Context, d time.Duration) error {
+	c.current = c.current.Add(d)
+	return ctx.Err()
+}
+
+func (c *fakeClock) elapsed() time.Duration {
+	return c.current.Sub(c.start)
+}
+
+func newTestReplayer(speed float64, prefix string) (*Replayer, *mockExchange, *fakeClock) {
+
+	clock := &fakeClock{time.Unix(1000, 0), time.Unix(1000, 0)}
+	exchange := &mockExchange{clock: clock}
+
+	replayer := NewReplayer(exchange, speed, prefix)
+	replayer.now = clock.now
+	replayer.sleep = clock.sleep
+
+	return replayer, exchange, clock
+}
+
+func TestReplay(t *testing.T) {
+
+	require := require.New(t)
+
+	replayer, exchange, _ := newTestReplayer(1, "R-")
+
+	report, err := replayer.Replay(context.Background(), expectedInstructions)
+	require.Nil(err)
+
+	require.Equal([]call{
+		{0, NewAction, handlers.OrderDTO{Symbol: "TT", Quantity: 10, Direction: "Buy", Price: 1.99, ClientOrderID: "R-A1"}},
+		{500 * time.Millisecond, NewAction, handlers.OrderDTO{Symbol: "TT", Quantity: 5, Direction: "Sell", Price: 2.01, ClientOrderID: "R-A2"}},
+		{time.Second, AmendAction, handlers.OrderDTO{ID: "X1", Symbol: "TT", Quantity: 20, Direction: "Buy", Price: 1.99}},
+		{1250 * time.Millisecond, CancelAction, handlers.OrderDTO{ID: "X2"}},
+	}, exchange.calls)
+
+	require.Equal(5, report.Instructions)
+	require.Equal(4, report.Sent)
+	require.Equal(map[string]int{NewAction: 2, AmendAction: 1, CancelAction: 1}, report.Accepted)
+	require.Equal([]Reject{{6, CancelAction, "A9", UnknownIDReason}}, report.Rejects)
+	require.Equal(2*time.Second, report.Recorded)
+	require.Equal(2*time.Second, report.Elapsed)
+	require.Zero(report.MaxDrift)
+}
+
+func TestReplaySpeed(t *testing.T) {
+
+	require := require.New(t)
+
+	replayer, exchange, _ := newTestReplayer(4, "")
+	exchange.latency = 200 * time.Millisecond
+
+	report, err := replayer.Replay(context.Background(), expectedInstructions)
+	require.Nil(err)
+
+	require.Equal(time.Duration(0), exchange.calls[0].at)
+	require.Equal(200*time.Millisecond, exchange.calls[1].at, "the second order is due at 125ms")
+	require.Equal(400*time.Millisecond, exchange.calls[2].at, "the amend is due at 250ms")
+	require.Equal("", exchange.calls[0].order.ClientOrderID)
+	require.Equal(300*time.Millisecond, report.MaxDrift)
+	require.Equal(162500*time.Microsecond, report.MeanDrift)
+
+	replayer, exchange, _ = newTestReplayer(0, "")
+	exchange.latency = time.Millisecond
+
+	report, err = replayer.Replay(context.Background(), expectedInstructions)
+	require.Nil(err)
+	require.Equal(3*time.Millisecond, exchange.calls[3].at)
+	require.Equal(4*time.Millisecond, report.Elapsed)
+	require.Zero(report.MeanDrift)
+}
+
+func TestReplayRejectsAndCancellation(t *testing.T) {
+
+	require := require.New(t)
+
+	instructions := append([]Instruction{}, expectedInstructions...)
+	instructions[0].Quantity = 0
+	instructions[3].Timestamp = instructions[0].Timestamp
+
+	replayer, _, _ := newTestReplayer(1, "")
+	report, err := replayer.Replay(context.Background(), instructions)
+	require.Nil(err)
+	require.Equal(1, report.OutOfOrder)
+	require.Equal([]Reject{
+		{2, NewAction, "A1", "400 ValidationFailed: invalid order"},
+		{4, AmendAction, "A1", UnknownIDReason},
+		{6, CancelAction, "A9", UnknownIDReason},
+	}, report.Rejects)
+
+	var out bytes.Buffer
+	report.Write(&out, 1)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Equal("instructions 5 sent 3 accepted new 1 amend 0 cancel 1 rejected 3", lines[0])
+	require.Equal("out of order 1", lines[2])
+	require.Equal([]string{"2", "unknown", "id"}, strings.Fields(lines[3]))
+	require.Equal("line 2 new A1: 400 ValidationFailed: invalid order", lines[5])
+	require.Equal("... 2 more rejects", lines[6])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	replayer, exchange, _ := newTestReplayer(1, "")
+	report, err = replayer.Replay(ctx, expectedInstructions)
+	require.Equal(context.Canceled, err)
+	require.Empty(exchange.calls)
+	require.Equal(0, report.Sent)
+}
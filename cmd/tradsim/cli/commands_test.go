@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+	"github.com/tradsim/tradsim-go/models"
+	common_http "github.com/tradsim/tradsim-go/net/http"
+)
+
+type recordedRequest struct {
+	method string
+	uri    string
+	apiKey string
+	body   string
+}
+
+// startExchange starts a server which records the requests and answers them with the canned responses of the paths
+func startExchange(t *testing.T, responses map[string]interface{}) (string, *[]recordedRequest) {
+
+	var requests []recordedRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{r.Method, r.URL.RequestURI(), r.Header.Get(common_http.APIKeyHeader), string(body)})
+
+		response, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			common_http.WriteNotFound(w, r, "not found")
+			return
+		}
+		if response == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		encoded, _ := json.Marshal(response)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(encoded)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, &requests
+}
+
+func run(args ...string) (string, error) {
+	var out bytes.Buffer
+	err := Run(context.Background(), args, &out)
+	return out.String(), err
+}
+
+func TestSubmit(t *testing.T) {
+
+	require := require.New(t)
+
+	url, requests := startExchange(t, map[string]interface{}{
+		"POST /orders": handlers.OrderIDResponse{ID: "0f8fad5b-d9cb-469f-a165-70867728950e", ClientOrderID: "C1"},
+	})
+
+	out, err := run("submit", "-url", url, "-api-key", "KEY1", "-symbol", "tt", "-side", "buy", "-qty", "10", "-price", "1.99", "-client-order-id", "C1")
+	require.Nil(err)
+	require.Equal("accepted 0f8fad5b-d9cb-469f-a165-70867728950e C1\n", out)
+
+	require.Len(*requests, 1)
+	request := (*requests)[0]
+	require.Equal("/orders", request.uri)
+	require.Equal("KEY1", request.apiKey)
+
+	var order handlers.OrderDTO
+	err = json.Unmarshal([]byte(request.body), &order)
+	require.Nil(err)
+	require.Equal(handlers.OrderDTO{Symbol: "TT", Quantity: 10, Direction: models.BuyText, Price: 1.99, ClientOrderID: "C1"}, order)
+
+	out, err = run("submit", "-url", url, "-json", "-symbol", "TT", "-side", "S", "-qty", "1", "-price", "2")
+	require.Nil(err)
+	require.Equal(`{"id":"0f8fad5b-d9cb-469f-a165-70867728950e","client_order_id":"C1"}`+"\n", out)
+
+	_, err = run("submit", "-url", url, "-symbol", "TT", "-side", "up", "-qty", "1", "-price", "2")
+	require.EqualError(err, `side "up" is not buy or sell`)
+	require.Len(*requests, 2)
+}
+
+func TestAmendAndCancel(t *testing.T) {
+
+	require := require.New(t)
+
+	url, requests := startExchange(t, map[string]interface{}{
+		"PUT /orders":      nil,
+		"DELETE /orders":   nil,
+		"DELETE /orders/1": nil,
+	})
+
+	out, err := run("amend", "-url", url, "-client-order-id", "C1", "-symbol", "TT", "-side", "buy", "-qty", "20", "-price", "1.99")
+	require.Nil(err)
+	require.Equal("amend accepted C1\n", out)
+	require.Equal(http.MethodPut, (*requests)[0].method)
+
+	_, err = run("amend", "-url", url, "-symbol", "TT", "-side", "buy", "-qty", "20", "-price", "1.99")
+	require.NotNil(err)
+
+	out, err = run("cancel", "-url", url, "1")
+	require.Nil(err)
+	require.Equal("cancel accepted 1\n", out)
+	require.Equal("/orders/1", (*requests)[1].uri)
+
+	_, err = run("cancel", "-url", url, "-client-order-id", "C1")
+	require.Nil(err)
+	require.Equal("/orders?client_order_id=C1", (*requests)[2].uri)
+
+	_, err = run("cancel", "-url", url, "2")
+	require.Equal("404 NotFound: not found", err.Error())
+}
+
+func TestSymbolsAndBook(t *testing.T) {
+
+	require := require.New(t)
+
+	url, _ := startExchange(t, map[string]interface{}{
+		"GET /orderbook": []handlers.SymbolResponse{
+			{Symbol: "XX", Prices: []handlers.SymbolPriceResponse{{Price: 2.5, SellQuantity: 5, SellDepth: 1}}},
+			{Symbol: "TT", Prices: []handlers.SymbolPriceResponse{{Price: 1.99, BuyQuantity: 10, BuyDepth: 2}, {Price: 2.01, SellQuantity: 4, SellDepth: 1}}},
+		},
+		"GET /orderbook/TT/snapshot": handlers.BookSnapshotResponse{
+			Symbol:   "TT",
+			Sequence: 7,
+			Bids:     []marketdata.Level{{Side: marketdata.BidSide, Price: 1.99, Quantity: 10, Orders: 2}, {Side: marketdata.BidSide, Price: 1.98, Quantity: 3, Orders: 1}},
+			Asks:     []marketdata.Level{{Side: marketdata.AskSide, Price: 2.01, Quantity: 4, Orders: 1}},
+		},
+	})
+
+	out, err := run("symbols", "-url", url)
+	require.Nil(err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(lines, 3)
+	require.Equal([]string{"TT", "2", "10", "4"}, strings.Fields(lines[1]))
+	require.Equal([]string{"XX", "1", "0", "5"}, strings.Fields(lines[2]))
+
+	out, err = run("book", "-url", url, "-levels", "1", "tt")
+	require.Nil(err)
+	lines = strings.Split(strings.TrimSpace(out), "\n")
+	require.Equal("TT  sequence 7", lines[0])
+	require.Len(lines, 5)
+	require.Equal([]string{"2.0100", "4", "1"}, strings.Fields(lines[2]))
+	require.Equal([]string{"spread", "0.0200"}, strings.Fields(lines[3]))
+	require.Equal([]string{"2", "10", "1.9900"}, strings.Fields(lines[4]))
+
+	out, err = run("book", "-url", url, "-json", "TT")
+	require.Nil(err)
+	var snapshot handlers.BookSnapshotResponse
+	err = json.Unmarshal([]byte(out), &snapshot)
+	require.Nil(err)
+	require.Len(snapshot.Bids, 2)
+}
+
+func TestTailFile(t *testing.T) {
+
+	require := require.New(t)
+
+	out, err := run("tail", "-file", "testdata/events.jsonl")
+	require.Nil(err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(lines, 5)
+	require.True(strings.HasPrefix(lines[0], "OrderAccepted: [O1]"))
+	require.True(strings.HasPrefix(lines[3], "invalid Unknown event"))
+
+	out, err = run("tail", "-file", "testdata/events.jsonl", "-account", "ACC1", "-symbol", "tt")
+	require.Nil(err)
+	require.Len(strings.Split(strings.TrimSpace(out), "\n"), 2)
+
+	out, err = run("tail", "-file", "testdata/events.jsonl", "-type", "OrderCancelled", "-json")
+	require.Nil(err)
+	require.Equal(`{"event_type":"OrderCancelled","id":"O2","account":"ACC2","occured":"2020-01-01T10:00:03Z","version":2}`+"\n", out)
+}
+
+func TestRunUsage(t *testing.T) {
+
+	require := require.New(t)
+
+	out, err := run()
+	require.Equal(ErrUsage, err)
+	require.Equal(Usage, out)
+
+	_, err = run("trade")
+	require.Equal(ErrUsage, err)
+
+	_, err = run("book", "-unknown")
+	require.Equal(ErrUsage, err)
+}
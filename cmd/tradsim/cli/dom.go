@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/client"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// DOMHelp describes the keys of the depth of market viewer
+const DOMHelp = "up/k down/j move  b buy  s sell  c cancel level  x cancel all  +/- quantity  space centre  r refresh  q quit"
+
+// Key is a keystroke of the terminal, arrow keys are mapped to their own keys
+type Key rune
+
+// Special keys
+const (
+	KeyUp   Key = -1
+	KeyDown Key = -2
+	KeyQuit Key = 3 // ctrl-c
+)
+
+// Exchange defines the calls of the exchange-service which the depth of market viewer uses, it is implemented by *client.Client
+type Exchange interface {
+	GetBook(ctx context.Context, symbol string) (*handlers.SymbolResponse, error)
+	GetTrades(ctx context.Context, symbol string, query client.TradesQuery) (*handlers.TradesResponse, error)
+	GetOrders(ctx context.Context, query client.OrdersQuery) (*handlers.OrdersResponse, error)
+	CreateOrder(ctx context.Context, order handlers.OrderDTO) (*handlers.OrderIDResponse, error)
+	CancelOrder(ctx context.Context, id string) error
+}
+
+// DOM is the state of the depth of market viewer of a symbol.
+// The ladder is a grid of prices, one tick per row, with the cursor marking the price of new orders and cancels.
+type DOM struct {
+	exchange Exchange
+	symbol   string
+	tick     float64
+	decimals int
+	levels   int
+	lot      uint
+	quantity uint
+	trades   int
+
+	prices   map[int64]handlers.SymbolPriceResponse
+	tape     []handlers.TradeResponse
+	after    uint64
+	orders   []handlers.OrderResponse
+	cursor   int64
+	centre   int64
+	centred  bool
+	status   string
+	quitting bool
+}
+
+// NewDOM creates a new depth of market viewer of the symbol, which shows levels ticks above and below the centre
+// and the last trades of the tape. Orders are placed with the lot quantity which +/- change by a lot.
+func NewDOM(exchange Exchange, symbol string, tick float64, levels int, lot uint, trades int) *DOM {
+	return &DOM{exchange, symbol, tick, getDecimals(tick), levels, lot, lot, trades,
+		map[int64]handlers.SymbolPriceResponse{}, nil, 0, nil, 0, 0, false, "", false}
+}
+
+// SetCursor moves the cursor and the centre of the ladder to the price
+func (d *DOM) SetCursor(price float64) {
+	d.cursor = d.toTick(price)
+	d.centre = d.cursor
+	d.centred = true
+}
+
+// Quitting returns true after the quit key
+func (d *DOM) Quitting() bool {
+	return d.quitting
+}
+
+// Refresh gets the book, the new trades and the open orders of the symbol
+func (d *DOM) Refresh(ctx context.Context) error {
+
+	book, err := d.exchange.GetBook(ctx, d.symbol)
+	if client.StatusCode(err) == http.StatusNotFound {
+		book, err = &handlers.SymbolResponse{Symbol: d.symbol}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.prices = make(map[int64]handlers.SymbolPriceResponse, len(book.Prices))
+	for _, price := range book.Prices {
+		d.prices[d.toTick(price.Price)] = price
+	}
+
+	err = d.refreshTrades(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = d.refreshOrders(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !d.centred {
+		if price, ok := d.reference(); ok {
+			d.SetCursor(price)
+		}
+	}
+	return nil
+}
+
+// refreshTrades pages through the trades after the last seen and keeps the latest, newest first
+func (d *DOM) refreshTrades(ctx context.Context) error {
+
+	for {
+		response, err := d.exchange.GetTrades(ctx, d.symbol, client.TradesQuery{After: d.after, Limit: handlers.MaxTradesLimit})
+		if err != nil {
+			return err
+		}
+
+		latest := make([]handlers.TradeResponse, 0, len(response.Trades)+len(d.tape))
+		for i := len(response.Trades) - 1; i >= 0; i-- {
+			latest = append(latest, response.Trades[i])
+		}
+		d.tape = append(latest, d.tape...)
+		if len(d.tape) > d.trades {
+			d.tape = d.tape[:d.trades]
+		}
+
+		d.after = response.NextSequence
+		if len(response.Trades) < handlers.MaxTradesLimit {
+			return nil
+		}
+	}
+}
+
+// refreshOrders gets the open orders of the account in the symbol
+func (d *DOM) refreshOrders(ctx context.Context) error {
+
+	d.orders = nil
+	query := client.OrdersQuery{Symbol: d.symbol, Limit: handlers.MaxOrdersLimit}
+
+	for {
+		response, err := d.exchange.GetOrders(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		for _, order := range response.Orders {
+			if order.Status == models.PendingText || order.Status == models.PartiallyFilledText {
+				d.orders = append(d.orders, order)
+			}
+		}
+
+		if len(response.Orders) < query.Limit || response.NextAfter == "" {
+			return nil
+		}
+		query.After = response.NextAfter
+	}
+}
+
+// reference returns the price to centre the ladder on, the mid of the best prices or else the last trade
+func (d *DOM) reference() (float64, bool) {
+
+	bid, ask := int64(math.MinInt64), int64(math.MaxInt64)
+	for tick, price := range d.prices {
+		if price.BuyQuantity > 0 && tick > bid {
+			bid = tick
+		}
+		if price.SellQuantity > 0 && tick < ask {
+			ask = tick
+		}
+	}
+
+	switch {
+	case bid != math.MinInt64 && ask != math.MaxInt64:
+		return d.toPrice((bid + ask) / 2), true
+	case bid != math.MinInt64:
+		return d.toPrice(bid), true
+	case ask != math.MaxInt64:
+		return d.toPrice(ask), true
+	case len(d.tape) > 0:
+		return d.tape[0].Price, true
+	}
+	return 0, false
+}
+
+// HandleKey applies a keystroke, orders are placed and cancelled at the cursor
+func (d *DOM) HandleKey(ctx context.Context, key Key) {
+
+	switch key {
+	case KeyUp, 'k':
+		d.move(1)
+	case KeyDown, 'j':
+		d.move(-1)
+	case '+', '=':
+		d.quantity += d.lot
+		d.status = fmt.Sprintf("quantity %d", d.quantity)
+	case '-', '_':
+		if d.quantity > d.lot {
+			d.quantity -= d.lot
+		}
+		d.status = fmt.Sprintf("quantity %d", d.quantity)
+	case ' ':
+		if price, ok := d.reference(); ok {
+			d.SetCursor(price)
+		}
+	case 'b', 'B':
+		d.place(ctx, models.BuyText)
+	case 's', 'S':
+		d.place(ctx, models.SellText)
+	case 'c', 'C':
+		d.cancel(ctx, d.levelOrders(d.cursor))
+	case 'x', 'X':
+		d.cancel(ctx, d.orders)
+	case 'r', 'R':
+		d.status = ""
+	case 'q', 'Q', KeyQuit:
+		d.quitting = true
+		return
+	default:
+		return
+	}
+
+	err := d.Refresh(ctx)
+	if err != nil {
+		d.status = fmt.Sprintf("refresh failed: %s", err)
+	}
+}
+
+// move moves the cursor by ticks and scrolls the ladder to keep it visible
+func (d *DOM) move(ticks int64) {
+
+	d.cursor += ticks
+	if d.cursor > d.centre+int64(d.levels) {
+		d.centre = d.cursor - int64(d.levels)
+	}
+	if d.cursor < d.centre-int64(d.levels) {
+		d.centre = d.cursor + int64(d.levels)
+	}
+}
+
+func (d *DOM) place(ctx context.Context, direction string) {
+
+	order := handlers.OrderDTO{Symbol: d.symbol, Quantity: d.quantity, Direction: direction, Price: d.toPrice(d.cursor)}
+
+	response, err := d.exchange.CreateOrder(ctx, order)
+	if err != nil {
+		d.status = fmt.Sprintf("%s %d@%s failed: %s", direction, order.Quantity, d.format(d.cursor), err)
+		return
+	}
+	d.status = fmt.Sprintf("%s %d@%s accepted %s", direction, order.Quantity, d.format(d.cursor), response.ID)
+}
+
+func (d *DOM) cancel(ctx context.Context, orders []handlers.OrderResponse) {
+
+	if len(orders) == 0 {
+		d.status = "no orders to cancel"
+		return
+	}
+
+	cancelled := 0
+	for _, order := range orders {
+		err := d.exchange.CancelOrder(ctx, order.ID)
+		if err != nil {
+			d.status = fmt.Sprintf("cancel %s failed: %s", order.ID, err)
+			return
+		}
+		cancelled++
+	}
+	d.status = fmt.Sprintf("cancel accepted for %d orders", cancelled)
+}
+
+// levelOrders returns the open orders at the price of the tick
+func (d *DOM) levelOrders(tick int64) []handlers.OrderResponse {
+
+	var orders []handlers.OrderResponse
+	for _, order := range d.orders {
+		if d.toTick(order.Price) == tick {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
+// Render returns the lines of the screen: the ladder with the own quantities, the recent trades and the open orders
+func (d *DOM) Render() []string {
+
+	lines := []string{
+		fmt.Sprintf("%s  quantity %d  tick %s", d.symbol, d.quantity, strconv.FormatFloat(d.tick, 'f', -1, 64)),
+		"",
+		fmt.Sprintf("  %6s %8s %8s %12s %8s %6s  %s", "ORDERS", "MINE", "BID QTY", "PRICE", "ASK QTY", "ORDERS", "MINE"),
+	}
+
+	for tick := d.centre + int64(d.levels); tick >= d.centre-int64(d.levels); tick-- {
+
+		cursor := " "
+		if tick == d.cursor {
+			cursor = ">"
+		}
+
+		price := d.prices[tick]
+
+		var myBuy, mySell uint
+		for _, order := range d.levelOrders(tick) {
+			if order.Direction == models.BuyText {
+				myBuy += order.Remaining
+			} else {
+				mySell += order.Remaining
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %6s %8s %8s %12s %8s %6s  %s", cursor,
+			blank(price.BuyDepth), blank(myBuy), blank(price.BuyQuantity), d.format(tick), blank(price.SellQuantity), blank(price.SellDepth), blank(mySell)))
+	}
+
+	lines = append(lines, "", "RECENT TRADES")
+	for _, trade := range d.tape {
+		lines = append(lines, fmt.Sprintf("  %s %12s %8d  %s", trade.Time.Format("15:04:05.000"), d.format(d.toTick(trade.Price)), trade.Quantity, trade.Aggressor))
+	}
+
+	orders := append([]handlers.OrderResponse{}, d.orders...)
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Price > orders[j].Price })
+
+	lines = append(lines, "", "MY ORDERS")
+	for _, order := range orders {
+		lines = append(lines, fmt.Sprintf("  %s %-4s %d@%s remaining %d %s", order.ID, order.Direction, order.Quantity, d.format(d.toTick(order.Price)), order.Remaining, order.Status))
+	}
+
+	return append(lines, "", d.status, DOMHelp)
+}
+
+func (d *DOM) toTick(price float64) int64 {
+	return int64(math.Round(price / d.tick))
+}
+
+func (d *DOM) toPrice(tick int64) float64 {
+	price, _ := strconv.ParseFloat(d.format(tick), 64)
+	return price
+}
+
+func (d *DOM) format(tick int64) string {
+	return strconv.FormatFloat(float64(tick)*d.tick, 'f', d.decimals, 64)
+}
+
+// getDecimals returns the decimals of the tick size
+func getDecimals(tick float64) int {
+
+	text := strconv.FormatFloat(tick, 'f', -1, 64)
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		return len(text) - i - 1
+	}
+	return 0
+}
+
+// blank formats the quantity, leaving zero empty
+func blank(quantity uint) string {
+	if quantity == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(quantity), 10)
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/client"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+type mockExchange struct {
+	book      handlers.SymbolResponse
+	trades    []handlers.TradeResponse
+	orders    []handlers.OrderResponse
+	created   []handlers.OrderDTO
+	cancelled []string
+}
+
+func (m *mockExchange) GetBook(ctx context.Context, symbol string) (*handlers.SymbolResponse, error) {
+	return &m.book, nil
+}
+
+func (m *mockExchange) GetTrades(ctx context.Context, symbol string, query client.TradesQuery) (*handlers.TradesResponse, error) {
+
+	response := handlers.TradesResponse{Symbol: symbol, NextSequence: query.After}
+	for _, trade := range m.trades {
+		if trade.Sequence > query.After {
+			response.Trades = append(response.Trades, trade)
+			response.NextSequence = trade.Sequence
+		}
+	}
+	return &response, nil
+}
+
+func (m *mockExchange) GetOrders(ctx context.Context, query client.OrdersQuery) (*handlers.OrdersResponse, error) {
+	return &handlers.OrdersResponse{Orders: m.orders}, nil
+}
+
+func (m *mockExchange) CreateOrder(ctx context.Context, order handlers.OrderDTO) (*handlers.OrderIDResponse, error) {
+	m.created = append(m.created, order)
+	return &handlers.OrderIDResponse{ID: "N1"}, nil
+}
+
+func (m *mockExchange) CancelOrder(ctx context.Context, id string) error {
+	m.cancelled = append(m.cancelled, id)
+	return nil
+}
+
+func newMockExchange() *mockExchange {
+	return &mockExchange{
+		book: handlers.SymbolResponse{Symbol: "TT", Prices: []handlers.SymbolPriceResponse{
+			{Price: 1.98, BuyQuantity: 10, BuyDepth: 2},
+			{Price: 2.02, SellQuantity: 4, SellDepth: 1},
+		}},
+		trades: []handlers.TradeResponse{
+			{TradeID: "T1", Sequence: 1, Price: 2.01, Quantity: 3, Aggressor: models.BuyText},
+			{TradeID: "T2", Sequence: 2, Price: 2.00, Quantity: 5, Aggressor: models.SellText},
+		},
+		orders: []handlers.OrderResponse{
+			{ID: "O1", Symbol: "TT", Direction: models.BuyText, Price: 1.98, Quantity: 6, Remaining: 6, Status: models.PendingText},
+			{ID: "O2", Symbol: "TT", Direction: models.SellText, Price: 2.02, Quantity: 4, Remaining: 1, Status: models.PartiallyFilledText},
+			{ID: "O3", Symbol: "TT", Direction: models.SellText, Price: 2.02, Quantity: 4, Status: models.FullyFilledText},
+		},
+	}
+}
+
+// row returns the fields of the ladder row of the price
+func row(lines []string, price string) []string {
+	for _, line := range lines {
+		fields := strings.Fields(strings.TrimPrefix(line, ">"))
+		for _, field := range fields {
+			if field == price {
+				return fields
+			}
+		}
+	}
+	return nil
+}
+
+func TestDOMRender(t *testing.T) {
+
+	require := require.New(t)
+
+	exchange := newMockExchange()
+	dom := NewDOM(exchange, "TT", 0.01, 3, 5, 1)
+
+	err := dom.Refresh(context.Background())
+	require.Nil(err)
+
+	lines := dom.Render()
+	require.Equal("TT  quantity 5  tick 0.01", lines[0])
+	require.Len(lines, 3+7+2+1+2+2+3)
+
+	require.True(strings.HasPrefix(lines[6], ">"))
+	require.Equal([]string{"2.00"}, strings.Fields(lines[6][1:]))
+	require.Equal([]string{"2", "6", "10", "1.98"}, row(lines, "1.98"))
+	require.Equal([]string{"2.02", "4", "1", "1"}, row(lines, "2.02"))
+	require.Equal([]string{"2.03"}, row(lines, "2.03"))
+
+	require.Equal("RECENT TRADES", lines[11])
+	require.Equal([]string{"2.00", "5", "Sell"}, strings.Fields(lines[12])[1:])
+	require.Equal("MY ORDERS", lines[14])
+	require.True(strings.HasPrefix(strings.TrimSpace(lines[15]), "O2 Sell 4@2.02 remaining 1"))
+	require.True(strings.HasPrefix(strings.TrimSpace(lines[16]), "O1 Buy  6@1.98 remaining 6"))
+	require.Equal(DOMHelp, lines[len(lines)-1])
+
+	exchange.trades = append(exchange.trades, handlers.TradeResponse{TradeID: "T3", Sequence: 3, Price: 1.99, Quantity: 1, Aggressor: models.SellText})
+	err = dom.Refresh(context.Background())
+	require.Nil(err)
+	require.Equal([]string{"1.99", "1", "Sell"}, strings.Fields(dom.Render()[12])[1:])
+}
+
+func TestDOMHandleKey(t *testing.T) {
+
+	require := require.New(t)
+
+	exchange := newMockExchange()
+	dom := NewDOM(exchange, "TT", 0.01, 1, 5, 10)
+	ctx := context.Background()
+
+	err := dom.Refresh(ctx)
+	require.Nil(err)
+
+	dom.HandleKey(ctx, KeyUp)
+	dom.HandleKey(ctx, 'k')
+	dom.HandleKey(ctx, '+')
+	dom.HandleKey(ctx, 's')
+	require.Equal([]handlers.OrderDTO{{Symbol: "TT", Quantity: 10, Direction: models.SellText, Price: 2.02}}, exchange.created)
+	require.Equal("Sell 10@2.02 accepted N1", dom.status)
+	require.True(strings.HasPrefix(dom.Render()[3], "> "), "the ladder scrolls with the cursor")
+
+	dom.HandleKey(ctx, 'c')
+	require.Equal([]string{"O2"}, exchange.cancelled)
+
+	dom.HandleKey(ctx, '-')
+	dom.HandleKey(ctx, '-')
+	dom.HandleKey(ctx, KeyDown)
+	dom.HandleKey(ctx, 'j')
+	dom.HandleKey(ctx, 'j')
+	dom.HandleKey(ctx, 'j')
+	dom.HandleKey(ctx, 'b')
+	require.Equal(handlers.OrderDTO{Symbol: "TT", Quantity: 5, Direction: models.BuyText, Price: 1.98}, exchange.created[1])
+
+	dom.HandleKey(ctx, 'c')
+	dom.HandleKey(ctx, 'j')
+	dom.HandleKey(ctx, 'c')
+	require.Equal("no orders to cancel", dom.status)
+
+	dom.HandleKey(ctx, 'x')
+	require.Equal([]string{"O2", "O1", "O1", "O2"}, exchange.cancelled)
+
+	dom.HandleKey(ctx, ' ')
+	require.Equal([]string{"2.00"}, strings.Fields(dom.Render()[4][1:]))
+
+	require.False(dom.Quitting())
+	dom.HandleKey(ctx, 'q')
+	require.True(dom.Quitting())
+}
+
+func TestReadKeys(t *testing.T) {
+
+	require := require.New(t)
+
+	keys := make(chan Key, 10)
+	ReadKeys(strings.NewReader("b\x1b[A\x1b[B\x1b[Cq\x1bx"), keys)
+
+	var read []Key
+	for key := range keys {
+		read = append(read, key)
+	}
+	require.Equal([]Key{'b', KeyUp, KeyDown, 'q', 'x'}, read)
+}
+
+func TestRunDOM(t *testing.T) {
+
+	require := require.New(t)
+
+	exchange := newMockExchange()
+	dom := NewDOM(exchange, "TT", 0.01, 2, 5, 10)
+
+	keys := make(chan Key, 2)
+	keys <- 'b'
+	keys <- 'q'
+
+	var out bytes.Buffer
+	err := RunDOM(context.Background(), dom, keys, &out, time.Hour)
+	require.Nil(err)
+	require.Len(exchange.created, 1)
+	require.Contains(out.String(), "Buy 5@2.00 accepted N1")
+	require.True(strings.HasSuffix(out.String(), clearScreen+showCursor))
+
+	close(keys)
+	err = RunDOM(context.Background(), NewDOM(exchange, "TT", 0.01, 2, 5, 10), keys, &out, time.Hour)
+	require.Nil(err)
+}
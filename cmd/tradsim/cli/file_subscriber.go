@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+
+	"github.com/tradsim/tradsim-go/events"
+)
+
+// FileEventSubscriber reads JSON envelopes, one per line, from a file or stdin.
+// It stands in for RabbitMQ when events have been captured to a file or are piped in.
+type FileEventSubscriber struct {
+	path   string
+	reader io.ReadCloser
+}
+
+// NewFileEventSubscriber creates a new file event subscriber, the path - reads stdin
+func NewFileEventSubscriber(path string) *FileEventSubscriber {
+	return &FileEventSubscriber{path: path}
+}
+
+// Open opens the file
+func (s *FileEventSubscriber) Open() error {
+
+	if s.path == "-" {
+		s.reader = os.Stdin
+		return nil
+	}
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	s.reader = file
+	return nil
+}
+
+// Close closes the file
+func (s *FileEventSubscriber) Close() {
+	if s.reader != nil && s.reader != os.Stdin {
+		s.reader.Close()
+	}
+}
+
+// Subscribe calls the handler for every envelope until the end of the file
+func (s *FileEventSubscriber) Subscribe(handler func(envelope *events.OrderEventEnvelope)) error {
+
+	scanner := bufio.NewScanner(s.reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var envelope events.OrderEventEnvelope
+
+		err := json.Unmarshal(scanner.Bytes(), &envelope)
+		if err != nil {
+			log.Printf("Failed to unmarshal envelope %s", err)
+			continue
+		}
+
+		handler(&envelope)
+	}
+
+	return scanner.Err()
+}
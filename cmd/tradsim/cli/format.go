@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/handlers"
+	"github.com/tradsim/tradsim-go/cmd/exchange-service/marketdata"
+)
+
+// Printer writes the results of the commands either human readable or as JSON, one value per line
+type Printer struct {
+	out  io.Writer
+	json bool
+}
+
+// NewPrinter creates a new printer
+func NewPrinter(out io.Writer, json bool) *Printer {
+	return &Printer{out, json}
+}
+
+// Print writes the value as JSON, or else calls the text function
+func (p *Printer) Print(value interface{}, text func(w io.Writer)) error {
+
+	if !p.json {
+		text(p.out)
+		return nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.out, string(encoded))
+	return err
+}
+
+// WriteSymbols writes a table of the symbols with their best prices
+func WriteSymbols(w io.Writer, symbols []handlers.SymbolResponse) {
+
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Symbol < symbols[j].Symbol })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "SYMBOL\tLEVELS\tBUY QTY\tSELL QTY\t")
+	for _, symbol := range symbols {
+		var buy, sell uint
+		for _, price := range symbol.Prices {
+			buy += price.BuyQuantity
+			sell += price.SellQuantity
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t\n", symbol.Symbol, len(symbol.Prices), buy, sell)
+	}
+	tw.Flush()
+}
+
+// WriteLadder writes the depth ladder of a snapshot, asks above bids with the best prices in the middle.
+// At most levels levels are written per side, zero writes every level.
+func WriteLadder(w io.Writer, snapshot *handlers.BookSnapshotResponse, levels int) {
+
+	asks := limitLevels(snapshot.Asks, levels)
+	bids := limitLevels(snapshot.Bids, levels)
+
+	fmt.Fprintf(w, "%s  sequence %d\n", snapshot.Symbol, snapshot.Sequence)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "ORDERS\tBID QTY\tPRICE\tASK QTY\tORDERS\t")
+	for i := len(asks) - 1; i >= 0; i-- {
+		fmt.Fprintf(tw, "\t\t%.4f\t%d\t%d\t\n", asks[i].Price, asks[i].Quantity, asks[i].Orders)
+	}
+	if len(asks) > 0 && len(bids) > 0 {
+		fmt.Fprintf(tw, "\t\tspread %.4f\t\t\t\n", asks[0].Price-bids[0].Price)
+	}
+	for _, bid := range bids {
+		fmt.Fprintf(tw, "%d\t%d\t%.4f\t\t\t\n", bid.Orders, bid.Quantity, bid.Price)
+	}
+	tw.Flush()
+}
+
+func limitLevels(levels []marketdata.Level, limit int) []marketdata.Level {
+	if limit > 0 && len(levels) > limit {
+		return levels[:limit]
+	}
+	return levels
+}
+
+// WriteEvent writes a line describing a event
+func WriteEvent(w io.Writer, event interface{}) {
+	fmt.Fprintln(w, describe(event))
+}
+
+// describe returns the String of the event, which the events implement on their pointers
+func describe(event interface{}) string {
+
+	value := reflect.New(reflect.TypeOf(event))
+	value.Elem().Set(reflect.ValueOf(event))
+
+	if stringer, ok := value.Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%+v", event)
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ANSI escape sequences of the screen
+const (
+	cursorHome  = "\x1b[H"
+	clearLine   = "\x1b[K"
+	clearBelow  = "\x1b[J"
+	clearScreen = "\x1b[H\x1b[2J"
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+)
+
+// ReadKeys sends the keystrokes of the reader, which is expected to be a terminal in raw mode, until it fails and then closes the keys.
+// The escape sequences of the up and down arrows are sent as KeyUp and KeyDown, other sequences are dropped.
+func ReadKeys(reader io.Reader, keys chan<- Key) {
+
+	defer close(keys)
+
+	buffered := bufio.NewReader(reader)
+
+	for {
+		r, _, err := buffered.ReadRune()
+		if err != nil {
+			return
+		}
+
+		if r != '\x1b' {
+			keys <- Key(r)
+			continue
+		}
+
+		r, _, err = buffered.ReadRune()
+		if err != nil {
+			return
+		}
+		if r != '[' && r != 'O' {
+			keys <- Key(r)
+			continue
+		}
+
+		r, _, err = buffered.ReadRune()
+		if err != nil {
+			return
+		}
+		switch r {
+		case 'A':
+			keys <- KeyUp
+		case 'B':
+			keys <- KeyDown
+		}
+	}
+}
+
+// RunDOM draws the viewer and applies the keys until the quit key, the end of the keys or the end of the context.
+// The book, trades and orders are refreshed on every tick of the refresh interval.
+func RunDOM(ctx context.Context, dom *DOM, keys <-chan Key, out io.Writer, refresh time.Duration) error {
+
+	fmt.Fprint(out, hideCursor+clearScreen)
+	defer fmt.Fprint(out, clearScreen+showCursor)
+
+	err := dom.Refresh(ctx)
+	if err != nil {
+		dom.status = fmt.Sprintf("refresh failed: %s", err)
+	}
+	draw(out, dom.Render())
+
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			dom.HandleKey(ctx, key)
+			if dom.Quitting() {
+				return nil
+			}
+		case <-ticker.C:
+			err := dom.Refresh(ctx)
+			if err != nil {
+				dom.status = fmt.Sprintf("refresh failed: %s", err)
+			}
+		}
+		draw(out, dom.Render())
+	}
+}
+
+// draw writes the lines over the previous screen, raw mode needs the carriage returns
+func draw(out io.Writer, lines []string) {
+	fmt.Fprint(out, cursorHome+strings.Join(lines, clearLine+"\r\n")+clearLine+"\r\n"+clearBelow)
+}
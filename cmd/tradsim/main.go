@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/tradsim/tradsim-go/cmd/tradsim/cli"
+)
+
+func main() {
+
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.Ldate | log.Lmicroseconds | log.LUTC | log.Lshortfile)
+	log.SetPrefix("tradsim ")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, syscall.SIGTERM)
+	go func() {
+		<-c
+		cancel()
+	}()
+
+	err := cli.Run(ctx, os.Args[1:], os.Stdout)
+	if err == cli.ErrUsage {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tradsim: %s\n", err)
+		os.Exit(1)
+	}
+}
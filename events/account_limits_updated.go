@@ -0,0 +1,43 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// AccountLimitsUpdated defines a account limits updated event
+type AccountLimitsUpdated struct {
+	EventType        OrderEventType `json:"event_type"`
+	Account          string         `json:"account"`
+	MaxLong          uint           `json:"max_long"`
+	MaxShort         uint           `json:"max_short"`
+	MaxGrossExposure float64        `json:"max_gross_exposure"`
+	DailyLossLimit   float64        `json:"daily_loss_limit"`
+	Removed          bool           `json:"removed"`
+	Occured          time.Time      `json:"occured"`
+	Version          uint           `json:"version"`
+}
+
+func (e *AccountLimitsUpdated) String() string {
+	return fmt.Sprintf("%s: [%s] %d/%d %f %f %t %s %d", e.EventType, e.Account, e.MaxLong, e.MaxShort, e.MaxGrossExposure, e.DailyLossLimit, e.Removed, e.Occured, e.Version)
+}
+
+// Limits returns the account limits of the event
+func (e *AccountLimitsUpdated) Limits() *models.AccountLimits {
+	return models.NewAccountLimits(e.Account, e.MaxLong, e.MaxShort, e.MaxGrossExposure, e.DailyLossLimit)
+}
+
+// NewAccountLimitsUpdated creates a new account limits updated event
+func NewAccountLimitsUpdated(limits *models.AccountLimits, occured time.Time, version uint) *AccountLimitsUpdated {
+
+	return &AccountLimitsUpdated{AccountLimitsUpdatedType, limits.Account, limits.MaxLong, limits.MaxShort,
+		limits.MaxGrossExposure, limits.DailyLossLimit, false, occured, version}
+}
+
+// NewAccountLimitsRemoved creates a new account limits updated event which removes the account limits
+func NewAccountLimitsRemoved(account string, occured time.Time, version uint) *AccountLimitsUpdated {
+
+	return &AccountLimitsUpdated{AccountLimitsUpdatedType, account, 0, 0, 0.0, 0.0, true, occured, version}
+}
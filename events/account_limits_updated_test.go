@@ -0,0 +1,31 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func TestAccountLimitsUpdatedString(t *testing.T) {
+
+	require := require.New(t)
+
+	l, _ := time.LoadLocation("Europe/Athens")
+	dt := time.Date(2016, 8, 13, 17, 33, 11, 111, l)
+	event := NewAccountLimitsUpdated(models.NewAccountLimits("ACC1", 100, 50, 1000.0, 10.0), dt, 1)
+
+	require.Equal("AccountLimitsUpdated: [ACC1] 100/50 1000.000000 10.000000 false 2016-08-13 17:33:11.000000111 +0300 EEST 1", event.String())
+	require.Equal(models.NewAccountLimits("ACC1", 100, 50, 1000.0, 10.0), event.Limits())
+}
+
+func TestAccountLimitsRemoved(t *testing.T) {
+
+	require := require.New(t)
+
+	event := NewAccountLimitsRemoved("ACC1", time.Now().UTC(), 1)
+
+	require.Equal("ACC1", event.Account)
+	require.True(event.Removed)
+}
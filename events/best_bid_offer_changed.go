@@ -0,0 +1,30 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// BestBidOfferChanged defines a book event of the best bid and offer, a empty side has zero price and quantity.
+// The sequence is per symbol and shared with the price level events.
+type BestBidOfferChanged struct {
+	EventType   OrderEventType `json:"event_type"`
+	Symbol      string         `json:"symbol"`
+	Sequence    uint64         `json:"sequence"`
+	BidPrice    float64        `json:"bid_price"`
+	BidQuantity uint           `json:"bid_quantity"`
+	AskPrice    float64        `json:"ask_price"`
+	AskQuantity uint           `json:"ask_quantity"`
+	Occured     time.Time      `json:"occured"`
+	Version     uint           `json:"version"`
+}
+
+func (e *BestBidOfferChanged) String() string {
+	return fmt.Sprintf("%s: [%d] %s %d@%f/%d@%f %s %d", e.EventType, e.Sequence, e.Symbol, e.BidQuantity, e.BidPrice, e.AskQuantity, e.AskPrice, e.Occured, e.Version)
+}
+
+// NewBestBidOfferChanged creates a new best bid offer changed event
+func NewBestBidOfferChanged(symbol string, sequence uint64, bidPrice float64, bidQuantity uint, askPrice float64, askQuantity uint, occured time.Time, version uint) *BestBidOfferChanged {
+
+	return &BestBidOfferChanged{BestBidOfferChangedType, symbol, sequence, bidPrice, bidQuantity, askPrice, askQuantity, occured, version}
+}
@@ -0,0 +1,35 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// EndOfDay defines the daily statistics and the official closing price of a symbol at session end
+type EndOfDay struct {
+	EventType     OrderEventType `json:"event_type"`
+	Symbol        string         `json:"symbol"`
+	Open          float64        `json:"open"`
+	High          float64        `json:"high"`
+	Low           float64        `json:"low"`
+	Last          float64        `json:"last"`
+	Close         float64        `json:"close"`
+	ClosingMethod string         `json:"closing_method"`
+	Volume        uint           `json:"volume"`
+	Turnover      float64        `json:"turnover"`
+	VWAP          float64        `json:"vwap"`
+	Trades        uint           `json:"trades"`
+	Occured       time.Time      `json:"occured"`
+	Version       uint           `json:"version"`
+}
+
+func (e *EndOfDay) String() string {
+	return fmt.Sprintf("%s: %s O:%f H:%f L:%f C:%f(%s) V:%d VWAP:%f %s %d", e.EventType, e.Symbol, e.Open, e.High, e.Low, e.Close, e.ClosingMethod, e.Volume, e.VWAP, e.Occured, e.Version)
+}
+
+// NewEndOfDay creates a new end of day event
+func NewEndOfDay(symbol string, open float64, high float64, low float64, last float64, close float64, closingMethod string,
+	volume uint, turnover float64, vwap float64, trades uint, occured time.Time, version uint) *EndOfDay {
+
+	return &EndOfDay{EndOfDayType, symbol, open, high, low, last, close, closingMethod, volume, turnover, vwap, trades, occured, version}
+}
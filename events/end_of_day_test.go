@@ -0,0 +1,19 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndOfDayString(t *testing.T) {
+
+	require := require.New(t)
+
+	l, _ := time.LoadLocation("Europe/Athens")
+	dt := time.Date(2016, 8, 13, 17, 33, 11, 111, l)
+	event := NewEndOfDay("TT", 1.0, 3.0, 0.5, 2.0, 2.5, "vwap", 100, 200.0, 2.0, 5, dt, 1)
+
+	require.Equal("EndOfDay: TT O:1.000000 H:3.000000 L:0.500000 C:2.500000(vwap) V:100 VWAP:2.000000 2016-08-13 17:33:11.000000111 +0300 EEST 1", event.String())
+}
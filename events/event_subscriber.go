@@ -0,0 +1,124 @@
+package events
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/streadway/amqp"
+)
+
+// EventSubscriber interface
+type EventSubscriber interface {
+	Open() error
+	Close()
+	Subscribe(handler func(envelope *OrderEventEnvelope)) error
+}
+
+// RabbitMqEventSubscriber subscribes to a fanout exchange with a exclusive queue,
+// so every subscriber receives every event
+type RabbitMqEventSubscriber struct {
+	channel    *amqp.Channel
+	connection *amqp.Connection
+	exchange   string
+	url        string
+}
+
+// NewRabbitMqEventSubscriber creates a new rabbit mq event subscriber
+func NewRabbitMqEventSubscriber(url string, exchange string) *RabbitMqEventSubscriber {
+
+	return &RabbitMqEventSubscriber{exchange: exchange, url: url}
+}
+
+// Open handles the opening of a connection, setting up a channel and declaring a exchange
+func (s *RabbitMqEventSubscriber) Open() error {
+
+	conn, err := amqp.Dial(s.url)
+	if err != nil {
+		return err
+	}
+	s.connection = conn
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	s.channel = ch
+
+	return ch.ExchangeDeclare(
+		s.exchange, // name
+		"fanout",   // type
+		true,       // durable
+		false,      // auto-deleted
+		false,      // internal
+		false,      // no-wait
+		nil,        // arguments
+	)
+}
+
+// Close handles the connection and channel closing
+func (s *RabbitMqEventSubscriber) Close() {
+
+	if s.channel != nil {
+		s.channel.Close()
+	}
+
+	if s.connection != nil {
+		s.connection.Close()
+	}
+}
+
+// Subscribe consumes the exchange and calls the handler for every envelope until the channel closes
+func (s *RabbitMqEventSubscriber) Subscribe(handler func(envelope *OrderEventEnvelope)) error {
+
+	q, err := s.channel.QueueDeclare(
+		"",    // name
+		false, // durable
+		true,  // delete when usused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		return err
+	}
+
+	err = s.channel.QueueBind(
+		q.Name,     // queue name
+		"",         // routing key
+		s.exchange, // exchange
+		false,
+		nil)
+	if err != nil {
+		return err
+	}
+
+	msgs, err := s.channel.Consume(
+		q.Name, // queue
+		"",     // consumer
+		true,   // auto-ack
+		true,   // exclusive
+		false,  // no-local
+		false,  // no-wait
+		nil,    // args
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("ampq: subscription on exchange %s set", s.exchange)
+
+	for d := range msgs {
+
+		var envelope OrderEventEnvelope
+
+		err := json.Unmarshal(d.Body, &envelope)
+		if err != nil {
+			log.Printf("Failed to unmarshal envelope %s", err)
+			continue
+		}
+
+		handler(&envelope)
+	}
+
+	return nil
+}
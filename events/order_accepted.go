@@ -21,7 +21,7 @@ func (e *OrderAccepted) String() string {
 }
 
 // NewOrderAccepted creates a new order accepted event
-func NewOrderAccepted(orderID string, occured time.Time, symbol string, price float64, quantity uint, direction models.TradeDirection, version uint) *OrderAccepted {
+func NewOrderAccepted(orderID string, account string, occured time.Time, symbol string, price float64, quantity uint, direction models.TradeDirection, version uint) *OrderAccepted {
 
-	return &OrderAccepted{*NewOrderEvent(OrderAcceptedType, orderID, occured, version), symbol, price, quantity, direction.String()}
+	return &OrderAccepted{*NewOrderEvent(OrderAcceptedType, orderID, account, occured, version), symbol, price, quantity, direction.String()}
 }
@@ -16,7 +16,7 @@ func (e *OrderAmended) String() string {
 }
 
 // NewOrderAmended creates a new order amed pending event
-func NewOrderAmended(orderID string, quantity uint, occured time.Time, version uint) *OrderAmended {
+func NewOrderAmended(orderID string, account string, quantity uint, occured time.Time, version uint) *OrderAmended {
 
-	return &OrderAmended{*NewOrderEvent(OrderAmendedType, orderID, occured, version), quantity}
+	return &OrderAmended{*NewOrderEvent(OrderAmendedType, orderID, account, occured, version), quantity}
 }
@@ -12,7 +12,7 @@ func (e *OrderCancelled) String() string {
 }
 
 // NewOrderCancelled creates a new order amed pending event
-func NewOrderCancelled(orderID string, occured time.Time, version uint) *OrderCancelled {
+func NewOrderCancelled(orderID string, account string, occured time.Time, version uint) *OrderCancelled {
 
-	return &OrderCancelled{*NewOrderEvent(OrderCancelledType, orderID, occured, version)}
+	return &OrderCancelled{*NewOrderEvent(OrderCancelledType, orderID, account, occured, version)}
 }
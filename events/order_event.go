@@ -9,6 +9,7 @@ import (
 type OrderEvent struct {
 	EventType OrderEventType `json:"event_type"`
 	OrderID   string         `json:"id"`
+	Account   string         `json:"account"`
 	Occured   time.Time      `json:"occured"`
 	Version   uint           `json:"version"`
 }
@@ -18,6 +19,6 @@ func (e *OrderEvent) String() string {
 }
 
 // NewOrderEvent creates a new order event
-func NewOrderEvent(eventType OrderEventType, orderID string, occured time.Time, version uint) *OrderEvent {
-	return &OrderEvent{eventType, orderID, occured, version}
+func NewOrderEvent(eventType OrderEventType, orderID string, account string, occured time.Time, version uint) *OrderEvent {
+	return &OrderEvent{eventType, orderID, account, occured, version}
 }
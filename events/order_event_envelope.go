@@ -14,7 +14,17 @@ const (
 	OrderAmendedType     OrderEventType = "OrderAmended"
 	OrderCancelledType   OrderEventType = "OrderCancelled"
 	OrderTradedType      OrderEventType = "OrderTraded"
+	OrderTradeBustedType OrderEventType = "OrderTradeBusted"
+	OrderRejectedType    OrderEventType = "OrderRejected"
 	OrderEventStoredType OrderEventType = "OrderEventStored"
+
+	PositionUpdatedType      OrderEventType = "PositionUpdated"
+	AccountLimitsUpdatedType OrderEventType = "AccountLimitsUpdated"
+
+	PriceLevelUpdatedType   OrderEventType = "PriceLevelUpdated"
+	BestBidOfferChangedType OrderEventType = "BestBidOfferChanged"
+
+	EndOfDayType OrderEventType = "EndOfDay"
 )
 
 // GetEventType returns the event type from a event
@@ -28,8 +38,22 @@ func GetEventType(event interface{}) (OrderEventType, error) {
 		return OrderCancelledType, nil
 	case OrderTraded:
 		return OrderTradedType, nil
+	case OrderTradeBusted:
+		return OrderTradeBustedType, nil
+	case OrderRejected:
+		return OrderRejectedType, nil
 	case OrderEventStored:
 		return OrderEventStoredType, nil
+	case PositionUpdated:
+		return PositionUpdatedType, nil
+	case AccountLimitsUpdated:
+		return AccountLimitsUpdatedType, nil
+	case PriceLevelUpdated:
+		return PriceLevelUpdatedType, nil
+	case BestBidOfferChanged:
+		return BestBidOfferChangedType, nil
+	case EndOfDay:
+		return EndOfDayType, nil
 	default:
 		return "", errors.New("invalid event provided")
 	}
@@ -65,8 +89,22 @@ func (e *OrderEventEnvelope) GetOrderEvent() (interface{}, error) {
 		return e.getCancelledEvent()
 	case OrderTradedType:
 		return e.getTradedEvent()
+	case OrderTradeBustedType:
+		return e.getTradeBustedEvent()
+	case OrderRejectedType:
+		return e.getRejectedEvent()
 	case OrderEventStoredType:
 		return e.getOrderEventStored()
+	case PositionUpdatedType:
+		return e.getPositionUpdated()
+	case AccountLimitsUpdatedType:
+		return e.getAccountLimitsUpdated()
+	case PriceLevelUpdatedType:
+		return e.getPriceLevelUpdated()
+	case BestBidOfferChangedType:
+		return e.getBestBidOfferChanged()
+	case EndOfDayType:
+		return e.getEndOfDay()
 	default:
 		return nil, errors.New("invalid order event type provided")
 	}
@@ -108,6 +146,24 @@ func (e *OrderEventEnvelope) getTradedEvent() (interface{}, error) {
 	return event, nil
 }
 
+func (e *OrderEventEnvelope) getTradeBustedEvent() (interface{}, error) {
+	var event OrderTradeBusted
+	err := e.getEvent(e.Payload, &event)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (e *OrderEventEnvelope) getRejectedEvent() (interface{}, error) {
+	var event OrderRejected
+	err := e.getEvent(e.Payload, &event)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
 func (e *OrderEventEnvelope) getOrderEventStored() (interface{}, error) {
 	var event OrderEventStored
 	err := e.getEvent(e.Payload, &event)
@@ -117,6 +173,51 @@ func (e *OrderEventEnvelope) getOrderEventStored() (interface{}, error) {
 	return event, nil
 }
 
+func (e *OrderEventEnvelope) getPositionUpdated() (interface{}, error) {
+	var event PositionUpdated
+	err := e.getEvent(e.Payload, &event)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (e *OrderEventEnvelope) getAccountLimitsUpdated() (interface{}, error) {
+	var event AccountLimitsUpdated
+	err := e.getEvent(e.Payload, &event)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (e *OrderEventEnvelope) getPriceLevelUpdated() (interface{}, error) {
+	var event PriceLevelUpdated
+	err := e.getEvent(e.Payload, &event)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (e *OrderEventEnvelope) getBestBidOfferChanged() (interface{}, error) {
+	var event BestBidOfferChanged
+	err := e.getEvent(e.Payload, &event)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (e *OrderEventEnvelope) getEndOfDay() (interface{}, error) {
+	var event EndOfDay
+	err := e.getEvent(e.Payload, &event)
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
 func (e *OrderEventEnvelope) getEvent(payload string, event interface{}) error {
 
 	err := json.Unmarshal([]byte(payload), &event)
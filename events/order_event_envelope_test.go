@@ -20,6 +20,13 @@ var orderEnvelopeGetErrorTests = []struct {
 	{OrderAmendedType},
 	{OrderCancelledType},
 	{OrderTradedType},
+	{OrderTradeBustedType},
+	{OrderRejectedType},
+	{PositionUpdatedType},
+	{AccountLimitsUpdatedType},
+	{PriceLevelUpdatedType},
+	{BestBidOfferChangedType},
+	{EndOfDayType},
 }
 
 func TestOrderEventEnvelopeError(t *testing.T) {
@@ -47,6 +54,13 @@ var orderEnvelopeTests = []struct {
 	{input{OrderAmended{}, OrderAmendedType}, OrderAmendedType},
 	{input{OrderCancelled{}, OrderCancelledType}, OrderCancelledType},
 	{input{OrderTraded{}, OrderTradedType}, OrderTradedType},
+	{input{OrderTradeBusted{}, OrderTradeBustedType}, OrderTradeBustedType},
+	{input{OrderRejected{}, OrderRejectedType}, OrderRejectedType},
+	{input{PositionUpdated{}, PositionUpdatedType}, PositionUpdatedType},
+	{input{AccountLimitsUpdated{}, AccountLimitsUpdatedType}, AccountLimitsUpdatedType},
+	{input{PriceLevelUpdated{}, PriceLevelUpdatedType}, PriceLevelUpdatedType},
+	{input{BestBidOfferChanged{}, BestBidOfferChangedType}, BestBidOfferChangedType},
+	{input{EndOfDay{}, EndOfDayType}, EndOfDayType},
 }
 
 func TestNewOrderEventEnvelope(t *testing.T) {
@@ -27,7 +27,8 @@ type OrderEventProcessor struct {
 	processor func(envelope *OrderEventEnvelope) (string, error)
 }
 
-// NewOrderEventProcessor creates a new order event processor
+// NewOrderEventProcessor creates a new order event processor.
+// The processor returns the order id of the processed event, a empty order id publishes no stored event.
 func NewOrderEventProcessor(url string, subExchange string, subQueue string, pubExchange string, processor func(envelope *OrderEventEnvelope) (string, error)) *OrderEventProcessor {
 	return &OrderEventProcessor{url, subExchange, subQueue, pubExchange, nil, nil, nil, processor}
 }
@@ -92,7 +93,9 @@ func (p *OrderEventProcessor) Process() error {
 			log.Printf("Failed to process envelope %s", err)
 		} else {
 			d.Ack(false)
-			p.publishOrderEventStored(orderID)
+			if orderID != "" {
+				p.publishOrderEventStored(orderID, p.getAccount(&envelope))
+			}
 		}
 	}
 
@@ -200,8 +203,17 @@ func (p *OrderEventProcessor) setupPublishChannel(pubExchange string) (*amqp.Cha
 	return ch, nil
 }
 
-func (p *OrderEventProcessor) publishOrderEventStored(orderID string) error {
-	event := NewOrderEventStored(orderID, time.Now().UTC(), 1)
+func (p *OrderEventProcessor) getAccount(envelope *OrderEventEnvelope) string {
+	var event OrderEvent
+	err := json.Unmarshal([]byte(envelope.Payload), &event)
+	if err != nil {
+		return ""
+	}
+	return event.Account
+}
+
+func (p *OrderEventProcessor) publishOrderEventStored(orderID string, account string) error {
+	event := NewOrderEventStored(orderID, account, time.Now().UTC(), 1)
 	envelope, err := NewOrderEventEnvelope(event, event.EventType)
 	if err != nil {
 		return err
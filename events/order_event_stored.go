@@ -12,7 +12,7 @@ func (e *OrderEventStored) String() string {
 }
 
 // NewOrderEventStored creates a new order event stored event
-func NewOrderEventStored(orderID string, occured time.Time, version uint) *OrderEventStored {
+func NewOrderEventStored(orderID string, account string, occured time.Time, version uint) *OrderEventStored {
 
-	return &OrderEventStored{*NewOrderEvent(OrderEventStoredType, orderID, occured, version)}
+	return &OrderEventStored{*NewOrderEvent(OrderEventStoredType, orderID, account, occured, version)}
 }
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// RejectReason defines the reason code of a rejection
+type RejectReason string
+
+// Reject reason constants
+const (
+	InvalidOrderReason      RejectReason = "InvalidOrder"
+	AppendFailedReason      RejectReason = "AppendFailed"
+	AmendFailedReason       RejectReason = "AmendFailed"
+	UnknownOrderReason      RejectReason = "UnknownOrder"
+	OrderNotTradeableReason RejectReason = "OrderNotTradeable"
+	LimitExceededReason     RejectReason = "LimitExceeded"
+	NotOwnerReason          RejectReason = "NotOwner"
+	DuplicateOrderReason    RejectReason = "DuplicateOrder"
+	SymbolHaltedReason      RejectReason = "SymbolHalted"
+	UnknownSymbolReason     RejectReason = "UnknownSymbol"
+)
+
+// IsFinal returns true if the rejection leaves the order out of the book
+func (r RejectReason) IsFinal() bool {
+	switch r {
+	case InvalidOrderReason, AppendFailedReason, LimitExceededReason, SymbolHaltedReason, UnknownSymbolReason:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderRejected defines a order rejected event
+type OrderRejected struct {
+	OrderEvent
+	Reason RejectReason `json:"reason"`
+	Text   string       `json:"text"`
+}
+
+func (e *OrderRejected) String() string {
+	return fmt.Sprintf("%s %s %s", e.OrderEvent.String(), e.Reason, e.Text)
+}
+
+// NewOrderRejected creates a new order rejected event
+func NewOrderRejected(orderID string, account string, occured time.Time, reason RejectReason, text string, version uint) *OrderRejected {
+
+	return &OrderRejected{*NewOrderEvent(OrderRejectedType, orderID, account, occured, version), reason, text}
+}
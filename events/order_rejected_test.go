@@ -0,0 +1,42 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRejectedString(t *testing.T) {
+
+	require := require.New(t)
+
+	l, _ := time.LoadLocation("Europe/Athens")
+	dt := time.Date(2016, 8, 13, 17, 33, 11, 111, l)
+	event := NewOrderRejected("d1de4242-6620-4030-b2a7-4a701631c3ba", "ACC1", dt, AmendFailedReason, "Price 1.990000 not found", 1)
+
+	require.Equal("OrderRejected: [d1de4242-6620-4030-b2a7-4a701631c3ba] 2016-08-13 17:33:11.000000111 +0300 EEST 1 AmendFailed Price 1.990000 not found", event.String())
+}
+
+var rejectReasonFinalTests = []struct {
+	in  RejectReason
+	out bool
+}{
+	{InvalidOrderReason, true},
+	{AppendFailedReason, true},
+	{AmendFailedReason, false},
+	{UnknownOrderReason, false},
+	{OrderNotTradeableReason, false},
+	{LimitExceededReason, true},
+	{NotOwnerReason, false},
+	{SymbolHaltedReason, true},
+	{UnknownSymbolReason, true},
+}
+
+func TestRejectReasonIsFinal(t *testing.T) {
+
+	for _, tt := range rejectReasonFinalTests {
+
+		require.Equal(t, tt.out, tt.in.IsFinal(), "Expected %t for %s", tt.out, tt.in)
+	}
+}
@@ -0,0 +1,26 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderTradeBusted defines a order trade busted event, the trade of the order is void.
+// Both orders of a busted trade publish one with the trade id, price and quantity of the trade.
+type OrderTradeBusted struct {
+	OrderEvent
+	TradeID  string  `json:"trade_id"`
+	Symbol   string  `json:"symbol"`
+	Price    float64 `json:"price"`
+	Quantity uint    `json:"quantity"`
+}
+
+func (e *OrderTradeBusted) String() string {
+	return fmt.Sprintf("%s %s %s %d@%f", e.OrderEvent.String(), e.TradeID, e.Symbol, e.Quantity, e.Price)
+}
+
+// NewOrderTradeBusted creates a new order trade busted event
+func NewOrderTradeBusted(orderID string, account string, occured time.Time, tradeID string, symbol string, price float64, quantity uint, version uint) *OrderTradeBusted {
+
+	return &OrderTradeBusted{*NewOrderEvent(OrderTradeBustedType, orderID, account, occured, version), tradeID, symbol, price, quantity}
+}
@@ -5,19 +5,21 @@ import (
 	"time"
 )
 
-// OrderTraded defines a order traded event
+// OrderTraded defines a order traded event. Both orders of a trade publish one with the same trade id.
 type OrderTraded struct {
 	OrderEvent
+	TradeID  string  `json:"trade_id"`
+	Symbol   string  `json:"symbol"`
 	Price    float64 `json:"price"`
 	Quantity uint    `json:"quantity"`
 }
 
 func (e *OrderTraded) String() string {
-	return fmt.Sprintf("%s %d@%f", e.OrderEvent.String(), e.Quantity, e.Price)
+	return fmt.Sprintf("%s %s %s %d@%f", e.OrderEvent.String(), e.TradeID, e.Symbol, e.Quantity, e.Price)
 }
 
 // NewOrderTraded creates a new order traded event
-func NewOrderTraded(orderID string, occured time.Time, price float64, quantity uint, version uint) *OrderTraded {
+func NewOrderTraded(orderID string, account string, occured time.Time, tradeID string, symbol string, price float64, quantity uint, version uint) *OrderTraded {
 
-	return &OrderTraded{*NewOrderEvent(OrderTradedType, orderID, occured, version), price, quantity}
+	return &OrderTraded{*NewOrderEvent(OrderTradedType, orderID, account, occured, version), tradeID, symbol, price, quantity}
 }
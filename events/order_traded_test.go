@@ -13,7 +13,7 @@ func TestOrderTradeString(t *testing.T) {
 
 	l, _ := time.LoadLocation("Europe/Athens")
 	dt := time.Date(2016, 8, 13, 17, 33, 11, 111, l)
-	event := NewOrderTraded("d1de4242-6620-4030-b2a7-4a701631c3ba", dt, 1.99, 10, 1)
+	event := NewOrderTraded("d1de4242-6620-4030-b2a7-4a701631c3ba", "ACC1", dt, "a0a7b0a1-1c3e-4c55-9d43-0f2a1b2c3d4e", "TT", 1.99, 10, 1)
 
-	require.Equal("OrderTraded: [d1de4242-6620-4030-b2a7-4a701631c3ba] 2016-08-13 17:33:11.000000111 +0300 EEST 1 10@1.990000", event.String())
+	require.Equal("OrderTraded: [d1de4242-6620-4030-b2a7-4a701631c3ba] 2016-08-13 17:33:11.000000111 +0300 EEST 1 a0a7b0a1-1c3e-4c55-9d43-0f2a1b2c3d4e TT 10@1.990000", event.String())
 }
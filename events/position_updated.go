@@ -0,0 +1,30 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// PositionUpdated defines a position updated event of a account
+type PositionUpdated struct {
+	EventType   OrderEventType `json:"event_type"`
+	Account     string         `json:"account"`
+	Symbol      string         `json:"symbol"`
+	Quantity    int            `json:"quantity"`
+	Price       float64        `json:"price"`
+	RealizedPnL float64        `json:"realized_pnl"`
+	DailyPnL    float64        `json:"daily_pnl"`
+	Occured     time.Time      `json:"occured"`
+	Version     uint           `json:"version"`
+}
+
+func (e *PositionUpdated) String() string {
+	return fmt.Sprintf("%s: [%s] %s %d@%f %f %f %s %d", e.EventType, e.Account, e.Symbol, e.Quantity, e.Price, e.RealizedPnL, e.DailyPnL, e.Occured, e.Version)
+}
+
+// NewPositionUpdated creates a new position updated event
+func NewPositionUpdated(account string, symbol string, quantity int, price float64, realizedPnL float64, dailyPnL float64, occured time.Time,
+	version uint) *PositionUpdated {
+
+	return &PositionUpdated{PositionUpdatedType, account, symbol, quantity, price, realizedPnL, dailyPnL, occured, version}
+}
@@ -0,0 +1,19 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositionUpdatedString(t *testing.T) {
+
+	require := require.New(t)
+
+	l, _ := time.LoadLocation("Europe/Athens")
+	dt := time.Date(2016, 8, 13, 17, 33, 11, 111, l)
+	event := NewPositionUpdated("ACC1", "TT", -10, 1.99, -2.5, -1.5, dt, 1)
+
+	require.Equal("PositionUpdated: [ACC1] TT -10@1.990000 -2.500000 -1.500000 2016-08-13 17:33:11.000000111 +0300 EEST 1", event.String())
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tradsim/tradsim-go/models"
+)
+
+// PriceLevelAction defines what happened to a price level
+type PriceLevelAction string
+
+// The price level actions
+const (
+	PriceLevelAdded   PriceLevelAction = "Added"
+	PriceLevelChanged PriceLevelAction = "Changed"
+	PriceLevelRemoved PriceLevelAction = "Removed"
+)
+
+// PriceLevelUpdated defines a book event of a price level, removed levels have zero quantity.
+// The sequence is per symbol and shared with the best bid offer events.
+type PriceLevelUpdated struct {
+	EventType OrderEventType   `json:"event_type"`
+	Symbol    string           `json:"symbol"`
+	Sequence  uint64           `json:"sequence"`
+	Action    PriceLevelAction `json:"action"`
+	Direction string           `json:"direction"`
+	Price     float64          `json:"price"`
+	Quantity  uint             `json:"quantity"`
+	Orders    uint             `json:"orders"`
+	Occured   time.Time        `json:"occured"`
+	Version   uint             `json:"version"`
+}
+
+func (e *PriceLevelUpdated) String() string {
+	return fmt.Sprintf("%s: [%d] %s %s %s %d@%f %d %s %d", e.EventType, e.Sequence, e.Symbol, e.Action, e.Direction, e.Quantity, e.Price, e.Orders, e.Occured, e.Version)
+}
+
+// NewPriceLevelUpdated creates a new price level updated event
+func NewPriceLevelUpdated(symbol string, sequence uint64, action PriceLevelAction, direction models.TradeDirection, price float64, quantity uint, orders uint, occured time.Time, version uint) *PriceLevelUpdated {
+
+	return &PriceLevelUpdated{PriceLevelUpdatedType, symbol, sequence, action, direction.String(), price, quantity, orders, occured, version}
+}
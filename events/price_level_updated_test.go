@@ -0,0 +1,20 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tradsim/tradsim-go/models"
+)
+
+func TestPriceLevelUpdatedString(t *testing.T) {
+
+	require := require.New(t)
+
+	l, _ := time.LoadLocation("Europe/Athens")
+	dt := time.Date(2016, 8, 13, 17, 33, 11, 111, l)
+	event := NewPriceLevelUpdated("TT", 5, PriceLevelChanged, models.Sell, 1.99, 10, 2, dt, 1)
+
+	require.Equal("PriceLevelUpdated: [5] TT Changed Sell 10@1.990000 2 2016-08-13 17:33:11.000000111 +0300 EEST 1", event.String())
+}
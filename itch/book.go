@@ -0,0 +1,150 @@
+package itch
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Order is a order resting in the book
+type Order struct {
+	Ref    uint64
+	Stock  string
+	Side   byte
+	Shares uint32
+	Price  uint32
+	Added  time.Duration
+}
+
+// Level aggregates the shares and the orders at a price
+type Level struct {
+	Price  uint32
+	Shares uint64
+	Orders int
+}
+
+// Book reconstructs the books of the stocks from the order messages
+type Book struct {
+	orders map[uint64]*Order
+	time   time.Duration
+}
+
+// NewBook creates a new empty book
+func NewBook() *Book {
+	return &Book{make(map[uint64]*Order), 0}
+}
+
+// Time returns the timestamp of the last applied message
+func (b *Book) Time() time.Duration {
+	return b.time
+}
+
+// Order returns the resting order of the reference
+func (b *Book) Order(ref uint64) (*Order, bool) {
+	order, ok := b.orders[ref]
+	return order, ok
+}
+
+// Orders returns the resting orders in the order they were added
+func (b *Book) Orders() []Order {
+
+	orders := make([]Order, 0, len(b.orders))
+	for _, order := range b.orders {
+		orders = append(orders, *order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].Added != orders[j].Added {
+			return orders[i].Added < orders[j].Added
+		}
+		return orders[i].Ref < orders[j].Ref
+	})
+	return orders
+}
+
+// Apply applies a message to the book, it fails for messages of unknown orders
+func (b *Book) Apply(message Message) error {
+
+	b.time = message.GetHeader().Timestamp
+
+	switch m := message.(type) {
+	case *AddOrder:
+		b.orders[m.OrderRef] = &Order{m.OrderRef, m.Stock, m.Side, m.Shares, m.Price, m.Timestamp}
+	case *OrderExecuted:
+		return b.reduce(m.OrderRef, m.Shares)
+	case *OrderCancel:
+		return b.reduce(m.OrderRef, m.Shares)
+	case *OrderDelete:
+		if _, ok := b.orders[m.OrderRef]; !ok {
+			return fmt.Errorf("unknown order %d", m.OrderRef)
+		}
+		delete(b.orders, m.OrderRef)
+	case *OrderReplace:
+		order, ok := b.orders[m.OrderRef]
+		if !ok {
+			return fmt.Errorf("unknown order %d", m.OrderRef)
+		}
+		delete(b.orders, m.OrderRef)
+		b.orders[m.NewOrderRef] = &Order{m.NewOrderRef, order.Stock, order.Side, m.Shares, m.Price, m.Timestamp}
+	}
+	return nil
+}
+
+// reduce removes shares of a order and the order when no shares are left
+func (b *Book) reduce(ref uint64, shares uint32) error {
+
+	order, ok := b.orders[ref]
+	if !ok {
+		return fmt.Errorf("unknown order %d", ref)
+	}
+
+	if shares >= order.Shares {
+		delete(b.orders, ref)
+		return nil
+	}
+	order.Shares -= shares
+	return nil
+}
+
+// Levels returns the price levels of a stock with the best prices first
+func (b *Book) Levels(stock string) ([]Level, []Level) {
+
+	bids := make(map[uint32]*Level)
+	asks := make(map[uint32]*Level)
+
+	for _, order := range b.orders {
+
+		if order.Stock != stock {
+			continue
+		}
+
+		levels := asks
+		if order.Side == BuySide {
+			levels = bids
+		}
+
+		level, ok := levels[order.Price]
+		if !ok {
+			level = &Level{Price: order.Price}
+			levels[order.Price] = level
+		}
+		level.Shares += uint64(order.Shares)
+		level.Orders++
+	}
+
+	return sortLevels(bids, true), sortLevels(asks, false)
+}
+
+func sortLevels(levels map[uint32]*Level, descending bool) []Level {
+
+	sorted := make([]Level, 0, len(levels))
+	for _, level := range levels {
+		sorted = append(sorted, *level)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if descending {
+			return sorted[i].Price > sorted[j].Price
+		}
+		return sorted[i].Price < sorted[j].Price
+	})
+	return sorted
+}
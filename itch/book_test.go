@@ -0,0 +1,80 @@
+package itch
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBook(t *testing.T) {
+
+	require := require.New(t)
+
+	file, err := OpenFile("testdata/sample.itch")
+	require.Nil(err)
+	defer file.Close()
+
+	book := NewBook()
+	at := 9*time.Hour + 30*time.Minute + 1500*time.Millisecond
+
+	for {
+		message, err := file.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(err)
+
+		if message.GetHeader().Timestamp > at && book.Time() <= at {
+			bids, asks := book.Levels("AAPL")
+			require.Equal([]Level{{1500000, 60, 1}, {1499000, 50, 1}}, bids)
+			require.Equal([]Level{{1501000, 200, 1}}, asks)
+
+			bids, asks = book.Levels("MSFT")
+			require.Empty(bids)
+			require.Equal([]Level{{1005000, 300, 1}}, asks)
+		}
+
+		require.Nil(book.Apply(message))
+	}
+
+	require.Equal(16*time.Hour+time.Second, book.Time())
+
+	bids, asks := book.Levels("AAPL")
+	require.Equal([]Level{{1500000, 130, 2}, {1499000, 40, 1}}, bids)
+	require.Equal([]Level{{1500500, 150, 1}}, asks)
+
+	bids, asks = book.Levels("MSFT")
+	require.Empty(bids)
+	require.Empty(asks)
+
+	order, ok := book.Order(5)
+	require.True(ok)
+	require.Equal(Order{5, "AAPL", SellSide, 150, 1500500, 9*time.Hour + 30*time.Minute + 4*time.Second}, *order)
+
+	_, ok = book.Order(2)
+	require.False(ok)
+
+	orders := book.Orders()
+	require.Len(orders, 4)
+	require.Equal([]uint64{1, 3, 5, 6}, []uint64{orders[0].Ref, orders[1].Ref, orders[2].Ref, orders[3].Ref})
+}
+
+func TestBookUnknownOrders(t *testing.T) {
+
+	require := require.New(t)
+
+	book := NewBook()
+	require.EqualError(book.Apply(&OrderExecuted{header(OrderExecutedType), 9, 1, 1, true, 0}), "unknown order 9")
+	require.EqualError(book.Apply(&OrderCancel{header(OrderCancelType), 9, 1}), "unknown order 9")
+	require.EqualError(book.Apply(&OrderDelete{header(OrderDeleteType), 9}), "unknown order 9")
+	require.EqualError(book.Apply(&OrderReplace{header(OrderReplaceType), 9, 10, 1, 1}), "unknown order 9")
+	require.Nil(book.Apply(&SystemEvent{header(SystemEventType), EndOfMessages}))
+	require.Equal(testHeader.Timestamp, book.Time())
+
+	require.Nil(book.Apply(&AddOrder{header(AddOrderType), 1, SellSide, 10, "TT", 100, ""}))
+	require.Nil(book.Apply(&OrderExecuted{header(OrderExecutedType), 1, 20, 1, true, 0}))
+	_, ok := book.Order(1)
+	require.False(ok)
+}
@@ -0,0 +1,301 @@
+package itch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MessageType is the type of a ITCH 5.0 message
+type MessageType byte
+
+// Supported message types
+const (
+	SystemEventType            MessageType = 'S'
+	AddOrderType               MessageType = 'A'
+	AddOrderMPIDType           MessageType = 'F'
+	OrderExecutedType          MessageType = 'E'
+	OrderExecutedWithPriceType MessageType = 'C'
+	OrderCancelType            MessageType = 'X'
+	OrderDeleteType            MessageType = 'D'
+	OrderReplaceType           MessageType = 'U'
+)
+
+// Lengths of the messages without the length prefix
+var lengths = map[MessageType]int{
+	SystemEventType:            12,
+	AddOrderType:               36,
+	AddOrderMPIDType:           40,
+	OrderExecutedType:          31,
+	OrderExecutedWithPriceType: 36,
+	OrderCancelType:            23,
+	OrderDeleteType:            19,
+	OrderReplaceType:           35,
+}
+
+// System event codes
+const (
+	StartOfMessages    byte = 'O'
+	StartOfSystemHours byte = 'S'
+	StartOfMarketHours byte = 'Q'
+	EndOfMarketHours   byte = 'M'
+	EndOfSystemHours   byte = 'E'
+	EndOfMessages      byte = 'C'
+)
+
+// Sides of the orders
+const (
+	BuySide  byte = 'B'
+	SellSide byte = 'S'
+)
+
+const (
+	headerLength = 11
+	priceScale   = 10000
+)
+
+// ErrUnsupported is returned for message types which are not parsed
+var ErrUnsupported = errors.New("unsupported message type")
+
+// Header is the common header of the messages, the timestamp is the time since midnight
+type Header struct {
+	Type           MessageType
+	StockLocate    uint16
+	TrackingNumber uint16
+	Timestamp      time.Duration
+}
+
+// Message is a parsed ITCH message
+type Message interface {
+	GetHeader() *Header
+}
+
+// GetHeader returns the header of the message
+func (h *Header) GetHeader() *Header {
+	return h
+}
+
+// SystemEvent signals a market or data feed handler event
+type SystemEvent struct {
+	Header
+	EventCode byte
+}
+
+// AddOrder adds a order to the book, the attribution is set for the Add Order with MPID message
+type AddOrder struct {
+	Header
+	OrderRef    uint64
+	Side        byte
+	Shares      uint32
+	Stock       string
+	Price       uint32
+	Attribution string
+}
+
+// OrderExecuted executes shares of a order, the price is set for the Order Executed With Price message
+type OrderExecuted struct {
+	Header
+	OrderRef    uint64
+	Shares      uint32
+	MatchNumber uint64
+	Printable   bool
+	Price       uint32
+}
+
+// OrderCancel cancels shares of a order
+type OrderCancel struct {
+	Header
+	OrderRef uint64
+	Shares   uint32
+}
+
+// OrderDelete removes a order from the book
+type OrderDelete struct {
+	Header
+	OrderRef uint64
+}
+
+// OrderReplace replaces a order by a new order of the same side and stock which loses the priority
+type OrderReplace struct {
+	Header
+	OrderRef    uint64
+	NewOrderRef uint64
+	Shares      uint32
+	Price       uint32
+}
+
+// Price returns the price of the fixed point price with four decimals
+func Price(price uint32) float64 {
+	return float64(price) / priceScale
+}
+
+// ToPrice returns the fixed point price with four decimals of the price
+func ToPrice(price float64) uint32 {
+	return uint32(price*priceScale + 0.5)
+}
+
+// ParseTime parses a time since midnight of the form HH:MM:SS with optional fractional seconds
+func ParseTime(value string) (time.Duration, error) {
+
+	parsed, err := time.Parse("15:04:05.999999999", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return parsed.Sub(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)), nil
+}
+
+// Parse parses a message without the length prefix, ErrUnsupported is returned for other message types
+func Parse(data []byte) (Message, error) {
+
+	if len(data) == 0 {
+		return nil, errors.New("empty message")
+	}
+
+	messageType := MessageType(data[0])
+	length, ok := lengths[messageType]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	if len(data) < length {
+		return nil, fmt.Errorf("%c message has %d bytes instead of %d", messageType, len(data), length)
+	}
+
+	header := Header{
+		Type:           messageType,
+		StockLocate:    binary.BigEndian.Uint16(data[1:3]),
+		TrackingNumber: binary.BigEndian.Uint16(data[3:5]),
+		Timestamp:      time.Duration(uint48(data[5:11])),
+	}
+	body := data[headerLength:]
+
+	switch messageType {
+	case SystemEventType:
+		return &SystemEvent{header, body[0]}, nil
+	case AddOrderType, AddOrderMPIDType:
+		add := &AddOrder{
+			Header:   header,
+			OrderRef: binary.BigEndian.Uint64(body[0:8]),
+			Side:     body[8],
+			Shares:   binary.BigEndian.Uint32(body[9:13]),
+			Stock:    strings.TrimRight(string(body[13:21]), " "),
+			Price:    binary.BigEndian.Uint32(body[21:25]),
+		}
+		if messageType == AddOrderMPIDType {
+			add.Attribution = strings.TrimRight(string(body[25:29]), " ")
+		}
+		return add, nil
+	case OrderExecutedType, OrderExecutedWithPriceType:
+		executed := &OrderExecuted{
+			Header:      header,
+			OrderRef:    binary.BigEndian.Uint64(body[0:8]),
+			Shares:      binary.BigEndian.Uint32(body[8:12]),
+			MatchNumber: binary.BigEndian.Uint64(body[12:20]),
+			Printable:   true,
+		}
+		if messageType == OrderExecutedWithPriceType {
+			executed.Printable = body[20] == 'Y'
+			executed.Price = binary.BigEndian.Uint32(body[21:25])
+		}
+		return executed, nil
+	case OrderCancelType:
+		return &OrderCancel{header, binary.BigEndian.Uint64(body[0:8]), binary.BigEndian.Uint32(body[8:12])}, nil
+	case OrderDeleteType:
+		return &OrderDelete{header, binary.BigEndian.Uint64(body[0:8])}, nil
+	default:
+		return &OrderReplace{header, binary.BigEndian.Uint64(body[0:8]), binary.BigEndian.Uint64(body[8:16]),
+			binary.BigEndian.Uint32(body[16:20]), binary.BigEndian.Uint32(body[20:24])}, nil
+	}
+}
+
+// Marshal encodes a message without the length prefix, the type of the header has to be a type of the message
+func Marshal(message Message) ([]byte, error) {
+
+	header := message.GetHeader()
+
+	if !matches(header.Type, message) {
+		return nil, fmt.Errorf("%c message of type %T", header.Type, message)
+	}
+
+	data := make([]byte, lengths[header.Type])
+	data[0] = byte(header.Type)
+	binary.BigEndian.PutUint16(data[1:3], header.StockLocate)
+	binary.BigEndian.PutUint16(data[3:5], header.TrackingNumber)
+	putUint48(data[5:11], uint64(header.Timestamp))
+	body := data[headerLength:]
+
+	switch m := message.(type) {
+	case *SystemEvent:
+		body[0] = m.EventCode
+	case *AddOrder:
+		binary.BigEndian.PutUint64(body[0:8], m.OrderRef)
+		body[8] = m.Side
+		binary.BigEndian.PutUint32(body[9:13], m.Shares)
+		putAlpha(body[13:21], m.Stock)
+		binary.BigEndian.PutUint32(body[21:25], m.Price)
+		if header.Type == AddOrderMPIDType {
+			putAlpha(body[25:29], m.Attribution)
+		}
+	case *OrderExecuted:
+		binary.BigEndian.PutUint64(body[0:8], m.OrderRef)
+		binary.BigEndian.PutUint32(body[8:12], m.Shares)
+		binary.BigEndian.PutUint64(body[12:20], m.MatchNumber)
+		if header.Type == OrderExecutedWithPriceType {
+			body[20] = 'N'
+			if m.Printable {
+				body[20] = 'Y'
+			}
+			binary.BigEndian.PutUint32(body[21:25], m.Price)
+		}
+	case *OrderCancel:
+		binary.BigEndian.PutUint64(body[0:8], m.OrderRef)
+		binary.BigEndian.PutUint32(body[8:12], m.Shares)
+	case *OrderDelete:
+		binary.BigEndian.PutUint64(body[0:8], m.OrderRef)
+	case *OrderReplace:
+		binary.BigEndian.PutUint64(body[0:8], m.OrderRef)
+		binary.BigEndian.PutUint64(body[8:16], m.NewOrderRef)
+		binary.BigEndian.PutUint32(body[16:20], m.Shares)
+		binary.BigEndian.PutUint32(body[20:24], m.Price)
+	}
+
+	return data, nil
+}
+
+// matches returns true if the message type is encoded by the message
+func matches(messageType MessageType, message Message) bool {
+
+	switch message.(type) {
+	case *SystemEvent:
+		return messageType == SystemEventType
+	case *AddOrder:
+		return messageType == AddOrderType || messageType == AddOrderMPIDType
+	case *OrderExecuted:
+		return messageType == OrderExecutedType || messageType == OrderExecutedWithPriceType
+	case *OrderCancel:
+		return messageType == OrderCancelType
+	case *OrderDelete:
+		return messageType == OrderDeleteType
+	case *OrderReplace:
+		return messageType == OrderReplaceType
+	}
+	return false
+}
+
+func uint48(data []byte) uint64 {
+	return uint64(binary.BigEndian.Uint16(data[0:2]))<<32 | uint64(binary.BigEndian.Uint32(data[2:6]))
+}
+
+func putUint48(data []byte, value uint64) {
+	binary.BigEndian.PutUint16(data[0:2], uint16(value>>32))
+	binary.BigEndian.PutUint32(data[2:6], uint32(value))
+}
+
+// putAlpha writes the text left justified and padded with spaces
+func putAlpha(data []byte, text string) {
+	copy(data, text)
+	for i := len(text); i < len(data); i++ {
+		data[i] = ' '
+	}
+}
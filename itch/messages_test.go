@@ -0,0 +1,95 @@
+package itch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testHeader = Header{StockLocate: 7, TrackingNumber: 3, Timestamp: 9*time.Hour + 30*time.Minute + 123456789}
+
+func header(messageType MessageType) Header {
+	h := testHeader
+	h.Type = messageType
+	return h
+}
+
+func TestMarshalParse(t *testing.T) {
+
+	require := require.New(t)
+
+	messages := []Message{
+		&SystemEvent{header(SystemEventType), StartOfMarketHours},
+		&AddOrder{header(AddOrderType), 1, BuySide, 100, "AAPL", 1500000, ""},
+		&AddOrder{header(AddOrderMPIDType), 2, SellSide, 200, "ZVZZT", 99, "GSCO"},
+		&OrderExecuted{header(OrderExecutedType), 1, 40, 11, true, 0},
+		&OrderExecuted{header(OrderExecutedWithPriceType), 1, 40, 12, false, 1499900},
+		&OrderCancel{header(OrderCancelType), 2, 50},
+		&OrderDelete{header(OrderDeleteType), 2},
+		&OrderReplace{header(OrderReplaceType), 1, 3, 60, 1500100},
+	}
+
+	for _, message := range messages {
+
+		data, err := Marshal(message)
+		require.Nil(err)
+		require.Len(data, lengths[message.GetHeader().Type])
+		require.Equal(byte(message.GetHeader().Type), data[0])
+
+		parsed, err := Parse(data)
+		require.Nil(err)
+		require.Equal(message, parsed)
+	}
+}
+
+func TestParseAddOrder(t *testing.T) {
+
+	require := require.New(t)
+
+	data := []byte{'A', 0, 7, 0, 3, 0x1F, 0x1A, 0xD6, 0x35, 0xBD, 0x15,
+		0, 0, 0, 0, 0, 0, 0, 1, 'B', 0, 0, 0, 100, 'A', 'A', 'P', 'L', ' ', ' ', ' ', ' ', 0, 0x16, 0xE3, 0x60}
+
+	message, err := Parse(data)
+	require.Nil(err)
+	require.Equal(&AddOrder{header(AddOrderType), 1, BuySide, 100, "AAPL", 1500000, ""}, message)
+	require.Equal(150.0, Price(message.(*AddOrder).Price))
+	require.Equal(uint32(1500000), ToPrice(150))
+	require.Equal(uint32(1999), ToPrice(0.1999))
+}
+
+func TestParseErrors(t *testing.T) {
+
+	require := require.New(t)
+
+	_, err := Parse(nil)
+	require.EqualError(err, "empty message")
+
+	_, err = Parse([]byte{'R', 0, 1})
+	require.Equal(ErrUnsupported, err)
+
+	_, err = Parse([]byte{'D', 0, 1})
+	require.EqualError(err, "D message has 3 bytes instead of 19")
+
+	_, err = Marshal(&OrderDelete{header(AddOrderType), 1})
+	require.EqualError(err, "A message of type *itch.OrderDelete")
+
+	_, err = Marshal(&SystemEvent{header('R'), 'O'})
+	require.NotNil(err)
+}
+
+func TestParseTime(t *testing.T) {
+
+	require := require.New(t)
+
+	parsed, err := ParseTime("09:30:01.5")
+	require.Nil(err)
+	require.Equal(9*time.Hour+30*time.Minute+1500*time.Millisecond, parsed)
+
+	parsed, err = ParseTime("16:00:00")
+	require.Nil(err)
+	require.Equal(16*time.Hour, parsed)
+
+	_, err = ParseTime("9:30")
+	require.EqualError(err, `invalid time "9:30"`)
+}
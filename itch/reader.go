@@ -0,0 +1,116 @@
+package itch
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+)
+
+// Reader reads the messages of a ITCH 5.0 file, in which every message has a two byte big endian length prefix
+type Reader struct {
+	reader  *bufio.Reader
+	buffer  []byte
+	skipped int
+}
+
+// NewReader creates a new reader
+func NewReader(reader io.Reader) *Reader {
+	return &Reader{bufio.NewReaderSize(reader, 64*1024), make([]byte, 0xFFFF), 0}
+}
+
+// Next returns the next supported message, other messages are skipped. It returns io.EOF at the end of the file.
+func (r *Reader) Next() (Message, error) {
+
+	for {
+		var prefix [2]byte
+		_, err := io.ReadFull(r.reader, prefix[:])
+		if err != nil {
+			return nil, err
+		}
+
+		data := r.buffer[:binary.BigEndian.Uint16(prefix[:])]
+		_, err = io.ReadFull(r.reader, data)
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		message, err := Parse(data)
+		if err == ErrUnsupported {
+			r.skipped++
+			continue
+		}
+		return message, err
+	}
+}
+
+// Skipped returns the number of messages of unsupported types which were skipped
+func (r *Reader) Skipped() int {
+	return r.skipped
+}
+
+// File is a ITCH file opened for reading
+type File struct {
+	*Reader
+	file *os.File
+	gzip *gzip.Reader
+}
+
+// OpenFile opens a ITCH file, files ending with .gz are decompressed
+func OpenFile(path string) (*File, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(path, ".gz") {
+		return &File{NewReader(file), file, nil}, nil
+	}
+
+	decompressed, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &File{NewReader(decompressed), file, decompressed}, nil
+}
+
+// Close closes the file
+func (f *File) Close() error {
+	if f.gzip != nil {
+		f.gzip.Close()
+	}
+	return f.file.Close()
+}
+
+// Writer writes messages with their length prefix
+type Writer struct {
+	writer io.Writer
+}
+
+// NewWriter creates a new writer
+func NewWriter(writer io.Writer) *Writer {
+	return &Writer{writer}
+}
+
+// Write writes a message
+func (w *Writer) Write(message Message) error {
+
+	data, err := Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	prefixed := make([]byte, 2+len(data))
+	binary.BigEndian.PutUint16(prefixed, uint16(len(data)))
+	copy(prefixed[2:], data)
+
+	_, err = w.writer.Write(prefixed)
+	return err
+}
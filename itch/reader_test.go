@@ -0,0 +1,73 @@
+package itch
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, reader *Reader) []Message {
+
+	var messages []Message
+	for {
+		message, err := reader.Next()
+		if err == io.EOF {
+			return messages
+		}
+		require.Nil(t, err)
+		messages = append(messages, message)
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+
+	require := require.New(t)
+
+	for _, path := range []string{"testdata/sample.itch", "testdata/sample.itch.gz"} {
+
+		file, err := OpenFile(path)
+		require.Nil(err)
+
+		messages := readAll(t, file.Reader)
+		require.Len(messages, 14, path)
+		require.Equal(2, file.Skipped())
+
+		require.Equal(&SystemEvent{Header{SystemEventType, 0, 0, 3 * 3600e9}, StartOfMessages}, messages[0])
+		require.Equal(&AddOrder{Header{AddOrderMPIDType, 1, 0, 34200003000000}, 3, BuySide, 50, "AAPL", 1499000, "GSCO"}, messages[4])
+		require.Equal(OrderDeleteType, messages[11].GetHeader().Type)
+
+		require.Nil(file.Close())
+	}
+
+	_, err := OpenFile("testdata/unknown.itch")
+	require.NotNil(err)
+}
+
+func TestWriterReader(t *testing.T) {
+
+	require := require.New(t)
+
+	var buffer bytes.Buffer
+	writer := NewWriter(&buffer)
+
+	messages := []Message{
+		&AddOrder{header(AddOrderType), 1, BuySide, 100, "AAPL", 1500000, ""},
+		&OrderDelete{header(OrderDeleteType), 1},
+	}
+	for _, message := range messages {
+		require.Nil(writer.Write(message))
+	}
+	require.Equal(2+36+2+19, buffer.Len())
+
+	require.Equal(messages, readAll(t, NewReader(bytes.NewReader(buffer.Bytes()))))
+
+	truncated := NewReader(bytes.NewReader(buffer.Bytes()[:buffer.Len()-1]))
+	_, err := truncated.Next()
+	require.Nil(err)
+	_, err = truncated.Next()
+	require.Equal(io.ErrUnexpectedEOF, err)
+
+	require.NotNil(writer.Write(&OrderDelete{header(AddOrderType), 1}))
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/satori/go.uuid"
 	"github.com/tradsim/tradsim-go/events"
 	"github.com/tradsim/tradsim-go/models"
@@ -40,8 +42,8 @@ type MockTrader struct {
 }
 
 // Trade order
-func (mt *MockTrader) Trade(book *models.OrderBook, order *models.Order) {
-
+func (mt *MockTrader) Trade(book *models.OrderBook, order *models.Order) []*models.Trade {
+	return nil
 }
 
 // MockCanceller for mocking the canceller
@@ -63,3 +65,95 @@ type MockAmender struct {
 func (ma *MockAmender) Amend(book *models.OrderBook, order *models.Order) bool {
 	return ma.Amended
 }
+
+// MockLimitChecker for mocking the limit checker
+type MockLimitChecker struct {
+	Err error
+}
+
+// Check the order
+func (mc *MockLimitChecker) Check(book *models.OrderBook, order *models.Order) error {
+	return mc.Err
+}
+
+// MockTradeListener for mocking a trade listener
+type MockTradeListener struct {
+	Trades []*models.Trade
+}
+
+// Traded records the trade
+func (ml *MockTradeListener) Traded(trade *models.Trade) {
+	ml.Trades = append(ml.Trades, trade)
+}
+
+// MockBustListener for mocking a bust listener
+type MockBustListener struct {
+	Trades []*models.Trade
+}
+
+// Busted records the trade
+func (ml *MockBustListener) Busted(trade *models.Trade) {
+	ml.Trades = append(ml.Trades, trade)
+}
+
+// MockBookListener for mocking a book listener
+type MockBookListener struct {
+	Symbols []string
+}
+
+// BookChanged records the symbol
+func (ml *MockBookListener) BookChanged(book *models.OrderBook, symbol string) {
+	ml.Symbols = append(ml.Symbols, symbol)
+}
+
+// MockSessionListener for mocking a session listener
+type MockSessionListener struct {
+	Closed []time.Time
+}
+
+// SessionClosed records the session close
+func (ml *MockSessionListener) SessionClosed(at time.Time) {
+	ml.Closed = append(ml.Closed, at)
+}
+
+// MockRiskRepository for mocking the risk repository
+type MockRiskRepository struct {
+	Limits    map[string]models.AccountLimits
+	Positions []models.AccountPosition
+	Err       error
+}
+
+// GetLimits returns the saved limits
+func (mr *MockRiskRepository) GetLimits() ([]models.AccountLimits, error) {
+	limits := make([]models.AccountLimits, 0, len(mr.Limits))
+	for _, l := range mr.Limits {
+		limits = append(limits, l)
+	}
+	return limits, mr.Err
+}
+
+// SaveLimits saves the limits unless the mock fails
+func (mr *MockRiskRepository) SaveLimits(limits models.AccountLimits) error {
+	if mr.Err != nil {
+		return mr.Err
+	}
+	if mr.Limits == nil {
+		mr.Limits = make(map[string]models.AccountLimits)
+	}
+	mr.Limits[limits.Account] = limits
+	return nil
+}
+
+// RemoveLimits removes the limits unless the mock fails
+func (mr *MockRiskRepository) RemoveLimits(account string) error {
+	if mr.Err != nil {
+		return mr.Err
+	}
+	delete(mr.Limits, account)
+	return nil
+}
+
+// GetPositions returns the positions
+func (mr *MockRiskRepository) GetPositions() ([]models.AccountPosition, error) {
+	return mr.Positions, mr.Err
+}
@@ -0,0 +1,21 @@
+package models
+
+import "fmt"
+
+// AccountLimits defines the risk limits of a account, a zero value disables the limit
+type AccountLimits struct {
+	Account          string
+	MaxLong          uint
+	MaxShort         uint
+	MaxGrossExposure float64
+	DailyLossLimit   float64
+}
+
+// NewAccountLimits creates new account limits
+func NewAccountLimits(account string, maxLong uint, maxShort uint, maxGrossExposure float64, dailyLossLimit float64) *AccountLimits {
+	return &AccountLimits{account, maxLong, maxShort, maxGrossExposure, dailyLossLimit}
+}
+
+func (l *AccountLimits) String() string {
+	return fmt.Sprintf("[%s] long %d short %d exposure %f loss %f", l.Account, l.MaxLong, l.MaxShort, l.MaxGrossExposure, l.DailyLossLimit)
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountLimitsString(t *testing.T) {
+
+	require := require.New(t)
+
+	limits := NewAccountLimits("ACC1", 100, 50, 10000.0, 500.0)
+
+	require.Equal("[ACC1] long 100 short 50 exposure 10000.000000 loss 500.000000", limits.String())
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// AccountPosition defines the position of a account in a symbol
+type AccountPosition struct {
+	Account     string
+	Symbol      string
+	Quantity    int
+	Price       float64
+	RealizedPnL float64
+	DailyPnL    float64
+	Updated     time.Time
+}
+
+// NewAccountPosition creates a new account position
+func NewAccountPosition(account string, symbol string, quantity int, price float64, realizedPnL float64, dailyPnL float64, updated time.Time) *AccountPosition {
+	return &AccountPosition{account, symbol, quantity, price, realizedPnL, dailyPnL, updated}
+}
+
+// Fill adds a signed fill quantity to the position, the price is the average cost of the open quantity.
+// The realized PnL is cumulative while the daily PnL starts over with the first fill of a day.
+func (p *AccountPosition) Fill(quantity int, price float64, occured time.Time) {
+
+	if !SameDay(p.Updated, occured) {
+		p.DailyPnL = 0.0
+	}
+	p.Updated = occured
+
+	q := p.Quantity
+	if q == 0 || (q > 0) == (quantity > 0) {
+		p.Price = (p.Price*float64(abs(q)) + price*float64(abs(quantity))) / float64(abs(q)+abs(quantity))
+		p.Quantity += quantity
+		return
+	}
+
+	closed := abs(quantity)
+	if closed > abs(q) {
+		closed = abs(q)
+	}
+
+	pnl := float64(closed) * (price - p.Price)
+	if q < 0 {
+		pnl = -pnl
+	}
+	p.RealizedPnL += pnl
+	p.DailyPnL += pnl
+
+	flipped := abs(quantity) > abs(q)
+	p.Quantity += quantity
+
+	if p.Quantity == 0 {
+		p.Price = 0.0
+	} else if flipped {
+		p.Price = price
+	}
+}
+
+// Exposure returns the absolute value of the position
+func (p *AccountPosition) Exposure() float64 {
+	if p.Quantity < 0 {
+		return float64(-p.Quantity) * p.Price
+	}
+	return float64(p.Quantity) * p.Price
+}
+
+func (p *AccountPosition) String() string {
+	return fmt.Sprintf("[%s] %s %d@%f %f %f", p.Account, p.Symbol, p.Quantity, p.Price, p.RealizedPnL, p.DailyPnL)
+}
+
+// SameDay returns true if both times are on the same UTC day
+func SameDay(t1 time.Time, t2 time.Time) bool {
+	y1, m1, d1 := t1.UTC().Date()
+	y2, m2, d2 := t2.UTC().Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountPositionExposure(t *testing.T) {
+
+	require := require.New(t)
+
+	long := NewAccountPosition("ACC1", "TT", 10, 2.0, 0.0, 0.0, time.Now().UTC())
+	short := NewAccountPosition("ACC1", "TT", -10, 2.0, 0.0, 0.0, time.Now().UTC())
+
+	require.Equal(20.0, long.Exposure())
+	require.Equal(20.0, short.Exposure())
+}
+
+func TestAccountPositionString(t *testing.T) {
+
+	require := require.New(t)
+
+	position := NewAccountPosition("ACC1", "TT", -10, 2.0, 1.5, 0.5, time.Now().UTC())
+
+	require.Equal("[ACC1] TT -10@2.000000 1.500000 0.500000", position.String())
+}
+
+func TestAccountPositionFill(t *testing.T) {
+
+	require := require.New(t)
+
+	start := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	position := NewAccountPosition("ACC1", "TT", 0, 0.0, 0.0, 0.0, start)
+
+	position.Fill(10, 2.0, start)
+	position.Fill(30, 3.0, start)
+	require.Equal(40, position.Quantity)
+	require.Equal(2.75, position.Price)
+
+	position.Fill(-20, 3.25, start.Add(time.Second))
+	require.Equal(20, position.Quantity)
+	require.Equal(2.75, position.Price)
+	require.Equal(10.0, position.RealizedPnL)
+	require.Equal(10.0, position.DailyPnL)
+	require.Equal(start.Add(time.Second), position.Updated)
+
+	position.Fill(-30, 2.5, start.Add(24*time.Hour))
+	require.Equal(-10, position.Quantity)
+	require.Equal(2.5, position.Price)
+	require.Equal(5.0, position.RealizedPnL)
+	require.Equal(-5.0, position.DailyPnL)
+
+	position.Fill(10, 2.0, start.Add(24*time.Hour))
+	require.Equal(0, position.Quantity)
+	require.Equal(0.0, position.Price)
+	require.Equal(10.0, position.RealizedPnL)
+	require.Equal(0.0, position.DailyPnL)
+}
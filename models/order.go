@@ -8,23 +8,25 @@ import (
 
 // Order defines a order for a specific symbol
 type Order struct {
-	ID        uuid.UUID
-	Symbol    string
-	Price     float64
-	Quantity  uint
-	Traded    uint
-	Direction TradeDirection
-	Status    OrderStatus
+	ID            uuid.UUID
+	Account       string
+	ClientOrderID string
+	Symbol        string
+	Price         float64
+	Quantity      uint
+	Traded        uint
+	Direction     TradeDirection
+	Status        OrderStatus
 }
 
 // NewOrder creates a new order
 func NewOrder(id uuid.UUID, symbol string, price float64, quantity uint, direction TradeDirection) *Order {
-	return &Order{id, symbol, price, quantity, 0, direction, Pending}
+	return &Order{id, "", "", symbol, price, quantity, 0, direction, Pending}
 }
 
 // NewOrderFull creates a new order with all parameters
 func NewOrderFull(id uuid.UUID, symbol string, price float64, quantity uint, traded uint, direction TradeDirection, orderStatus OrderStatus) *Order {
-	return &Order{id, symbol, price, quantity, traded, direction, orderStatus}
+	return &Order{id, "", "", symbol, price, quantity, traded, direction, orderStatus}
 }
 
 // Remaining return the reamining quantity
@@ -44,6 +46,16 @@ func (o *Order) Trade(q uint) {
 	o.Status = ResolveStatus(o.Quantity, o.Traded)
 }
 
+// Bust removes the quantity of a busted trade from the order.
+// The remaining quantity does not change so the busted quantity does not return to the book, orders out of the book keep their status.
+func (o *Order) Bust(q uint) {
+	o.Quantity -= q
+	o.Traded -= q
+	if o.Status.IsTradeable() {
+		o.Status = ResolveStatus(o.Quantity, o.Traded)
+	}
+}
+
 func (o *Order) String() string {
 	return fmt.Sprintf("[%s] %s@%f %s %d/%d/%d %s", o.ID, o.Symbol, o.Price, o.Direction.String(), o.Quantity, o.Traded, o.Remaining(), o.Status.String())
 }
@@ -1,15 +1,51 @@
 package models
 
-import "github.com/satori/go.uuid"
+import (
+	"sync"
 
-// OrderBook contains all orders currently in the market
+	"github.com/satori/go.uuid"
+)
+
+// OrderBook contains all orders currently in the market, the open orders of every account, the instruments and the halted symbols with the reason of the halt.
+// Without instruments every symbol trades, once instruments are set only the listed symbols trade.
+// The lock guards the symbols, orders, open orders, instruments and halts, a request holds it from its checks until the book is updated.
 type OrderBook struct {
-	Symbols map[string][]*OrderPrice
-	Orders  map[uuid.UUID]*Order
+	Symbols     map[string][]*OrderPrice
+	Orders      map[uuid.UUID]*Order
+	Accounts    map[string]map[uuid.UUID]*Order
+	Instruments map[string]bool
+	Halted      map[string]string
+	sync.RWMutex
 }
 
 // NewOrderBook creates a new order book
 func NewOrderBook() *OrderBook {
 
-	return &OrderBook{make(map[string][]*OrderPrice), make(map[uuid.UUID]*Order)}
+	return &OrderBook{Symbols: make(map[string][]*OrderPrice), Orders: make(map[uuid.UUID]*Order), Accounts: make(map[string]map[uuid.UUID]*Order), Halted: make(map[string]string)}
+}
+
+// IsInstrument returns true if the symbol trades
+func (ob *OrderBook) IsInstrument(symbol string) bool {
+	return ob.Instruments == nil || ob.Instruments[symbol]
+}
+
+// Open adds the order to the open orders of its account
+func (ob *OrderBook) Open(order *Order) {
+
+	orders, ok := ob.Accounts[order.Account]
+	if !ok {
+		orders = make(map[uuid.UUID]*Order)
+		ob.Accounts[order.Account] = orders
+	}
+	orders[order.ID] = order
+}
+
+// Close removes the order from the open orders of its account
+func (ob *OrderBook) Close(order *Order) {
+
+	orders := ob.Accounts[order.Account]
+	delete(orders, order.ID)
+	if len(orders) == 0 {
+		delete(ob.Accounts, order.Account)
+	}
 }
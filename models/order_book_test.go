@@ -3,6 +3,7 @@ package models
 import (
 	"testing"
 
+	"github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,3 +16,41 @@ func TestNewOrderBook(t *testing.T) {
 	require.NotNil(book)
 	require.Empty(book.Symbols)
 }
+
+func TestOrderBookOpenClose(t *testing.T) {
+
+	require := require.New(t)
+
+	book := NewOrderBook()
+
+	order1 := NewOrder(uuid.NewV4(), "TT", 2.0, 10, Buy)
+	order1.Account = "ACC1"
+	order2 := NewOrder(uuid.NewV4(), "TT", 2.0, 10, Sell)
+	order2.Account = "ACC1"
+
+	book.Open(order1)
+	book.Open(order2)
+	require.Len(book.Accounts["ACC1"], 2)
+
+	book.Close(order1)
+	require.Equal(map[uuid.UUID]*Order{order2.ID: order2}, book.Accounts["ACC1"])
+
+	book.Close(order2)
+	book.Close(order2)
+	require.Empty(book.Accounts)
+}
+
+func TestOrderBookIsInstrument(t *testing.T) {
+
+	require := require.New(t)
+
+	book := NewOrderBook()
+	require.True(book.IsInstrument("TT"))
+
+	book.Instruments = map[string]bool{"AA": true}
+	require.True(book.IsInstrument("AA"))
+	require.False(book.IsInstrument("TT"))
+
+	delete(book.Instruments, "AA")
+	require.False(book.IsInstrument("AA"))
+}
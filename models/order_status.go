@@ -12,6 +12,7 @@ const (
 	FullyFilled
 	OverFilled
 	Cancelled
+	Rejected
 )
 
 // Order status string
@@ -21,6 +22,7 @@ const (
 	FullyFilledText     = "FullyFilled"
 	OverFilledText      = "OverFilled"
 	CancelledText       = "Cancelled"
+	RejectedText        = "Rejected"
 )
 
 func (o OrderStatus) String() string {
@@ -35,6 +37,8 @@ func (o OrderStatus) String() string {
 		return OverFilledText
 	case Cancelled:
 		return CancelledText
+	case Rejected:
+		return RejectedText
 	default:
 		return fmt.Sprintf("Not mapped value %d", o)
 	}
@@ -63,6 +67,8 @@ func OrderStatusFromString(value string) (OrderStatus, error) {
 		return OverFilled, nil
 	case CancelledText:
 		return Cancelled, nil
+	case RejectedText:
+		return Rejected, nil
 	default:
 		return 9, fmt.Errorf("Not mapped %s", value)
 	}
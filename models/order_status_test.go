@@ -15,6 +15,7 @@ var orderStatusTradeableTests = []struct {
 	{FullyFilled, false},
 	{OverFilled, false},
 	{Cancelled, false},
+	{Rejected, false},
 }
 
 func TestOrderStatusIsTradeable(t *testing.T) {
@@ -34,6 +35,7 @@ var orderStatusTests = []struct {
 	{FullyFilled, FullyFilledText},
 	{OverFilled, OverFilledText},
 	{Cancelled, CancelledText},
+	{Rejected, RejectedText},
 	{9, "Not mapped value 9"},
 }
 
@@ -72,6 +74,10 @@ var orderStatusFromStringTests = []struct {
 		status   OrderStatus
 		hasError bool
 	}{Cancelled, false}},
+	{RejectedText, struct {
+		status   OrderStatus
+		hasError bool
+	}{Rejected, false}},
 	{"9", struct {
 		status   OrderStatus
 		hasError bool
@@ -58,6 +58,27 @@ func TestTrade(t *testing.T) {
 	require.Equal(PartiallyFilled, order.Status)
 }
 
+func TestBust(t *testing.T) {
+
+	require := require.New(t)
+
+	order := getOrder(10, 0)
+	order.Trade(4)
+	order.Bust(4)
+
+	require.Equal(uint(6), order.Quantity)
+	require.Equal(uint(0), order.Traded)
+	require.Equal(uint(6), order.Remaining())
+	require.Equal(Pending, order.Status)
+
+	order.Trade(6)
+	order.Bust(2)
+
+	require.Equal(uint(4), order.Quantity)
+	require.Equal(uint(4), order.Traded)
+	require.Equal(FullyFilled, order.Status)
+}
+
 func getOrder(quantity uint, traded uint) *Order {
 
 	u, _ := uuid.FromString("d1de4242-6620-4030-b2a7-4a701631c3ba")
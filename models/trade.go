@@ -0,0 +1,33 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// Trade defines a match between a buy and a sell order. The sequence is per symbol.
+// The accounts of the orders are set by the exchange, they are never published with the trade.
+type Trade struct {
+	ID          uuid.UUID
+	Sequence    uint64
+	Symbol      string
+	Price       float64
+	Quantity    uint
+	Aggressor   TradeDirection
+	BuyOrderID  uuid.UUID
+	SellOrderID uuid.UUID
+	Occured     time.Time
+	BuyAccount  string
+	SellAccount string
+}
+
+// NewTrade creates a new trade
+func NewTrade(id uuid.UUID, sequence uint64, symbol string, price float64, quantity uint, aggressor TradeDirection, buyOrderID uuid.UUID, sellOrderID uuid.UUID, occured time.Time) *Trade {
+	return &Trade{id, sequence, symbol, price, quantity, aggressor, buyOrderID, sellOrderID, occured, "", ""}
+}
+
+func (t *Trade) String() string {
+	return fmt.Sprintf("[%d] %s %d@%f %s %s/%s", t.Sequence, t.Symbol, t.Quantity, t.Price, t.Aggressor.String(), t.BuyOrderID, t.SellOrderID)
+}
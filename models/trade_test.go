@@ -0,0 +1,20 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradeString(t *testing.T) {
+
+	require := require.New(t)
+
+	buyID := uuid.NewV4()
+	sellID := uuid.NewV4()
+	trade := NewTrade(uuid.NewV4(), 1, "TT", 2.0, 10, Buy, buyID, sellID, time.Now().UTC())
+
+	require.Equal("[1] TT 10@2.000000 Buy "+buyID.String()+"/"+sellID.String(), trade.String())
+}
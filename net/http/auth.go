@@ -0,0 +1,231 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Authentication headers
+const (
+	APIKeyHeader    = "X-API-Key"
+	TimestampHeader = "X-Timestamp"
+	SignatureHeader = "X-Signature"
+)
+
+// MaxSignatureAge is the maximum allowed difference between the signature timestamp and now
+const MaxSignatureAge = 5 * time.Minute
+
+type contextKey string
+
+const (
+	accountContextKey contextKey = "account"
+	apiKeyContextKey  contextKey = "apikey"
+)
+
+// Account defines a participant account. Accounts with a secret have to sign their requests.
+// Accounts without a role are traders.
+type Account struct {
+	Name   string `json:"name"`
+	APIKey string `json:"api_key"`
+	Secret string `json:"secret"`
+	Role   Role   `json:"role"`
+}
+
+// GetRole returns the role of the account
+func (a *Account) GetRole() Role {
+	if a.Role == "" {
+		return TraderRole
+	}
+	return a.Role
+}
+
+// AccountStore interface
+type AccountStore interface {
+	GetAccount(apiKey string) (*Account, bool)
+}
+
+// InMemoryAccountStore holds the accounts in memory
+type InMemoryAccountStore struct {
+	accounts map[string]*Account
+	mu       sync.RWMutex
+}
+
+// NewInMemoryAccountStore creates a new in memory account store
+func NewInMemoryAccountStore(accounts ...*Account) *InMemoryAccountStore {
+
+	store := &InMemoryAccountStore{accounts: make(map[string]*Account)}
+
+	for _, a := range accounts {
+		store.accounts[a.APIKey] = a
+	}
+
+	return store
+}
+
+// NewInMemoryAccountStoreFromFile creates a new in memory account store from a json file
+func NewInMemoryAccountStoreFromFile(path string) (*InMemoryAccountStore, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var accounts []*Account
+
+	err = json.NewDecoder(file).Decode(&accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInMemoryAccountStore(accounts...), nil
+}
+
+// GetAccount returns the account of the api key
+func (s *InMemoryAccountStore) GetAccount(apiKey string) (*Account, bool) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, ok := s.accounts[apiKey]
+	return account, ok
+}
+
+// WithAccount returns a copy of the context which carries the account name
+func WithAccount(ctx context.Context, account string) context.Context {
+	return context.WithValue(ctx, accountContextKey, account)
+}
+
+// AccountFromContext returns the authenticated account name
+func AccountFromContext(ctx context.Context) (string, bool) {
+	account, ok := ctx.Value(accountContextKey).(string)
+	return account, ok
+}
+
+// WithAPIKey returns a copy of the context which carries the api key the caller authenticated with
+func WithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey, apiKey)
+}
+
+// APIKeyFromContext returns the api key the caller authenticated with
+func APIKeyFromContext(ctx context.Context) (string, bool) {
+	apiKey, ok := ctx.Value(apiKeyContextKey).(string)
+	return apiKey, ok
+}
+
+// Sign returns the hex encoded HMAC-SHA256 signature of the request parts
+func Sign(secret string, method string, uri string, timestamp string, body []byte) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + uri + "\n" + timestamp + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest sets the api key, and when the account has a secret the timestamp and signature headers
+func SignRequest(r *http.Request, account *Account) error {
+
+	r.Header.Set(APIKeyHeader, account.APIKey)
+
+	if account.Secret == "" {
+		return nil
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+	r.Header.Set(TimestampHeader, timestamp)
+	r.Header.Set(SignatureHeader, Sign(account.Secret, r.Method, r.URL.RequestURI(), timestamp, body))
+	return nil
+}
+
+// APIKeyAuthMiddleware authenticates requests by api key and, for accounts with a secret, by HMAC signature.
+// The account name, role and api key are attached to the request context.
+func APIKeyAuthMiddleware(store AccountStore, next httprouter.Handle) httprouter.Handle {
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+		account, ok := store.GetAccount(r.Header.Get(APIKeyHeader))
+		if !ok {
+			log.Print("Request with unknown api key")
+			WriteError(w, r, http.StatusUnauthorized, UnauthorizedCode, "Unknown api key")
+			return
+		}
+
+		if account.Secret != "" && !verifySignature(r, account) {
+			WriteError(w, r, http.StatusUnauthorized, UnauthorizedCode, "Invalid signature")
+			return
+		}
+
+		next(w, r.WithContext(WithAPIKey(WithRole(WithAccount(r.Context(), account.Name), account.GetRole()), account.APIKey)), ps)
+	}
+}
+
+func verifySignature(r *http.Request, account *Account) bool {
+
+	body, err := readBody(r)
+	if err != nil {
+		log.Printf("Failed to read body. %s", err)
+		return false
+	}
+
+	return VerifySignature(account, r.Method, r.URL.RequestURI(), r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader), body)
+}
+
+// VerifySignature validates the timestamp and the HMAC signature of the request parts
+func VerifySignature(account *Account, method string, uri string, timestamp string, signature string, body []byte) bool {
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		log.Printf("Account %s sent invalid timestamp %s", account.Name, timestamp)
+		return false
+	}
+
+	age := time.Since(time.Unix(seconds, 0))
+	if age > MaxSignatureAge || age < -MaxSignatureAge {
+		log.Printf("Account %s sent expired timestamp %s", account.Name, timestamp)
+		return false
+	}
+
+	expected := Sign(account.Secret, method, uri, timestamp, body)
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		log.Printf("Account %s sent invalid signature", account.Name)
+		return false
+	}
+
+	return true
+}
+
+// readBody reads the body and replaces it so it can be read again
+func readBody(r *http.Request) ([]byte, error) {
+
+	if r.Body == nil {
+		return []byte{}, nil
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
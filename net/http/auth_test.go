@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+)
+
+func getAuthRouter(store AccountStore, account *string) *httprouter.Router {
+
+	router := httprouter.New()
+	router.Handle(http.MethodPost, "/orders", APIKeyAuthMiddleware(store, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		*account, _ = AccountFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+		if r.Body != nil {
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write(body)
+		}
+	}))
+	return router
+}
+
+func TestAPIKeyAuthMiddlewareSuccess(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewInMemoryAccountStore(&Account{"ACC1", "KEY1", "", TraderRole})
+	var account string
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", nil)
+	request.Header.Set(APIKeyHeader, "KEY1")
+
+	response := httptest.NewRecorder()
+	getAuthRouter(store, &account).ServeHTTP(response, request)
+
+	require.Equal(http.StatusOK, response.Code)
+	require.Equal("ACC1", account)
+}
+
+func TestAPIKeyAuthMiddlewareUnknownKey(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewInMemoryAccountStore(&Account{"ACC1", "KEY1", "", TraderRole})
+	var account string
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", nil)
+	request.Header.Set(APIKeyHeader, "KEY2")
+
+	response := httptest.NewRecorder()
+	getAuthRouter(store, &account).ServeHTTP(response, request)
+
+	require.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func TestAPIKeyAuthMiddlewareSignedSuccess(t *testing.T) {
+
+	require := require.New(t)
+
+	acc := &Account{"ACC1", "KEY1", "SECRET", TraderRole}
+	store := NewInMemoryAccountStore(acc)
+	var account string
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(`{"id":"1"}`))
+	err := SignRequest(request, acc)
+	require.Nil(err)
+
+	response := httptest.NewRecorder()
+	getAuthRouter(store, &account).ServeHTTP(response, request)
+
+	require.Equal(http.StatusOK, response.Code)
+	require.Equal("ACC1", account)
+	require.Equal(`{"id":"1"}`, response.Body.String())
+}
+
+func TestAPIKeyAuthMiddlewareMissingSignature(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewInMemoryAccountStore(&Account{"ACC1", "KEY1", "SECRET", TraderRole})
+	var account string
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(`{"id":"1"}`))
+	request.Header.Set(APIKeyHeader, "KEY1")
+
+	response := httptest.NewRecorder()
+	getAuthRouter(store, &account).ServeHTTP(response, request)
+
+	require.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func TestAPIKeyAuthMiddlewareTamperedBody(t *testing.T) {
+
+	require := require.New(t)
+
+	acc := &Account{"ACC1", "KEY1", "SECRET", TraderRole}
+	store := NewInMemoryAccountStore(acc)
+	var account string
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(`{"id":"1"}`))
+	SignRequest(request, acc)
+	request.Body = ioutil.NopCloser(bytes.NewBufferString(`{"id":"2"}`))
+
+	response := httptest.NewRecorder()
+	getAuthRouter(store, &account).ServeHTTP(response, request)
+
+	require.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func TestNewInMemoryAccountStoreFromFile(t *testing.T) {
+
+	require := require.New(t)
+
+	file, err := ioutil.TempFile("", "accounts")
+	require.Nil(err)
+	defer os.Remove(file.Name())
+
+	file.WriteString(`[{"name":"ACC1","api_key":"KEY1","secret":""}]`)
+	file.Close()
+
+	store, err := NewInMemoryAccountStoreFromFile(file.Name())
+	require.Nil(err)
+
+	account, ok := store.GetAccount("KEY1")
+	require.True(ok)
+	require.Equal("ACC1", account.Name)
+}
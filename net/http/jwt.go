@@ -0,0 +1,175 @@
+package http
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Claims defines the claims of a bearer token
+type Claims struct {
+	Subject   string `json:"sub"`
+	Role      Role   `json:"role"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// JWTVerifier verifies bearer tokens offline against locally configured public keys
+type JWTVerifier struct {
+	keys map[string]crypto.PublicKey
+}
+
+// NewJWTVerifier creates a new verifier with the public keys by key id
+func NewJWTVerifier(keys map[string]crypto.PublicKey) *JWTVerifier {
+	return &JWTVerifier{keys}
+}
+
+// LoadPublicKeys loads the PEM encoded public keys of a directory, the file name without .pem is the key id
+func LoadPublicKeys(dir string) (map[string]crypto.PublicKey, error) {
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+
+	for _, file := range files {
+
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("No PEM data found in %s", file)
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		keys[strings.TrimSuffix(filepath.Base(file), ".pem")] = key
+	}
+
+	return keys, nil
+}
+
+// Verify checks the signature and the validity period of the token and returns its claims.
+// Supported algorithms are RS256, ES256 and EdDSA.
+func (v *JWTVerifier) Verify(token string) (*Claims, error) {
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("Token is malformed")
+	}
+
+	var header jwtHeader
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := v.keys[header.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("Key %s not found", header.KeyID)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	err = verifySignatureWithKey(header.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Unix()
+
+	if claims.ExpiresAt == 0 || now >= claims.ExpiresAt {
+		return nil, errors.New("Token is expired")
+	}
+
+	if now < claims.NotBefore {
+		return nil, errors.New("Token is not valid yet")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("Token has no subject")
+	}
+
+	return &claims, nil
+}
+
+func verifySignatureWithKey(algorithm string, key crypto.PublicKey, signed []byte, signature []byte) error {
+
+	switch algorithm {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("Key is not a RSA key")
+		}
+		hash := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, hash[:], signature)
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return errors.New("Key is not a ECDSA key or signature is malformed")
+		}
+		hash := sha256.Sum256(signed)
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, hash[:], r, s) {
+			return errors.New("Signature is invalid")
+		}
+		return nil
+	case "EdDSA":
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("Key is not a Ed25519 key")
+		}
+		if !ed25519.Verify(edKey, signed, signature) {
+			return errors.New("Signature is invalid")
+		}
+		return nil
+	default:
+		return fmt.Errorf("Algorithm %s not supported", algorithm)
+	}
+}
+
+func decodeSegment(segment string, v interface{}) error {
+
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
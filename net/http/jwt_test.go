@@ -0,0 +1,134 @@
+package http
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func signToken(algorithm string, kid string, key crypto.PrivateKey, claims Claims) string {
+
+	header, _ := json.Marshal(jwtHeader{algorithm, kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signature, _ = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, hash[:])
+	case *ecdsa.PrivateKey:
+		r, s, _ := ecdsa.Sign(rand.Reader, k, hash[:])
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signed))
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims(role Role) Claims {
+	return Claims{"ACC1", role, time.Now().Add(time.Hour).Unix(), 0}
+}
+
+func TestJWTVerifierAlgorithms(t *testing.T) {
+
+	require := require.New(t)
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPublic, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	verifier := NewJWTVerifier(map[string]crypto.PublicKey{
+		"rsa": &rsaKey.PublicKey,
+		"ec":  &ecKey.PublicKey,
+		"ed":  edPublic,
+	})
+
+	tokens := []string{
+		signToken("RS256", "rsa", rsaKey, validClaims(AdminRole)),
+		signToken("ES256", "ec", ecKey, validClaims(AdminRole)),
+		signToken("EdDSA", "ed", edKey, validClaims(AdminRole)),
+	}
+
+	for _, token := range tokens {
+		claims, err := verifier.Verify(token)
+		require.Nil(err)
+		require.Equal("ACC1", claims.Subject)
+		require.Equal(AdminRole, claims.Role)
+	}
+}
+
+func TestJWTVerifierInvalid(t *testing.T) {
+
+	require := require.New(t)
+
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	otherPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	verifier := NewJWTVerifier(map[string]crypto.PublicKey{"ed": otherPublic})
+
+	_, err := verifier.Verify(signToken("EdDSA", "ed", key, validClaims(TraderRole)))
+	require.NotNil(err)
+
+	_, err = verifier.Verify(signToken("EdDSA", "unknown", key, validClaims(TraderRole)))
+	require.NotNil(err)
+
+	_, err = verifier.Verify("not.a-token")
+	require.NotNil(err)
+}
+
+func TestJWTVerifierExpired(t *testing.T) {
+
+	require := require.New(t)
+
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	verifier := NewJWTVerifier(map[string]crypto.PublicKey{"ed": public})
+
+	claims := validClaims(TraderRole)
+	claims.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	_, err := verifier.Verify(signToken("EdDSA", "ed", key, claims))
+	require.NotNil(err)
+
+	claims = validClaims(TraderRole)
+	claims.NotBefore = time.Now().Add(time.Hour).Unix()
+	_, err = verifier.Verify(signToken("EdDSA", "ed", key, claims))
+	require.NotNil(err)
+}
+
+func TestLoadPublicKeys(t *testing.T) {
+
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "keys")
+	require.Nil(err)
+	defer os.RemoveAll(dir)
+
+	public, _, _ := ed25519.GenerateKey(rand.Reader)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.Nil(err)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "key1.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600)
+	require.Nil(err)
+
+	keys, err := LoadPublicKeys(dir)
+	require.Nil(err)
+	require.Len(keys, 1)
+	require.Equal(public, keys["key1"])
+}
@@ -1,7 +1,11 @@
 package http
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -40,6 +44,20 @@ func (w *statusLoggingResponseWriter) WriteHeader(code int) {
 	w.statusHeaderWritten = true
 }
 
+// Hijack lets the connection be taken over, e.g. by websockets
+func (w *statusLoggingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+
+	hijacker, ok := w.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("Response writer does not support hijacking")
+	}
+
+	w.status = http.StatusSwitchingProtocols
+	w.statusHeaderWritten = true
+
+	return hijacker.Hijack()
+}
+
 // DefaultMiddleware which handles Logging and Recover middleware
 func DefaultMiddleware(next httprouter.Handle) httprouter.Handle {
 	return LoggingMiddleware(RecoveryMiddleware(next))
@@ -85,7 +103,7 @@ func RecoveryMiddleware(next httprouter.Handle) httprouter.Handle {
 		defer func() {
 			if err := recover(); err != nil {
 				log.Printf("[ERROR] %s", err)
-				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				WriteError(w, r, http.StatusInternalServerError, InternalErrorCode, "")
 			}
 		}()
 
@@ -101,7 +119,7 @@ func POSTJSONValidationMiddleware(next httprouter.Handle) httprouter.Handle {
 		if r.Method != http.MethodPost {
 
 			log.Printf("Http method POST was expected, but received %s instead", r.Method)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			WriteError(w, r, http.StatusBadRequest, InvalidMethodCode, fmt.Sprintf("Http method POST was expected, but received %s instead", r.Method))
 			return
 		}
 
@@ -110,7 +128,7 @@ func POSTJSONValidationMiddleware(next httprouter.Handle) httprouter.Handle {
 		if !strings.HasPrefix(contentType, "application/json") {
 
 			log.Printf("Content type is not 'application/json', but %s instead", contentType)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			WriteError(w, r, http.StatusBadRequest, InvalidContentTypeCode, fmt.Sprintf("Content type is not 'application/json', but %s instead", contentType))
 			return
 		}
 
@@ -126,7 +144,7 @@ func GETValidationMiddleware(next httprouter.Handle) httprouter.Handle {
 		if r.Method != http.MethodGet {
 
 			log.Printf("Http method GET was expected, but received %s instead", r.Method)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			WriteError(w, r, http.StatusBadRequest, InvalidMethodCode, fmt.Sprintf("Http method GET was expected, but received %s instead", r.Method))
 			return
 		}
 
@@ -142,7 +160,7 @@ func PUTJSONValidationMiddleware(next httprouter.Handle) httprouter.Handle {
 		if r.Method != http.MethodPut {
 
 			log.Printf("Http method PUT was expected, but received %s instead", r.Method)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			WriteError(w, r, http.StatusBadRequest, InvalidMethodCode, fmt.Sprintf("Http method PUT was expected, but received %s instead", r.Method))
 			return
 		}
 
@@ -151,7 +169,7 @@ func PUTJSONValidationMiddleware(next httprouter.Handle) httprouter.Handle {
 		if !strings.HasPrefix(contentType, "application/json") {
 
 			log.Printf("Content type is not 'application/json', but %s instead", contentType)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			WriteError(w, r, http.StatusBadRequest, InvalidContentTypeCode, fmt.Sprintf("Content type is not 'application/json', but %s instead", contentType))
 			return
 		}
 
@@ -167,7 +185,7 @@ func DELETEValidationMiddleware(next httprouter.Handle) httprouter.Handle {
 		if r.Method != http.MethodDelete {
 
 			log.Printf("Http method DELETE was expected, but received %s instead", r.Method)
-			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			WriteError(w, r, http.StatusBadRequest, InvalidMethodCode, fmt.Sprintf("Http method DELETE was expected, but received %s instead", r.Method))
 			return
 		}
 
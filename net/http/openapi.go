@@ -0,0 +1,451 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification of the documents
+const OpenAPIVersion = "3.0.3"
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// OpenAPI defines a OpenAPI 3 document
+type OpenAPI struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]*PathItem  `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+// Info defines the title and version of a api
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem defines the operations of a path
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Operation defines a api operation, the keys of the responses are the http status codes
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter defines a path or query parameter of a operation
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path or query
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody defines the body of a operation by content type
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response defines a response of a operation by content type
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType defines the schema of a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components defines the schemas and security schemes referenced by the document
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme defines a authentication method of the api
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Schema defines the subset of the OpenAPI schema object which is used by the services and validated by RequestValidationMiddleware
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+}
+
+// NewOpenAPI creates a new document which contains the Problem and FieldError schemas of the error responses
+func NewOpenAPI(title string, version string) *OpenAPI {
+
+	schemas := map[string]*Schema{
+		"FieldError": ObjectSchema([]string{"field", "message"}, map[string]*Schema{
+			"field":   {Type: "string"},
+			"message": {Type: "string"},
+		}),
+		"Problem": ObjectSchema([]string{"type", "title", "status", "code"}, map[string]*Schema{
+			"type":     {Type: "string"},
+			"title":    {Type: "string"},
+			"status":   {Type: "integer"},
+			"code":     {Type: "string", Description: "stable error code"},
+			"detail":   {Type: "string"},
+			"instance": {Type: "string"},
+			"errors":   ArraySchema(RefSchema("FieldError")),
+		}),
+	}
+
+	return &OpenAPI{OpenAPIVersion, Info{title, version}, make(map[string]*PathItem), Components{schemas, nil}, nil}
+}
+
+// RefSchema returns a reference to a schema of the components
+func RefSchema(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// ObjectSchema returns a object schema with the required properties
+func ObjectSchema(required []string, properties map[string]*Schema) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// ArraySchema returns a array schema of the items
+func ArraySchema(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// JSONContent returns the content of a schema as application/json
+func JSONContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {schema}}
+}
+
+// ProblemResponse returns a response of the problem schema
+func ProblemResponse(description string) *Response {
+	return &Response{description, map[string]*MediaType{ProblemContentType: {RefSchema("Problem")}}}
+}
+
+// Operation returns the operation of the method on the path, the path can be a httprouter route e.g. /orders/:orderid
+func (d *OpenAPI) Operation(method string, path string) (*Operation, bool) {
+
+	item, ok := d.Paths[getOpenAPIPath(path)]
+	if !ok {
+		return nil, false
+	}
+
+	var operation *Operation
+	switch method {
+	case http.MethodGet:
+		operation = item.Get
+	case http.MethodPost:
+		operation = item.Post
+	case http.MethodPut:
+		operation = item.Put
+	case http.MethodDelete:
+		operation = item.Delete
+	}
+	return operation, operation != nil
+}
+
+// Validate validates the decoded JSON value against the schema and returns the errors of the invalid fields.
+// The field is the name of the value in the errors, empty for the root of the body.
+func (d *OpenAPI) Validate(schema *Schema, field string, value interface{}) []FieldError {
+
+	var validation ValidationError
+	d.validate(schema, field, value, &validation)
+	return validation.Fields
+}
+
+func (d *OpenAPI) validate(schema *Schema, field string, value interface{}, validation *ValidationError) {
+
+	schema = d.resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	name := field
+	if name == "" {
+		name = "body"
+	}
+
+	if value == nil {
+		validation.Add(name, "must not be null")
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			validation.Add(name, "must be an object")
+			return
+		}
+		for _, required := range schema.Required {
+			if _, ok := object[required]; !ok {
+				validation.Add(joinField(field, required), "is required")
+			}
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			property := object[key]
+			propertySchema, ok := schema.Properties[key]
+			if !ok {
+				if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					validation.Add(joinField(field, key), "is not allowed")
+				}
+				continue
+			}
+			d.validate(propertySchema, joinField(field, key), property, validation)
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			validation.Add(name, "must be an array")
+			return
+		}
+		if schema.MinItems != nil && len(array) < *schema.MinItems {
+			validation.Add(name, "must have at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			validation.Add(name, "must have at most %d items", *schema.MaxItems)
+		}
+		for i, item := range array {
+			d.validate(schema.Items, fmt.Sprintf("%s[%d]", field, i), item, validation)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			validation.Add(name, "must be a string")
+			return
+		}
+		validateString(schema, name, text, validation)
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			validation.Add(name, "must be a %s", schema.Type)
+			return
+		}
+		if schema.Type == "integer" && number != math.Trunc(number) {
+			validation.Add(name, "must be an integer")
+			return
+		}
+		validateNumber(schema, name, number, validation)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			validation.Add(name, "must be a boolean")
+		}
+	}
+}
+
+func validateString(schema *Schema, name string, text string, validation *ValidationError) {
+
+	if schema.MinLength != nil && len(text) < *schema.MinLength {
+		if *schema.MinLength == 1 {
+			validation.Add(name, "is required")
+		} else {
+			validation.Add(name, "must have at least %d characters", *schema.MinLength)
+		}
+		return
+	}
+
+	if len(schema.Enum) > 0 && !contains(schema.Enum, text) {
+		validation.Add(name, "%q is not one of %s", text, strings.Join(schema.Enum, ", "))
+		return
+	}
+
+	if schema.Format == "uuid" && !uuidPattern.MatchString(text) {
+		validation.Add(name, "%q is not a uuid", text)
+	}
+}
+
+func validateNumber(schema *Schema, name string, number float64, validation *ValidationError) {
+
+	if schema.Minimum != nil {
+		if schema.ExclusiveMinimum && number <= *schema.Minimum {
+			validation.Add(name, "must be greater than %g", *schema.Minimum)
+		} else if !schema.ExclusiveMinimum && number < *schema.Minimum {
+			validation.Add(name, "must be at least %g", *schema.Minimum)
+		}
+	}
+
+	if schema.Maximum != nil && number > *schema.Maximum {
+		validation.Add(name, "must be at most %g", *schema.Maximum)
+	}
+}
+
+// validateParameter converts the text of a path or query parameter to the type of its schema and validates it
+func (d *OpenAPI) validateParameter(parameter *Parameter, text string, validation *ValidationError) {
+
+	schema := d.resolve(parameter.Schema)
+	if schema == nil {
+		return
+	}
+
+	var value interface{} = text
+
+	switch schema.Type {
+	case "integer", "number":
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			validation.Add(parameter.Name, "%q is not a %s", text, schema.Type)
+			return
+		}
+		value = number
+	case "boolean":
+		flag, err := strconv.ParseBool(text)
+		if err != nil {
+			validation.Add(parameter.Name, "%q is not a boolean", text)
+			return
+		}
+		value = flag
+	}
+
+	d.validate(schema, parameter.Name, value, validation)
+}
+
+func (d *OpenAPI) resolve(schema *Schema) *Schema {
+
+	for schema != nil && schema.Ref != "" {
+		schema = d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+// RequestValidationMiddleware validates the parameters and the JSON body of requests against the operation of the route in the document.
+// Requests of methods without a operation are passed to the next handler.
+func RequestValidationMiddleware(doc *OpenAPI, route string, next httprouter.Handle) httprouter.Handle {
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+		operation, ok := doc.Operation(r.Method, route)
+		if !ok {
+			next(w, r, ps)
+			return
+		}
+
+		var validation ValidationError
+
+		for _, parameter := range operation.Parameters {
+
+			var value string
+			if parameter.In == "path" {
+				value = ps.ByName(parameter.Name)
+			} else {
+				value = r.URL.Query().Get(parameter.Name)
+			}
+
+			if value == "" {
+				if parameter.Required {
+					validation.Add(parameter.Name, "is required")
+				}
+				continue
+			}
+			doc.validateParameter(parameter, value, &validation)
+		}
+
+		if operation.RequestBody != nil {
+
+			body, err := readBody(r)
+			if err != nil {
+				log.Printf("Failed to read body. %s", err)
+				WriteError(w, r, http.StatusBadRequest, InvalidRequestCode, "Failed to read body")
+				return
+			}
+
+			var value interface{}
+			err = json.Unmarshal(body, &value)
+			if err != nil {
+				log.Printf("Request body is not valid JSON! %s", err)
+				WriteError(w, r, http.StatusBadRequest, InvalidRequestCode, err.Error())
+				return
+			}
+
+			if media, ok := operation.RequestBody.Content["application/json"]; ok {
+				doc.validate(media.Schema, "", value, &validation)
+			}
+		}
+
+		if err := validation.Err(); err != nil {
+			log.Printf("Request of %s does not match the schema! %s", operation.OperationID, err)
+			WriteProblem(w, r, NewProblem(http.StatusBadRequest, ValidationFailedCode, "Request does not match the schema", validation.Fields...))
+			return
+		}
+
+		next(w, r, ps)
+	}
+}
+
+// OpenAPIHandle returns a handler which serves the document as JSON
+func OpenAPIHandle(doc *OpenAPI) httprouter.Handle {
+
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		encoded, _ := json.Marshal(doc)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(encoded)
+	}
+}
+
+// getOpenAPIPath converts the parameters of a httprouter route, e.g. :orderid, to OpenAPI path parameters
+func getOpenAPIPath(route string) string {
+
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func joinField(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestOpenAPI() *OpenAPI {
+
+	zero := 0.0
+	limit := 100.0
+	one := 1
+
+	doc := NewOpenAPI("test", "1.0.0")
+	doc.Components.Schemas["Order"] = ObjectSchema([]string{"symbol", "quantity"}, map[string]*Schema{
+		"id":        {Type: "string", Format: "uuid"},
+		"symbol":    {Type: "string", MinLength: &one},
+		"quantity":  {Type: "integer", Minimum: &zero, ExclusiveMinimum: true},
+		"direction": {Type: "string", Enum: []string{"Buy", "Sell"}},
+		"tags":      ArraySchema(&Schema{Type: "string"}),
+	})
+	doc.Paths["/orders/{orderid}"] = &PathItem{
+		Put: &Operation{
+			OperationID: "amendOrder",
+			Parameters: []*Parameter{
+				{Name: "orderid", In: "path", Required: true, Schema: &Schema{Type: "string", Format: "uuid"}},
+				{Name: "limit", In: "query", Schema: &Schema{Type: "integer", Minimum: &zero, Maximum: &limit}},
+			},
+			RequestBody: &RequestBody{true, JSONContent(RefSchema("Order"))},
+			Responses:   map[string]*Response{"202": {Description: "Accepted"}},
+		},
+	}
+	return doc
+}
+
+func TestOpenAPIValidate(t *testing.T) {
+
+	require := require.New(t)
+
+	doc := getTestOpenAPI()
+
+	var value interface{}
+	json.Unmarshal([]byte(`{"id":"1","quantity":1.5,"direction":"Up","tags":["A",1]}`), &value)
+
+	errors := doc.Validate(RefSchema("Order"), "", value)
+	require.Equal([]FieldError{
+		{"symbol", "is required"},
+		{"direction", `"Up" is not one of Buy, Sell`},
+		{"id", `"1" is not a uuid`},
+		{"quantity", "must be an integer"},
+		{"tags[1]", "must be a string"},
+	}, errors)
+
+	json.Unmarshal([]byte(`{"id":"0f8fad5b-d9cb-469f-a165-70867728950e","symbol":"TT","quantity":10,"direction":"Buy"}`), &value)
+	require.Empty(doc.Validate(RefSchema("Order"), "", value))
+
+	json.Unmarshal([]byte(`{"symbol":"","quantity":0}`), &value)
+	require.Equal([]FieldError{{"quantity", "must be greater than 0"}, {"symbol", "is required"}}, doc.Validate(RefSchema("Order"), "", value))
+
+	json.Unmarshal([]byte(`[]`), &value)
+	require.Equal([]FieldError{{"body", "must be an object"}}, doc.Validate(RefSchema("Order"), "", value))
+}
+
+func TestRequestValidationMiddleware(t *testing.T) {
+
+	require := require.New(t)
+
+	doc := getTestOpenAPI()
+	var body []byte
+
+	router := httprouter.New()
+	router.Handle(http.MethodPut, "/orders/:orderid", RequestValidationMiddleware(doc, "/orders/:orderid", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	put := func(uri string, payload string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(http.MethodPut, uri, bytes.NewBufferString(payload))
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	response := put("/orders/0f8fad5b-d9cb-469f-a165-70867728950e?limit=10", `{"symbol":"TT","quantity":10}`)
+	require.Equal(http.StatusAccepted, response.Code)
+	require.Equal(`{"symbol":"TT","quantity":10}`, string(body))
+
+	response = put("/orders/1?limit=500", `{"symbol":"TT","quantity":10}`)
+	require.Equal(http.StatusBadRequest, response.Code)
+
+	var problem Problem
+	err := json.Unmarshal(response.Body.Bytes(), &problem)
+	require.Nil(err)
+	require.Equal(ValidationFailedCode, problem.Code)
+	require.Equal([]FieldError{{"orderid", `"1" is not a uuid`}, {"limit", "must be at most 100"}}, problem.Errors)
+
+	response = put("/orders/0f8fad5b-d9cb-469f-a165-70867728950e", `{"symbol":`)
+	require.Equal(http.StatusBadRequest, response.Code)
+	err = json.Unmarshal(response.Body.Bytes(), &problem)
+	require.Nil(err)
+	require.Equal(InvalidRequestCode, problem.Code)
+}
+
+func TestOpenAPIHandle(t *testing.T) {
+
+	require := require.New(t)
+
+	request, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	response := httptest.NewRecorder()
+
+	OpenAPIHandle(getTestOpenAPI())(response, request, nil)
+
+	require.Equal(http.StatusOK, response.Code)
+
+	var doc map[string]interface{}
+	err := json.Unmarshal(response.Body.Bytes(), &doc)
+	require.Nil(err)
+	require.Equal(OpenAPIVersion, doc["openapi"])
+	require.Contains(doc["paths"], "/orders/{orderid}")
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the content type of the error responses
+const ProblemContentType = "application/problem+json"
+
+// ErrorCode defines the stable code of a error response
+type ErrorCode string
+
+// Error codes shared by the services, services add their own codes e.g. the reject reasons of orders
+const (
+	InvalidRequestCode     ErrorCode = "InvalidRequest"
+	InvalidMethodCode      ErrorCode = "InvalidMethod"
+	InvalidContentTypeCode ErrorCode = "InvalidContentType"
+	ValidationFailedCode   ErrorCode = "ValidationFailed"
+	UnauthorizedCode       ErrorCode = "Unauthorized"
+	ForbiddenCode          ErrorCode = "Forbidden"
+	NotFoundCode           ErrorCode = "NotFound"
+	ConflictCode           ErrorCode = "Conflict"
+	InternalErrorCode      ErrorCode = "InternalError"
+)
+
+// FieldError defines the validation error of a request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError defines the failed validation of a request with the errors of its fields
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Add adds the error of a field
+func (e *ValidationError) Add(field string, format string, a ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{field, fmt.Sprintf(format, a...)})
+}
+
+// Err returns the validation error, or nil when no field failed
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Field+": "+f.Message)
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Problem defines a RFC 7807 problem details error response, extended with the error code and field errors
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Code     ErrorCode    `json:"code"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem creates a new problem, the title is the text of the status
+func NewProblem(status int, code ErrorCode, detail string, errors ...FieldError) *Problem {
+	return &Problem{"about:blank", http.StatusText(status), status, code, detail, "", errors}
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return fmt.Sprintf("%d %s", p.Status, p.Code)
+	}
+	return fmt.Sprintf("%d %s: %s", p.Status, p.Code, p.Detail)
+}
+
+// WriteProblem writes the problem as the response, the instance is the path of the request
+func WriteProblem(w http.ResponseWriter, r *http.Request, problem *Problem) {
+
+	problem.Instance = r.URL.Path
+
+	encoded, _ := json.Marshal(problem)
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	w.Write(encoded)
+}
+
+// WriteError writes a problem of the status and code as the response
+func WriteError(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, detail string) {
+	WriteProblem(w, r, NewProblem(status, code, detail))
+}
+
+// WriteNotFound writes a not found problem as the response
+func WriteNotFound(w http.ResponseWriter, r *http.Request, detail string) {
+	WriteError(w, r, http.StatusNotFound, NotFoundCode, detail)
+}
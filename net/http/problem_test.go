@@ -0,0 +1,60 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteProblem(t *testing.T) {
+
+	require := require.New(t)
+
+	request, _ := http.NewRequest(http.MethodPost, "/orders", nil)
+	response := httptest.NewRecorder()
+
+	WriteProblem(response, request, NewProblem(http.StatusBadRequest, ValidationFailedCode, "Invalid order", FieldError{"symbol", "is required"}))
+
+	require.Equal(http.StatusBadRequest, response.Code)
+	require.Equal(ProblemContentType, response.Header().Get("Content-Type"))
+
+	var problem Problem
+	err := json.Unmarshal(response.Body.Bytes(), &problem)
+	require.Nil(err)
+	require.Equal(Problem{"about:blank", "Bad Request", http.StatusBadRequest, ValidationFailedCode, "Invalid order", "/orders", []FieldError{{"symbol", "is required"}}}, problem)
+}
+
+func TestValidationError(t *testing.T) {
+
+	require := require.New(t)
+
+	var validation ValidationError
+	require.Nil(validation.Err())
+
+	validation.Add("symbol", "is required")
+	validation.Add("quantity", "%d is not greater than zero", 0)
+
+	err := validation.Err()
+	require.NotNil(err)
+	require.Equal("symbol: is required, quantity: 0 is not greater than zero", err.Error())
+	require.Len(validation.Fields, 2)
+}
+
+func TestMiddlewareProblem(t *testing.T) {
+
+	require := require.New(t)
+
+	request, _ := http.NewRequest(http.MethodPost, "/orderbook/TT", nil)
+	response := httptest.NewRecorder()
+
+	GETValidationMiddleware(MockHandler{false}.MockHandle)(response, request, nil)
+
+	var problem Problem
+	err := json.Unmarshal(response.Body.Bytes(), &problem)
+	require.Nil(err)
+	require.Equal(http.StatusBadRequest, problem.Status)
+	require.Equal(InvalidMethodCode, problem.Code)
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// RateLimitedCode is the error code of requests rejected by a rate limiter
+const RateLimitedCode ErrorCode = "RateLimited"
+
+// RetryAfterHeader tells rate limited clients how many seconds to wait
+const RetryAfterHeader = "Retry-After"
+
+// KeyFunc returns the key of the budget a request is counted against
+type KeyFunc func(r *http.Request) string
+
+// Rate limit keys
+const (
+	APIKeyRateLimitKey     = "apikey"
+	AccountRateLimitKey    = "account"
+	RemoteAddrRateLimitKey = "remote"
+)
+
+// KeyFuncFromString returns the key function of a rate limit key
+func KeyFuncFromString(value string) (KeyFunc, error) {
+	switch value {
+	case APIKeyRateLimitKey:
+		return KeyByAPIKey, nil
+	case AccountRateLimitKey:
+		return KeyByAccount, nil
+	case RemoteAddrRateLimitKey:
+		return KeyByRemoteAddr, nil
+	default:
+		return nil, fmt.Errorf("Rate limit key %s not supported", value)
+	}
+}
+
+// KeyByAPIKey keys requests by the api key they authenticated with, requests authenticated by token
+// by account and anonymous requests by remote address. Unauthenticated api key headers are not keys,
+// otherwise every made up api key would get a budget of its own.
+func KeyByAPIKey(r *http.Request) string {
+	if apiKey, ok := APIKeyFromContext(r.Context()); ok && apiKey != "" {
+		return "apikey:" + apiKey
+	}
+	return KeyByAccount(r)
+}
+
+// KeyByAccount keys requests by the authenticated account, anonymous requests by remote address
+func KeyByAccount(r *http.Request) string {
+	if account, ok := AccountFromContext(r.Context()); ok && account != "" {
+		return "account:" + account
+	}
+	return KeyByRemoteAddr(r)
+}
+
+// KeyByRemoteAddr keys requests by the remote host
+func KeyByRemoteAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "remote:" + host
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter holds a token bucket per key, refilled at rate tokens per second up to burst tokens
+type RateLimiter struct {
+	rate      float64
+	burst     float64
+	key       KeyFunc
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewRateLimiter creates a new rate limiter allowing rate requests per second with bursts of burst requests
+func NewRateLimiter(rate float64, burst int, key KeyFunc) *RateLimiter {
+	return &RateLimiter{rate, float64(burst), key, make(map[string]*tokenBucket), time.Time{}, time.Now, sync.Mutex{}}
+}
+
+// Key returns the key of the budget a request is counted against
+func (rl *RateLimiter) Key(r *http.Request) string {
+	return rl.key(r)
+}
+
+// Allow takes a token from the bucket of the key.
+// When the bucket is empty it returns false and the time until the next token.
+func (rl *RateLimiter) Allow(key string) (bool, time.Duration) {
+	return rl.AllowN(key, 1)
+}
+
+// AllowN takes n tokens from the bucket of the key.
+// A request costing more than the burst is allowed on a full bucket and leaves the bucket in debt.
+// When the bucket lacks the tokens it returns false and the time until it has them.
+func (rl *RateLimiter) AllowN(key string, n int) (bool, time.Duration) {
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.prune(now)
+
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{rl.burst, now}
+		rl.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(rl.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*rl.rate)
+	bucket.last = now
+
+	required := math.Min(float64(n), rl.burst)
+	if bucket.tokens < required {
+		return false, time.Duration((required - bucket.tokens) / rl.rate * float64(time.Second))
+	}
+
+	bucket.tokens -= float64(n)
+	return true, 0
+}
+
+// prune removes the buckets which have been refilled in full, debt included, at most once per refill period
+func (rl *RateLimiter) prune(now time.Time) {
+
+	full := time.Duration(rl.burst / rl.rate * float64(time.Second))
+	if now.Sub(rl.lastPrune) < full {
+		return
+	}
+
+	for key, bucket := range rl.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*rl.rate >= rl.burst {
+			delete(rl.buckets, key)
+		}
+	}
+	rl.lastPrune = now
+}
+
+// RateLimitMiddleware rejects requests over the budget of their key with 429 and a Retry-After header.
+// Placed after the authentication middlewares it can key requests by account,
+// placed before them with KeyByRemoteAddr it limits the authentication attempts of a host.
+func RateLimitMiddleware(limiter *RateLimiter, next httprouter.Handle) httprouter.Handle {
+	return RateLimitCostMiddleware(limiter, func(r *http.Request) int { return 1 }, next)
+}
+
+// RateLimitCostMiddleware rejects requests over the budget of their key like RateLimitMiddleware,
+// charging each request the tokens returned by cost
+func RateLimitCostMiddleware(limiter *RateLimiter, cost func(r *http.Request) int, next httprouter.Handle) httprouter.Handle {
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+		key := limiter.key(r)
+
+		ok, wait := limiter.AllowN(key, cost(r))
+		if !ok {
+			log.Printf("Request of %s rate limited", key)
+			w.Header().Set(RetryAfterHeader, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			WriteError(w, r, http.StatusTooManyRequests, RateLimitedCode, fmt.Sprintf("Rate limit of %g requests per second exceeded", limiter.rate))
+			return
+		}
+
+		next(w, r, ps)
+	}
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+
+	require := require.New(t)
+
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(2, 3, KeyByRemoteAddr)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		ok, _ := limiter.Allow("A")
+		require.True(ok)
+	}
+
+	ok, wait := limiter.Allow("A")
+	require.False(ok)
+	require.Equal(500*time.Millisecond, wait)
+
+	ok, _ = limiter.Allow("B")
+	require.True(ok)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = limiter.Allow("A")
+	require.True(ok)
+	ok, _ = limiter.Allow("A")
+	require.False(ok)
+
+	now = now.Add(time.Hour)
+	ok, _ = limiter.Allow("C")
+	require.True(ok)
+	require.Len(limiter.buckets, 1)
+}
+
+func TestRateLimiterAllowN(t *testing.T) {
+
+	require := require.New(t)
+
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(2, 3, KeyByRemoteAddr)
+	limiter.now = func() time.Time { return now }
+
+	ok, _ := limiter.AllowN("A", 2)
+	require.True(ok)
+	ok, wait := limiter.AllowN("A", 2)
+	require.False(ok)
+	require.Equal(500*time.Millisecond, wait)
+
+	// a cost over the burst takes a full bucket into debt
+	now = now.Add(time.Second)
+	ok, _ = limiter.AllowN("A", 9)
+	require.True(ok)
+
+	// the bucket in debt outlives the refill period of a full bucket
+	now = now.Add(2 * time.Second)
+	ok, wait = limiter.Allow("A")
+	require.False(ok)
+	require.Equal(1500*time.Millisecond, wait)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+
+	require := require.New(t)
+
+	limiter := NewRateLimiter(0.5, 1, KeyByAccount)
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/orders", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		RateLimitMiddleware(limiter, MockHandler{false}.MockHandle)(w, r.WithContext(WithAccount(r.Context(), r.Header.Get("X-Account"))), ps)
+	})
+
+	get := func(account string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(http.MethodGet, "/orders", nil)
+		request.Header.Set("X-Account", account)
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	require.Equal(http.StatusOK, get("ACC1").Code)
+
+	response := get("ACC1")
+	require.Equal(http.StatusTooManyRequests, response.Code)
+	require.Equal("2", response.Header().Get(RetryAfterHeader))
+
+	var problem Problem
+	err := json.Unmarshal(response.Body.Bytes(), &problem)
+	require.Nil(err)
+	require.Equal(RateLimitedCode, problem.Code)
+
+	require.Equal(http.StatusOK, get("ACC2").Code)
+}
+
+func TestRateLimitCostMiddleware(t *testing.T) {
+
+	require := require.New(t)
+
+	limiter := NewRateLimiter(1, 5, KeyByRemoteAddr)
+	handle := RateLimitCostMiddleware(limiter, func(r *http.Request) int { return 3 }, MockHandler{false}.MockHandle)
+
+	post := func() int {
+		request, _ := http.NewRequest(http.MethodPost, "/orders/batch", nil)
+		request.RemoteAddr = "10.0.0.1:5555"
+		response := httptest.NewRecorder()
+		handle(response, request, nil)
+		return response.Code
+	}
+
+	require.Equal(http.StatusOK, post())
+	require.Equal(http.StatusTooManyRequests, post())
+}
+
+func TestRateLimitKeys(t *testing.T) {
+
+	require := require.New(t)
+
+	request, _ := http.NewRequest(http.MethodGet, "/orders", nil)
+	request.RemoteAddr = "10.0.0.1:5555"
+
+	require.Equal("remote:10.0.0.1", KeyByRemoteAddr(request))
+	require.Equal("remote:10.0.0.1", KeyByAccount(request))
+	require.Equal("remote:10.0.0.1", KeyByAPIKey(request))
+
+	request.Header.Set(APIKeyHeader, "KEY1")
+	require.Equal("remote:10.0.0.1", KeyByAPIKey(request))
+
+	request = request.WithContext(WithAccount(request.Context(), "ACC1"))
+	require.Equal("account:ACC1", KeyByAccount(request))
+	require.Equal("account:ACC1", KeyByAPIKey(request))
+
+	request = request.WithContext(WithAPIKey(request.Context(), "KEY1"))
+	require.Equal("apikey:KEY1", KeyByAPIKey(request))
+
+	_, err := KeyFuncFromString("account")
+	require.Nil(err)
+	_, err = KeyFuncFromString("user")
+	require.NotNil(err)
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"log"
@@ -15,6 +16,8 @@ type RestClient interface {
 	Post(url string, payload interface{}) (*basehttp.Response, error)
 	Put(url string, payload interface{}) (*basehttp.Response, error)
 	Delete(url string) (*basehttp.Response, error)
+	NewRequest(ctx context.Context, httpMethod string, url string, payload interface{}) (*basehttp.Request, error)
+	Do(request *basehttp.Request) (*basehttp.Response, error)
 }
 
 // RestClientImpl defines a http client
@@ -75,6 +78,30 @@ func (c *RestClientImpl) Delete(url string) (*basehttp.Response, error) {
 	return c.sendRequest(req)
 }
 
+// NewRequest creates a request bound to the context, with a json body when the payload is not nil
+func (c *RestClientImpl) NewRequest(ctx context.Context, httpMethod string, url string, payload interface{}) (*basehttp.Request, error) {
+
+	var req *basehttp.Request
+	var err error
+
+	if payload == nil {
+		req, err = c.createRequest(httpMethod, url, nil)
+	} else {
+		req, err = c.createJSONRequest(httpMethod, url, payload)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return req.WithContext(ctx), nil
+}
+
+// Do sends a request created by NewRequest
+func (c *RestClientImpl) Do(request *basehttp.Request) (*basehttp.Response, error) {
+	return c.sendRequest(request)
+}
+
 func (c *RestClientImpl) createJSONRequest(httpMethod string, url string, payload interface{}) (*basehttp.Request, error) {
 
 	jsonPayload, err := json.Marshal(payload)
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Role defines the access role of a caller
+type Role string
+
+// Roles from least to most privileged
+const (
+	ViewerRole Role = "viewer"
+	TraderRole Role = "trader"
+	AdminRole  Role = "admin"
+)
+
+const roleContextKey contextKey = "role"
+
+func (r Role) level() int {
+	switch r {
+	case ViewerRole:
+		return 1
+	case TraderRole:
+		return 2
+	case AdminRole:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Allows returns true if the role has at least the privileges of the required role
+func (r Role) Allows(required Role) bool {
+	return r.level() > 0 && r.level() >= required.level()
+}
+
+// WithRole returns a copy of the context which carries the role
+func WithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, roleContextKey, role)
+}
+
+// RoleFromContext returns the role of the authenticated caller
+func RoleFromContext(ctx context.Context) (Role, bool) {
+	role, ok := ctx.Value(roleContextKey).(Role)
+	return role, ok
+}
+
+// Authorizer authenticates requests by bearer token or api key and authorizes them by role
+type Authorizer struct {
+	store    AccountStore
+	verifier *JWTVerifier
+}
+
+// NewAuthorizer creates a new authorizer
+func NewAuthorizer(store AccountStore, verifier *JWTVerifier) *Authorizer {
+	return &Authorizer{store, verifier}
+}
+
+// Authorize returns a middleware which requires the caller to have at least the provided role
+func (a *Authorizer) Authorize(role Role, next httprouter.Handle) httprouter.Handle {
+
+	authorized := RoleMiddleware(role, next)
+	apiKeyAuthenticated := APIKeyAuthMiddleware(a.store, authorized)
+	jwtAuthenticated := JWTAuthMiddleware(a.verifier, authorized)
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+		if strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			jwtAuthenticated(w, r, ps)
+		} else {
+			apiKeyAuthenticated(w, r, ps)
+		}
+	}
+}
+
+// JWTAuthMiddleware authenticates requests by bearer token and attaches the subject as account and the role to the request context
+func JWTAuthMiddleware(verifier *JWTVerifier, next httprouter.Handle) httprouter.Handle {
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		claims, err := verifier.Verify(token)
+		if err != nil {
+			log.Printf("Failed to verify bearer token! %s", err)
+			WriteError(w, r, http.StatusUnauthorized, UnauthorizedCode, "Invalid bearer token")
+			return
+		}
+
+		ctx := WithRole(WithAccount(r.Context(), claims.Subject), claims.Role)
+
+		next(w, r.WithContext(ctx), ps)
+	}
+}
+
+// RoleMiddleware validates that the authenticated caller has at least the required role
+func RoleMiddleware(required Role, next httprouter.Handle) httprouter.Handle {
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+		role, ok := RoleFromContext(r.Context())
+
+		if !ok || !role.Allows(required) {
+			log.Printf("Role %s was required, but caller has %s", required, role)
+			WriteError(w, r, http.StatusForbidden, ForbiddenCode, fmt.Sprintf("Role %s is required", required))
+			return
+		}
+
+		next(w, r, ps)
+	}
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/require"
+)
+
+func getRoleRouter(authorizer *Authorizer, role Role, account *string) *httprouter.Router {
+
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/resource", authorizer.Authorize(role, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		*account, _ = AccountFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	return router
+}
+
+func TestRoleAllows(t *testing.T) {
+
+	require := require.New(t)
+
+	require.True(AdminRole.Allows(TraderRole))
+	require.True(TraderRole.Allows(ViewerRole))
+	require.True(ViewerRole.Allows(ViewerRole))
+	require.False(ViewerRole.Allows(TraderRole))
+	require.False(TraderRole.Allows(AdminRole))
+	require.False(Role("unknown").Allows(ViewerRole))
+}
+
+func TestAuthorizerBearer(t *testing.T) {
+
+	require := require.New(t)
+
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	authorizer := NewAuthorizer(NewInMemoryAccountStore(), NewJWTVerifier(map[string]crypto.PublicKey{"ed": public}))
+
+	tests := []struct {
+		tokenRole Role
+		required  Role
+		expected  int
+	}{
+		{ViewerRole, ViewerRole, http.StatusOK},
+		{ViewerRole, TraderRole, http.StatusForbidden},
+		{TraderRole, TraderRole, http.StatusOK},
+		{TraderRole, AdminRole, http.StatusForbidden},
+		{AdminRole, AdminRole, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		var account string
+
+		request, _ := http.NewRequest(http.MethodGet, "/resource", nil)
+		request.Header.Set("Authorization", "Bearer "+signToken("EdDSA", "ed", key, validClaims(test.tokenRole)))
+
+		response := httptest.NewRecorder()
+		getRoleRouter(authorizer, test.required, &account).ServeHTTP(response, request)
+
+		require.Equal(test.expected, response.Code)
+		if test.expected == http.StatusOK {
+			require.Equal("ACC1", account)
+		}
+	}
+}
+
+func TestAuthorizerRateLimitKeys(t *testing.T) {
+
+	require := require.New(t)
+
+	public, key, _ := ed25519.GenerateKey(rand.Reader)
+	authorizer := NewAuthorizer(NewInMemoryAccountStore(&Account{"ACC2", "KEY2", "", TraderRole}), NewJWTVerifier(map[string]crypto.PublicKey{"ed": public}))
+
+	var rateLimitKey string
+	router := httprouter.New()
+	router.Handle(http.MethodGet, "/resource", authorizer.Authorize(TraderRole, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		rateLimitKey = KeyByAPIKey(r)
+	}))
+
+	request, _ := http.NewRequest(http.MethodGet, "/resource", nil)
+	request.Header.Set("Authorization", "Bearer "+signToken("EdDSA", "ed", key, validClaims(TraderRole)))
+	request.Header.Set(APIKeyHeader, "MADE-UP")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	require.Equal("account:ACC1", rateLimitKey)
+
+	request, _ = http.NewRequest(http.MethodGet, "/resource", nil)
+	request.Header.Set(APIKeyHeader, "KEY2")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	require.Equal("apikey:KEY2", rateLimitKey)
+}
+
+func TestAuthorizerInvalidBearer(t *testing.T) {
+
+	require := require.New(t)
+
+	authorizer := NewAuthorizer(NewInMemoryAccountStore(), NewJWTVerifier(map[string]crypto.PublicKey{}))
+	var account string
+
+	request, _ := http.NewRequest(http.MethodGet, "/resource", nil)
+	request.Header.Set("Authorization", "Bearer a.b.c")
+
+	response := httptest.NewRecorder()
+	getRoleRouter(authorizer, ViewerRole, &account).ServeHTTP(response, request)
+
+	require.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func TestAuthorizerAPIKey(t *testing.T) {
+
+	require := require.New(t)
+
+	store := NewInMemoryAccountStore(&Account{"ACC1", "KEY1", "", ""}, &Account{"ADM1", "KEY2", "", AdminRole})
+	authorizer := NewAuthorizer(store, NewJWTVerifier(map[string]crypto.PublicKey{}))
+	var account string
+
+	request, _ := http.NewRequest(http.MethodGet, "/resource", nil)
+	request.Header.Set(APIKeyHeader, "KEY1")
+	response := httptest.NewRecorder()
+	getRoleRouter(authorizer, AdminRole, &account).ServeHTTP(response, request)
+	require.Equal(http.StatusForbidden, response.Code)
+
+	request.Header.Set(APIKeyHeader, "KEY2")
+	response = httptest.NewRecorder()
+	getRoleRouter(authorizer, AdminRole, &account).ServeHTTP(response, request)
+	require.Equal(http.StatusOK, response.Code)
+	require.Equal("ADM1", account)
+}